@@ -2,16 +2,21 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 
 	"github.com/rss3-network/global-indexer/internal/config"
 	"github.com/rss3-network/global-indexer/internal/config/flag"
+	"github.com/rss3-network/global-indexer/internal/database"
+	"github.com/rss3-network/global-indexer/internal/provider"
 	"github.com/rss3-network/global-indexer/internal/service"
 	"github.com/rss3-network/global-indexer/internal/service/hub"
 	"github.com/rss3-network/global-indexer/internal/service/indexer"
 	"github.com/rss3-network/global-indexer/internal/service/scheduler"
 	"github.com/rss3-network/global-indexer/internal/service/settler"
+	"github.com/rss3-network/global-indexer/internal/service/settler/failure"
+	"github.com/rss3-network/global-indexer/schema"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"go.uber.org/fx"
@@ -58,6 +63,34 @@ var indexCommand = &cobra.Command{
 	},
 }
 
+var indexBackfillCommand = &cobra.Command{
+	Use:   "backfill",
+	Short: "Index a range of indexed blocks again after a fix of the handlers, replacing the rows indexed from them",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		configFile, err := provider.ProvideConfig()
+		if err != nil {
+			return fmt.Errorf("setup config file: %w", err)
+		}
+
+		databaseClient, err := provider.ProvideDatabaseClient(configFile)
+		if err != nil {
+			return err
+		}
+
+		redisClient, err := provider.ProvideRedisClient(configFile)
+		if err != nil {
+			return fmt.Errorf("dial redis: %w", err)
+		}
+
+		ethereumMultiChainClient, err := provider.ProvideEthereumMultiChainClient(configFile)
+		if err != nil {
+			return fmt.Errorf("dial ethereum clients: %w", err)
+		}
+
+		return indexer.Backfill(cmd.Context(), databaseClient, redisClient, ethereumMultiChainClient, configFile, viper.GetString(flag.KeyChain), viper.GetUint64(flag.KeyFrom), viper.GetUint64(flag.KeyTo))
+	},
+}
+
 var schedulerCommand = &cobra.Command{
 	Use: "scheduler",
 	RunE: func(cmd *cobra.Command, _ []string) error {
@@ -94,6 +127,121 @@ var settlerCommand = &cobra.Command{
 	},
 }
 
+var settlerPreviewCommand = &cobra.Command{
+	Use:   "preview",
+	Short: "Compute the Settlement data of an epoch without submitting it on chain",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		configFile, err := provider.ProvideConfig()
+		if err != nil {
+			return fmt.Errorf("setup config file: %w", err)
+		}
+
+		databaseClient, err := provider.ProvideDatabaseClient(configFile)
+		if err != nil {
+			return err
+		}
+
+		ethereumMultiChainClient, err := provider.ProvideEthereumMultiChainClient(configFile)
+		if err != nil {
+			return fmt.Errorf("dial ethereum clients: %w", err)
+		}
+
+		httpClient, err := provider.ProvideHTTPClient()
+		if err != nil {
+			return fmt.Errorf("new http client: %w", err)
+		}
+
+		previewer, err := settler.NewPreviewer(databaseClient, ethereumMultiChainClient, configFile, httpClient)
+		if err != nil {
+			return fmt.Errorf("new settlement previewer: %w", err)
+		}
+
+		preview, err := previewer.Preview(cmd.Context(), viper.GetUint64(flag.KeyEpoch))
+		if err != nil {
+			return fmt.Errorf("preview settlement: %w", err)
+		}
+
+		return encodeJSON(cmd, preview)
+	},
+}
+
+var settlerFailureCommand = &cobra.Command{
+	Use:   "failure",
+	Short: "Manage the failed settlement transactions waiting for an operator to resolve them",
+}
+
+var settlerFailureListCommand = &cobra.Command{
+	Use:   "list",
+	Short: "List the unresolved settlement failures along with their audit trails",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		databaseClient, err := provideSettlementFailureDatabaseClient()
+		if err != nil {
+			return err
+		}
+
+		failures, err := databaseClient.FindSettlementFailures(cmd.Context(), schema.SettlementFailuresQuery{
+			Statuses: []schema.SettlementFailureStatus{schema.SettlementFailureStatusFailed, schema.SettlementFailureStatusRetrying},
+		})
+		if err != nil {
+			return fmt.Errorf("find settlement failures: %w", err)
+		}
+
+		for _, settlementFailure := range failures {
+			if settlementFailure.Audits, err = databaseClient.FindSettlementFailureAudits(cmd.Context(), settlementFailure.ID); err != nil {
+				return fmt.Errorf("find settlement failure audits: %w", err)
+			}
+		}
+
+		return encodeJSON(cmd, failures)
+	},
+}
+
+// newSettlerFailureActionCommand creates a command which applies the action to a settlement failure,
+// the blocked service picks it up on its next poll.
+func newSettlerFailureActionCommand(action schema.SettlementFailureAction, short string) *cobra.Command {
+	actionCommand := &cobra.Command{
+		Use:   string(action),
+		Short: short,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			databaseClient, err := provideSettlementFailureDatabaseClient()
+			if err != nil {
+				return err
+			}
+
+			settlementFailure, err := failure.Resolve(cmd.Context(), databaseClient, viper.GetUint64(flag.KeySettlementFailureID), action, viper.GetString(flag.KeyOperator), viper.GetString(flag.KeyReason))
+			if err != nil {
+				return fmt.Errorf("%s settlement failure: %w", action, err)
+			}
+
+			return encodeJSON(cmd, settlementFailure)
+		},
+	}
+
+	actionCommand.Flags().Uint64(flag.KeySettlementFailureID, 0, "id of the settlement failure")
+	actionCommand.Flags().String(flag.KeyOperator, "", "name of the operator taking the action, recorded in the audit trail")
+	actionCommand.Flags().String(flag.KeyReason, "", "reason for the action, recorded in the audit trail")
+	_ = actionCommand.MarkFlagRequired(flag.KeySettlementFailureID)
+	_ = actionCommand.MarkFlagRequired(flag.KeyOperator)
+
+	return actionCommand
+}
+
+func provideSettlementFailureDatabaseClient() (database.Client, error) {
+	configFile, err := provider.ProvideConfig()
+	if err != nil {
+		return nil, fmt.Errorf("setup config file: %w", err)
+	}
+
+	return provider.ProvideDatabaseClient(configFile)
+}
+
+func encodeJSON(cmd *cobra.Command, value any) error {
+	encoder := json.NewEncoder(cmd.OutOrStdout())
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(value)
+}
+
 func initializeLogger() {
 	if os.Getenv(config.Environment) == config.EnvironmentDevelopment {
 		zap.ReplaceGlobals(zap.Must(zap.NewDevelopment()))
@@ -109,14 +257,32 @@ func init() {
 	command.AddCommand(schedulerCommand)
 	command.AddCommand(settlerCommand)
 
+	indexCommand.AddCommand(indexBackfillCommand)
+
+	settlerCommand.AddCommand(settlerPreviewCommand)
+	settlerCommand.AddCommand(settlerFailureCommand)
+
+	settlerFailureCommand.AddCommand(settlerFailureListCommand)
+	settlerFailureCommand.AddCommand(newSettlerFailureActionCommand(schema.SettlementFailureActionRetry, "Resend a failed settlement transaction"))
+	settlerFailureCommand.AddCommand(newSettlerFailureActionCommand(schema.SettlementFailureActionSkip, "Skip a failed settlement transaction and let the service move on"))
+	settlerFailureCommand.AddCommand(newSettlerFailureActionCommand(schema.SettlementFailureActionAbort, "Abort a failed settlement transaction and stop its epoch submission"))
+
 	command.PersistentFlags().String(flag.KeyConfig, "./deploy/config.yaml", "config file path")
 	command.PersistentFlags().Uint64(flag.KeyChainIDL1, flag.ValueChainIDL1, "l1 chain id")
 	command.PersistentFlags().Uint64(flag.KeyChainIDL2, flag.ValueChainIDL2, "l2 chain id")
 
 	indexCommand.PersistentFlags().String(flag.KeyConfig, "./deploy/config.yaml", "config file path")
+	indexBackfillCommand.Flags().String(flag.KeyChain, indexer.ChainL2, "chain to backfill, l1 or l2")
+	indexBackfillCommand.Flags().Uint64(flag.KeyFrom, 0, "first block to backfill")
+	indexBackfillCommand.Flags().Uint64(flag.KeyTo, 0, "last block to backfill")
+	_ = indexBackfillCommand.MarkFlagRequired(flag.KeyFrom)
+	_ = indexBackfillCommand.MarkFlagRequired(flag.KeyTo)
 	schedulerCommand.PersistentFlags().String(flag.KeyConfig, "./deploy/config.yaml", "config file path")
 	schedulerCommand.PersistentFlags().String(flag.KeyServer, "detector", "server name")
+	schedulerCommand.PersistentFlags().StringSlice(flag.KeyJobs, nil, "names of the jobs or groups of jobs to run, or all, overriding the server flag")
 	settlerCommand.PersistentFlags().String(flag.KeyConfig, "./deploy/config.yaml", "config file path")
+	settlerPreviewCommand.Flags().Uint64(flag.KeyEpoch, 0, "epoch to preview the Settlement data for")
+	_ = settlerPreviewCommand.MarkFlagRequired(flag.KeyEpoch)
 }
 
 func main() {
@@ -37,19 +37,36 @@ type SignerFn func(context.Context, common.Address, *types.Transaction) (*types.
 
 type SignerFactory func(chainID *big.Int) SignerFn
 
-func NewSignerFactory(privateKey, endpoint, address string) (SignerFactory, common.Address, error) {
+// SignerOptions selects the signer of a wallet, the first configured of a remote signer endpoint,
+// a keystore file, a signer plugin and a private key is used.
+type SignerOptions struct {
+	PrivateKey string
+
+	Endpoint string
+	Address  string
+
+	KeystorePath           string
+	KeystorePassphraseFile string
+	KeystorePassphraseEnv  string
+
+	Plugin        string
+	PluginOptions map[string]string
+}
+
+func NewSignerFactory(options SignerOptions) (SignerFactory, common.Address, error) {
 	var (
 		signer      SignerFactory
 		fromAddress common.Address
 	)
 
-	if endpoint != "" && address != "" {
-		signerClient, err := gisigner.NewSignerClient(endpoint)
+	switch {
+	case options.Endpoint != "" && options.Address != "":
+		signerClient, err := gisigner.NewSignerClient(options.Endpoint)
 		if err != nil {
 			return nil, common.Address{}, fmt.Errorf("failed to create the signer client: %w", err)
 		}
 
-		fromAddress = common.HexToAddress(address)
+		fromAddress = common.HexToAddress(options.Address)
 		signer = func(chainID *big.Int) SignerFn {
 			return func(ctx context.Context, address common.Address, tx *types.Transaction) (*types.Transaction, error) {
 				if !bytes.Equal(address[:], fromAddress[:]) {
@@ -59,18 +76,34 @@ func NewSignerFactory(privateKey, endpoint, address string) (SignerFactory, comm
 				return signerClient.SignTransaction(ctx, chainID, address, tx)
 			}
 		}
-	} else {
+	case options.KeystorePath != "":
+		keystoreSigner, err := NewKeystoreSigner(options.KeystorePath, options.KeystorePassphraseFile, options.KeystorePassphraseEnv)
+		if err != nil {
+			return nil, common.Address{}, fmt.Errorf("failed to load the keystore: %w", err)
+		}
+
+		fromAddress = keystoreSigner.Address()
+		signer = SignerFactoryFromSigner(keystoreSigner)
+	case options.Plugin != "":
+		pluginSigner, err := NewPluginSigner(options.Plugin, options.PluginOptions)
+		if err != nil {
+			return nil, common.Address{}, err
+		}
+
+		fromAddress = pluginSigner.Address()
+		signer = SignerFactoryFromSigner(pluginSigner)
+	default:
 		var (
 			privKey *ecdsa.PrivateKey
 
 			err error
 		)
 
-		if privateKey == "" {
+		if options.PrivateKey == "" {
 			return nil, common.Address{}, fmt.Errorf("at least specify a private key")
 		}
 
-		privKey, err = crypto.HexToECDSA(strings.TrimPrefix(privateKey, "0x"))
+		privKey, err = crypto.HexToECDSA(strings.TrimPrefix(options.PrivateKey, "0x"))
 		if err != nil {
 			return nil, common.Address{}, fmt.Errorf("failed to parse the private key: %w", err)
 		}
@@ -85,5 +118,9 @@ func NewSignerFactory(privateKey, endpoint, address string) (SignerFactory, comm
 		}
 	}
 
+	if options.Address != "" && common.HexToAddress(options.Address) != fromAddress {
+		return nil, common.Address{}, fmt.Errorf("the wallet address %s does not match the address %s of the signer", options.Address, fromAddress)
+	}
+
 	return signer, fromAddress, nil
 }
@@ -0,0 +1,167 @@
+package crypto
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signer is a backend holding the key of a wallet, such as a KMS, a PKCS#11 token or a HSM, which never exposes the key.
+type Signer interface {
+	// Address returns the address of the wallet.
+	Address() common.Address
+	// SignHash signs the hash with the key of the wallet,
+	// the signature is in the [R || S || V] format where V is 0 or 1.
+	SignHash(ctx context.Context, hash common.Hash) ([]byte, error)
+}
+
+// SignerPluginFactory creates a signer from the options of its plugin.
+type SignerPluginFactory func(options map[string]string) (Signer, error)
+
+var (
+	signerPluginsLocker sync.RWMutex
+	signerPlugins       = make(map[string]SignerPluginFactory)
+)
+
+// RegisterSignerPlugin registers a signer backend by name, it is usually called in the init function of the package of the backend.
+func RegisterSignerPlugin(name string, factory SignerPluginFactory) {
+	signerPluginsLocker.Lock()
+	defer signerPluginsLocker.Unlock()
+
+	if _, exists := signerPlugins[name]; exists {
+		panic(fmt.Sprintf("signer plugin %s is already registered", name))
+	}
+
+	signerPlugins[name] = factory
+}
+
+// NewPluginSigner creates a signer by a registered plugin.
+func NewPluginSigner(name string, options map[string]string) (Signer, error) {
+	signerPluginsLocker.RLock()
+	factory, exists := signerPlugins[name]
+	signerPluginsLocker.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("unknown signer plugin %s, the registered plugins are [%s]", name, strings.Join(signerPluginNames(), ", "))
+	}
+
+	signer, err := factory(options)
+	if err != nil {
+		return nil, fmt.Errorf("create signer by plugin %s: %w", name, err)
+	}
+
+	return signer, nil
+}
+
+func signerPluginNames() []string {
+	signerPluginsLocker.RLock()
+	defer signerPluginsLocker.RUnlock()
+
+	names := make([]string, 0, len(signerPlugins))
+
+	for name := range signerPlugins {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// SignerFactoryFromSigner signs the transactions with the signer, the sender of the signed transactions is verified,
+// so a backend returning signatures of another key is caught before the transactions are published.
+func SignerFactoryFromSigner(signer Signer) SignerFactory {
+	from := signer.Address()
+
+	return func(chainID *big.Int) SignerFn {
+		transactionSigner := types.LatestSignerForChainID(chainID)
+
+		return func(ctx context.Context, address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != from {
+				return nil, fmt.Errorf("attempting to sign for %s, expected %s", address, from)
+			}
+
+			signature, err := signer.SignHash(ctx, transactionSigner.Hash(tx))
+			if err != nil {
+				return nil, fmt.Errorf("sign transaction: %w", err)
+			}
+
+			signedTx, err := tx.WithSignature(transactionSigner, signature)
+			if err != nil {
+				return nil, fmt.Errorf("invalid signature: %w", err)
+			}
+
+			if sender, err := types.Sender(transactionSigner, signedTx); err != nil || sender != from {
+				return nil, fmt.Errorf("the transaction is not signed by %s", from)
+			}
+
+			return signedTx, nil
+		}
+	}
+}
+
+var _ Signer = (*privateKeySigner)(nil)
+
+// privateKeySigner is a signer holding the private key in memory.
+type privateKeySigner struct {
+	key *ecdsa.PrivateKey
+}
+
+func (s *privateKeySigner) Address() common.Address {
+	return crypto.PubkeyToAddress(s.key.PublicKey)
+}
+
+func (s *privateKeySigner) SignHash(_ context.Context, hash common.Hash) ([]byte, error) {
+	return crypto.Sign(hash.Bytes(), s.key)
+}
+
+// NewPrivateKeySigner creates a signer holding the private key in memory.
+func NewPrivateKeySigner(key *ecdsa.PrivateKey) Signer {
+	return &privateKeySigner{key: key}
+}
+
+// NewKeystoreSigner creates a signer from an encrypted keystore file of geth, the passphrase is read from the file,
+// or from the environment variable if the file is not specified.
+func NewKeystoreSigner(path, passphraseFile, passphraseEnv string) (Signer, error) {
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read keystore file: %w", err)
+	}
+
+	var passphrase string
+
+	switch {
+	case passphraseFile != "":
+		content, err := os.ReadFile(passphraseFile)
+		if err != nil {
+			return nil, fmt.Errorf("read passphrase file: %w", err)
+		}
+
+		passphrase = strings.TrimRight(string(content), "\r\n")
+	case passphraseEnv != "":
+		var exists bool
+
+		if passphrase, exists = os.LookupEnv(passphraseEnv); !exists {
+			return nil, fmt.Errorf("environment variable %s of the passphrase is not set", passphraseEnv)
+		}
+	default:
+		return nil, fmt.Errorf("must provide a passphrase file or environment variable")
+	}
+
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt keystore file: %w", err)
+	}
+
+	return NewPrivateKeySigner(key.PrivateKey), nil
+}
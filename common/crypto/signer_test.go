@@ -0,0 +1,209 @@
+package crypto
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ Signer = (*mockSigner)(nil)
+
+// mockSigner is a local signer backend, which signs with another key than the key of its address if it is broken.
+type mockSigner struct {
+	key    *ecdsa.PrivateKey
+	broken bool
+}
+
+func (s *mockSigner) Address() common.Address {
+	return crypto.PubkeyToAddress(s.key.PublicKey)
+}
+
+func (s *mockSigner) SignHash(_ context.Context, hash common.Hash) ([]byte, error) {
+	if s.broken {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			return nil, err
+		}
+
+		return crypto.Sign(hash.Bytes(), key)
+	}
+
+	return crypto.Sign(hash.Bytes(), s.key)
+}
+
+func init() {
+	RegisterSignerPlugin("mock", func(options map[string]string) (Signer, error) {
+		key, err := crypto.HexToECDSA(options["key"])
+		if err != nil {
+			return nil, fmt.Errorf("parse key: %w", err)
+		}
+
+		return &mockSigner{key: key, broken: options["broken"] == "true"}, nil
+	})
+}
+
+func newTransaction() *types.Transaction {
+	return types.NewTx(&types.DynamicFeeTx{
+		Nonce:     1,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(2),
+		Gas:       21000,
+		To:        &common.Address{},
+	})
+}
+
+func TestPluginSigner(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	chainID := big.NewInt(12553)
+
+	testCases := []struct {
+		name          string
+		options       SignerOptions
+		from          common.Address
+		wantError     bool
+		wantSignError bool
+	}{
+		{
+			name:    "Sign",
+			options: SignerOptions{Plugin: "mock", PluginOptions: map[string]string{"key": fmt.Sprintf("%x", crypto.FromECDSA(key))}},
+			from:    address,
+		},
+		{
+			name:          "SignForAnotherAddress",
+			options:       SignerOptions{Plugin: "mock", PluginOptions: map[string]string{"key": fmt.Sprintf("%x", crypto.FromECDSA(key))}},
+			from:          common.HexToAddress("0x01"),
+			wantSignError: true,
+		},
+		{
+			name:          "SignWithAnotherKey",
+			options:       SignerOptions{Plugin: "mock", PluginOptions: map[string]string{"key": fmt.Sprintf("%x", crypto.FromECDSA(key)), "broken": "true"}},
+			from:          address,
+			wantSignError: true,
+		},
+		{
+			name:      "WalletAddressMismatch",
+			options:   SignerOptions{Plugin: "mock", PluginOptions: map[string]string{"key": fmt.Sprintf("%x", crypto.FromECDSA(key))}, Address: "0x01"},
+			wantError: true,
+		},
+		{
+			name:      "UnknownPlugin",
+			options:   SignerOptions{Plugin: "unknown"},
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			factory, from, err := NewSignerFactory(tc.options)
+			if tc.wantError {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, address, from)
+
+			signedTx, err := factory(chainID)(context.Background(), tc.from, newTransaction())
+			if tc.wantSignError {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			sender, err := types.Sender(types.LatestSignerForChainID(chainID), signedTx)
+			require.NoError(t, err)
+			assert.Equal(t, address, sender)
+		})
+	}
+}
+
+func TestKeystoreSigner(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+
+	account, err := keystore.StoreKey(directory, "passphrase", keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+
+	passphraseFile := filepath.Join(directory, "passphrase")
+	require.NoError(t, os.WriteFile(passphraseFile, []byte("passphrase\n"), 0o600))
+
+	wrongPassphraseFile := filepath.Join(directory, "wrong-passphrase")
+	require.NoError(t, os.WriteFile(wrongPassphraseFile, []byte("wrong"), 0o600))
+
+	testCases := []struct {
+		name      string
+		options   SignerOptions
+		wantError bool
+	}{
+		{
+			name:    "PassphraseFile",
+			options: SignerOptions{KeystorePath: account.URL.Path, KeystorePassphraseFile: passphraseFile},
+		},
+		{
+			name:      "WrongPassphrase",
+			options:   SignerOptions{KeystorePath: account.URL.Path, KeystorePassphraseFile: wrongPassphraseFile},
+			wantError: true,
+		},
+		{
+			name:      "UnsetPassphraseEnv",
+			options:   SignerOptions{KeystorePath: account.URL.Path, KeystorePassphraseEnv: "GLOBAL_INDEXER_TEST_UNSET_PASSPHRASE"},
+			wantError: true,
+		},
+		{
+			name:      "NoPassphrase",
+			options:   SignerOptions{KeystorePath: account.URL.Path},
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			factory, from, err := NewSignerFactory(tc.options)
+			if tc.wantError {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, account.Address, from)
+
+			chainID := big.NewInt(12553)
+
+			signedTx, err := factory(chainID)(context.Background(), from, newTransaction())
+			require.NoError(t, err)
+
+			sender, err := types.Sender(types.LatestSignerForChainID(chainID), signedTx)
+			require.NoError(t, err)
+			assert.Equal(t, account.Address, sender)
+		})
+	}
+}
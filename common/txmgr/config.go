@@ -2,6 +2,7 @@ package txmgr
 
 import (
 	"fmt"
+	"math/big"
 	"time"
 )
 
@@ -41,6 +42,31 @@ type Config struct {
 	// are required to give up on a tx at a particular nonce without receiving
 	// confirmation.
 	SafeAbortNonceTooLowCount uint64
+
+	// MaxFeePerGas is the ceiling of the fee cap of the transactions, including their resubmissions.
+	// There is no ceiling if it is nil.
+	MaxFeePerGas *big.Int
+
+	// GasLimitMargin is the margin added to the estimated gas of the transactions,
+	// the gas limit of a candidate is then the ceiling of its gas limit. The gas limit of a candidate is used as is if it is 0.
+	GasLimitMargin float64
+
+	// EpochBudget is the maximum spent on the fees within an EpochInterval, there is no budget if it is nil.
+	EpochBudget   *big.Int
+	EpochInterval time.Duration
+
+	// DailyBudget is the maximum spent on the fees within a day, there is no budget if it is nil.
+	DailyBudget *big.Int
+
+	// MinBalance is the balance left in the wallet after the highest fee a transaction may pay, there is no guard if it is nil.
+	MinBalance *big.Int
+
+	// CraftRetryInterval is the base delay between the attempts to create a transaction, which backs off at each attempt.
+	// The attempts are retried while a send is deferred by a policy or the gas price cannot be fetched.
+	CraftRetryInterval time.Duration
+
+	// CraftRetryAttempts is the number of attempts to create a transaction before the send is given up.
+	CraftRetryAttempts uint
 }
 
 func (m Config) Check() error {
@@ -72,5 +98,21 @@ func (m Config) Check() error {
 		return fmt.Errorf("SafeAbortNonceTooLowCount must not be 0")
 	}
 
+	if m.CraftRetryInterval == 0 {
+		return fmt.Errorf("must provide CraftRetryInterval")
+	}
+
+	if m.CraftRetryAttempts == 0 {
+		return fmt.Errorf("CraftRetryAttempts must not be 0")
+	}
+
+	if m.GasLimitMargin < 0 {
+		return fmt.Errorf("GasLimitMargin must not be negative")
+	}
+
+	if m.EpochBudget != nil && m.EpochInterval == 0 {
+		return fmt.Errorf("must provide EpochInterval with EpochBudget")
+	}
+
 	return nil
 }
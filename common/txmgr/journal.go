@@ -0,0 +1,267 @@
+package txmgr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rss3-network/global-indexer/schema"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+)
+
+// The journal records the transactions sent by the manager in the database, so they can be followed after the service stops.
+// It is disabled if the manager has no database client. Failures to write it only fail the sending of the candidates requiring the journal,
+// as the transactions of the others can still be sent without being followed.
+
+// ErrJournalDisabled is returned when a transaction is looked up in the journal of a manager without a database client.
+var ErrJournalDisabled = errors.New("journal disabled")
+
+// journalTransaction records a crafted transaction as pending.
+func (m *SimpleTxManager) journalTransaction(ctx context.Context, tx *types.Transaction) (*schema.VSLTransaction, error) {
+	if m.databaseClient == nil {
+		return nil, ErrJournalDisabled
+	}
+
+	transaction := schema.VSLTransaction{
+		ChainID:   m.chainID.Uint64(),
+		From:      m.from,
+		To:        tx.To(),
+		Nonce:     tx.Nonce(),
+		Input:     tx.Data(),
+		Value:     tx.Value(),
+		GasLimit:  tx.Gas(),
+		GasTipCap: tx.GasTipCap(),
+		GasFeeCap: tx.GasFeeCap(),
+		Status:    schema.VSLTransactionStatusPending,
+	}
+
+	if err := m.databaseClient.SaveVSLTransaction(context.WithoutCancel(ctx), &transaction); err != nil {
+		return nil, fmt.Errorf("journal transaction with nonce %d: %w", tx.Nonce(), err)
+	}
+
+	return &transaction, nil
+}
+
+// journalSubmission records a signed transaction before it is published, the nonce and the fee caps of the transaction follow the latest submission.
+func (m *SimpleTxManager) journalSubmission(ctx context.Context, transaction *schema.VSLTransaction, tx *types.Transaction) error {
+	if transaction == nil || lo.FromPtr(transaction.TransactionHash) == tx.Hash() {
+		return nil
+	}
+
+	ctx = context.WithoutCancel(ctx)
+
+	submission := schema.VSLTransactionSubmission{
+		TransactionID:   transaction.ID,
+		TransactionHash: tx.Hash(),
+		Nonce:           tx.Nonce(),
+		GasTipCap:       tx.GasTipCap(),
+		GasFeeCap:       tx.GasFeeCap(),
+	}
+
+	if err := m.databaseClient.SaveVSLTransactionSubmission(ctx, &submission); err != nil {
+		return fmt.Errorf("journal transaction submission %s: %w", tx.Hash(), err)
+	}
+
+	transaction.Nonce = tx.Nonce()
+	transaction.GasTipCap = tx.GasTipCap()
+	transaction.GasFeeCap = tx.GasFeeCap()
+	transaction.TransactionHash = lo.ToPtr(tx.Hash())
+
+	// The receipt of the submission is found by its own record, so a failure to update the transaction is not returned.
+	m.saveJournal(ctx, transaction)
+
+	return nil
+}
+
+// journalReceipt records the receipt of an included transaction.
+func (m *SimpleTxManager) journalReceipt(ctx context.Context, transaction *schema.VSLTransaction, receipt *types.Receipt) {
+	if transaction == nil {
+		return
+	}
+
+	resolveTransaction(transaction, receipt)
+
+	m.saveJournal(context.WithoutCancel(ctx), transaction)
+}
+
+// journalAbort records a transaction given up before it was included.
+func (m *SimpleTxManager) journalAbort(ctx context.Context, transaction *schema.VSLTransaction, err error) {
+	if transaction == nil {
+		return
+	}
+
+	transaction.Status = schema.VSLTransactionStatusAborted
+	transaction.Error = err.Error()
+
+	m.saveJournal(context.WithoutCancel(ctx), transaction)
+}
+
+func (m *SimpleTxManager) saveJournal(ctx context.Context, transaction *schema.VSLTransaction) {
+	if err := m.databaseClient.SaveVSLTransaction(ctx, transaction); err != nil {
+		zap.L().Error("failed to journal transaction", zap.Error(err), zap.Uint64("id", transaction.ID))
+	}
+}
+
+func resolveTransaction(transaction *schema.VSLTransaction, receipt *types.Receipt) {
+	transaction.Status = lo.Ternary(receipt.Status == types.ReceiptStatusSuccessful, schema.VSLTransactionStatusConfirmed, schema.VSLTransactionStatusFailed)
+	transaction.TransactionHash = lo.ToPtr(receipt.TxHash)
+	transaction.BlockNumber = lo.ToPtr(receipt.BlockNumber.Uint64())
+	transaction.GasUsed = lo.ToPtr(receipt.GasUsed)
+	transaction.Fee = receiptFee(receipt)
+	transaction.Error = ""
+}
+
+// receiptFee returns the fee paid for an included transaction, the L1 data fee is charged on top of the gas by the OP Stack chains.
+func receiptFee(receipt *types.Receipt) *big.Int {
+	fee := new(big.Int)
+
+	if receipt.EffectiveGasPrice != nil {
+		fee.Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+	}
+
+	if receipt.L1Fee != nil {
+		fee.Add(fee, receipt.L1Fee)
+	}
+
+	return fee
+}
+
+// Reconcile resolves the transactions left unresolved by the previous runs of the services against the chain.
+// A transaction is resolved by the receipt of any of its submissions, or is dropped if its nonce has been used by another transaction.
+// The other transactions may still be in the mempool, they are replaced by the next transactions sent with bumped fees,
+// as the manager always starts from the nonce of the latest block.
+func (m *SimpleTxManager) Reconcile(ctx context.Context) error {
+	if m.databaseClient == nil {
+		return nil
+	}
+
+	transactions, err := m.databaseClient.FindVSLTransactions(ctx, schema.VSLTransactionsQuery{
+		From:     &m.from,
+		Statuses: []schema.VSLTransactionStatus{schema.VSLTransactionStatusPending, schema.VSLTransactionStatusAborted},
+	})
+	if err != nil {
+		return fmt.Errorf("find unresolved transactions: %w", err)
+	}
+
+	nonce, err := m.ethereumClient.NonceAt(ctx, m.from, nil)
+	if err != nil {
+		return fmt.Errorf("get nonce: %w", err)
+	}
+
+	pendingNonce, err := m.ethereumClient.PendingNonceAt(ctx, m.from)
+	if err != nil {
+		return fmt.Errorf("get pending nonce: %w", err)
+	}
+
+	if pendingNonce > nonce {
+		zap.L().Warn("transactions are pending in the mempool", zap.Stringer("from", m.from), zap.Uint64("nonce", nonce), zap.Uint64("pending_nonce", pendingNonce))
+	}
+
+	m.resetNonce()
+
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	submissions, err := m.databaseClient.FindVSLTransactionSubmissions(ctx, lo.Map(transactions, func(transaction *schema.VSLTransaction, _ int) uint64 {
+		return transaction.ID
+	}))
+	if err != nil {
+		return fmt.Errorf("find transaction submissions: %w", err)
+	}
+
+	for _, transaction := range transactions {
+		hashes := lo.FilterMap(submissions, func(submission *schema.VSLTransactionSubmission, _ int) (common.Hash, bool) {
+			return submission.TransactionHash, submission.TransactionID == transaction.ID
+		})
+
+		receipt, err := m.findReceipt(ctx, hashes)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case receipt != nil:
+			resolveTransaction(transaction, receipt)
+		case transaction.Nonce < nonce:
+			transaction.Status = schema.VSLTransactionStatusDropped
+		default:
+			continue
+		}
+
+		if err := m.databaseClient.SaveVSLTransaction(ctx, transaction); err != nil {
+			return fmt.Errorf("save transaction %d: %w", transaction.ID, err)
+		}
+
+		zap.L().Info("reconciled transaction", zap.Uint64("id", transaction.ID), zap.Uint64("nonce", transaction.Nonce), zap.String("status", string(transaction.Status)))
+	}
+
+	return nil
+}
+
+// FindReceipt returns the successful receipt of a transaction with the input sent by the manager, or nil if none of them is included.
+// The receipts are fetched by the hashes of the journaled submissions, so no block is searched.
+func (m *SimpleTxManager) FindReceipt(ctx context.Context, input []byte) (*types.Receipt, error) {
+	if m.databaseClient == nil {
+		return nil, ErrJournalDisabled
+	}
+
+	transactions, err := m.databaseClient.FindVSLTransactions(ctx, schema.VSLTransactionsQuery{
+		From:  &m.from,
+		Input: input,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("find journaled transactions: %w", err)
+	}
+
+	if len(transactions) == 0 {
+		return nil, nil
+	}
+
+	submissions, err := m.databaseClient.FindVSLTransactionSubmissions(ctx, lo.Map(transactions, func(transaction *schema.VSLTransaction, _ int) uint64 {
+		return transaction.ID
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("find transaction submissions: %w", err)
+	}
+
+	for _, transaction := range transactions {
+		hashes := lo.FilterMap(submissions, func(submission *schema.VSLTransactionSubmission, _ int) (common.Hash, bool) {
+			return submission.TransactionHash, submission.TransactionID == transaction.ID
+		})
+
+		receipt, err := m.findReceipt(ctx, hashes)
+		if err != nil {
+			return nil, err
+		}
+
+		if receipt != nil && receipt.Status == types.ReceiptStatusSuccessful {
+			return receipt, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// findReceipt returns the receipt of the included transaction of the hashes, or nil if none of them is included.
+func (m *SimpleTxManager) findReceipt(ctx context.Context, hashes []common.Hash) (*types.Receipt, error) {
+	for _, hash := range hashes {
+		receipt, err := m.ethereumClient.TransactionReceipt(ctx, hash)
+		if err != nil {
+			if errors.Is(err, ethereum.NotFound) {
+				continue
+			}
+
+			return nil, fmt.Errorf("get receipt of transaction %s: %w", hash, err)
+		}
+
+		return receipt, nil
+	}
+
+	return nil, nil
+}
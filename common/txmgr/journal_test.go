@@ -0,0 +1,315 @@
+package txmgr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/rss3-network/global-indexer/internal/database"
+	"github.com/rss3-network/global-indexer/schema"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// journalDatabaseClient is a database client holding the journal in memory.
+type journalDatabaseClient struct {
+	database.Client
+
+	transactions []*schema.VSLTransaction
+	submissions  []*schema.VSLTransactionSubmission
+}
+
+func (c *journalDatabaseClient) SaveVSLTransaction(_ context.Context, transaction *schema.VSLTransaction) error {
+	if transaction.ID == 0 {
+		transaction.ID = uint64(len(c.transactions) + 1)
+		c.transactions = append(c.transactions, transaction)
+	}
+
+	return nil
+}
+
+func (c *journalDatabaseClient) FindVSLTransactions(_ context.Context, query schema.VSLTransactionsQuery) ([]*schema.VSLTransaction, error) {
+	return lo.Filter(c.transactions, func(transaction *schema.VSLTransaction, _ int) bool {
+		return (len(query.Statuses) == 0 || lo.Contains(query.Statuses, transaction.Status)) &&
+			(query.Input == nil || bytes.Equal(query.Input, transaction.Input))
+	}), nil
+}
+
+func (c *journalDatabaseClient) SaveVSLTransactionSubmission(_ context.Context, submission *schema.VSLTransactionSubmission) error {
+	submission.ID = uint64(len(c.submissions) + 1)
+	c.submissions = append(c.submissions, submission)
+
+	return nil
+}
+
+func (c *journalDatabaseClient) FindVSLTransactionSubmissions(_ context.Context, transactionIDs []uint64) ([]*schema.VSLTransactionSubmission, error) {
+	return lo.Filter(c.submissions, func(submission *schema.VSLTransactionSubmission, _ int) bool {
+		return lo.Contains(transactionIDs, submission.TransactionID)
+	}), nil
+}
+
+// newChain starts a chain at the nonce, which has included the transactions of the receipts.
+func newChain(t *testing.T, nonce uint64, receipts ...*types.Receipt) *ethclient.Client {
+	t.Helper()
+
+	return newRPCServer(t, func(method string, params []json.RawMessage) any {
+		switch method {
+		case "eth_getTransactionCount":
+			return hexUint64(nonce)
+		case "eth_getTransactionReceipt":
+			var hash common.Hash
+
+			require.NoError(t, json.Unmarshal(params[0], &hash))
+
+			receipt, found := lo.Find(receipts, func(receipt *types.Receipt) bool {
+				return receipt.TxHash == hash
+			})
+			if found {
+				return receipt
+			}
+
+			return nil
+		default:
+			t.Errorf("unexpected method %s", method)
+
+			return nil
+		}
+	})
+}
+
+// newRPCServer starts a JSON-RPC server answering the calls with the results of the handler.
+func newRPCServer(t *testing.T, handler func(method string, params []json.RawMessage) any) *ethclient.Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		var message struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+
+		require.NoError(t, json.NewDecoder(request.Body).Decode(&message))
+
+		writer.Header().Set("Content-Type", "application/json")
+
+		require.NoError(t, json.NewEncoder(writer).Encode(map[string]any{
+			"jsonrpc": "2.0",
+			"id":      message.ID,
+			"result":  handler(message.Method, message.Params),
+		}))
+	}))
+
+	t.Cleanup(server.Close)
+
+	ethereumClient, err := ethclient.Dial(server.URL)
+	require.NoError(t, err)
+
+	return ethereumClient
+}
+
+func hexUint64(value uint64) string {
+	return "0x" + new(big.Int).SetUint64(value).Text(16)
+}
+
+func TestReconcile(t *testing.T) {
+	t.Parallel()
+
+	var (
+		includedHash = common.HexToHash("0x01")
+		replacedHash = common.HexToHash("0x02")
+	)
+
+	databaseClient := &journalDatabaseClient{
+		transactions: []*schema.VSLTransaction{
+			{ID: 1, Nonce: 1, Status: schema.VSLTransactionStatusPending},
+			{ID: 2, Nonce: 2, Status: schema.VSLTransactionStatusAborted},
+			{ID: 3, Nonce: 3, Status: schema.VSLTransactionStatusPending},
+			{ID: 4, Nonce: 10, Status: schema.VSLTransactionStatusPending},
+		},
+		submissions: []*schema.VSLTransactionSubmission{
+			{TransactionID: 1, TransactionHash: replacedHash},
+			{TransactionID: 1, TransactionHash: includedHash},
+		},
+	}
+
+	receipt := types.Receipt{
+		Status:      types.ReceiptStatusSuccessful,
+		TxHash:      includedHash,
+		BlockNumber: big.NewInt(100),
+		GasUsed:     21000,
+		Logs:        []*types.Log{},
+	}
+
+	manager := SimpleTxManager{
+		ethereumClient: newChain(t, 5, &receipt),
+		nonce:          lo.ToPtr(uint64(8)),
+		databaseClient: databaseClient,
+	}
+
+	require.NoError(t, manager.Reconcile(context.Background()))
+
+	// The transaction is resolved by the receipt of its included submission.
+	assert.Equal(t, schema.VSLTransactionStatusConfirmed, databaseClient.transactions[0].Status)
+	assert.Equal(t, includedHash, lo.FromPtr(databaseClient.transactions[0].TransactionHash))
+	assert.Equal(t, uint64(100), lo.FromPtr(databaseClient.transactions[0].BlockNumber))
+
+	// The nonces of the transactions without receipts have been used by other transactions.
+	assert.Equal(t, schema.VSLTransactionStatusDropped, databaseClient.transactions[1].Status)
+	assert.Equal(t, schema.VSLTransactionStatusDropped, databaseClient.transactions[2].Status)
+
+	// The transaction may still be in the mempool.
+	assert.Equal(t, schema.VSLTransactionStatusPending, databaseClient.transactions[3].Status)
+
+	// The nonce is fetched from the chain on the next transaction.
+	assert.Nil(t, manager.nonce)
+}
+
+func TestFindReceipt(t *testing.T) {
+	t.Parallel()
+
+	var (
+		input        = []byte{0x01}
+		failedHash   = common.HexToHash("0x01")
+		replacedHash = common.HexToHash("0x02")
+		includedHash = common.HexToHash("0x03")
+		otherHash    = common.HexToHash("0x04")
+	)
+
+	databaseClient := &journalDatabaseClient{
+		transactions: []*schema.VSLTransaction{
+			{ID: 1, Input: input, Status: schema.VSLTransactionStatusFailed},
+			{ID: 2, Input: input, Status: schema.VSLTransactionStatusPending},
+			{ID: 3, Input: []byte{0x02}, Status: schema.VSLTransactionStatusConfirmed},
+		},
+		submissions: []*schema.VSLTransactionSubmission{
+			{TransactionID: 1, TransactionHash: failedHash},
+			{TransactionID: 2, TransactionHash: replacedHash},
+			{TransactionID: 2, TransactionHash: includedHash},
+			{TransactionID: 3, TransactionHash: otherHash},
+		},
+	}
+
+	manager := SimpleTxManager{
+		ethereumClient: newChain(t, 5,
+			&types.Receipt{Status: types.ReceiptStatusFailed, TxHash: failedHash, BlockNumber: big.NewInt(99), Logs: []*types.Log{}},
+			&types.Receipt{Status: types.ReceiptStatusSuccessful, TxHash: includedHash, BlockNumber: big.NewInt(100), Logs: []*types.Log{}},
+			&types.Receipt{Status: types.ReceiptStatusSuccessful, TxHash: otherHash, BlockNumber: big.NewInt(101), Logs: []*types.Log{}},
+		),
+		databaseClient: databaseClient,
+	}
+
+	// The failed transaction is passed over for the included submission of the resent one.
+	receipt, err := manager.FindReceipt(context.Background(), input)
+	require.NoError(t, err)
+	require.NotNil(t, receipt)
+	assert.Equal(t, includedHash, receipt.TxHash)
+
+	receipt, err = manager.FindReceipt(context.Background(), []byte{0x03})
+	require.NoError(t, err)
+	assert.Nil(t, receipt)
+
+	_, err = (&SimpleTxManager{}).FindReceipt(context.Background(), input)
+	require.ErrorIs(t, err, ErrJournalDisabled)
+}
+
+func TestJournal(t *testing.T) {
+	t.Parallel()
+
+	databaseClient := &journalDatabaseClient{}
+
+	manager := SimpleTxManager{
+		chainID:        big.NewInt(1),
+		databaseClient: databaseClient,
+	}
+
+	newTx := func(nonce uint64, gasTipCap int64) *types.Transaction {
+		return types.NewTx(&types.DynamicFeeTx{
+			Nonce:     nonce,
+			GasTipCap: big.NewInt(gasTipCap),
+			GasFeeCap: big.NewInt(gasTipCap * 2),
+			Gas:       21000,
+		})
+	}
+
+	tx := newTx(1, 10)
+
+	transaction, err := manager.journalTransaction(context.Background(), tx)
+	require.NoError(t, err)
+	assert.Equal(t, schema.VSLTransactionStatusPending, transaction.Status)
+
+	// A submission is recorded once for each signed transaction.
+	require.NoError(t, manager.journalSubmission(context.Background(), transaction, tx))
+	require.NoError(t, manager.journalSubmission(context.Background(), transaction, tx))
+
+	bumpedTx := newTx(2, 11)
+
+	require.NoError(t, manager.journalSubmission(context.Background(), transaction, bumpedTx))
+
+	require.Len(t, databaseClient.submissions, 2)
+	assert.Equal(t, uint64(2), transaction.Nonce)
+	assert.Equal(t, big.NewInt(11), transaction.GasTipCap)
+	assert.Equal(t, bumpedTx.Hash(), lo.FromPtr(transaction.TransactionHash))
+
+	manager.journalReceipt(context.Background(), transaction, &types.Receipt{
+		Status:      types.ReceiptStatusFailed,
+		TxHash:      bumpedTx.Hash(),
+		BlockNumber: big.NewInt(100),
+	})
+
+	assert.Equal(t, schema.VSLTransactionStatusFailed, transaction.Status)
+
+	// The journal is disabled without a database client.
+	_, err = (&SimpleTxManager{chainID: big.NewInt(1)}).journalTransaction(context.Background(), tx)
+	require.ErrorIs(t, err, ErrJournalDisabled)
+}
+
+// failingJournalDatabaseClient is a database client failing to write the journal.
+type failingJournalDatabaseClient struct {
+	database.Client
+}
+
+func (c *failingJournalDatabaseClient) SaveVSLTransactionSubmission(context.Context, *schema.VSLTransactionSubmission) error {
+	return errors.New("connection refused")
+}
+
+func TestPrepareSubmission(t *testing.T) {
+	t.Parallel()
+
+	manager := SimpleTxManager{
+		chainID:        big.NewInt(1),
+		databaseClient: &failingJournalDatabaseClient{},
+	}
+
+	tx := types.NewTx(&types.DynamicFeeTx{Nonce: 1, GasTipCap: big.NewInt(10), GasFeeCap: big.NewInt(20), Gas: 21000})
+	transaction := &schema.VSLTransaction{ID: 1}
+
+	var published []common.Hash
+
+	beforePublish := func(_ context.Context, tx *types.Transaction) error {
+		published = append(published, tx.Hash())
+
+		return nil
+	}
+
+	// A submission which cannot be journaled is only published if the candidate does not require the journal.
+	require.NoError(t, manager.prepareSubmission(context.Background(), TxCandidate{BeforePublish: beforePublish}, transaction, tx))
+	require.Error(t, manager.prepareSubmission(context.Background(), TxCandidate{RequireJournal: true, BeforePublish: beforePublish}, transaction, tx))
+	assert.Equal(t, []common.Hash{tx.Hash()}, published)
+
+	// A submission is not published if the hook fails.
+	err := (&SimpleTxManager{}).prepareSubmission(context.Background(), TxCandidate{
+		BeforePublish: func(context.Context, *types.Transaction) error {
+			return errors.New("save hash")
+		},
+	}, nil, tx)
+	require.ErrorContains(t, err, "save hash")
+}
@@ -0,0 +1,172 @@
+package txmgr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rss3-network/global-indexer/schema"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrFeeCeilingExceeded is returned when the fee of a transaction would exceed MaxFeePerGas,
+	// the send is deferred until the fee falls below it, or is given up once the retries are exhausted.
+	ErrFeeCeilingExceeded = errors.New("fee ceiling exceeded")
+	// ErrGasLimitExceeded is returned when the estimated gas of a candidate exceeds its gas limit, the send is refused.
+	ErrGasLimitExceeded = errors.New("gas limit exceeded")
+	// ErrBudgetExceeded is returned when the highest fee of a transaction would exceed a spend budget, the send is refused.
+	ErrBudgetExceeded = errors.New("spend budget exceeded")
+	// ErrBalanceTooLow is returned when the wallet cannot pay the highest fee of a transaction and keep MinBalance, the send is refused.
+	ErrBalanceTooLow = errors.New("balance too low")
+)
+
+const (
+	PolicyMaxFeePerGas = "max_fee_per_gas"
+	PolicyGasLimit     = "gas_limit"
+	PolicyEpochBudget  = "epoch_budget"
+	PolicyDailyBudget  = "daily_budget"
+	PolicyMinBalance   = "min_balance"
+)
+
+var policyViolationCounter = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "txmgr_policy_violations_total",
+		Help: "Total number of sends refused or deferred by a send policy",
+	},
+	[]string{"policy", "action"},
+)
+
+// refused reports whether a policy violation refuses the send, rather than deferring it.
+func refused(err error) bool {
+	return errors.Is(err, ErrGasLimitExceeded) || errors.Is(err, ErrBudgetExceeded) || errors.Is(err, ErrBalanceTooLow)
+}
+
+// violate records a violation of the policy.
+func violate(policy string, err error) error {
+	action := "deferred"
+	if refused(err) {
+		action = "refused"
+	}
+
+	zap.L().Warn("send policy violated", zap.String("policy", policy), zap.String("action", action), zap.Error(err))
+
+	policyViolationCounter.WithLabelValues(policy, action).Inc()
+
+	return err
+}
+
+// capGasFeeCap lowers the fee cap to the ceiling, as long as the base fee and the tip can still be paid below it.
+func capGasFeeCap(gasFeeCap, gasTipCap, baseFee, ceiling *big.Int) (*big.Int, error) {
+	if ceiling == nil || gasFeeCap.Cmp(ceiling) <= 0 {
+		return gasFeeCap, nil
+	}
+
+	if required := new(big.Int).Add(baseFee, gasTipCap); required.Cmp(ceiling) > 0 {
+		return nil, fmt.Errorf("%w: base fee %s and tip %s are over the ceiling %s", ErrFeeCeilingExceeded, baseFee, gasTipCap, ceiling)
+	}
+
+	return new(big.Int).Set(ceiling), nil
+}
+
+// gasLimit returns the gas limit of a candidate. If GasLimitMargin is set, the gas is estimated and the margin is added,
+// up to the gas limit of the candidate, which is used as is if the estimation fails.
+func (m *SimpleTxManager) gasLimit(ctx context.Context, candidate TxCandidate, gasTipCap, gasFeeCap *big.Int) (uint64, error) {
+	if candidate.GasLimit != 0 && m.cfg.GasLimitMargin == 0 {
+		return candidate.GasLimit, nil
+	}
+
+	estimatedGas, err := m.ethereumClient.EstimateGas(ctx, ethereum.CallMsg{
+		From:      m.from,
+		To:        candidate.To,
+		GasFeeCap: gasFeeCap,
+		GasTipCap: gasTipCap,
+		Data:      candidate.TxData,
+		Value:     candidate.Value,
+	})
+	if err != nil {
+		if candidate.GasLimit != 0 {
+			zap.L().Warn("failed to estimate gas, using the gas limit of the candidate", zap.Error(err), zap.Uint64("gas_limit", candidate.GasLimit))
+
+			return candidate.GasLimit, nil
+		}
+
+		return 0, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+
+	return applyGasLimitMargin(estimatedGas, m.cfg.GasLimitMargin, candidate.GasLimit)
+}
+
+// applyGasLimitMargin adds the margin to the estimated gas, up to the ceiling if it is not 0.
+func applyGasLimitMargin(estimatedGas uint64, margin float64, ceiling uint64) (uint64, error) {
+	if ceiling != 0 && estimatedGas > ceiling {
+		return 0, violate(PolicyGasLimit, fmt.Errorf("%w: estimated gas %d is over the gas limit %d", ErrGasLimitExceeded, estimatedGas, ceiling))
+	}
+
+	gas := uint64(math.Ceil(float64(estimatedGas) * (1 + margin)))
+
+	if ceiling != 0 {
+		gas = min(gas, ceiling)
+	}
+
+	return gas, nil
+}
+
+// checkSpend checks the highest fee a transaction may pay against the spend budgets and the balance of the wallet.
+func (m *SimpleTxManager) checkSpend(ctx context.Context, rawTx *types.DynamicFeeTx) error {
+	maxFee := new(big.Int).Mul(new(big.Int).SetUint64(rawTx.Gas), rawTx.GasFeeCap)
+
+	budgets := []struct {
+		policy string
+		budget *big.Int
+		window time.Duration
+	}{
+		{policy: PolicyEpochBudget, budget: m.cfg.EpochBudget, window: m.cfg.EpochInterval},
+		{policy: PolicyDailyBudget, budget: m.cfg.DailyBudget, window: 24 * time.Hour},
+	}
+
+	for _, budget := range budgets {
+		if budget.budget == nil {
+			continue
+		}
+
+		spent, err := m.databaseClient.SumVSLTransactionFees(ctx, schema.VSLTransactionFeesQuery{
+			From:  m.from,
+			Since: time.Now().Add(-budget.window),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get spent fees: %w", err)
+		}
+
+		if new(big.Int).Add(spent, maxFee).Cmp(budget.budget) > 0 {
+			return violate(budget.policy, fmt.Errorf("%w: %s spent within %s, the transaction may spend %s more over the budget %s", ErrBudgetExceeded, spent, budget.window, maxFee, budget.budget))
+		}
+	}
+
+	if m.cfg.MinBalance == nil {
+		return nil
+	}
+
+	balance, err := m.ethereumClient.BalanceAt(ctx, m.from, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get balance: %w", err)
+	}
+
+	required := new(big.Int).Add(maxFee, m.cfg.MinBalance)
+	if rawTx.Value != nil {
+		required.Add(required, rawTx.Value)
+	}
+
+	if balance.Cmp(required) < 0 {
+		return violate(PolicyMinBalance, fmt.Errorf("%w: balance %s is below %s", ErrBalanceTooLow, balance, required))
+	}
+
+	return nil
+}
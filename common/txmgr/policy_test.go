@@ -0,0 +1,222 @@
+package txmgr
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rss3-network/global-indexer/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// spendDatabaseClient is a database client returning the same spent fees for any window.
+type spendDatabaseClient struct {
+	journalDatabaseClient
+
+	spent *big.Int
+}
+
+func (c *spendDatabaseClient) SumVSLTransactionFees(_ context.Context, _ schema.VSLTransactionFeesQuery) (*big.Int, error) {
+	return c.spent, nil
+}
+
+func TestCapGasFeeCap(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		gasFeeCap int64
+		baseFee   int64
+		ceiling   *big.Int
+		want      *big.Int
+		wantError error
+	}{
+		{
+			name:      "NoCeiling",
+			gasFeeCap: 300,
+			baseFee:   100,
+			want:      big.NewInt(300),
+		},
+		{
+			name:      "BelowCeiling",
+			gasFeeCap: 300,
+			baseFee:   100,
+			ceiling:   big.NewInt(500),
+			want:      big.NewInt(300),
+		},
+		{
+			name:      "Capped",
+			gasFeeCap: 300,
+			baseFee:   100,
+			ceiling:   big.NewInt(200),
+			want:      big.NewInt(200),
+		},
+		{
+			name:      "BaseFeeOverCeiling",
+			gasFeeCap: 300,
+			baseFee:   195,
+			ceiling:   big.NewInt(200),
+			wantError: ErrFeeCeilingExceeded,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gasFeeCap, err := capGasFeeCap(big.NewInt(tc.gasFeeCap), big.NewInt(10), big.NewInt(tc.baseFee), tc.ceiling)
+			if tc.wantError != nil {
+				require.ErrorIs(t, err, tc.wantError)
+				assert.False(t, refused(err))
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, gasFeeCap)
+		})
+	}
+}
+
+func TestApplyGasLimitMargin(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		estimatedGas uint64
+		margin       float64
+		ceiling      uint64
+		want         uint64
+		wantError    error
+	}{
+		{
+			name:         "NoCeiling",
+			estimatedGas: 100000,
+			margin:       0.2,
+			want:         120000,
+		},
+		{
+			name:         "NoMargin",
+			estimatedGas: 100000,
+			ceiling:      500000,
+			want:         100000,
+		},
+		{
+			name:         "Capped",
+			estimatedGas: 100000,
+			margin:       0.2,
+			ceiling:      110000,
+			want:         110000,
+		},
+		{
+			name:         "EstimateOverCeiling",
+			estimatedGas: 600000,
+			margin:       0.2,
+			ceiling:      500000,
+			wantError:    ErrGasLimitExceeded,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gas, err := applyGasLimitMargin(tc.estimatedGas, tc.margin, tc.ceiling)
+			if tc.wantError != nil {
+				require.ErrorIs(t, err, tc.wantError)
+				assert.True(t, refused(err))
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, gas)
+		})
+	}
+}
+
+func TestCheckSpend(t *testing.T) {
+	t.Parallel()
+
+	// The transaction may pay 100000 * 10 = 1000000 wei.
+	rawTx := types.DynamicFeeTx{
+		Gas:       100000,
+		GasFeeCap: big.NewInt(10),
+		Value:     big.NewInt(500),
+	}
+
+	testCases := []struct {
+		name      string
+		config    Config
+		spent     int64
+		balance   int64
+		wantError error
+	}{
+		{
+			name: "NoPolicy",
+		},
+		{
+			name:   "WithinBudgets",
+			config: Config{EpochBudget: big.NewInt(2000000), DailyBudget: big.NewInt(3000000)},
+			spent:  1000000,
+		},
+		{
+			name:      "EpochBudgetExceeded",
+			config:    Config{EpochBudget: big.NewInt(2000000), DailyBudget: big.NewInt(3000000)},
+			spent:     1000001,
+			wantError: ErrBudgetExceeded,
+		},
+		{
+			name:      "DailyBudgetExceeded",
+			config:    Config{DailyBudget: big.NewInt(1500000)},
+			spent:     600000,
+			wantError: ErrBudgetExceeded,
+		},
+		{
+			name:    "AboveMinBalance",
+			config:  Config{MinBalance: big.NewInt(1000)},
+			balance: 1001500,
+		},
+		{
+			name:      "BelowMinBalance",
+			config:    Config{MinBalance: big.NewInt(1000)},
+			balance:   1001499,
+			wantError: ErrBalanceTooLow,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			manager := SimpleTxManager{
+				cfg: tc.config,
+				ethereumClient: newRPCServer(t, func(method string, _ []json.RawMessage) any {
+					assert.Equal(t, "eth_getBalance", method)
+
+					return hexUint64(uint64(tc.balance))
+				}),
+				databaseClient: &spendDatabaseClient{spent: big.NewInt(tc.spent)},
+			}
+
+			err := manager.checkSpend(context.Background(), &rawTx)
+			if tc.wantError != nil {
+				require.ErrorIs(t, err, tc.wantError)
+				assert.True(t, refused(err))
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
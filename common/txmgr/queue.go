@@ -0,0 +1,334 @@
+package txmgr
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-redsync/redsync/v4"
+	"github.com/go-redsync/redsync/v4/redis/goredis/v9"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Priority is the priority of a transaction in the send queue of a wallet, the transactions with a higher priority are sent first.
+type Priority int
+
+const (
+	PriorityDefault Priority = iota
+	PriorityTax
+	PriorityDemotion
+	PrioritySettlement
+)
+
+// priorities are the priorities from the highest to the lowest.
+var priorities = []Priority{PrioritySettlement, PriorityDemotion, PriorityTax, PriorityDefault}
+
+func (p Priority) String() string {
+	switch p {
+	case PrioritySettlement:
+		return "settlement"
+	case PriorityDemotion:
+		return "demotion"
+	case PriorityTax:
+		return "tax"
+	default:
+		return "default"
+	}
+}
+
+var (
+	// QueueCacheKey is the prefix used for cache keys related to the sorted set of the tickets waiting to send from a wallet,
+	// scored by their priority and the time they were enqueued.
+	QueueCacheKey = "txmgr:queue"
+	// TicketCacheKey is the prefix used for cache keys related to the heartbeats of the waiting tickets.
+	TicketCacheKey = "txmgr:ticket"
+	// LockCacheKey is the prefix used for cache keys related to the lock of a wallet, held while a transaction is sent from it.
+	LockCacheKey = "txmgr:lock"
+	// NonceCacheKey is the prefix used for cache keys related to the next nonce of a wallet.
+	NonceCacheKey = "txmgr:nonce"
+)
+
+const (
+	// priorityScoreStep separates the scores of the priorities, it is greater than any time in milliseconds a ticket is enqueued at.
+	priorityScoreStep = 1e13
+
+	queuePollInterval = time.Second
+	// ticketExpiry is the time after which the ticket of a stopped process is dropped from the queue.
+	ticketExpiry = 30 * time.Second
+	// lockExpiry is the time after which the lock held by a stopped process is released.
+	lockExpiry = time.Minute
+)
+
+var queueWaitDuration = promauto.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "txmgr_send_queue_wait_seconds",
+		Help:    "Time a transaction waited in the send queue of its wallet",
+		Buckets: prometheus.ExponentialBuckets(0.1, 2, 14),
+	},
+	[]string{"priority"},
+)
+
+// SendQueue is the send queue of a wallet, shared in Redis by the processes sending transactions from it.
+//
+// A transaction waits in the queue for its turn, which comes by priority and then in the order the transactions were enqueued.
+// The transaction at the head of the queue takes the lock of the wallet, and holds it until it is included or given up,
+// so that only one transaction is sent from the wallet at a time and its resubmissions are never replaced by another process.
+// The next nonce of the wallet is kept along with the lock, as the RPC endpoints may lag behind the latest transaction.
+type SendQueue struct {
+	redisClient *redis.Client
+	redsync     *redsync.Redsync
+	wallet      string
+}
+
+func NewSendQueue(redisClient *redis.Client, chainID *big.Int, from common.Address) *SendQueue {
+	return &SendQueue{
+		redisClient: redisClient,
+		redsync:     redsync.New(goredis.NewPool(redisClient)),
+		wallet:      fmt.Sprintf("%s:%s", chainID, from),
+	}
+}
+
+// Acquire waits for the turn of a transaction with the priority, and returns a function releasing the wallet once the transaction is done.
+func (q *SendQueue) Acquire(ctx context.Context, priority Priority) (func(), error) {
+	ticket, err := newTicket()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		queueKey  = formatCacheKey(QueueCacheKey, q.wallet)
+		ticketKey = formatCacheKey(TicketCacheKey, ticket)
+		startedAt = time.Now()
+	)
+
+	pipe := q.redisClient.Pipeline()
+
+	pipe.ZAdd(ctx, queueKey, redis.Z{Member: ticket, Score: ticketScore(priority, startedAt)})
+	pipe.Set(ctx, ticketKey, priority.String(), ticketExpiry)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("enqueue ticket: %w", err)
+	}
+
+	// The ticket leaves the queue once the lock is taken, or the waiting is given up.
+	defer func() {
+		ctx := context.WithoutCancel(ctx)
+
+		pipe := q.redisClient.Pipeline()
+
+		pipe.ZRem(ctx, queueKey, ticket)
+		pipe.Del(ctx, ticketKey)
+
+		if _, err := pipe.Exec(ctx); err != nil {
+			zap.L().Error("failed to dequeue ticket", zap.String("wallet", q.wallet), zap.Error(err))
+		}
+	}()
+
+	mutex := q.redsync.NewMutex(formatCacheKey(LockCacheKey, q.wallet), redsync.WithExpiry(lockExpiry), redsync.WithTries(1))
+
+	ticker := time.NewTicker(queuePollInterval)
+	defer ticker.Stop()
+
+	for {
+		head, err := q.head(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if head == ticket && mutex.TryLockContext(ctx) == nil {
+			break
+		}
+
+		if err := q.redisClient.Expire(ctx, ticketKey, ticketExpiry).Err(); err != nil {
+			return nil, fmt.Errorf("refresh ticket: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	queueWaitDuration.WithLabelValues(priority.String()).Observe(time.Since(startedAt).Seconds())
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	go q.extend(ctx, mutex)
+
+	return func() {
+		cancel()
+
+		if _, err := mutex.Unlock(); err != nil {
+			zap.L().Error("release wallet lock error", zap.String("key", mutex.Name()), zap.Error(err))
+		}
+	}, nil
+}
+
+// head returns the ticket at the head of the queue, the tickets of the stopped processes are dropped on the way.
+func (q *SendQueue) head(ctx context.Context) (string, error) {
+	queueKey := formatCacheKey(QueueCacheKey, q.wallet)
+
+	for {
+		tickets, err := q.redisClient.ZRange(ctx, queueKey, 0, 0).Result()
+		if err != nil {
+			return "", fmt.Errorf("get head of queue: %w", err)
+		}
+
+		if len(tickets) == 0 {
+			return "", nil
+		}
+
+		exists, err := q.redisClient.Exists(ctx, formatCacheKey(TicketCacheKey, tickets[0])).Result()
+		if err != nil {
+			return "", fmt.Errorf("get ticket: %w", err)
+		}
+
+		if exists > 0 {
+			return tickets[0], nil
+		}
+
+		zap.L().Warn("dropped expired ticket", zap.String("wallet", q.wallet), zap.String("ticket", tickets[0]))
+
+		if err := q.redisClient.ZRem(ctx, queueKey, tickets[0]).Err(); err != nil {
+			return "", fmt.Errorf("drop expired ticket: %w", err)
+		}
+	}
+}
+
+// extend extends the lock of the wallet every half of its expiry, until the context is done.
+func (q *SendQueue) extend(ctx context.Context, mutex *redsync.Mutex) {
+	ticker := time.NewTicker(lockExpiry / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if ok, err := mutex.ExtendContext(ctx); err != nil || !ok {
+				zap.L().Error("extend wallet lock failed", zap.String("key", mutex.Name()), zap.Error(err))
+			}
+		}
+	}
+}
+
+// nextNonce returns the next nonce of the wallet, it is never below the nonce of the latest block.
+func (q *SendQueue) nextNonce(ctx context.Context, chainNonce uint64) (uint64, error) {
+	nonce, err := q.redisClient.Get(ctx, formatCacheKey(NonceCacheKey, q.wallet)).Uint64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return chainNonce, nil
+		}
+
+		return 0, fmt.Errorf("get next nonce: %w", err)
+	}
+
+	return max(nonce, chainNonce), nil
+}
+
+// commitNonce records the nonce of an included transaction.
+func (q *SendQueue) commitNonce(ctx context.Context, nonce uint64) error {
+	return q.redisClient.Set(ctx, formatCacheKey(NonceCacheKey, q.wallet), nonce+1, 0).Err()
+}
+
+// resetNonce forgets the next nonce of the wallet after a transaction is given up, the nonce of the latest block is used again.
+func (q *SendQueue) resetNonce(ctx context.Context) error {
+	return q.redisClient.Del(ctx, formatCacheKey(NonceCacheKey, q.wallet)).Err()
+}
+
+// depths returns the number of the waiting tickets by priority.
+func (q *SendQueue) depths(ctx context.Context) (map[Priority]int64, error) {
+	queueKey := formatCacheKey(QueueCacheKey, q.wallet)
+
+	pipe := q.redisClient.Pipeline()
+	commands := make(map[Priority]*redis.IntCmd, len(priorities))
+
+	for _, priority := range priorities {
+		// The scores of a priority are in [step * (PrioritySettlement - priority), step * (PrioritySettlement - priority + 1)).
+		low := ticketScore(priority, time.UnixMilli(0))
+
+		commands[priority] = pipe.ZCount(ctx, queueKey, strconv.FormatFloat(low, 'f', -1, 64), "("+strconv.FormatFloat(low+priorityScoreStep, 'f', -1, 64))
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("count tickets: %w", err)
+	}
+
+	depths := make(map[Priority]int64, len(commands))
+
+	for priority, command := range commands {
+		depths[priority] = command.Val()
+	}
+
+	return depths, nil
+}
+
+// ticketScore returns the score of a ticket, the tickets with a lower score are at the head of the queue.
+func ticketScore(priority Priority, enqueuedAt time.Time) float64 {
+	return float64(PrioritySettlement-priority)*priorityScoreStep + float64(enqueuedAt.UnixMilli())
+}
+
+func newTicket() (string, error) {
+	ticket := make([]byte, 16)
+
+	if _, err := rand.Read(ticket); err != nil {
+		return "", fmt.Errorf("generate ticket: %w", err)
+	}
+
+	return hex.EncodeToString(ticket), nil
+}
+
+func formatCacheKey(key, value string) string {
+	return fmt.Sprintf("%s:%s", key, value)
+}
+
+var _ prometheus.Collector = (*QueueCollector)(nil)
+
+// QueueCollector exports the depth of the send queue of a wallet from Redis,
+// so that the transactions waiting in the settler and the scheduler can be scraped from the hub.
+type QueueCollector struct {
+	queue *SendQueue
+	depth *prometheus.Desc
+}
+
+func NewQueueCollector(queue *SendQueue) *QueueCollector {
+	return &QueueCollector{
+		queue: queue,
+		depth: prometheus.NewDesc(
+			"txmgr_send_queue_depth",
+			"Number of transactions waiting in the send queue of a wallet",
+			[]string{"wallet", "priority"},
+			nil,
+		),
+	}
+}
+
+func (c *QueueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.depth
+}
+
+func (c *QueueCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	depths, err := c.queue.depths(ctx)
+	if err != nil {
+		zap.L().Error("collect send queue depth", zap.Error(err))
+
+		return
+	}
+
+	for priority, depth := range depths {
+		ch <- prometheus.MustNewConstMetric(c.depth, prometheus.GaugeValue, float64(depth), c.queue.wallet, priority.String())
+	}
+}
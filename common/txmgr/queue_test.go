@@ -0,0 +1,272 @@
+package txmgr
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/redis/go-redis/v9"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+)
+
+func TestTicketScore(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	type ticket struct {
+		name       string
+		priority   Priority
+		enqueuedAt time.Time
+	}
+
+	tickets := []ticket{
+		{name: "LateTax", priority: PriorityTax, enqueuedAt: now.Add(time.Minute)},
+		{name: "Default", priority: PriorityDefault, enqueuedAt: now.Add(-time.Hour)},
+		{name: "EarlyTax", priority: PriorityTax, enqueuedAt: now},
+		{name: "LateSettlement", priority: PrioritySettlement, enqueuedAt: now.Add(time.Hour)},
+		{name: "Demotion", priority: PriorityDemotion, enqueuedAt: now.Add(-time.Minute)},
+		{name: "EarlySettlement", priority: PrioritySettlement, enqueuedAt: now.Add(time.Second)},
+	}
+
+	sort.Slice(tickets, func(i, j int) bool {
+		return ticketScore(tickets[i].priority, tickets[i].enqueuedAt) < ticketScore(tickets[j].priority, tickets[j].enqueuedAt)
+	})
+
+	names := make([]string, 0, len(tickets))
+	for _, ticket := range tickets {
+		names = append(names, ticket.name)
+	}
+
+	// The tickets are sent by priority, and then in the order they were enqueued.
+	assert.Equal(t, []string{"EarlySettlement", "LateSettlement", "Demotion", "EarlyTax", "LateTax", "Default"}, names)
+
+	// The scores of a priority stay within its band.
+	for _, priority := range priorities {
+		low := ticketScore(priority, time.UnixMilli(0))
+
+		assert.GreaterOrEqual(t, ticketScore(priority, now), low)
+		assert.Less(t, ticketScore(priority, now), low+priorityScoreStep)
+	}
+}
+
+func newTestSendQueue(t *testing.T) (*SendQueue, *miniredis.Miniredis) {
+	t.Helper()
+
+	redisServer := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: redisServer.Addr()})
+
+	t.Cleanup(func() {
+		_ = redisClient.Close()
+	})
+
+	return NewSendQueue(redisClient, big.NewInt(1), common.HexToAddress("0x0000000000000000000000000000000000000001")), redisServer
+}
+
+func TestSendQueueAcquire(t *testing.T) {
+	t.Parallel()
+
+	queue, redisServer := newTestSendQueue(t)
+
+	ctx := context.Background()
+	queueKey := formatCacheKey(QueueCacheKey, queue.wallet)
+
+	release, err := queue.Acquire(ctx, PriorityDefault)
+	require.NoError(t, err)
+
+	// The ticket leaves the queue once the lock is taken.
+	assert.False(t, redisServer.Exists(queueKey))
+
+	var (
+		order = make(chan Priority, 2)
+		group errgroup.Group
+	)
+
+	acquire := func(priority Priority) {
+		group.Go(func() error {
+			release, err := queue.Acquire(ctx, priority)
+			if err != nil {
+				return err
+			}
+
+			order <- priority
+
+			release()
+
+			return nil
+		})
+	}
+
+	// The default transaction is enqueued before the settlement, but waits behind it.
+	acquire(PriorityDefault)
+	require.Eventually(t, func() bool { return queueLength(t, queue) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	acquire(PrioritySettlement)
+	require.Eventually(t, func() bool { return queueLength(t, queue) == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	// No transaction is sent while the wallet is locked.
+	time.Sleep(queuePollInterval + 100*time.Millisecond)
+	assert.Empty(t, order)
+
+	release()
+
+	require.NoError(t, group.Wait())
+	close(order)
+
+	var acquired []Priority
+	for priority := range order {
+		acquired = append(acquired, priority)
+	}
+
+	assert.Equal(t, []Priority{PrioritySettlement, PriorityDefault}, acquired)
+	assert.Equal(t, int64(0), queueLength(t, queue))
+}
+
+func TestSendQueueAcquireCanceled(t *testing.T) {
+	t.Parallel()
+
+	queue, _ := newTestSendQueue(t)
+
+	release, err := queue.Acquire(context.Background(), PriorityDefault)
+	require.NoError(t, err)
+
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err = queue.Acquire(ctx, PrioritySettlement)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The ticket of the given up transaction leaves the queue.
+	assert.Equal(t, int64(0), queueLength(t, queue))
+}
+
+func TestSendQueueHead(t *testing.T) {
+	t.Parallel()
+
+	queue, redisServer := newTestSendQueue(t)
+
+	ctx := context.Background()
+	queueKey := formatCacheKey(QueueCacheKey, queue.wallet)
+	now := time.Now()
+
+	// The ticket of a stopped process is at the head of the queue, but its heartbeat has expired.
+	_, err := redisServer.ZAdd(queueKey, ticketScore(PrioritySettlement, now), "expired")
+	require.NoError(t, err)
+	_, err = redisServer.ZAdd(queueKey, ticketScore(PriorityDefault, now), "waiting")
+	require.NoError(t, err)
+	require.NoError(t, redisServer.Set(formatCacheKey(TicketCacheKey, "waiting"), PriorityDefault.String()))
+
+	head, err := queue.head(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "waiting", head)
+
+	members, err := redisServer.ZMembers(queueKey)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"waiting"}, members)
+
+	// The queue is empty once the last ticket expires.
+	redisServer.Del(formatCacheKey(TicketCacheKey, "waiting"))
+
+	head, err = queue.head(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, head)
+	assert.False(t, redisServer.Exists(queueKey))
+}
+
+func TestSendQueueNonce(t *testing.T) {
+	t.Parallel()
+
+	queue, _ := newTestSendQueue(t)
+
+	ctx := context.Background()
+
+	// The nonce of the latest block is used until a transaction is included.
+	nonce, err := queue.nextNonce(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), nonce)
+
+	require.NoError(t, queue.commitNonce(ctx, 5))
+
+	// The committed nonce is used while the RPC endpoint lags behind.
+	nonce, err = queue.nextNonce(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(6), nonce)
+
+	// The nonce of the latest block is used once it is ahead.
+	nonce, err = queue.nextNonce(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), nonce)
+
+	require.NoError(t, queue.resetNonce(ctx))
+
+	nonce, err = queue.nextNonce(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), nonce)
+}
+
+func TestQueueCollector(t *testing.T) {
+	t.Parallel()
+
+	queue, redisServer := newTestSendQueue(t)
+
+	queueKey := formatCacheKey(QueueCacheKey, queue.wallet)
+	now := time.Now()
+
+	for ticket, priority := range map[string]Priority{
+		"settlement": PrioritySettlement,
+		"tax-1":      PriorityTax,
+		"tax-2":      PriorityTax,
+		"default":    PriorityDefault,
+	} {
+		_, err := redisServer.ZAdd(queueKey, ticketScore(priority, now), ticket)
+		require.NoError(t, err)
+	}
+
+	depths, err := queue.depths(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[Priority]int64{PrioritySettlement: 1, PriorityDemotion: 0, PriorityTax: 2, PriorityDefault: 1}, depths)
+
+	collector := NewQueueCollector(queue)
+
+	metrics := make(chan prometheus.Metric, len(priorities))
+	collector.Collect(metrics)
+	close(metrics)
+
+	collected := make(map[string]float64, len(priorities))
+
+	for metric := range metrics {
+		var value dto.Metric
+
+		require.NoError(t, metric.Write(&value))
+
+		labels := lo.SliceToMap(value.GetLabel(), func(label *dto.LabelPair) (string, string) {
+			return label.GetName(), label.GetValue()
+		})
+
+		assert.Equal(t, queue.wallet, labels["wallet"])
+
+		collected[labels["priority"]] = value.GetGauge().GetValue()
+	}
+
+	assert.Equal(t, map[string]float64{"settlement": 1, "demotion": 0, "tax": 2, "default": 1}, collected)
+}
+
+func queueLength(t *testing.T, queue *SendQueue) int64 {
+	t.Helper()
+
+	length, err := queue.redisClient.ZCard(context.Background(), formatCacheKey(QueueCacheKey, queue.wallet)).Result()
+	require.NoError(t, err)
+
+	return length
+}
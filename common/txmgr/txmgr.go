@@ -18,6 +18,8 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	gicrypto "github.com/rss3-network/global-indexer/common/crypto"
+	"github.com/rss3-network/global-indexer/internal/database"
+	"github.com/rss3-network/global-indexer/schema"
 	"go.uber.org/zap"
 )
 
@@ -32,6 +34,10 @@ var oneHundred = big.NewInt(100)
 
 type TxManager interface {
 	Send(ctx context.Context, candidate TxCandidate) (*types.Receipt, error)
+	// From returns the address of the account sending the transactions.
+	From() common.Address
+	// FindReceipt returns the successful receipt of a journaled transaction with the input, or nil if none is included.
+	FindReceipt(ctx context.Context, input []byte) (*types.Receipt, error)
 }
 
 type SimpleTxManager struct {
@@ -44,6 +50,12 @@ type SimpleTxManager struct {
 	nonceLock      sync.RWMutex
 
 	signer gicrypto.SignerFn
+
+	// databaseClient is the client of the journal of the transactions, the journal is disabled if it is nil.
+	databaseClient database.Client
+	// sendQueue is the send queue shared by the processes sending from the wallet,
+	// the nonce is only kept in memory if it is nil.
+	sendQueue *SendQueue
 }
 
 type TxCandidate struct {
@@ -55,18 +67,68 @@ type TxCandidate struct {
 	GasLimit uint64
 	// Value is the value to be used in the constructed tx.
 	Value *big.Int
+	// Priority is the priority of the tx in the send queue of the wallet.
+	Priority Priority
+	// RequireJournal fails the send if the tx cannot be journaled, and skips the publishing of a submission which cannot be journaled,
+	// so the tx can always be found by FindReceipt after it is published.
+	RequireJournal bool
+	// BeforePublish is called with each signed submission of the tx before it is published,
+	// the submission is not published if it fails.
+	BeforePublish func(ctx context.Context, tx *types.Transaction) error
+}
+
+func (m *SimpleTxManager) From() common.Address {
+	return m.from
+}
+
+// SendQueue returns the send queue shared by the processes sending from the wallet, or nil if there is none.
+func (m *SimpleTxManager) SendQueue() *SendQueue {
+	return m.sendQueue
 }
 
-// Send sends a candidate to the VSL.
+// Send sends a candidate to the VSL, after the candidates sent from the wallet by any process with a higher or the same priority.
 func (m *SimpleTxManager) Send(ctx context.Context, candidate TxCandidate) (*types.Receipt, error) {
+	if m.sendQueue != nil {
+		release, err := m.sendQueue.Acquire(ctx, candidate.Priority)
+		if err != nil {
+			return nil, fmt.Errorf("wait in send queue: %w", err)
+		}
+
+		defer release()
+	}
+
 	receipt, err := m.send(ctx, candidate)
 	if err != nil {
 		m.resetNonce()
 	}
 
+	if m.sendQueue != nil {
+		m.settleSharedNonce(ctx, err)
+	}
+
 	return receipt, err
 }
 
+// settleSharedNonce shares the nonce of a sent transaction with the other processes, or resets it if the transaction was given up.
+func (m *SimpleTxManager) settleSharedNonce(ctx context.Context, sendErr error) {
+	ctx = context.WithoutCancel(ctx)
+
+	if sendErr != nil {
+		if err := m.sendQueue.resetNonce(ctx); err != nil {
+			zap.L().Error("failed to reset shared nonce", zap.Error(err))
+		}
+
+		return
+	}
+
+	m.nonceLock.RLock()
+	defer m.nonceLock.RUnlock()
+
+	if err := m.sendQueue.commitNonce(ctx, *m.nonce); err != nil {
+		zap.L().Error("failed to commit shared nonce", zap.Error(err), zap.Uint64("nonce", *m.nonce))
+	}
+}
+
 func (m *SimpleTxManager) resetNonce() {
 	m.nonceLock.Lock()
 	defer m.nonceLock.Unlock()
@@ -97,11 +159,34 @@ func (m *SimpleTxManager) send(ctx context.Context, candidate TxCandidate) (*typ
 		}
 
 		return tx, nil
-	}, retry.Delay(2*time.Second), retry.Attempts(30)); err != nil {
+	}, retry.Delay(m.cfg.CraftRetryInterval), retry.Attempts(m.cfg.CraftRetryAttempts), retry.RetryIf(func(err error) bool {
+		// The sends refused by the policies are not retried, as they would be refused again.
+		return !refused(err)
+	})); err != nil {
 		return nil, fmt.Errorf("failed to create the tx: %w", err)
 	}
 
-	return m.sendTx(ctx, tx)
+	transaction, err := m.journalTransaction(ctx, tx)
+	if err != nil {
+		if candidate.RequireJournal {
+			return nil, err
+		}
+
+		if !errors.Is(err, ErrJournalDisabled) {
+			zap.L().Error("failed to journal transaction", zap.Error(err))
+		}
+	}
+
+	receipt, err := m.sendTx(ctx, candidate, tx, transaction)
+	if err != nil {
+		m.journalAbort(ctx, transaction, err)
+
+		return nil, err
+	}
+
+	m.journalReceipt(ctx, transaction, receipt)
+
+	return receipt, nil
 }
 
 func (m *SimpleTxManager) craftTx(ctx context.Context, candidate TxCandidate) (*types.Transaction, error) {
@@ -110,7 +195,10 @@ func (m *SimpleTxManager) craftTx(ctx context.Context, candidate TxCandidate) (*
 		return nil, fmt.Errorf("failed to get gas price info: %w", err)
 	}
 
-	gasFeeCap := calcGasFeeCap(basefee, gasTipCap)
+	gasFeeCap, err := capGasFeeCap(calcGasFeeCap(basefee, gasTipCap), gasTipCap, basefee, m.cfg.MaxFeePerGas)
+	if err != nil {
+		return nil, violate(PolicyMaxFeePerGas, err)
+	}
 
 	rawTx := &types.DynamicFeeTx{
 		ChainID:   m.chainID,
@@ -121,30 +209,18 @@ func (m *SimpleTxManager) craftTx(ctx context.Context, candidate TxCandidate) (*
 		Value:     candidate.Value,
 	}
 
-	// If the gas limit is set, we can use that as the gas
-	if candidate.GasLimit != 0 {
-		rawTx.Gas = candidate.GasLimit
-	} else {
-		// Calculate the intrinsic gas for the transaction
-		gas, err := m.ethereumClient.EstimateGas(ctx, ethereum.CallMsg{
-			From:      m.from,
-			To:        candidate.To,
-			GasFeeCap: gasFeeCap,
-			GasTipCap: gasTipCap,
-			Data:      rawTx.Data,
-			Value:     rawTx.Value,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("failed to estimate gas: %w", err)
-		}
+	if rawTx.Gas, err = m.gasLimit(ctx, candidate, gasTipCap, gasFeeCap); err != nil {
+		return nil, err
+	}
 
-		rawTx.Gas = gas
+	if err := m.checkSpend(ctx, rawTx); err != nil {
+		return nil, err
 	}
 
 	return m.signWithNextNonce(ctx, rawTx)
 }
 
-func (m *SimpleTxManager) sendTx(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
+func (m *SimpleTxManager) sendTx(ctx context.Context, candidate TxCandidate, tx *types.Transaction, transaction *schema.VSLTransaction) (*types.Receipt, error) {
 	var wg sync.WaitGroup
 	defer wg.Wait()
 
@@ -157,7 +233,7 @@ func (m *SimpleTxManager) sendTx(ctx context.Context, tx *types.Transaction) (*t
 	publishAndWait := func(tx *types.Transaction, bumpFees bool) *types.Transaction {
 		wg.Add(1)
 
-		tx, published := m.publishTx(ctx, tx, sendState, bumpFees)
+		tx, published := m.publishTx(ctx, candidate, tx, transaction, sendState, bumpFees)
 
 		if published {
 			go func() {
@@ -239,7 +315,8 @@ func (m *SimpleTxManager) signWithNextNonce(ctx context.Context, rawTx *types.Dy
 	m.nonceLock.Lock()
 	defer m.nonceLock.Unlock()
 
-	if m.nonce == nil {
+	// The nonce shared by the processes is allocated for each transaction, as other processes may have sent transactions since
+	if m.nonce == nil || m.sendQueue != nil {
 		// Fetch the sender's nonce from the latest known block (nil `blockNumber`)
 		childCtx, cancel := context.WithTimeout(ctx, m.cfg.NetworkTimeout)
 		defer cancel()
@@ -249,6 +326,12 @@ func (m *SimpleTxManager) signWithNextNonce(ctx context.Context, rawTx *types.Dy
 			return nil, fmt.Errorf("failed to get nonce: %w", err)
 		}
 
+		if m.sendQueue != nil {
+			if nonce, err = m.sendQueue.nextNonce(childCtx, nonce); err != nil {
+				return nil, err
+			}
+		}
+
 		m.nonce = &nonce
 	} else {
 		*m.nonce++
@@ -270,7 +353,7 @@ func (m *SimpleTxManager) signWithNextNonce(ctx context.Context, rawTx *types.Dy
 	return tx, err
 }
 
-func (m *SimpleTxManager) publishTx(ctx context.Context, tx *types.Transaction, sendState *SendState, bumpFeesImmediately bool) (*types.Transaction, bool) {
+func (m *SimpleTxManager) publishTx(ctx context.Context, candidate TxCandidate, tx *types.Transaction, transaction *schema.VSLTransaction, sendState *SendState, bumpFeesImmediately bool) (*types.Transaction, bool) {
 	var resetCurrentNonce bool
 
 	for {
@@ -301,6 +384,13 @@ func (m *SimpleTxManager) publishTx(ctx context.Context, tx *types.Transaction,
 			return tx, false
 		}
 
+		if err := m.prepareSubmission(ctx, candidate, transaction, tx); err != nil {
+			zap.L().Error("unable to prepare transaction submission", zap.Error(err), zap.String("hash", tx.Hash().String()))
+
+			// will retry on next resubmission timeout
+			return tx, false
+		}
+
 		cCtx, cancel := context.WithTimeout(ctx, m.cfg.NetworkTimeout)
 		err := m.ethereumClient.SendTransaction(cCtx, tx)
 
@@ -340,6 +430,25 @@ func (m *SimpleTxManager) publishTx(ctx context.Context, tx *types.Transaction,
 	}
 }
 
+// prepareSubmission journals a signed transaction and calls the hook of the candidate before the transaction is published.
+func (m *SimpleTxManager) prepareSubmission(ctx context.Context, candidate TxCandidate, transaction *schema.VSLTransaction, tx *types.Transaction) error {
+	if err := m.journalSubmission(ctx, transaction, tx); err != nil {
+		if candidate.RequireJournal {
+			return err
+		}
+
+		zap.L().Error("failed to journal transaction submission", zap.Error(err), zap.Stringer("hash", tx.Hash()))
+	}
+
+	if candidate.BeforePublish != nil {
+		if err := candidate.BeforePublish(ctx, tx); err != nil {
+			return fmt.Errorf("before publish: %w", err)
+		}
+	}
+
+	return nil
+}
+
 func errStringMatch(err, target error) bool {
 	if err == nil && target == nil {
 		return true
@@ -494,6 +603,11 @@ func (m *SimpleTxManager) increaseGasPrice(ctx context.Context, tx *types.Transa
 		return nil, fmt.Errorf("bumped fee 0x%s is over %dx multiple of the suggested value", bumpedFee.Text(16), m.cfg.FeeLimitMultiplier)
 	}
 
+	// Keep waiting for the published transaction if the bumped fee is over the ceiling
+	if m.cfg.MaxFeePerGas != nil && bumpedFee.Cmp(m.cfg.MaxFeePerGas) > 0 {
+		return nil, violate(PolicyMaxFeePerGas, fmt.Errorf("%w: bumped fee 0x%s is over the ceiling", ErrFeeCeilingExceeded, bumpedFee.Text(16)))
+	}
+
 	rawTx := &types.DynamicFeeTx{
 		ChainID:    tx.ChainId(),
 		Nonce:      tx.Nonce(),
@@ -578,11 +692,17 @@ func EncodeInput(contractABI, methodName string, args ...interface{}) ([]byte, e
 	return encodedArgs, nil
 }
 
-func NewSimpleTxManager(conf Config, chainID *big.Int, nonce *uint64, ethereumClient *ethclient.Client, from common.Address, singer gicrypto.SignerFn) (*SimpleTxManager, error) {
+// NewSimpleTxManager creates a transaction manager, the transactions are journaled if the database client is not nil,
+// and are queued with the other processes sending from the wallet if the send queue is not nil.
+func NewSimpleTxManager(conf Config, chainID *big.Int, nonce *uint64, ethereumClient *ethclient.Client, from common.Address, singer gicrypto.SignerFn, databaseClient database.Client, sendQueue *SendQueue) (*SimpleTxManager, error) {
 	if err := conf.Check(); err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 
+	if databaseClient == nil && (conf.EpochBudget != nil || conf.DailyBudget != nil) {
+		return nil, fmt.Errorf("the spend budgets require the journal of the transactions")
+	}
+
 	return &SimpleTxManager{
 		cfg: conf,
 
@@ -592,5 +712,8 @@ func NewSimpleTxManager(conf Config, chainID *big.Int, nonce *uint64, ethereumCl
 		nonce:          nonce,
 
 		signer: singer,
+
+		databaseClient: databaseClient,
+		sendQueue:      sendQueue,
 	}, nil
 }
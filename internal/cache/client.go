@@ -10,6 +10,8 @@ import (
 
 type Client interface {
 	Get(ctx context.Context, key string, dest interface{}) error
+	// GetDel gets the value of the key and deletes the key atomically, so the value is only got once.
+	GetDel(ctx context.Context, key string, dest interface{}) error
 	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
 	IncrBy(ctx context.Context, key string, value int64) error
 	PSubscribe(ctx context.Context, pattern string) *redis.PubSub
@@ -35,6 +37,15 @@ func (c *client) Get(ctx context.Context, key string, dest interface{}) error {
 	return json.Unmarshal(data, dest)
 }
 
+func (c *client) GetDel(ctx context.Context, key string, dest interface{}) error {
+	data, err := c.redisClient.GetDel(ctx, key).Bytes()
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, dest)
+}
+
 func (c *client) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
 	data, err := json.Marshal(value)
 	if err != nil {
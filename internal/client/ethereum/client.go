@@ -3,10 +3,14 @@ package ethereum
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/sourcegraph/conc/pool"
+	"go.uber.org/zap"
 )
 
 type MultiChainClient struct {
@@ -34,28 +38,43 @@ func (m *MultiChainClient) Get(chainID uint64) (*ethclient.Client, error) {
 	return ethereumClient, nil
 }
 
-func Dial(ctx context.Context, endpoints []string) (*MultiChainClient, error) {
+// Dial dials the chains, each with a list of endpoints the RPC requests fail over between.
+// The critical reads marked by WithQuorum require `quorum` endpoints of a chain to agree on the result,
+// and the health of the endpoints is checked at each healthCheckInterval until the context is done.
+func Dial(ctx context.Context, chainEndpoints [][]string, quorum int, healthCheckInterval time.Duration) (*MultiChainClient, error) {
 	client := MultiChainClient{
 		chainMap: make(map[uint64]*ethclient.Client),
 	}
 
+	// The health checks outlive the dialing, they stop with the context passed in.
+	healthCheckContext := ctx
+
 	contextPool := pool.New().WithContext(ctx).WithFirstError().WithCancelOnError()
 
-	for _, endpoint := range endpoints {
-		endpoint := endpoint
+	for _, endpoints := range chainEndpoints {
+		endpoints := endpoints
 
 		contextPool.Go(func(ctx context.Context) error {
-			ethereumClient, err := ethclient.DialContext(ctx, endpoint)
+			transport, err := newTransport(endpoints, max(quorum, 1))
 			if err != nil {
-				return fmt.Errorf("dial to endpoint: %w", err)
+				return fmt.Errorf("new transport: %w", err)
+			}
+
+			chainID, err := verifyChainID(ctx, endpoints)
+			if err != nil {
+				return err
 			}
 
-			chainID, err := ethereumClient.ChainID(ctx)
+			rpcClient, err := rpc.DialOptions(ctx, endpoints[0], rpc.WithHTTPClient(&http.Client{Transport: transport}))
 			if err != nil {
-				return fmt.Errorf("get chain id: %w", err)
+				return fmt.Errorf("dial to endpoint: %w", err)
+			}
+
+			if healthCheckInterval > 0 {
+				go transport.checkHealth(healthCheckContext, healthCheckInterval)
 			}
 
-			client.Put(chainID.Uint64(), ethereumClient)
+			client.Put(chainID, ethclient.NewClient(rpcClient))
 
 			return nil
 		})
@@ -67,3 +86,38 @@ func Dial(ctx context.Context, endpoints []string) (*MultiChainClient, error) {
 
 	return &client, nil
 }
+
+// verifyChainID returns the chain id of the endpoints, which all reachable endpoints must agree on.
+// The unreachable endpoints are left to the failover, as long as one of the endpoints is reachable.
+func verifyChainID(ctx context.Context, endpoints []string) (uint64, error) {
+	var chainID uint64
+
+	for _, endpoint := range endpoints {
+		ethereumClient, err := ethclient.DialContext(ctx, endpoint)
+		if err != nil {
+			return 0, fmt.Errorf("dial to endpoint: %w", err)
+		}
+
+		endpointChainID, err := ethereumClient.ChainID(ctx)
+
+		ethereumClient.Close()
+
+		if err != nil {
+			zap.L().Warn("get chain id of endpoint", zap.Error(err))
+
+			continue
+		}
+
+		if chainID != 0 && chainID != endpointChainID.Uint64() {
+			return 0, fmt.Errorf("endpoints of different chains %d and %d", chainID, endpointChainID.Uint64())
+		}
+
+		chainID = endpointChainID.Uint64()
+	}
+
+	if chainID == 0 {
+		return 0, fmt.Errorf("get chain id: no endpoint is reachable")
+	}
+
+	return chainID, nil
+}
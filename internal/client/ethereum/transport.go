@@ -0,0 +1,332 @@
+package ethereum
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrQuorumNotReached is returned by the critical reads which not enough endpoints agree on.
+var ErrQuorumNotReached = errors.New("quorum not reached")
+
+type quorumContextKey struct{}
+
+// WithQuorum marks the RPC requests made with the context as critical reads,
+// which are sent to several endpoints and only succeed if enough of them agree on the result.
+func WithQuorum(ctx context.Context) context.Context {
+	return context.WithValue(ctx, quorumContextKey{}, true)
+}
+
+func isQuorumRequested(ctx context.Context) bool {
+	requested, _ := ctx.Value(quorumContextKey{}).(bool)
+
+	return requested
+}
+
+// endpoint is an RPC endpoint of a chain, along with its health.
+type endpoint struct {
+	url     *url.URL
+	healthy atomic.Bool
+	// latency is the moving average of the response time in nanoseconds.
+	latency atomic.Int64
+}
+
+// observe records the outcome of a request sent to the endpoint.
+func (e *endpoint) observe(latency time.Duration, err error) {
+	if err != nil {
+		if e.healthy.Swap(false) {
+			zap.L().Warn("rpc endpoint is unhealthy", zap.String("endpoint", e.url.Redacted()), zap.Error(err))
+		}
+
+		return
+	}
+
+	if !e.healthy.Swap(true) {
+		zap.L().Info("rpc endpoint is healthy", zap.String("endpoint", e.url.Redacted()))
+	}
+
+	if previous := e.latency.Load(); previous > 0 {
+		latency = (time.Duration(previous)*4 + latency) / 5
+	}
+
+	e.latency.Store(int64(latency))
+}
+
+// transport is the HTTP transport of the RPC client of a chain, which routes each request to the endpoints of the chain.
+// A request is sent to the healthy endpoint with the lowest latency, and fails over to the next endpoints if it fails.
+// A request marked by WithQuorum is sent to all endpoints, and only succeeds if `quorum` of them agree on the result.
+type transport struct {
+	endpoints []*endpoint
+	quorum    int
+	base      http.RoundTripper
+}
+
+func newTransport(endpointURLs []string, quorum int) (*transport, error) {
+	if len(endpointURLs) == 0 {
+		return nil, errors.New("no endpoint")
+	}
+
+	if quorum > len(endpointURLs) {
+		return nil, fmt.Errorf("quorum %d is greater than the number of endpoints %d", quorum, len(endpointURLs))
+	}
+
+	t := transport{
+		endpoints: make([]*endpoint, 0, len(endpointURLs)),
+		quorum:    quorum,
+		base:      http.DefaultTransport,
+	}
+
+	for _, endpointURL := range endpointURLs {
+		parsedURL, err := url.Parse(endpointURL)
+		if err != nil {
+			return nil, fmt.Errorf("parse endpoint: %w", err)
+		}
+
+		e := endpoint{url: parsedURL}
+		e.healthy.Store(true)
+
+		t.endpoints = append(t.endpoints, &e)
+	}
+
+	return &t, nil
+}
+
+func (t *transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(request.Body)
+	_ = request.Body.Close()
+
+	if err != nil {
+		return nil, fmt.Errorf("read request body: %w", err)
+	}
+
+	if t.quorum > 1 && isQuorumRequested(request.Context()) {
+		return t.roundTripQuorum(request, body)
+	}
+
+	var errs error
+
+	for _, e := range t.ranked() {
+		response, err := t.send(request, body, e)
+		if err == nil {
+			return response, nil
+		}
+
+		// The request has been canceled by the caller, and is not to be sent to the other endpoints.
+		if request.Context().Err() != nil {
+			return nil, err
+		}
+
+		errs = errors.Join(errs, err)
+	}
+
+	return nil, fmt.Errorf("all endpoints failed: %w", errs)
+}
+
+// roundTripQuorum sends the request to all endpoints in parallel, and returns the response agreed on by `quorum` endpoints.
+// It returns as soon as a result reaches the quorum, and the requests still in flight are canceled.
+func (t *transport) roundTripQuorum(request *http.Request, body []byte) (*http.Response, error) {
+	type result struct {
+		response *http.Response
+		body     []byte
+		key      string
+		err      error
+	}
+
+	ctx, cancel := context.WithCancel(request.Context())
+	defer cancel()
+
+	request = request.WithContext(ctx)
+
+	// The channel is buffered so that the requests finishing after the quorum is reached do not block.
+	results := make(chan result, len(t.endpoints))
+
+	for _, e := range t.endpoints {
+		go func(e *endpoint) {
+			response, err := t.send(request, body, e)
+			if err != nil {
+				results <- result{err: err}
+
+				return
+			}
+
+			defer response.Body.Close()
+
+			responseBody, err := io.ReadAll(response.Body)
+			if err != nil {
+				results <- result{err: fmt.Errorf("read response body: %w", err)}
+
+				return
+			}
+
+			results <- result{response: response, body: responseBody, key: quorumKey(responseBody)}
+		}(e)
+	}
+
+	var (
+		votes = make(map[string]int)
+		errs  error
+	)
+
+	for range t.endpoints {
+		r := <-results
+		if r.err != nil {
+			errs = errors.Join(errs, r.err)
+
+			continue
+		}
+
+		if votes[r.key]++; votes[r.key] < t.quorum {
+			continue
+		}
+
+		response := *r.response
+		response.Body = io.NopCloser(bytes.NewReader(r.body))
+		response.ContentLength = int64(len(r.body))
+
+		return &response, nil
+	}
+
+	return nil, fmt.Errorf("%d endpoints required to agree on the result: %w", t.quorum, errors.Join(ErrQuorumNotReached, errs))
+}
+
+// send sends the request to an endpoint, the responses with a server error are considered as failures of the endpoint.
+func (t *transport) send(request *http.Request, body []byte, e *endpoint) (*http.Response, error) {
+	endpointRequest := request.Clone(request.Context())
+	endpointRequest.URL = e.url
+	endpointRequest.Host = e.url.Host
+	endpointRequest.Body = io.NopCloser(bytes.NewReader(body))
+	endpointRequest.ContentLength = int64(len(body))
+
+	startedAt := time.Now()
+
+	response, err := t.base.RoundTrip(endpointRequest)
+	if err == nil && (response.StatusCode >= http.StatusInternalServerError || response.StatusCode == http.StatusTooManyRequests) {
+		_ = response.Body.Close()
+
+		err = fmt.Errorf("unexpected status %s", response.Status)
+	}
+
+	// The failures caused by the caller are not held against the endpoint.
+	if err != nil && request.Context().Err() != nil {
+		return nil, err
+	}
+
+	e.observe(time.Since(startedAt), err)
+
+	if err != nil {
+		return nil, fmt.Errorf("endpoint %s: %w", e.url.Redacted(), err)
+	}
+
+	return response, nil
+}
+
+// ranked returns the healthy endpoints by ascending latency, followed by the unhealthy ones as a last resort.
+func (t *transport) ranked() []*endpoint {
+	endpoints := make([]*endpoint, len(t.endpoints))
+	copy(endpoints, t.endpoints)
+
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		if healthy := endpoints[i].healthy.Load(); healthy != endpoints[j].healthy.Load() {
+			return healthy
+		}
+
+		return endpoints[i].latency.Load() < endpoints[j].latency.Load()
+	})
+
+	return endpoints
+}
+
+// checkHealth probes every endpoint with eth_blockNumber at each interval, until the context is done.
+func (t *transport) checkHealth(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for _, e := range t.endpoints {
+			t.probe(ctx, e)
+		}
+	}
+}
+
+// probe sends an eth_blockNumber request to an endpoint, and records its outcome.
+func (t *transport) probe(ctx context.Context, e *endpoint) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	body := []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url.String(), bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := t.send(request, body, e)
+	if err != nil {
+		return
+	}
+
+	_, _ = io.Copy(io.Discard, response.Body)
+	_ = response.Body.Close()
+}
+
+// quorumKey returns the key which the responses agreeing on the result share.
+// Objects with a hash, such as blocks and transactions, are compared by their hashes,
+// as providers may return different sets of fields for them.
+func quorumKey(body []byte) string {
+	var message struct {
+		Result json.RawMessage `json:"result"`
+		Error  json.RawMessage `json:"error"`
+	}
+
+	if err := json.Unmarshal(body, &message); err != nil {
+		return string(body)
+	}
+
+	if len(message.Error) > 0 {
+		return "error:" + canonicalJSON(message.Error)
+	}
+
+	var object struct {
+		Hash string `json:"hash"`
+	}
+
+	if err := json.Unmarshal(message.Result, &object); err == nil && object.Hash != "" {
+		return "hash:" + object.Hash
+	}
+
+	return "result:" + canonicalJSON(message.Result)
+}
+
+// canonicalJSON returns the JSON value with sorted object keys and no spaces.
+func canonicalJSON(value json.RawMessage) string {
+	var decoded any
+
+	if err := json.Unmarshal(value, &decoded); err != nil {
+		return string(value)
+	}
+
+	encoded, err := json.Marshal(decoded)
+	if err != nil {
+		return string(value)
+	}
+
+	return string(encoded)
+}
@@ -0,0 +1,225 @@
+package ethereum
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newEndpoint starts an endpoint which responds to every request with the status and the result.
+func newEndpoint(t *testing.T, status int, result string) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+
+	var requests atomic.Int64
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(status)
+		_, _ = writer.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":` + result + `}`))
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+// dialTransport dials an ethereum client over a transport of the endpoints.
+func dialTransport(t *testing.T, quorum int, servers ...*httptest.Server) (*ethclient.Client, *transport) {
+	t.Helper()
+
+	endpoints := make([]string, 0, len(servers))
+	for _, server := range servers {
+		endpoints = append(endpoints, server.URL)
+	}
+
+	transport, err := newTransport(endpoints, quorum)
+	require.NoError(t, err)
+
+	rpcClient, err := rpc.DialOptions(context.Background(), endpoints[0], rpc.WithHTTPClient(&http.Client{Transport: transport}))
+	require.NoError(t, err)
+
+	return ethclient.NewClient(rpcClient), transport
+}
+
+func TestTransportFailover(t *testing.T) {
+	t.Parallel()
+
+	unavailable, unavailableRequests := newEndpoint(t, http.StatusServiceUnavailable, `"0x0"`)
+	available, availableRequests := newEndpoint(t, http.StatusOK, `"0x10"`)
+
+	client, transport := dialTransport(t, 1, unavailable, available)
+
+	blockNumber, err := client.BlockNumber(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(16), blockNumber)
+
+	// The unavailable endpoint is ranked last, and is not requested again while the other endpoint is healthy.
+	assert.False(t, transport.endpoints[0].healthy.Load())
+	assert.Equal(t, transport.endpoints[1], transport.ranked()[0])
+
+	_, err = client.BlockNumber(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(1), unavailableRequests.Load())
+	assert.Equal(t, int64(2), availableRequests.Load())
+
+	// The endpoint is healthy again once it responds to a probe.
+	transport.probe(context.Background(), transport.endpoints[1])
+	assert.True(t, transport.endpoints[1].healthy.Load())
+}
+
+func TestTransportAllEndpointsFailed(t *testing.T) {
+	t.Parallel()
+
+	unavailable0, _ := newEndpoint(t, http.StatusServiceUnavailable, `"0x0"`)
+	unavailable1, _ := newEndpoint(t, http.StatusTooManyRequests, `"0x0"`)
+
+	client, _ := dialTransport(t, 1, unavailable0, unavailable1)
+
+	_, err := client.BlockNumber(context.Background())
+	require.Error(t, err)
+}
+
+func TestTransportQuorum(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		results []string
+		quorum  int
+		wantErr bool
+	}{
+		{
+			name:    "Agreed",
+			results: []string{`"0x10"`, `"0x11"`, `"0x10"`},
+			quorum:  2,
+		},
+		{
+			name:    "Disagreed",
+			results: []string{`"0x10"`, `"0x11"`, `"0x12"`},
+			quorum:  2,
+			wantErr: true,
+		},
+		{
+			name:    "QuorumNotRequested",
+			results: []string{`"0x10"`},
+			quorum:  1,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			servers := make([]*httptest.Server, 0, len(tc.results))
+
+			for _, result := range tc.results {
+				server, _ := newEndpoint(t, http.StatusOK, result)
+				servers = append(servers, server)
+			}
+
+			client, _ := dialTransport(t, tc.quorum, servers...)
+
+			blockNumber, err := client.BlockNumber(WithQuorum(context.Background()))
+			if tc.wantErr {
+				require.ErrorIs(t, err, ErrQuorumNotReached)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, uint64(16), blockNumber)
+		})
+	}
+}
+
+func TestTransportQuorumCancelsRemainingRequests(t *testing.T) {
+	t.Parallel()
+
+	canceled := make(chan struct{})
+
+	// The slow endpoint only responds once its request is canceled.
+	slow := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, request *http.Request) {
+		// The server only notices the canceled request once the body has been read.
+		_, _ = io.Copy(io.Discard, request.Body)
+
+		<-request.Context().Done()
+		close(canceled)
+	}))
+	t.Cleanup(slow.Close)
+
+	first, _ := newEndpoint(t, http.StatusOK, `"0x10"`)
+	second, _ := newEndpoint(t, http.StatusOK, `"0x10"`)
+
+	client, _ := dialTransport(t, 2, first, slow, second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	blockNumber, err := client.BlockNumber(WithQuorum(ctx))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(16), blockNumber)
+
+	select {
+	case <-canceled:
+	case <-ctx.Done():
+		t.Fatal("the request to the slow endpoint is not canceled once the quorum is reached")
+	}
+}
+
+func TestQuorumKey(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name  string
+		a     string
+		b     string
+		equal bool
+	}{
+		{
+			name:  "SameHash",
+			a:     `{"jsonrpc":"2.0","id":1,"result":{"hash":"0x01","number":"0x10","totalDifficulty":"0x0"}}`,
+			b:     `{"jsonrpc":"2.0","id":1,"result":{"number":"0x10","hash":"0x01"}}`,
+			equal: true,
+		},
+		{
+			name: "DifferentHash",
+			a:    `{"jsonrpc":"2.0","id":1,"result":{"hash":"0x01"}}`,
+			b:    `{"jsonrpc":"2.0","id":1,"result":{"hash":"0x02"}}`,
+		},
+		{
+			name:  "SameResultInDifferentOrder",
+			a:     `{"jsonrpc":"2.0","id":1,"result":{"a":1,"b":2}}`,
+			b:     `{"id":1,"result":{"b":2, "a":1},"jsonrpc":"2.0"}`,
+			equal: true,
+		},
+		{
+			name: "ResultAndError",
+			a:    `{"jsonrpc":"2.0","id":1,"result":"0x"}`,
+			b:    `{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"execution reverted"}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.equal, quorumKey([]byte(tc.a)) == quorumKey([]byte(tc.b)))
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"time"
 	"unsafe"
 
 	"github.com/creasty/defaults"
@@ -21,18 +22,24 @@ const (
 )
 
 type File struct {
-	Environment   string         `yaml:"environment" validate:"required" default:"development"`
-	Database      *Database      `yaml:"database"`
-	Redis         *Redis         `yaml:"redis"`
-	RSS3Chain     *RSS3Chain     `yaml:"rss3_chain"`
-	Settler       *Settler       `yaml:"settler"`
-	Distributor   *Distributor   `yaml:"distributor"`
-	Rewards       *Rewards       `yaml:"rewards"`
-	ActiveScores  *ActiveScores  `yaml:"active_scores"`
-	GeoIP         *GeoIP         `yaml:"geo_ip"`
-	RPC           *RPC           `yaml:"rpc"`
-	Telemetry     *Telemetry     `json:"telemetry"`
-	TokenPriceAPI *TokenPriceAPI `yaml:"token_price_api"`
+	Environment     string           `yaml:"environment" validate:"required" default:"development"`
+	Database        *Database        `yaml:"database"`
+	Redis           *Redis           `yaml:"redis"`
+	RSS3Chain       *RSS3Chain       `yaml:"rss3_chain"`
+	Settler         *Settler         `yaml:"settler"`
+	Distributor     *Distributor     `yaml:"distributor"`
+	Rewards         *Rewards         `yaml:"rewards"`
+	ActiveScores    *ActiveScores    `yaml:"active_scores"`
+	GeoIP           *GeoIP           `yaml:"geo_ip"`
+	RPC             *RPC             `yaml:"rpc"`
+	Telemetry       *Telemetry       `json:"telemetry"`
+	TokenPriceAPI   *TokenPriceAPI   `yaml:"token_price_api"`
+	Admin           *Admin           `yaml:"admin"`
+	NodeChallenge   *NodeChallenge   `yaml:"node_challenge" default:"{}"`
+	NodeEndpoint    *NodeEndpoint    `yaml:"node_endpoint" default:"{}"`
+	NodePreferences *NodePreferences `yaml:"node_preferences" default:"{}"`
+	NameResolver    *NameResolver    `yaml:"name_resolver" default:"{}"`
+	CronJob         *CronJob         `yaml:"cron_job" default:"{}"`
 }
 
 type Database struct {
@@ -45,16 +52,38 @@ type Redis struct {
 }
 
 type RSS3Chain struct {
-	EndpointL1     string `yaml:"endpoint_l1" validate:"required"`
-	EndpointL2     string `yaml:"endpoint_l2" validate:"required"`
+	EndpointL1 string `yaml:"endpoint_l1" validate:"required"`
+	EndpointL2 string `yaml:"endpoint_l2" validate:"required"`
+	// FallbackEndpointsL1 and FallbackEndpointsL2 are the extra endpoints of the chains, which the RPC requests fail over to.
+	FallbackEndpointsL1 []string `yaml:"fallback_endpoints_l1"`
+	FallbackEndpointsL2 []string `yaml:"fallback_endpoints_l2"`
+	// HealthCheckInterval is the interval of checking the health and the latency of the endpoints.
+	HealthCheckInterval time.Duration `yaml:"health_check_interval" default:"30s"`
+	// Quorum is the number of endpoints of a chain required to agree on the result of a critical read,
+	// such as the finalized block and the Nodes of the staking contract.
+	Quorum         int    `yaml:"quorum" default:"1" validate:"gte=1"`
 	BlockThreadsL1 uint64 `yaml:"block_threads_l1" default:"1"`
 	BlockThreadsL2 uint64 `yaml:"block_threads_l2" default:"1"`
+	// IndexMode is block to fetch every block and receipt, or log to only query the logs of the indexed contracts.
+	IndexMode string `yaml:"index_mode" default:"block" validate:"oneof=block log"`
+	// LogBlockRangeL1 and LogBlockRangeL2 are the largest block ranges the logs are queried over in log mode.
+	LogBlockRangeL1 uint64 `yaml:"log_block_range_l1" default:"1000" validate:"gt=0"`
+	LogBlockRangeL2 uint64 `yaml:"log_block_range_l2" default:"1000" validate:"gt=0"`
 }
 
+const (
+	IndexModeBlock = "block"
+	IndexModeLog   = "log"
+)
+
 type Settler struct {
 	PrivateKey     string `yaml:"private_key"`
 	WalletAddress  string `yaml:"wallet_address"`
 	SignerEndpoint string `yaml:"signer_endpoint"`
+	// Keystore is an encrypted keystore file of the wallet, used instead of the PrivateKey.
+	Keystore *SignerKeystore `yaml:"keystore"`
+	// Plugin is a signer backend registered by a plugin, such as a PKCS#11 token or a HSM, used instead of the PrivateKey.
+	Plugin *SignerPlugin `yaml:"plugin"`
 	// EpochIntervalInHours
 	EpochIntervalInHours int    `yaml:"epoch_interval_in_hours" default:"18"`
 	GasLimit             uint64 `yaml:"gas_limit" default:"2500000"`
@@ -63,6 +92,51 @@ type Settler struct {
 	BatchSize            int `yaml:"batch_size" default:"200"`
 	ProductionStartEpoch int `yaml:"production_start_epoch" default:"227"`
 	GracePeriodEpochs    int `yaml:"grace_period_epochs" default:"28"`
+	// Failure configures the handling of the transactions to the Settlement contract included with a failed receipt.
+	Failure *SettlementFailure `yaml:"failure" default:"{}"`
+	// Policy limits the gas and the fees of the transactions sent from the wallet.
+	Policy *SendPolicy `yaml:"policy" default:"{}"`
+}
+
+// SignerKeystore is an encrypted keystore file of geth, the passphrase is read from the PassphraseFile,
+// or from the PassphraseEnv environment variable if the file is not specified.
+type SignerKeystore struct {
+	Path           string `yaml:"path" validate:"required"`
+	PassphraseFile string `yaml:"passphrase_file" validate:"required_without=PassphraseEnv"`
+	PassphraseEnv  string `yaml:"passphrase_env"`
+}
+
+// SignerPlugin is a signer backend registered by name, the options are passed to the backend as is.
+type SignerPlugin struct {
+	Name    string            `yaml:"name" validate:"required"`
+	Options map[string]string `yaml:"options"`
+}
+
+// SendPolicy limits the transactions sent from the settler wallet, the sends violating it are refused or deferred.
+// A zero value disables a limit.
+type SendPolicy struct {
+	// MaxFeePerGas is the ceiling in gwei of the fee cap of a transaction, including its resubmissions.
+	// The sends are deferred while the base fee is above it.
+	MaxFeePerGas float64 `yaml:"max_fee_per_gas" validate:"gte=0"`
+	// GasLimitMargin is the margin added to the estimated gas of a transaction, the gas_limit is then the ceiling of the gas limit.
+	// The gas limit is always the gas_limit if it is zero, as it was before the margin.
+	GasLimitMargin float64 `yaml:"gas_limit_margin" validate:"gte=0"`
+	// DeferInterval is the base delay, backing off at each attempt, and DeferAttempts the number of attempts of a deferred send before it is given up.
+	DeferInterval time.Duration `yaml:"defer_interval" default:"2s" validate:"gt=0"`
+	DeferAttempts uint          `yaml:"defer_attempts" default:"30" validate:"gt=0"`
+	// EpochBudget is the maximum in RSS3 spent on the fees within an Epoch interval.
+	EpochBudget float64 `yaml:"epoch_budget" validate:"gte=0"`
+	// DailyBudget is the maximum in RSS3 spent on the fees within a day.
+	DailyBudget float64 `yaml:"daily_budget" validate:"gte=0"`
+	// MinBalance is the balance in RSS3 left in the wallet after the highest fee a transaction may pay.
+	MinBalance float64 `yaml:"min_balance" validate:"gte=0"`
+}
+
+type SettlementFailure struct {
+	// AlertWebhook is the URL notified with a POST request when a transaction fails, no alert is sent if it is not set.
+	AlertWebhook string `yaml:"alert_webhook" validate:"omitempty,url"`
+	// PollInterval is how often a blocked service checks whether an operator has resolved the failure.
+	PollInterval time.Duration `yaml:"poll_interval" default:"30s" validate:"gt=0"`
 }
 
 type Distributor struct {
@@ -72,7 +146,96 @@ type Distributor struct {
 	QualifiedNodeCount int `yaml:"qualified_node_count" default:"3"`
 	// The number of verification activities selected during the second verification.
 	VerificationCount int `yaml:"verification_count" default:"3"`
-	ToleranceSeconds  int `yaml:"tolerance_seconds" default:"1200"`
+	// ToleranceSeconds is the default timestamp tolerance of the verification policies.
+	ToleranceSeconds int `yaml:"tolerance_seconds" default:"1200"`
+	// AccountsPerShard is the number of accounts in a single request of the batch account activities,
+	// larger batches are sharded into several requests, each sent to the qualified nodes. A zero value disables the sharding.
+	AccountsPerShard int `yaml:"accounts_per_shard" default:"5" validate:"gte=0"`
+	// Hedge enables the hedged distribution of requests, the requests are fanned out to all qualified nodes if it is not set.
+	Hedge *Hedge `yaml:"hedge"`
+	// ResponseCache enables the caching of DSL responses, the responses are never cached if it is not set.
+	ResponseCache *ResponseCache `yaml:"response_cache"`
+	// NodeSelection is the node selection strategy per node cache key (nodes:full, nodes:rss, nodes:ai),
+	// the top-N nodes by reliability score are selected for the keys not set.
+	NodeSelection map[string]*NodeSelection `yaml:"node_selection" validate:"dive"`
+	// CircuitBreaker enables the per-node circuit breaker shared by the hub replicas, the nodes are never skipped if it is not set.
+	CircuitBreaker *CircuitBreaker `yaml:"circuit_breaker"`
+	// Verification holds the policies used to verify the responses of the nodes, the built-in policies are used if it is not set.
+	Verification *Verification `yaml:"verification"`
+}
+
+// Verification holds the verification policies of the DSL responses.
+// The policy of a request type overrides the default policy,
+// and is overridden in turn by the policies of the network and the platform of each activity.
+type Verification struct {
+	Default *VerificationPolicy `yaml:"default"`
+	// Requests are keyed by request type (ai, decentralized_activity, decentralized_activities, federated_activity, federated_activities).
+	Requests  map[string]*VerificationPolicy `yaml:"requests" validate:"dive"`
+	Networks  map[string]*VerificationPolicy `yaml:"networks" validate:"dive"`
+	Platforms map[string]*VerificationPolicy `yaml:"platforms" validate:"dive"`
+}
+
+// VerificationPolicy is a verification policy, the fields not set are inherited from the policy it overrides.
+type VerificationPolicy struct {
+	// Mode is identity to compare the responses with each other, majority to reward the majority of identical responses,
+	// or data to only check that the responses carry data.
+	Mode string `yaml:"mode" validate:"omitempty,oneof=identity majority data"`
+	// Fields are the activity fields that must match, ActionFields are the fields of each action that must match if actions is one of the Fields.
+	Fields       []string `yaml:"fields"`
+	ActionFields []string `yaml:"action_fields"`
+	// Tolerance is how old an activity must be to be compared, as newer activities may not be indexed by all nodes yet.
+	Tolerance *time.Duration `yaml:"tolerance"`
+	// Quorum is the number of matching responses required to reward the responses.
+	Quorum int `yaml:"quorum" validate:"gte=0"`
+	// Mutable is skip to leave the activities out of the comparison, as their content can change after they are indexed, or compare.
+	Mutable string `yaml:"mutable" validate:"omitempty,oneof=skip compare"`
+}
+
+type NodeSelection struct {
+	Strategy string `yaml:"strategy" default:"top_n" validate:"oneof=top_n weighted_random epsilon_greedy geo_proximity"`
+	// Epsilon is the probability of exploring a random node for the epsilon_greedy strategy.
+	Epsilon float64 `yaml:"epsilon" default:"0.1" validate:"gte=0,lte=1"`
+	// Latitude and Longitude are the location of the hub, required by the geo_proximity strategy.
+	Latitude  *float64 `yaml:"latitude" validate:"required_if=Strategy geo_proximity,omitempty,gte=-90,lte=90"`
+	Longitude *float64 `yaml:"longitude" validate:"required_if=Strategy geo_proximity,omitempty,gte=-180,lte=180"`
+	// Candidates is the number of the nodes with the highest reliability scores the strategies select from.
+	Candidates int `yaml:"candidates" default:"100" validate:"gte=0"`
+}
+
+type Hedge struct {
+	Enabled bool `yaml:"enabled"`
+	// VerificationRate is the fraction of requests still fanned out to all qualified nodes for verification.
+	VerificationRate float64 `yaml:"verification_rate" default:"0.1" validate:"gte=0,lte=1"`
+	// DefaultBudget is the latency budget of a node that has not built up enough history.
+	DefaultBudget time.Duration `yaml:"default_budget" default:"2s"`
+	MinBudget     time.Duration `yaml:"min_budget" default:"200ms"`
+	MaxBudget     time.Duration `yaml:"max_budget" default:"10s"`
+	// WindowSize is the number of recent latencies kept per node to compute the p95 budget.
+	WindowSize int `yaml:"window_size" default:"100" validate:"gt=0"`
+}
+
+type CircuitBreaker struct {
+	Enabled bool `yaml:"enabled"`
+	// ConsecutiveFailures is the number of consecutive failed requests that opens the breaker of a node.
+	ConsecutiveFailures int64 `yaml:"consecutive_failures" default:"5" validate:"gt=0"`
+	// ErrorRate is the failure rate within a window that opens the breaker, once MinRequests are reached.
+	ErrorRate   float64       `yaml:"error_rate" default:"0.5" validate:"gt=0,lte=1"`
+	MinRequests int64         `yaml:"min_requests" default:"20" validate:"gt=0"`
+	Window      time.Duration `yaml:"window" default:"1m" validate:"gt=0"`
+	// OpenDuration is how long an open breaker skips a node, before it turns half-open to probe the node again.
+	OpenDuration time.Duration `yaml:"open_duration" default:"30s" validate:"gt=0"`
+	// HalfOpenSuccesses is the number of successful probes that closes a half-open breaker.
+	HalfOpenSuccesses int64 `yaml:"half_open_successes" default:"3" validate:"gt=0"`
+}
+
+// ResponseCache holds the time-to-live of cached DSL responses per request type, a zero TTL disables the cache for the request type.
+type ResponseCache struct {
+	Enabled                bool          `yaml:"enabled"`
+	Activity               time.Duration `yaml:"activity" default:"1h"`
+	AccountActivities      time.Duration `yaml:"account_activities" default:"30s"`
+	BatchAccountActivities time.Duration `yaml:"batch_account_activities" default:"30s"`
+	NetworkActivities      time.Duration `yaml:"network_activities" default:"10s"`
+	PlatformActivities     time.Duration `yaml:"platform_activities" default:"10s"`
 }
 
 type Rewards struct {
@@ -125,6 +288,12 @@ type RPCNetwork struct {
 	Crossbell *RPCEndpoint `yaml:"crossbell"`
 	Polygon   *RPCEndpoint `yaml:"polygon"`
 	Farcaster *RPCEndpoint `yaml:"farcaster"`
+	// BSC resolves the .bnb names of SPACE ID.
+	BSC *RPCEndpoint `yaml:"bsc"`
+	// Base resolves the .base.eth names of Basenames.
+	Base *RPCEndpoint `yaml:"base"`
+	// UnstoppableDomains is the endpoint of the Resolution API of Unstoppable Domains.
+	UnstoppableDomains *RPCEndpoint `yaml:"unstoppable_domains"`
 }
 
 type RPCEndpoint struct {
@@ -142,7 +311,85 @@ type TokenPriceAPI struct {
 	AuthToken string `yaml:"auth_token"`
 }
 
+// Admin enables the admin API of the hub, the admin API is not served if it is not set.
+type Admin struct {
+	// AccessToken is the Bearer token required by all admin API requests.
+	AccessToken string `yaml:"access_token" validate:"required"`
+}
+
+// NodeChallenge configures the challenges signed by the Nodes to register, send heartbeats and hide their tax rates.
+type NodeChallenge struct {
+	// Domain is the domain of the hub included in the challenges, so the signatures are not valid for another hub.
+	Domain string `yaml:"domain" default:"gi.rss3.io" validate:"required"`
+	// Expiry is how long a challenge can be used after it is issued, a challenge is also consumed once it is used.
+	Expiry time.Duration `yaml:"expiry" default:"5m" validate:"gt=0"`
+	// LegacyDeadline ends the transition window in which the static messages are still accepted as challenges,
+	// it is 31 December 2026 if it is not set.
+	LegacyDeadline *time.Time `yaml:"legacy_deadline"`
+	// MaxChallengesPerIP is the number of challenges issued to a client IP within the Expiry, a zero value disables the limit.
+	// The challenges are limited by the client rather than the Node, so no one can use up the challenges of another Node.
+	MaxChallengesPerIP int `yaml:"max_challenges_per_ip" default:"60" validate:"gte=0"`
+}
+
+// defaultLegacyDeadline is the LegacyDeadline of the challenges if it is not set.
+var defaultLegacyDeadline = time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+// SetDefaults sets the LegacyDeadline, which cannot be set by a default tag.
+func (c *NodeChallenge) SetDefaults() {
+	if c.LegacyDeadline == nil {
+		deadline := defaultLegacyDeadline
+
+		c.LegacyDeadline = &deadline
+	}
+}
+
+// NodeEndpoint limits the changes of the endpoints of the Nodes, an endpoint change also requires a proof of the ownership of the new endpoint.
+type NodeEndpoint struct {
+	// MaxChanges is the number of endpoint changes a Node can make within the Window, a zero value disables the limit.
+	MaxChanges int           `yaml:"max_changes" default:"3" validate:"gte=0"`
+	Window     time.Duration `yaml:"window" default:"24h" validate:"gt=0"`
+}
+
+// CronJob configures the records of the runs of the cron jobs.
+type CronJob struct {
+	// RunRetention is how long the runs are kept, a zero value keeps them forever.
+	RunRetention time.Duration `yaml:"run_retention" default:"720h" validate:"gte=0"`
+}
+
+// NodePreferences limits the preferences set by the operators of the Nodes.
+type NodePreferences struct {
+	MaxAlertWebhooks      int `yaml:"max_alert_webhooks" default:"3" validate:"gte=0"`
+	MaxMaintenanceWindows int `yaml:"max_maintenance_windows" default:"4" validate:"gte=0"`
+	// MaxMaintenanceDuration bounds the duration of a maintenance window.
+	MaxMaintenanceDuration time.Duration `yaml:"max_maintenance_duration" default:"6h" validate:"gt=0"`
+	// MaxMaintenanceTime bounds the total duration of the maintenance windows of a Node ending within the rolling MaintenancePeriod,
+	// a zero value disables the limit.
+	MaxMaintenanceTime time.Duration `yaml:"max_maintenance_time" default:"24h" validate:"gte=0"`
+	MaintenancePeriod  time.Duration `yaml:"maintenance_period" default:"720h" validate:"gt=0"`
+}
+
+// NameResolver configures the cache of the resolutions of the names.
+type NameResolver struct {
+	// TTL is the time the resolved names are cached, the cache is disabled if it is zero.
+	TTL time.Duration `yaml:"ttl" default:"10m" validate:"gte=0"`
+	// NegativeTTL is the time the unregistered names are cached.
+	NegativeTTL time.Duration `yaml:"negative_ttl" default:"1m" validate:"gte=0"`
+}
+
 func Setup(configFilePath string) (*File, error) {
+	configFile, err := Load(configFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize some common global variables.
+	initGlobalVars(configFile)
+
+	return configFile, nil
+}
+
+// Load reads the config file without initializing the global variables, so it can be used to reload parts of the config.
+func Load(configFilePath string) (*File, error) {
 	// Read config file.
 	config, err := os.ReadFile(configFilePath)
 	if err != nil {
@@ -166,9 +413,6 @@ func Setup(configFilePath string) (*File, error) {
 		return nil, fmt.Errorf("validate config file: %w", err)
 	}
 
-	// Initialize some common global variables.
-	initGlobalVars(&configFile)
-
 	return &configFile, nil
 }
 
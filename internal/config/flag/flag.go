@@ -8,6 +8,16 @@ import (
 const (
 	KeyConfig = "config"
 	KeyServer = "server"
+	KeyJobs   = "jobs"
+	KeyEpoch  = "epoch"
+
+	KeySettlementFailureID = "id"
+	KeyOperator            = "operator"
+	KeyReason              = "reason"
+
+	KeyChain = "chain"
+	KeyFrom  = "from"
+	KeyTo    = "to"
 
 	KeyChainIDL1 = "chain-id.l1"
 	KeyChainIDL2 = "chain-id.l2"
@@ -2,52 +2,259 @@ package cronjob
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/go-redsync/redsync/v4"
 	"github.com/go-redsync/redsync/v4/redis/goredis/v9"
 	"github.com/redis/go-redis/v9"
 	"github.com/robfig/cron/v3"
+	"github.com/rss3-network/global-indexer/internal/database"
+	"github.com/rss3-network/global-indexer/schema"
 	"go.uber.org/zap"
 )
 
-type CronJob struct {
-	crontab *cron.Cron
+var KeyPrefix = "cronjob:%s"
+
+var (
+	ErrJobNotFound = errors.New("job not found")
+	ErrJobRunning  = errors.New("job is running")
+)
+
+// Job is a cron job, which is run on its Spec by a Runner under a lock shared by all the schedulers.
+type Job interface {
+	Name() string
+	Spec() string
+	// Timeout is the expiry of the lock of the job, the lock is renewed while the job is running.
+	Timeout() time.Duration
+	Run(ctx context.Context) error
+}
+
+// StartupJob is a Job which is also run when the Runner is started.
+type StartupJob interface {
+	Job
+	RunOnStart() bool
+}
+
+// Entry is a job of a Runner.
+type Entry struct {
+	Job Job
+	// Next is the next time the job is run on its Spec, it is zero before the Runner is started.
+	Next time.Time
+}
+
+type entry struct {
+	job     Job
 	mutex   *redsync.Mutex
-	timeout time.Duration
+	entryID cron.EntryID
 }
 
-var KeyPrefix = "cronjob:%s"
+// Runner runs the jobs on their specs, and records their runs in the database.
+// The runs started before the retention are deleted after each run, they are kept forever if the retention is zero.
+type Runner struct {
+	crontab        *cron.Cron
+	databaseClient database.Client
+	entries        []*entry
+	retention      time.Duration
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	waitGroup sync.WaitGroup
+}
+
+// Entries returns the jobs of the runner, in the order they were added.
+func (r *Runner) Entries() []Entry {
+	entries := make([]Entry, 0, len(r.entries))
+
+	for _, entry := range r.entries {
+		entries = append(entries, Entry{
+			Job:  entry.job,
+			Next: r.crontab.Entry(entry.entryID).Next,
+		})
+	}
+
+	return entries
+}
+
+// Start starts running the jobs on their specs, the jobs are canceled when the context is done or the runner is stopped.
+func (r *Runner) Start(ctx context.Context) {
+	r.ctx, r.cancel = context.WithCancel(ctx)
+
+	for _, entry := range r.entries {
+		if job, ok := entry.job.(StartupJob); ok && job.RunOnStart() {
+			r.waitGroup.Add(1)
+
+			go func() {
+				defer r.waitGroup.Done()
+
+				r.schedule(entry)
+			}()
+		}
+	}
+
+	r.crontab.Start()
+}
+
+// Stop stops the scheduling of the jobs, and waits for the running jobs to return after canceling them.
+func (r *Runner) Stop() {
+	stopped := r.crontab.Stop()
+
+	r.cancel()
+
+	<-stopped.Done()
+	r.waitGroup.Wait()
+}
+
+// Trigger runs the job immediately in the background, it fails with ErrJobRunning if the job is running in any scheduler.
+func (r *Runner) Trigger(ctx context.Context, name string) (*schema.CronJobRun, error) {
+	entry, found := r.find(name)
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFound, name)
+	}
+
+	if err := entry.mutex.TryLockContext(ctx); err != nil {
+		if isLockTaken(err) {
+			return nil, fmt.Errorf("%w: %s", ErrJobRunning, name)
+		}
+
+		return nil, fmt.Errorf("lock job %s: %w", name, err)
+	}
+
+	run := r.startRun(ctx, entry, schema.CronJobRunTriggerManual)
+
+	r.waitGroup.Add(1)
+
+	go func() {
+		defer r.waitGroup.Done()
+
+		r.run(entry, run)
+	}()
+
+	return run, nil
+}
+
+func (r *Runner) find(name string) (*entry, bool) {
+	for _, entry := range r.entries {
+		if entry.job.Name() == name {
+			return entry, true
+		}
+	}
+
+	return nil, false
+}
 
-func (c *CronJob) AddFunc(ctx context.Context, spec string, cmd func()) error {
-	_, err := c.crontab.AddFunc(spec, func() {
-		ctx, cancel := context.WithCancel(ctx)
-		defer cancel()
+// schedule runs the job on its spec, it waits for the lock if the job is running in another scheduler,
+// and records the run as skipped if the lock is still taken.
+func (r *Runner) schedule(entry *entry) {
+	if err := entry.mutex.LockContext(r.ctx); err != nil {
+		if isLockTaken(err) {
+			zap.L().Info("job is running, skipped", zap.String("key", entry.mutex.Name()))
 
-		if err := c.mutex.Lock(); err != nil {
-			zap.L().Error("lock error", zap.String("key", c.mutex.Name()), zap.Error(err))
+			r.skipRun(r.ctx, entry)
 
 			return
 		}
 
-		defer func() {
-			if _, err := c.mutex.Unlock(); err != nil {
-				zap.L().Error("release lock error", zap.String("key", c.mutex.Name()), zap.Error(err))
-			}
-		}()
+		zap.L().Error("lock error", zap.String("key", entry.mutex.Name()), zap.Error(err))
+
+		return
+	}
+
+	r.run(entry, r.startRun(r.ctx, entry, schema.CronJobRunTriggerSchedule))
+}
+
+// startRun records the start of a run of a locked job.
+func (r *Runner) startRun(ctx context.Context, entry *entry, trigger schema.CronJobRunTrigger) *schema.CronJobRun {
+	run := schema.CronJobRun{
+		Job:       entry.job.Name(),
+		Trigger:   trigger,
+		Status:    schema.CronJobRunStatusRunning,
+		StartedAt: time.Now(),
+	}
+
+	if err := r.databaseClient.SaveCronJobRun(context.WithoutCancel(ctx), &run); err != nil {
+		zap.L().Error("save cron job run error", zap.String("job", run.Job), zap.Error(err))
+	}
+
+	return &run
+}
+
+// skipRun records a skipped run of a job.
+func (r *Runner) skipRun(ctx context.Context, entry *entry) {
+	now := time.Now()
+
+	run := schema.CronJobRun{
+		Job:       entry.job.Name(),
+		Trigger:   schema.CronJobRunTriggerSchedule,
+		Status:    schema.CronJobRunStatusSkipped,
+		StartedAt: now,
+		EndedAt:   &now,
+	}
+
+	if err := r.databaseClient.SaveCronJobRun(context.WithoutCancel(ctx), &run); err != nil {
+		zap.L().Error("save cron job run error", zap.String("job", run.Job), zap.Error(err))
+	}
+}
+
+// run runs a locked job, records the end of its run and releases the lock.
+func (r *Runner) run(entry *entry, run *schema.CronJobRun) {
+	ctx, cancel := context.WithCancel(r.ctx)
+	defer cancel()
+
+	defer func() {
+		if _, err := entry.mutex.Unlock(); err != nil {
+			zap.L().Error("release lock error", zap.String("key", entry.mutex.Name()), zap.Error(err))
+		}
+	}()
+
+	renew(ctx, entry.mutex, entry.job.Timeout())
+
+	err := entry.job.Run(ctx)
 
-		c.Renewal(ctx)
-		cmd()
-	})
+	endedAt := time.Now()
 
-	return err
+	run.EndedAt = &endedAt
+	run.Duration = endedAt.Sub(run.StartedAt).Milliseconds()
+	run.Status = schema.CronJobRunStatusSucceeded
+
+	if err != nil {
+		zap.L().Error("run cron job error", zap.String("job", run.Job), zap.String("trigger", string(run.Trigger)), zap.Error(err))
+
+		run.Status = schema.CronJobRunStatusFailed
+		run.Error = err.Error()
+	}
+
+	if err := r.databaseClient.SaveCronJobRun(context.WithoutCancel(ctx), run); err != nil {
+		zap.L().Error("save cron job run error", zap.String("job", run.Job), zap.Error(err))
+	}
+
+	r.prune(context.WithoutCancel(ctx))
 }
 
-func (c *CronJob) Renewal(ctx context.Context) {
+// prune deletes the runs started before the retention.
+func (r *Runner) prune(ctx context.Context) {
+	if r.retention == 0 {
+		return
+	}
+
+	if err := r.databaseClient.DeleteCronJobRuns(ctx, time.Now().Add(-r.retention)); err != nil {
+		zap.L().Error("delete cron job runs error", zap.Error(err))
+	}
+}
+
+// isLockTaken reports whether the lock of a job could not be taken as the job is running.
+func isLockTaken(err error) bool {
+	var errTaken *redsync.ErrTaken
+
+	return errors.Is(err, redsync.ErrFailed) || errors.As(err, &errTaken)
+}
+
+// renew extends the lock every half of its expiry until the context is done.
+func renew(ctx context.Context, mutex *redsync.Mutex, timeout time.Duration) {
 	go func(ctx context.Context) {
-		// Renewal lock every half of timeout.
-		ticker := time.NewTicker(c.timeout / 2)
+		ticker := time.NewTicker(timeout / 2)
 		defer ticker.Stop()
 
 		for {
@@ -55,15 +262,15 @@ func (c *CronJob) Renewal(ctx context.Context) {
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				result, err := c.mutex.ExtendContext(ctx)
+				result, err := mutex.ExtendContext(ctx)
 				if err != nil {
-					zap.L().Error("extend lock error", zap.String("key", c.mutex.Name()), zap.Error(err))
+					zap.L().Error("extend lock error", zap.String("key", mutex.Name()), zap.Error(err))
 
 					continue
 				}
 
 				if !result {
-					zap.L().Error("extend lock failed", zap.String("key", c.mutex.Name()))
+					zap.L().Error("extend lock failed", zap.String("key", mutex.Name()))
 
 					continue
 				}
@@ -72,21 +279,38 @@ func (c *CronJob) Renewal(ctx context.Context) {
 	}(ctx)
 }
 
-func (c *CronJob) Start() {
-	c.crontab.Start()
-}
+func NewRunner(redisClient *redis.Client, databaseClient database.Client, retention time.Duration, jobs ...Job) (*Runner, error) {
+	rs := redsync.New(goredis.NewPool(redisClient))
 
-func (c *CronJob) Stop() {
-	c.crontab.Stop()
-}
+	runner := Runner{
+		crontab:        cron.New(cron.WithLocation(time.UTC), cron.WithSeconds()),
+		databaseClient: databaseClient,
+		retention:      retention,
+		// The context is replaced when the runner is started.
+		ctx:    context.Background(),
+		cancel: func() {},
+	}
 
-func New(redisClient *redis.Client, name string, timeout time.Duration) *CronJob {
-	pool := goredis.NewPool(redisClient)
-	rs := redsync.New(pool)
+	for _, job := range jobs {
+		if _, found := runner.find(job.Name()); found {
+			return nil, fmt.Errorf("duplicate job %s", job.Name())
+		}
 
-	return &CronJob{
-		crontab: cron.New(cron.WithLocation(time.UTC), cron.WithSeconds()),
-		mutex:   rs.NewMutex(fmt.Sprintf(KeyPrefix, name), redsync.WithExpiry(timeout)),
-		timeout: timeout,
+		entry := entry{
+			job:   job,
+			mutex: rs.NewMutex(fmt.Sprintf(KeyPrefix, job.Name()), redsync.WithExpiry(job.Timeout())),
+		}
+
+		entryID, err := runner.crontab.AddFunc(job.Spec(), func() {
+			runner.schedule(&entry)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("add job %s: %w", job.Name(), err)
+		}
+
+		entry.entryID = entryID
+		runner.entries = append(runner.entries, &entry)
 	}
+
+	return &runner, nil
 }
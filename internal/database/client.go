@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/pressly/goose/v3"
@@ -26,13 +27,18 @@ type Client interface {
 
 	FindCheckpoint(ctx context.Context, chainID uint64) (*schema.Checkpoint, error)
 	SaveCheckpoint(ctx context.Context, checkpoint *schema.Checkpoint) error
+	FindUnfinalizedCheckpoints(ctx context.Context, chainID uint64, limit int) ([]*schema.Checkpoint, error)
+	SaveUnfinalizedCheckpoint(ctx context.Context, checkpoint *schema.Checkpoint, limit int) error
+	DeleteUnfinalizedCheckpoints(ctx context.Context, chainID, blockNumber uint64) error
 
 	FindNode(ctx context.Context, nodeAddress common.Address) (*schema.Node, error)
+	FindNodeForUpdate(ctx context.Context, nodeAddress common.Address) (*schema.Node, error)
 	FindNodes(ctx context.Context, query schema.FindNodesQuery) ([]*schema.Node, error)
 	FindNodeAvatar(ctx context.Context, nodeAddress common.Address) (*l2.ChipsTokenMetadata, error)
 	SaveNode(ctx context.Context, node *schema.Node) error
 	UpdateNodesStatusOffline(ctx context.Context, lastHeartbeatTimestamp int64) error
 	UpdateNodesHideTaxRate(ctx context.Context, nodeAddress common.Address, hideTaxRate bool) error
+	UpdateNodePreferences(ctx context.Context, nodeAddress common.Address, hideTaxRate bool, preferences *schema.NodePreferences) error
 	UpdateNodesScore(ctx context.Context, nodes []*schema.Node) error
 	UpdateNodePublicGood(ctx context.Context, nodeAddress common.Address, isPublicGood bool) error
 
@@ -40,6 +46,7 @@ type Client interface {
 	SaveNodeEvent(ctx context.Context, nodeEvent *schema.NodeEvent) error
 	FindNodeEvents(ctx context.Context, nodeEventsQuery *schema.NodeEventsQuery) ([]*schema.NodeEvent, error)
 	DeleteNodeEventsByBlockNumber(ctx context.Context, blockNumber uint64) error
+	PurgeNodeEventsByBlockNumber(ctx context.Context, blockNumber uint64) error
 	UpdateNodeEventsFinalizedByBlockNumber(ctx context.Context, blockNumber uint64) error
 
 	FindNodeStat(ctx context.Context, nodeAddress common.Address) (*schema.Stat, error)
@@ -73,6 +80,8 @@ type Client interface {
 	SaveBridgeEvent(ctx context.Context, bridgeEvent *schema.BridgeEvent) error
 	DeleteBridgeTransactionsByBlockNumber(ctx context.Context, chainID, blockNumber uint64) error
 	DeleteBridgeEventsByBlockNumber(ctx context.Context, chainID, blockNumber uint64) error
+	PurgeBridgeTransactionsByBlockNumber(ctx context.Context, chainID, blockNumber uint64) error
+	PurgeBridgeEventsByBlockNumber(ctx context.Context, chainID, blockNumber uint64) error
 
 	FindStakeTransaction(ctx context.Context, query schema.StakeTransactionQuery) (*schema.StakeTransaction, error)
 	FindStakeTransactions(ctx context.Context, query schema.StakeTransactionsQuery) ([]*schema.StakeTransaction, error)
@@ -90,6 +99,8 @@ type Client interface {
 	SaveStakeEvent(ctx context.Context, stakeEvent *schema.StakeEvent) error
 	DeleteStakeTransactionsByBlockNumber(ctx context.Context, blockNumber uint64) error
 	DeleteStakeEventsByBlockNumber(ctx context.Context, blockNumber uint64) error
+	PurgeStakeTransactionsByBlockNumber(ctx context.Context, blockNumber uint64) error
+	PurgeStakeEventsByBlockNumber(ctx context.Context, blockNumber uint64) error
 	SaveStakeChips(ctx context.Context, stakeChips ...*schema.StakeChip) error
 	UpdateStakeChipsOwner(ctx context.Context, owner common.Address, stakeChips ...*big.Int) error
 
@@ -100,13 +111,37 @@ type Client interface {
 	FindEpochNodeRewards(ctx context.Context, nodeAddress common.Address, limit int, cursor *string) ([]*schema.Epoch, error)
 	UpdateEpochsFinalizedByBlockNumber(ctx context.Context, blockNumber uint64) error
 	DeleteEpochsByBlockNumber(ctx context.Context, blockNumber uint64) error
+	PurgeEpochsByBlockNumber(ctx context.Context, blockNumber uint64) error
 
 	SaveEpochTrigger(ctx context.Context, epochTrigger *schema.EpochTrigger) error
 	FindLatestEpochTrigger(ctx context.Context) (*schema.EpochTrigger, error)
 	FindEpochTriggers(ctx context.Context, epochID uint64) ([]*schema.EpochTrigger, error)
+	SaveSettlementBatch(ctx context.Context, batch *schema.SettlementBatch) error
+	FindSettlementBatches(ctx context.Context, epochID uint64) ([]*schema.SettlementBatch, error)
+	FindLatestSettlementBatch(ctx context.Context) (*schema.SettlementBatch, error)
 
 	FindAverageTaxSubmissions(ctx context.Context, query schema.AverageTaxRateSubmissionQuery) ([]*schema.AverageTaxRateSubmission, error)
 	SaveAverageTaxSubmission(ctx context.Context, averageTaxSubmission *schema.AverageTaxRateSubmission) error
+
+	SaveSettlementFailure(ctx context.Context, failure *schema.SettlementFailure) error
+	FindSettlementFailure(ctx context.Context, id uint64) (*schema.SettlementFailure, error)
+	FindSettlementFailures(ctx context.Context, query schema.SettlementFailuresQuery) ([]*schema.SettlementFailure, error)
+	SaveSettlementFailureAudit(ctx context.Context, audit *schema.SettlementFailureAudit) error
+	FindSettlementFailureAudits(ctx context.Context, failureID uint64) ([]*schema.SettlementFailureAudit, error)
+
+	SaveVSLTransaction(ctx context.Context, transaction *schema.VSLTransaction) error
+	FindVSLTransactions(ctx context.Context, query schema.VSLTransactionsQuery) ([]*schema.VSLTransaction, error)
+	SumVSLTransactionFees(ctx context.Context, query schema.VSLTransactionFeesQuery) (*big.Int, error)
+	SaveVSLTransactionSubmission(ctx context.Context, submission *schema.VSLTransactionSubmission) error
+	FindVSLTransactionSubmissions(ctx context.Context, transactionIDs []uint64) ([]*schema.VSLTransactionSubmission, error)
+
+	SaveCronJobRun(ctx context.Context, run *schema.CronJobRun) error
+	FindCronJobRuns(ctx context.Context, query schema.CronJobRunsQuery) ([]*schema.CronJobRun, error)
+	DeleteCronJobRuns(ctx context.Context, before time.Time) error
+
+	SaveNodeEndpointHistory(ctx context.Context, history *schema.NodeEndpointHistory) error
+	FindNodeEndpointHistories(ctx context.Context, query schema.NodeEndpointHistoryQuery) ([]*schema.NodeEndpointHistory, error)
+	CountNodeEndpointChanges(ctx context.Context, address common.Address, since time.Time) (int64, error)
 }
 
 type Session interface {
@@ -216,6 +216,22 @@ func (c *client) DeleteBridgeEventsByBlockNumber(ctx context.Context, chainID, b
 		Error
 }
 
+// PurgeBridgeTransactionsByBlockNumber deletes the bridge transactions of a block, the finalized ones included.
+func (c *client) PurgeBridgeTransactionsByBlockNumber(ctx context.Context, chainID, blockNumber uint64) error {
+	return c.database.
+		WithContext(ctx).
+		Delete(new(table.BridgeTransaction), `"chain_id" = ? AND "block_number" = ?`, chainID, blockNumber).
+		Error
+}
+
+// PurgeBridgeEventsByBlockNumber deletes the bridge events of a block, the finalized ones included.
+func (c *client) PurgeBridgeEventsByBlockNumber(ctx context.Context, chainID, blockNumber uint64) error {
+	return c.database.
+		WithContext(ctx).
+		Delete(new(table.BridgeEvent), `"chain_id" = ? AND "block_number" = ?`, chainID, blockNumber).
+		Error
+}
+
 func (c *client) UpdateBridgeTransactionsFinalizedByBlockNumber(ctx context.Context, chainID, blockNumber uint64) error {
 	return c.database.
 		WithContext(ctx).
@@ -36,3 +36,73 @@ func (c *client) SaveCheckpoint(ctx context.Context, checkpoint *schema.Checkpoi
 
 	return c.database.WithContext(ctx).Clauses(clauses...).Create(&value).Error
 }
+
+// FindUnfinalizedCheckpoints returns the latest blocks indexed by the unfinalized indexer of a chain, in ascending order of block number.
+func (c *client) FindUnfinalizedCheckpoints(ctx context.Context, chainID uint64, limit int) ([]*schema.Checkpoint, error) {
+	var values []table.UnfinalizedCheckpoint
+
+	if err := c.database.
+		WithContext(ctx).
+		Where(`"chain_id" = ?`, chainID).
+		Order(`"block_number" DESC`).
+		Limit(limit).
+		Find(&values).Error; err != nil {
+		return nil, err
+	}
+
+	checkpoints := make([]*schema.Checkpoint, 0, len(values))
+
+	for index := len(values) - 1; index >= 0; index-- {
+		checkpoint, err := values[index].Export()
+		if err != nil {
+			return nil, fmt.Errorf("export checkpoint: %w", err)
+		}
+
+		checkpoints = append(checkpoints, checkpoint)
+	}
+
+	return checkpoints, nil
+}
+
+// SaveUnfinalizedCheckpoint saves a block indexed by the unfinalized indexer of a chain,
+// replacing the blocks at the same height or above, and keeping only the latest `limit` blocks.
+func (c *client) SaveUnfinalizedCheckpoint(ctx context.Context, checkpoint *schema.Checkpoint, limit int) error {
+	var value table.UnfinalizedCheckpoint
+	if err := value.Import(*checkpoint); err != nil {
+		return fmt.Errorf("import checkpoint: %w", err)
+	}
+
+	if err := c.database.
+		WithContext(ctx).
+		Where(`"chain_id" = ? AND "block_number" >= ?`, checkpoint.ChainID, checkpoint.BlockNumber).
+		Delete(new(table.UnfinalizedCheckpoint)).Error; err != nil {
+		return fmt.Errorf("delete replaced checkpoints: %w", err)
+	}
+
+	if err := c.database.WithContext(ctx).Create(&value).Error; err != nil {
+		return fmt.Errorf("create checkpoint: %w", err)
+	}
+
+	evicted := c.database.
+		Model(new(table.UnfinalizedCheckpoint)).
+		Select(`"block_number"`).
+		Where(`"chain_id" = ?`, checkpoint.ChainID).
+		Order(`"block_number" DESC`).
+		Offset(limit).
+		Limit(1)
+
+	return c.database.
+		WithContext(ctx).
+		Where(`"chain_id" = ? AND "block_number" <= (?)`, checkpoint.ChainID, evicted).
+		Delete(new(table.UnfinalizedCheckpoint)).
+		Error
+}
+
+// DeleteUnfinalizedCheckpoints deletes the blocks indexed by the unfinalized indexer of a chain above a height.
+func (c *client) DeleteUnfinalizedCheckpoints(ctx context.Context, chainID, blockNumber uint64) error {
+	return c.database.
+		WithContext(ctx).
+		Where(`"chain_id" = ? AND "block_number" > ?`, chainID, blockNumber).
+		Delete(new(table.UnfinalizedCheckpoint)).
+		Error
+}
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/rss3-network/global-indexer/internal/database/dialer/postgres/table"
+	"github.com/rss3-network/global-indexer/schema"
+	"go.uber.org/zap"
+)
+
+// SaveCronJobRun inserts a new run, or updates an existing one if its ID is set.
+func (c *client) SaveCronJobRun(ctx context.Context, run *schema.CronJobRun) error {
+	var data table.CronJobRun
+
+	data.Import(run)
+
+	if err := c.database.WithContext(ctx).Save(&data).Error; err != nil {
+		zap.L().Error("save cron job run", zap.Error(err), zap.String("job", run.Job))
+
+		return err
+	}
+
+	run.ID = data.ID
+
+	return nil
+}
+
+func (c *client) FindCronJobRuns(ctx context.Context, query schema.CronJobRunsQuery) ([]*schema.CronJobRun, error) {
+	databaseStatement := c.database.WithContext(ctx).Table((*table.CronJobRun).TableName(nil))
+
+	if query.Cursor != nil {
+		databaseStatement = databaseStatement.Where("id < ?", *query.Cursor)
+	}
+
+	if query.Job != nil {
+		databaseStatement = databaseStatement.Where("job = ?", *query.Job)
+	}
+
+	if query.Limit != nil {
+		databaseStatement = databaseStatement.Limit(*query.Limit)
+	}
+
+	var runs table.CronJobRuns
+
+	if err := databaseStatement.Order("id DESC").Find(&runs).Error; err != nil {
+		zap.L().Error("find cron job runs", zap.Error(err), zap.Any("query", query))
+
+		return nil, err
+	}
+
+	return runs.Export(), nil
+}
+
+// DeleteCronJobRuns deletes the runs started before a time.
+func (c *client) DeleteCronJobRuns(ctx context.Context, before time.Time) error {
+	if err := c.database.WithContext(ctx).Where("started_at < ?", before).Delete(new(table.CronJobRun)).Error; err != nil {
+		zap.L().Error("delete cron job runs", zap.Error(err), zap.Time("before", before))
+
+		return err
+	}
+
+	return nil
+}
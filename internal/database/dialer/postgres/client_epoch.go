@@ -284,6 +284,15 @@ func (c *client) UpdateEpochsFinalizedByBlockNumber(ctx context.Context, blockNu
 }
 
 func (c *client) DeleteEpochsByBlockNumber(ctx context.Context, blockNumber uint64) error {
+	return c.deleteEpochsByBlockNumber(ctx, blockNumber, false)
+}
+
+// PurgeEpochsByBlockNumber deletes the epochs of a block and their node reward records, the finalized ones included.
+func (c *client) PurgeEpochsByBlockNumber(ctx context.Context, blockNumber uint64) error {
+	return c.deleteEpochsByBlockNumber(ctx, blockNumber, true)
+}
+
+func (c *client) deleteEpochsByBlockNumber(ctx context.Context, blockNumber uint64, finalized bool) error {
 	epoch, err := c.FindEpochs(ctx, &schema.FindEpochsQuery{
 		BlockNumber: lo.ToPtr(blockNumber),
 	})
@@ -297,7 +306,12 @@ func (c *client) DeleteEpochsByBlockNumber(ctx context.Context, blockNumber uint
 		return nil
 	}
 
-	if err = c.database.WithContext(ctx).Where(`block_number = ? AND NOT "finalized"`, blockNumber).Delete(&table.Epoch{}).Error; err != nil {
+	databaseStatement := c.database.WithContext(ctx).Where(`block_number = ?`, blockNumber)
+	if !finalized {
+		databaseStatement = databaseStatement.Where(`NOT "finalized"`)
+	}
+
+	if err = databaseStatement.Delete(&table.Epoch{}).Error; err != nil {
 		zap.L().Error("delete epochs by block number", zap.Error(err), zap.Uint64("blockNumber", blockNumber))
 
 		return err
@@ -325,7 +339,13 @@ func (c *client) SaveEpochTrigger(ctx context.Context, epochTrigger *schema.Epoc
 		return err
 	}
 
-	if err := c.database.WithContext(ctx).Create(&data).Error; err != nil {
+	// The same transaction may be saved again when a resumed Settlement reconciles it
+	onConflict := clause.OnConflict{
+		Columns:   []clause.Column{{Name: "transaction_hash"}},
+		DoNothing: true,
+	}
+
+	if err := c.database.WithContext(ctx).Clauses(onConflict).Create(&data).Error; err != nil {
 		zap.L().Error("insert epoch trigger", zap.Error(err), zap.Any("epochTrigger", epochTrigger))
 
 		return err
@@ -33,6 +33,21 @@ func (c *client) FindNode(ctx context.Context, nodeAddress common.Address) (*sch
 	return node.Export()
 }
 
+// FindNodeForUpdate finds the Node and locks its row until the end of the transaction.
+func (c *client) FindNodeForUpdate(ctx context.Context, nodeAddress common.Address) (*schema.Node, error) {
+	var node table.Node
+
+	if err := c.database.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&node, "address = ?", nodeAddress).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, database.ErrorRowNotFound
+		}
+
+		return nil, err
+	}
+
+	return node.Export()
+}
+
 func (c *client) FindNodes(ctx context.Context, query schema.FindNodesQuery) ([]*schema.Node, error) {
 	databaseStatement := c.database.WithContext(ctx)
 
@@ -163,6 +178,32 @@ func (c *client) UpdateNodesHideTaxRate(ctx context.Context, nodeAddress common.
 		Error
 }
 
+// UpdateNodePreferences updates the preferences of the Node, including whether its tax rate is hidden.
+func (c *client) UpdateNodePreferences(ctx context.Context, nodeAddress common.Address, hideTaxRate bool, preferences *schema.NodePreferences) error {
+	data, err := json.Marshal(preferences)
+	if err != nil {
+		return fmt.Errorf("marshal node preferences: %w", err)
+	}
+
+	result := c.database.
+		WithContext(ctx).
+		Model((*table.Node)(nil)).
+		Where("address = ?", nodeAddress).
+		Updates(map[string]any{
+			"hide_tax_rate": hideTaxRate,
+			"preferences":   json.RawMessage(data),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return database.ErrorRowNotFound
+	}
+
+	return nil
+}
+
 func (c *client) UpdateNodesScore(ctx context.Context, nodes []*schema.Node) error {
 	var tNodes table.Nodes
 
@@ -637,6 +678,14 @@ func (c *client) DeleteNodeEventsByBlockNumber(ctx context.Context, blockNumber
 		Error
 }
 
+// PurgeNodeEventsByBlockNumber deletes the node events of a block, the finalized ones included.
+func (c *client) PurgeNodeEventsByBlockNumber(ctx context.Context, blockNumber uint64) error {
+	return c.database.
+		WithContext(ctx).
+		Delete(new(table.NodeEvent), `"block_number" = ?`, blockNumber).
+		Error
+}
+
 func (c *client) UpdateNodeEventsFinalizedByBlockNumber(ctx context.Context, blockNumber uint64) error {
 	return c.database.
 		WithContext(ctx).
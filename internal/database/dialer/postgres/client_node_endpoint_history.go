@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rss3-network/global-indexer/internal/database/dialer/postgres/table"
+	"github.com/rss3-network/global-indexer/schema"
+	"go.uber.org/zap"
+)
+
+func (c *client) SaveNodeEndpointHistory(ctx context.Context, history *schema.NodeEndpointHistory) error {
+	var data table.NodeEndpointHistory
+
+	data.Import(history)
+
+	if err := c.database.WithContext(ctx).Create(&data).Error; err != nil {
+		zap.L().Error("save node endpoint history", zap.Error(err), zap.String("address", history.Address.String()))
+
+		return err
+	}
+
+	history.ID = data.ID
+	history.CreatedAt = data.CreatedAt
+
+	return nil
+}
+
+func (c *client) FindNodeEndpointHistories(ctx context.Context, query schema.NodeEndpointHistoryQuery) ([]*schema.NodeEndpointHistory, error) {
+	databaseStatement := c.database.WithContext(ctx).Table((*table.NodeEndpointHistory).TableName(nil))
+
+	if query.Address != nil {
+		databaseStatement = databaseStatement.Where("address = ?", *query.Address)
+	}
+
+	if query.Cursor != nil {
+		databaseStatement = databaseStatement.Where("id < ?", *query.Cursor)
+	}
+
+	if query.Limit != nil {
+		databaseStatement = databaseStatement.Limit(*query.Limit)
+	}
+
+	var histories table.NodeEndpointHistories
+
+	if err := databaseStatement.Order("id DESC").Find(&histories).Error; err != nil {
+		zap.L().Error("find node endpoint histories", zap.Error(err), zap.Any("query", query))
+
+		return nil, err
+	}
+
+	return histories.Export(), nil
+}
+
+// CountNodeEndpointChanges counts the changes of the endpoint of a Node since the time, the first registration is not a change.
+func (c *client) CountNodeEndpointChanges(ctx context.Context, address common.Address, since time.Time) (int64, error) {
+	var count int64
+
+	if err := c.database.WithContext(ctx).
+		Table((*table.NodeEndpointHistory).TableName(nil)).
+		Where("address = ? AND created_at >= ? AND previous_endpoint <> '' AND endpoint <> previous_endpoint", address, since).
+		Count(&count).Error; err != nil {
+		zap.L().Error("count node endpoint changes", zap.Error(err), zap.String("address", address.String()))
+
+		return 0, err
+	}
+
+	return count, nil
+}
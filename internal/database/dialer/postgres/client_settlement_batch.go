@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rss3-network/global-indexer/internal/database"
+	"github.com/rss3-network/global-indexer/internal/database/dialer/postgres/table"
+	"github.com/rss3-network/global-indexer/schema"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SaveSettlementBatch inserts or updates the checkpoint of a batch of an Epoch.
+func (c *client) SaveSettlementBatch(ctx context.Context, batch *schema.SettlementBatch) error {
+	var data table.SettlementBatch
+	if err := data.Import(batch); err != nil {
+		zap.L().Error("import settlement batch", zap.Error(err), zap.Any("batch", batch))
+
+		return err
+	}
+
+	onConflict := clause.OnConflict{
+		Columns: []clause.Column{
+			{
+				Name: "epoch_id",
+			},
+			{
+				Name: "batch_index",
+			},
+		},
+		DoUpdates: clause.AssignmentColumns([]string{"cursor", "calldata_hash", "block_number", "transaction_hash", "data", "status", "updated_at"}),
+	}
+
+	if err := c.database.WithContext(ctx).Clauses(onConflict).Create(&data).Error; err != nil {
+		zap.L().Error("save settlement batch", zap.Error(err), zap.Any("batch", batch))
+
+		return err
+	}
+
+	return nil
+}
+
+// FindSettlementBatches returns the checkpoints of the batches of an Epoch in order.
+func (c *client) FindSettlementBatches(ctx context.Context, epochID uint64) ([]*schema.SettlementBatch, error) {
+	var batches table.SettlementBatches
+
+	if err := c.database.WithContext(ctx).Where("epoch_id = ?", epochID).Order("batch_index").Find(&batches).Error; err != nil {
+		zap.L().Error("find settlement batches", zap.Error(err), zap.Uint64("epoch_id", epochID))
+
+		return nil, err
+	}
+
+	return batches.Export()
+}
+
+// FindLatestSettlementBatch returns the checkpoint of the last batch of the latest Epoch.
+func (c *client) FindLatestSettlementBatch(ctx context.Context) (*schema.SettlementBatch, error) {
+	var batch table.SettlementBatch
+
+	if err := c.database.WithContext(ctx).Order("epoch_id DESC, batch_index DESC").First(&batch).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, database.ErrorRowNotFound
+		}
+
+		return nil, err
+	}
+
+	return batch.Export()
+}
@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rss3-network/global-indexer/internal/database"
+	"github.com/rss3-network/global-indexer/internal/database/dialer/postgres/table"
+	"github.com/rss3-network/global-indexer/schema"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// SaveSettlementFailure inserts a new failure, or updates an existing one if its ID is set.
+func (c *client) SaveSettlementFailure(ctx context.Context, failure *schema.SettlementFailure) error {
+	var data table.SettlementFailure
+	if err := data.Import(failure); err != nil {
+		zap.L().Error("import settlement failure", zap.Error(err), zap.Any("failure", failure))
+
+		return err
+	}
+
+	if err := c.database.WithContext(ctx).Save(&data).Error; err != nil {
+		zap.L().Error("save settlement failure", zap.Error(err), zap.Any("failure", failure))
+
+		return err
+	}
+
+	failure.ID = data.ID
+	failure.CreatedAt = data.CreatedAt
+	failure.UpdatedAt = data.UpdatedAt
+
+	return nil
+}
+
+func (c *client) FindSettlementFailure(ctx context.Context, id uint64) (*schema.SettlementFailure, error) {
+	var failure table.SettlementFailure
+
+	if err := c.database.WithContext(ctx).First(&failure, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, database.ErrorRowNotFound
+		}
+
+		return nil, err
+	}
+
+	return failure.Export()
+}
+
+func (c *client) FindSettlementFailures(ctx context.Context, query schema.SettlementFailuresQuery) ([]*schema.SettlementFailure, error) {
+	databaseStatement := c.database.WithContext(ctx).Table((*table.SettlementFailure).TableName(nil))
+
+	if query.Service != nil {
+		databaseStatement = databaseStatement.Where("service = ?", *query.Service)
+	}
+
+	if query.EpochID != nil {
+		databaseStatement = databaseStatement.Where("epoch_id = ?", *query.EpochID)
+	}
+
+	if len(query.Statuses) > 0 {
+		databaseStatement = databaseStatement.Where("status IN ?", query.Statuses)
+	}
+
+	if query.Limit != nil {
+		databaseStatement = databaseStatement.Limit(*query.Limit)
+	}
+
+	var failures table.SettlementFailures
+
+	if err := databaseStatement.Order("id DESC").Find(&failures).Error; err != nil {
+		zap.L().Error("find settlement failures", zap.Error(err), zap.Any("query", query))
+
+		return nil, err
+	}
+
+	return failures.Export()
+}
+
+func (c *client) SaveSettlementFailureAudit(ctx context.Context, audit *schema.SettlementFailureAudit) error {
+	var data table.SettlementFailureAudit
+
+	data.Import(audit)
+
+	if err := c.database.WithContext(ctx).Create(&data).Error; err != nil {
+		zap.L().Error("insert settlement failure audit", zap.Error(err), zap.Any("audit", audit))
+
+		return err
+	}
+
+	audit.ID = data.ID
+	audit.CreatedAt = data.CreatedAt
+
+	return nil
+}
+
+func (c *client) FindSettlementFailureAudits(ctx context.Context, failureID uint64) ([]*schema.SettlementFailureAudit, error) {
+	var audits table.SettlementFailureAudits
+
+	if err := c.database.WithContext(ctx).Where("failure_id = ?", failureID).Order("id").Find(&audits).Error; err != nil {
+		zap.L().Error("find settlement failure audits", zap.Error(err), zap.Uint64("failure_id", failureID))
+
+		return nil, err
+	}
+
+	return audits.Export(), nil
+}
@@ -721,6 +721,22 @@ func (c *client) DeleteStakeEventsByBlockNumber(ctx context.Context, blockNumber
 		Error
 }
 
+// PurgeStakeTransactionsByBlockNumber deletes the stake transactions of a block, the finalized ones included.
+func (c *client) PurgeStakeTransactionsByBlockNumber(ctx context.Context, blockNumber uint64) error {
+	return c.database.
+		WithContext(ctx).
+		Delete(new(table.StakeTransaction), `"block_number" = ?`, blockNumber).
+		Error
+}
+
+// PurgeStakeEventsByBlockNumber deletes the stake events of a block, the finalized ones included.
+func (c *client) PurgeStakeEventsByBlockNumber(ctx context.Context, blockNumber uint64) error {
+	return c.database.
+		WithContext(ctx).
+		Delete(new(table.StakeEvent), `"block_number" = ?`, blockNumber).
+		Error
+}
+
 func (c *client) UpdateStakeTransactionsFinalizedByBlockNumber(ctx context.Context, blockNumber uint64) error {
 	return c.database.
 		WithContext(ctx).
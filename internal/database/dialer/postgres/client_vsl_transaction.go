@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/rss3-network/global-indexer/internal/database/dialer/postgres/table"
+	"github.com/rss3-network/global-indexer/schema"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// SaveVSLTransaction inserts a new transaction, or updates an existing one if its ID is set.
+func (c *client) SaveVSLTransaction(ctx context.Context, transaction *schema.VSLTransaction) error {
+	var data table.VSLTransaction
+
+	data.Import(transaction)
+
+	if err := c.database.WithContext(ctx).Save(&data).Error; err != nil {
+		zap.L().Error("save vsl transaction", zap.Error(err), zap.Uint64("nonce", transaction.Nonce))
+
+		return err
+	}
+
+	transaction.ID = data.ID
+	transaction.CreatedAt = data.CreatedAt
+	transaction.UpdatedAt = data.UpdatedAt
+
+	return nil
+}
+
+func (c *client) FindVSLTransactions(ctx context.Context, query schema.VSLTransactionsQuery) ([]*schema.VSLTransaction, error) {
+	databaseStatement := c.database.WithContext(ctx).Table((*table.VSLTransaction).TableName(nil))
+
+	if query.Cursor != nil {
+		databaseStatement = databaseStatement.Where("id < ?", *query.Cursor)
+	}
+
+	if query.From != nil {
+		databaseStatement = databaseStatement.Where(`"from" = ?`, query.From.String())
+	}
+
+	if len(query.Statuses) > 0 {
+		databaseStatement = databaseStatement.Where("status IN ?", query.Statuses)
+	}
+
+	if query.Input != nil {
+		databaseStatement = databaseStatement.Where("input = ?", query.Input)
+	}
+
+	if query.Limit != nil {
+		databaseStatement = databaseStatement.Limit(*query.Limit)
+	}
+
+	var transactions table.VSLTransactions
+
+	if err := databaseStatement.Order("id DESC").Find(&transactions).Error; err != nil {
+		zap.L().Error("find vsl transactions", zap.Error(err), zap.Any("query", query))
+
+		return nil, err
+	}
+
+	return transactions.Export(), nil
+}
+
+// SumVSLTransactionFees returns the total fee paid for the transactions sent from an address since a time.
+func (c *client) SumVSLTransactionFees(ctx context.Context, query schema.VSLTransactionFeesQuery) (*big.Int, error) {
+	var fees decimal.Decimal
+
+	if err := c.database.WithContext(ctx).
+		Table((*table.VSLTransaction).TableName(nil)).
+		Select("COALESCE(SUM(fee), 0)").
+		Where(`"from" = ? AND created_at >= ?`, query.From.String(), query.Since).
+		Row().
+		Scan(&fees); err != nil {
+		zap.L().Error("sum vsl transaction fees", zap.Error(err), zap.Any("query", query))
+
+		return nil, err
+	}
+
+	return fees.BigInt(), nil
+}
+
+func (c *client) SaveVSLTransactionSubmission(ctx context.Context, submission *schema.VSLTransactionSubmission) error {
+	var data table.VSLTransactionSubmission
+
+	data.Import(submission)
+
+	if err := c.database.WithContext(ctx).Create(&data).Error; err != nil {
+		zap.L().Error("insert vsl transaction submission", zap.Error(err), zap.Any("submission", submission))
+
+		return err
+	}
+
+	submission.ID = data.ID
+	submission.CreatedAt = data.CreatedAt
+
+	return nil
+}
+
+// FindVSLTransactionSubmissions returns the submissions of the transactions, in the order they were published.
+func (c *client) FindVSLTransactionSubmissions(ctx context.Context, transactionIDs []uint64) ([]*schema.VSLTransactionSubmission, error) {
+	var submissions table.VSLTransactionSubmissions
+
+	if err := c.database.WithContext(ctx).Where("transaction_id IN ?", transactionIDs).Order("id").Find(&submissions).Error; err != nil {
+		zap.L().Error("find vsl transaction submissions", zap.Error(err), zap.Uint64s("transaction_ids", transactionIDs))
+
+		return nil, err
+	}
+
+	return submissions.Export(), nil
+}
@@ -0,0 +1,39 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE IF NOT EXISTS "settlement_failure"
+(
+    id               bigserial                              NOT NULL,
+    service          text                                   NOT NULL,
+    epoch_id         bigint                                 NOT NULL,
+    transaction_hash text                                   NOT NULL,
+    input            bytea                                  NOT NULL,
+    data             jsonb,
+    attempts         bigint                                 NOT NULL,
+    status           text                                   NOT NULL,
+    created_at       timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at       timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT pk_settlement_failure PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_settlement_failure_service_status
+    ON settlement_failure (service, status);
+
+CREATE TABLE IF NOT EXISTS "settlement_failure_audit"
+(
+    id         bigserial                              NOT NULL,
+    failure_id bigint                                 NOT NULL,
+    action     text                                   NOT NULL,
+    operator   text                                   NOT NULL,
+    reason     text                                   NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT pk_settlement_failure_audit PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_settlement_failure_audit_failure_id
+    ON settlement_failure_audit (failure_id);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS "settlement_failure_audit";
+
+DROP TABLE IF EXISTS "settlement_failure";
@@ -0,0 +1,20 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE IF NOT EXISTS "settlement_batch"
+(
+    epoch_id         bigint                                 NOT NULL,
+    batch_index      bigint                                 NOT NULL,
+    cursor           text,
+    calldata_hash    text                                   NOT NULL,
+    block_number     bigint                                 NOT NULL,
+    transaction_hash text,
+    data             jsonb                                  NOT NULL,
+    status           text                                   NOT NULL,
+    created_at       timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at       timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT pk_settlement_batch PRIMARY KEY (epoch_id, batch_index)
+);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS "settlement_batch";
@@ -0,0 +1,47 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE IF NOT EXISTS "vsl_transaction"
+(
+    id               bigserial                              NOT NULL,
+    chain_id         bigint                                 NOT NULL,
+    "from"           text                                   NOT NULL,
+    "to"             text,
+    nonce            bigint                                 NOT NULL,
+    input            bytea                                  NOT NULL,
+    value            numeric                                NOT NULL,
+    gas_limit        bigint                                 NOT NULL,
+    gas_tip_cap      numeric                                NOT NULL,
+    gas_fee_cap      numeric                                NOT NULL,
+    transaction_hash text,
+    status           text                                   NOT NULL,
+    block_number     bigint,
+    gas_used         bigint,
+    error            text                                   NOT NULL DEFAULT '',
+    created_at       timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at       timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT pk_vsl_transaction PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_vsl_transaction_from_status
+    ON vsl_transaction ("from", status);
+
+CREATE TABLE IF NOT EXISTS "vsl_transaction_submission"
+(
+    id               bigserial                              NOT NULL,
+    transaction_id   bigint                                 NOT NULL,
+    transaction_hash text                                   NOT NULL,
+    nonce            bigint                                 NOT NULL,
+    gas_tip_cap      numeric                                NOT NULL,
+    gas_fee_cap      numeric                                NOT NULL,
+    created_at       timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT pk_vsl_transaction_submission PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_vsl_transaction_submission_transaction_id
+    ON vsl_transaction_submission (transaction_id);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS "vsl_transaction_submission";
+
+DROP TABLE IF EXISTS "vsl_transaction";
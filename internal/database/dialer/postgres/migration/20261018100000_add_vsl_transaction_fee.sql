@@ -0,0 +1,14 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE "vsl_transaction"
+    ADD COLUMN IF NOT EXISTS fee numeric;
+
+CREATE INDEX IF NOT EXISTS idx_vsl_transaction_from_created_at
+    ON vsl_transaction ("from", created_at);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP INDEX IF EXISTS idx_vsl_transaction_from_created_at;
+
+ALTER TABLE "vsl_transaction"
+    DROP COLUMN IF EXISTS fee;
@@ -0,0 +1,24 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE IF NOT EXISTS "cron_job_run"
+(
+    id         bigserial                NOT NULL,
+    job        text                     NOT NULL,
+    "trigger"  text                     NOT NULL,
+    status     text                     NOT NULL,
+    error      text                     NOT NULL,
+    started_at timestamp with time zone NOT NULL,
+    ended_at   timestamp with time zone,
+    duration   bigint                   NOT NULL,
+    CONSTRAINT pk_cron_job_run PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_cron_job_run_job
+    ON cron_job_run (job, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_cron_job_run_started_at
+    ON cron_job_run (started_at);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS "cron_job_run";
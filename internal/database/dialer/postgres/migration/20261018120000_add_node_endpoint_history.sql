@@ -0,0 +1,22 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE IF NOT EXISTS "node_endpoint_history"
+(
+    id                   bigserial                              NOT NULL,
+    address              bytea                                  NOT NULL,
+    endpoint             text                                   NOT NULL,
+    previous_endpoint    text                                   NOT NULL,
+    stream               jsonb,
+    config               jsonb,
+    access_token_changed boolean                                NOT NULL,
+    verified             boolean                                NOT NULL,
+    created_at           timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT pk_node_endpoint_history PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_node_endpoint_history_address
+    ON node_endpoint_history (address, id DESC);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS "node_endpoint_history";
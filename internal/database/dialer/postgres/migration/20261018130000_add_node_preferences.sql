@@ -0,0 +1,9 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE "node_info"
+    ADD COLUMN IF NOT EXISTS "preferences" jsonb;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE "node_info"
+    DROP COLUMN IF EXISTS "preferences";
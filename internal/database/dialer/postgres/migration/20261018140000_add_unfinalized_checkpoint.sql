@@ -0,0 +1,15 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE IF NOT EXISTS "unfinalized_checkpoints"
+(
+    chain_id     bigint                                 NOT NULL,
+    block_number bigint                                 NOT NULL,
+    block_hash   text                                   NOT NULL,
+    created_at   timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at   timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT pk_unfinalized_checkpoints PRIMARY KEY (chain_id, block_number)
+);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS "unfinalized_checkpoints";
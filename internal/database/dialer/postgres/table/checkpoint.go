@@ -42,3 +42,14 @@ func (c *Checkpoint) Export() (*schema.Checkpoint, error) {
 
 	return &checkpoint, nil
 }
+
+var _ gorm.Tabler = (*UnfinalizedCheckpoint)(nil)
+
+// UnfinalizedCheckpoint is a recent block indexed by the unfinalized indexer of a chain.
+type UnfinalizedCheckpoint struct {
+	Checkpoint
+}
+
+func (c *UnfinalizedCheckpoint) TableName() string {
+	return "unfinalized_checkpoints"
+}
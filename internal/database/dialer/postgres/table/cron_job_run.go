@@ -0,0 +1,58 @@
+package table
+
+import (
+	"time"
+
+	"github.com/rss3-network/global-indexer/schema"
+)
+
+type CronJobRun struct {
+	ID        uint64                   `gorm:"column:id;primaryKey"`
+	Job       string                   `gorm:"column:job"`
+	Trigger   schema.CronJobRunTrigger `gorm:"column:trigger"`
+	Status    schema.CronJobRunStatus  `gorm:"column:status"`
+	Error     string                   `gorm:"column:error"`
+	StartedAt time.Time                `gorm:"column:started_at"`
+	EndedAt   *time.Time               `gorm:"column:ended_at"`
+	Duration  int64                    `gorm:"column:duration"`
+}
+
+func (*CronJobRun) TableName() string {
+	return "cron_job_run"
+}
+
+func (c *CronJobRun) Import(run *schema.CronJobRun) {
+	c.ID = run.ID
+	c.Job = run.Job
+	c.Trigger = run.Trigger
+	c.Status = run.Status
+	c.Error = run.Error
+	c.StartedAt = run.StartedAt
+	c.EndedAt = run.EndedAt
+	c.Duration = run.Duration
+}
+
+func (c *CronJobRun) Export() *schema.CronJobRun {
+	return &schema.CronJobRun{
+		ID:        c.ID,
+		Job:       c.Job,
+		Trigger:   c.Trigger,
+		Status:    c.Status,
+		Error:     c.Error,
+		StartedAt: c.StartedAt,
+		EndedAt:   c.EndedAt,
+		Duration:  c.Duration,
+	}
+}
+
+type CronJobRuns []*CronJobRun
+
+func (c CronJobRuns) Export() []*schema.CronJobRun {
+	result := make([]*schema.CronJobRun, 0, len(c))
+
+	for _, run := range c {
+		result = append(result, run.Export())
+	}
+
+	return result
+}
@@ -28,6 +28,7 @@ type Node struct {
 	Version                string            `gorm:"column:version"`
 	Type                   string            `gorm:"column:type"`
 	AccessToken            string            `gorm:"column:access_token"`
+	Preferences            json.RawMessage   `gorm:"column:preferences;type:jsonb"`
 	CreatedAt              time.Time         `gorm:"column:created_at"`
 	UpdatedAt              time.Time         `gorm:"column:updated_at"`
 }
@@ -61,6 +62,13 @@ func (n *Node) Import(node *schema.Node) (err error) {
 		return fmt.Errorf("marshal node avatar: %w", err)
 	}
 
+	if node.Preferences != nil {
+		n.Preferences, err = json.Marshal(node.Preferences)
+		if err != nil {
+			return fmt.Errorf("marshal node preferences: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -76,6 +84,11 @@ func (n *Node) Export() (*schema.Node, error) {
 		return nil, fmt.Errorf("unmarshal node avatar: %w", err)
 	}
 
+	var preferences *schema.NodePreferences
+	if err := json.Unmarshal(n.Preferences, &preferences); len(n.Preferences) > 0 && err != nil {
+		return nil, fmt.Errorf("unmarshal node preferences: %w", err)
+	}
+
 	return &schema.Node{
 		Address:                n.Address,
 		ID:                     big.NewInt(int64(n.NodeID)),
@@ -93,6 +106,7 @@ func (n *Node) Export() (*schema.Node, error) {
 		Type:                   n.Type,
 		AccessToken:            n.AccessToken,
 		CreatedAt:              n.CreatedAt.Unix(),
+		Preferences:            preferences,
 	}, nil
 }
 
@@ -0,0 +1,63 @@
+package table
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rss3-network/global-indexer/schema"
+)
+
+type NodeEndpointHistory struct {
+	ID                 uint64          `gorm:"column:id;primaryKey"`
+	Address            common.Address  `gorm:"column:address"`
+	Endpoint           string          `gorm:"column:endpoint"`
+	PreviousEndpoint   string          `gorm:"column:previous_endpoint"`
+	Stream             json.RawMessage `gorm:"column:stream;type:jsonb"`
+	Config             json.RawMessage `gorm:"column:config;type:jsonb"`
+	AccessTokenChanged bool            `gorm:"column:access_token_changed"`
+	Verified           bool            `gorm:"column:verified"`
+	CreatedAt          time.Time       `gorm:"column:created_at"`
+}
+
+func (*NodeEndpointHistory) TableName() string {
+	return "node_endpoint_history"
+}
+
+func (n *NodeEndpointHistory) Import(history *schema.NodeEndpointHistory) {
+	n.ID = history.ID
+	n.Address = history.Address
+	n.Endpoint = history.Endpoint
+	n.PreviousEndpoint = history.PreviousEndpoint
+	n.Stream = history.Stream
+	n.Config = history.Config
+	n.AccessTokenChanged = history.AccessTokenChanged
+	n.Verified = history.Verified
+	n.CreatedAt = history.CreatedAt
+}
+
+func (n *NodeEndpointHistory) Export() *schema.NodeEndpointHistory {
+	return &schema.NodeEndpointHistory{
+		ID:                 n.ID,
+		Address:            n.Address,
+		Endpoint:           n.Endpoint,
+		PreviousEndpoint:   n.PreviousEndpoint,
+		Stream:             n.Stream,
+		Config:             n.Config,
+		AccessTokenChanged: n.AccessTokenChanged,
+		Verified:           n.Verified,
+		CreatedAt:          n.CreatedAt,
+	}
+}
+
+type NodeEndpointHistories []*NodeEndpointHistory
+
+func (n NodeEndpointHistories) Export() []*schema.NodeEndpointHistory {
+	result := make([]*schema.NodeEndpointHistory, 0, len(n))
+
+	for _, history := range n {
+		result = append(result, history.Export())
+	}
+
+	return result
+}
@@ -0,0 +1,86 @@
+package table
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rss3-network/global-indexer/schema"
+	"github.com/samber/lo"
+)
+
+type SettlementBatch struct {
+	EpochID         uint64                       `gorm:"column:epoch_id;primaryKey"`
+	Index           uint64                       `gorm:"column:batch_index;primaryKey"`
+	Cursor          *string                      `gorm:"column:cursor"`
+	CalldataHash    string                       `gorm:"column:calldata_hash"`
+	BlockNumber     uint64                       `gorm:"column:block_number"`
+	TransactionHash *string                      `gorm:"column:transaction_hash"`
+	Data            json.RawMessage              `gorm:"column:data;type:jsonb"`
+	Status          schema.SettlementBatchStatus `gorm:"column:status"`
+	CreatedAt       time.Time                    `gorm:"column:created_at"`
+	UpdatedAt       time.Time                    `gorm:"column:updated_at"`
+}
+
+func (*SettlementBatch) TableName() string {
+	return "settlement_batch"
+}
+
+func (s *SettlementBatch) Import(batch *schema.SettlementBatch) (err error) {
+	s.EpochID = batch.EpochID
+	s.Index = batch.Index
+	s.Cursor = batch.Cursor
+	s.CalldataHash = batch.CalldataHash.String()
+	s.BlockNumber = batch.BlockNumber
+	s.Status = batch.Status
+	s.CreatedAt = batch.CreatedAt
+	s.UpdatedAt = batch.UpdatedAt
+
+	if batch.TransactionHash != nil {
+		s.TransactionHash = lo.ToPtr(batch.TransactionHash.String())
+	}
+
+	s.Data, err = json.Marshal(batch.Data)
+
+	return err
+}
+
+func (s *SettlementBatch) Export() (*schema.SettlementBatch, error) {
+	batch := schema.SettlementBatch{
+		EpochID:      s.EpochID,
+		Index:        s.Index,
+		Cursor:       s.Cursor,
+		CalldataHash: common.HexToHash(s.CalldataHash),
+		BlockNumber:  s.BlockNumber,
+		Status:       s.Status,
+		CreatedAt:    s.CreatedAt,
+		UpdatedAt:    s.UpdatedAt,
+	}
+
+	if s.TransactionHash != nil {
+		batch.TransactionHash = lo.ToPtr(common.HexToHash(*s.TransactionHash))
+	}
+
+	if err := json.Unmarshal(s.Data, &batch.Data); err != nil {
+		return nil, err
+	}
+
+	return &batch, nil
+}
+
+type SettlementBatches []*SettlementBatch
+
+func (s SettlementBatches) Export() ([]*schema.SettlementBatch, error) {
+	result := make([]*schema.SettlementBatch, 0, len(s))
+
+	for _, batch := range s {
+		exported, err := batch.Export()
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, exported)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,128 @@
+package table
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rss3-network/global-indexer/schema"
+)
+
+type SettlementFailure struct {
+	ID              uint64                         `gorm:"column:id;primaryKey"`
+	Service         string                         `gorm:"column:service"`
+	EpochID         uint64                         `gorm:"column:epoch_id"`
+	TransactionHash string                         `gorm:"column:transaction_hash"`
+	Input           []byte                         `gorm:"column:input"`
+	Data            json.RawMessage                `gorm:"column:data;type:jsonb"`
+	Attempts        uint64                         `gorm:"column:attempts"`
+	Status          schema.SettlementFailureStatus `gorm:"column:status"`
+	CreatedAt       time.Time                      `gorm:"column:created_at"`
+	UpdatedAt       time.Time                      `gorm:"column:updated_at"`
+}
+
+func (*SettlementFailure) TableName() string {
+	return "settlement_failure"
+}
+
+func (s *SettlementFailure) Import(failure *schema.SettlementFailure) (err error) {
+	s.ID = failure.ID
+	s.Service = failure.Service
+	s.EpochID = failure.EpochID
+	s.TransactionHash = failure.TransactionHash.String()
+	s.Input = failure.Input
+	s.Attempts = failure.Attempts
+	s.Status = failure.Status
+	s.CreatedAt = failure.CreatedAt
+	s.UpdatedAt = failure.UpdatedAt
+
+	if failure.Data != nil {
+		s.Data, err = json.Marshal(failure.Data)
+	}
+
+	return err
+}
+
+func (s *SettlementFailure) Export() (*schema.SettlementFailure, error) {
+	failure := schema.SettlementFailure{
+		ID:              s.ID,
+		Service:         s.Service,
+		EpochID:         s.EpochID,
+		TransactionHash: common.HexToHash(s.TransactionHash),
+		Input:           s.Input,
+		Attempts:        s.Attempts,
+		Status:          s.Status,
+		CreatedAt:       s.CreatedAt,
+		UpdatedAt:       s.UpdatedAt,
+	}
+
+	if len(s.Data) > 0 && string(s.Data) != "null" {
+		if err := json.Unmarshal(s.Data, &failure.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	return &failure, nil
+}
+
+type SettlementFailures []*SettlementFailure
+
+func (s SettlementFailures) Export() ([]*schema.SettlementFailure, error) {
+	result := make([]*schema.SettlementFailure, 0, len(s))
+
+	for _, failure := range s {
+		exported, err := failure.Export()
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, exported)
+	}
+
+	return result, nil
+}
+
+type SettlementFailureAudit struct {
+	ID        uint64                         `gorm:"column:id;primaryKey"`
+	FailureID uint64                         `gorm:"column:failure_id"`
+	Action    schema.SettlementFailureAction `gorm:"column:action"`
+	Operator  string                         `gorm:"column:operator"`
+	Reason    string                         `gorm:"column:reason"`
+	CreatedAt time.Time                      `gorm:"column:created_at"`
+}
+
+func (*SettlementFailureAudit) TableName() string {
+	return "settlement_failure_audit"
+}
+
+func (s *SettlementFailureAudit) Import(audit *schema.SettlementFailureAudit) {
+	s.ID = audit.ID
+	s.FailureID = audit.FailureID
+	s.Action = audit.Action
+	s.Operator = audit.Operator
+	s.Reason = audit.Reason
+	s.CreatedAt = audit.CreatedAt
+}
+
+func (s *SettlementFailureAudit) Export() *schema.SettlementFailureAudit {
+	return &schema.SettlementFailureAudit{
+		ID:        s.ID,
+		FailureID: s.FailureID,
+		Action:    s.Action,
+		Operator:  s.Operator,
+		Reason:    s.Reason,
+		CreatedAt: s.CreatedAt,
+	}
+}
+
+type SettlementFailureAudits []*SettlementFailureAudit
+
+func (s SettlementFailureAudits) Export() []*schema.SettlementFailureAudit {
+	result := make([]*schema.SettlementFailureAudit, 0, len(s))
+
+	for _, audit := range s {
+		result = append(result, audit.Export())
+	}
+
+	return result
+}
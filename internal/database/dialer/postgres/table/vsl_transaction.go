@@ -0,0 +1,163 @@
+package table
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rss3-network/global-indexer/schema"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+)
+
+type VSLTransaction struct {
+	ID              uint64                      `gorm:"column:id;primaryKey"`
+	ChainID         uint64                      `gorm:"column:chain_id"`
+	From            string                      `gorm:"column:from"`
+	To              *string                     `gorm:"column:to"`
+	Nonce           uint64                      `gorm:"column:nonce"`
+	Input           []byte                      `gorm:"column:input"`
+	Value           decimal.Decimal             `gorm:"column:value"`
+	GasLimit        uint64                      `gorm:"column:gas_limit"`
+	GasTipCap       decimal.Decimal             `gorm:"column:gas_tip_cap"`
+	GasFeeCap       decimal.Decimal             `gorm:"column:gas_fee_cap"`
+	TransactionHash *string                     `gorm:"column:transaction_hash"`
+	Status          schema.VSLTransactionStatus `gorm:"column:status"`
+	BlockNumber     *uint64                     `gorm:"column:block_number"`
+	GasUsed         *uint64                     `gorm:"column:gas_used"`
+	Fee             *decimal.Decimal            `gorm:"column:fee"`
+	Error           string                      `gorm:"column:error"`
+	CreatedAt       time.Time                   `gorm:"column:created_at"`
+	UpdatedAt       time.Time                   `gorm:"column:updated_at"`
+}
+
+func (*VSLTransaction) TableName() string {
+	return "vsl_transaction"
+}
+
+func (v *VSLTransaction) Import(transaction *schema.VSLTransaction) {
+	v.ID = transaction.ID
+	v.ChainID = transaction.ChainID
+	v.From = transaction.From.String()
+	v.Nonce = transaction.Nonce
+	v.Input = transaction.Input
+	v.GasLimit = transaction.GasLimit
+	v.GasTipCap = decimal.NewFromBigInt(transaction.GasTipCap, 0)
+	v.GasFeeCap = decimal.NewFromBigInt(transaction.GasFeeCap, 0)
+	v.Status = transaction.Status
+	v.BlockNumber = transaction.BlockNumber
+	v.GasUsed = transaction.GasUsed
+	v.Error = transaction.Error
+	v.CreatedAt = transaction.CreatedAt
+	v.UpdatedAt = transaction.UpdatedAt
+
+	// The value of a transaction candidate is optional.
+	if transaction.Value != nil {
+		v.Value = decimal.NewFromBigInt(transaction.Value, 0)
+	}
+
+	if transaction.To != nil {
+		v.To = lo.ToPtr(transaction.To.String())
+	}
+
+	if transaction.Fee != nil {
+		v.Fee = lo.ToPtr(decimal.NewFromBigInt(transaction.Fee, 0))
+	}
+
+	if transaction.TransactionHash != nil {
+		v.TransactionHash = lo.ToPtr(transaction.TransactionHash.String())
+	}
+}
+
+func (v *VSLTransaction) Export() *schema.VSLTransaction {
+	transaction := schema.VSLTransaction{
+		ID:          v.ID,
+		ChainID:     v.ChainID,
+		From:        common.HexToAddress(v.From),
+		Nonce:       v.Nonce,
+		Input:       v.Input,
+		Value:       v.Value.BigInt(),
+		GasLimit:    v.GasLimit,
+		GasTipCap:   v.GasTipCap.BigInt(),
+		GasFeeCap:   v.GasFeeCap.BigInt(),
+		Status:      v.Status,
+		BlockNumber: v.BlockNumber,
+		GasUsed:     v.GasUsed,
+		Error:       v.Error,
+		CreatedAt:   v.CreatedAt,
+		UpdatedAt:   v.UpdatedAt,
+	}
+
+	if v.To != nil {
+		transaction.To = lo.ToPtr(common.HexToAddress(*v.To))
+	}
+
+	if v.Fee != nil {
+		transaction.Fee = v.Fee.BigInt()
+	}
+
+	if v.TransactionHash != nil {
+		transaction.TransactionHash = lo.ToPtr(common.HexToHash(*v.TransactionHash))
+	}
+
+	return &transaction
+}
+
+type VSLTransactions []*VSLTransaction
+
+func (v VSLTransactions) Export() []*schema.VSLTransaction {
+	result := make([]*schema.VSLTransaction, 0, len(v))
+
+	for _, transaction := range v {
+		result = append(result, transaction.Export())
+	}
+
+	return result
+}
+
+type VSLTransactionSubmission struct {
+	ID              uint64          `gorm:"column:id;primaryKey"`
+	TransactionID   uint64          `gorm:"column:transaction_id"`
+	TransactionHash string          `gorm:"column:transaction_hash"`
+	Nonce           uint64          `gorm:"column:nonce"`
+	GasTipCap       decimal.Decimal `gorm:"column:gas_tip_cap"`
+	GasFeeCap       decimal.Decimal `gorm:"column:gas_fee_cap"`
+	CreatedAt       time.Time       `gorm:"column:created_at"`
+}
+
+func (*VSLTransactionSubmission) TableName() string {
+	return "vsl_transaction_submission"
+}
+
+func (v *VSLTransactionSubmission) Import(submission *schema.VSLTransactionSubmission) {
+	v.ID = submission.ID
+	v.TransactionID = submission.TransactionID
+	v.TransactionHash = submission.TransactionHash.String()
+	v.Nonce = submission.Nonce
+	v.GasTipCap = decimal.NewFromBigInt(submission.GasTipCap, 0)
+	v.GasFeeCap = decimal.NewFromBigInt(submission.GasFeeCap, 0)
+	v.CreatedAt = submission.CreatedAt
+}
+
+func (v *VSLTransactionSubmission) Export() *schema.VSLTransactionSubmission {
+	return &schema.VSLTransactionSubmission{
+		ID:              v.ID,
+		TransactionID:   v.TransactionID,
+		TransactionHash: common.HexToHash(v.TransactionHash),
+		Nonce:           v.Nonce,
+		GasTipCap:       v.GasTipCap.BigInt(),
+		GasFeeCap:       v.GasFeeCap.BigInt(),
+		CreatedAt:       v.CreatedAt,
+	}
+}
+
+type VSLTransactionSubmissions []*VSLTransactionSubmission
+
+func (v VSLTransactionSubmissions) Export() []*schema.VSLTransactionSubmission {
+	result := make([]*schema.VSLTransactionSubmission, 0, len(v))
+
+	for _, submission := range v {
+		result = append(result, submission.Export())
+	}
+
+	return result
+}
@@ -0,0 +1,325 @@
+package nameresolver_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/redis/go-redis/v9"
+	"github.com/rss3-network/global-indexer/internal/cache"
+	"github.com/rss3-network/global-indexer/internal/config"
+	"github.com/rss3-network/global-indexer/internal/nameresolver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	addressVitalik = common.HexToAddress("0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045")
+	addressJesse   = common.HexToAddress("0x849151d7D0bF1F34b70d5caD5149D28CC2308bf1")
+)
+
+// memoryCacheClient serves Get and Set from memory, and records the expiration of the keys.
+type memoryCacheClient struct {
+	cache.Client
+
+	locker      sync.Mutex
+	values      map[string][]byte
+	expirations map[string]time.Duration
+}
+
+func (c *memoryCacheClient) Get(_ context.Context, key string, dest interface{}) error {
+	c.locker.Lock()
+	defer c.locker.Unlock()
+
+	data, exists := c.values[key]
+	if !exists {
+		return redis.Nil
+	}
+
+	return json.Unmarshal(data, dest)
+}
+
+func (c *memoryCacheClient) Set(_ context.Context, key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	c.locker.Lock()
+	defer c.locker.Unlock()
+
+	c.values[key] = data
+	c.expirations[key] = expiration
+
+	return nil
+}
+
+func newMemoryCacheClient() *memoryCacheClient {
+	return &memoryCacheClient{
+		values:      make(map[string][]byte),
+		expirations: make(map[string]time.Duration),
+	}
+}
+
+// failingResolver fails all the resolutions.
+type failingResolver struct {
+	nameService nameresolver.NameService
+}
+
+func (r *failingResolver) NameService() nameresolver.NameService {
+	return r.nameService
+}
+
+func (r *failingResolver) Suffixes() []string {
+	return []string{r.nameService.String()}
+}
+
+func (r *failingResolver) Resolve(context.Context, string) (string, error) {
+	return "", errors.New("connection refused")
+}
+
+func (r *failingResolver) ReverseResolve(context.Context, common.Address) (string, error) {
+	return "", errors.New("connection refused")
+}
+
+func TestNameResolver_Resolve(t *testing.T) {
+	t.Parallel()
+
+	ensResolver := nameresolver.NewStaticResolver(nameresolver.NameServiceENS, []string{"eth"}, map[string]common.Address{
+		"vitalik.eth": addressVitalik,
+	})
+
+	basenamesResolver := nameresolver.NewStaticResolver(nameresolver.NameServiceBasenames, []string{"base.eth"}, map[string]common.Address{
+		"jesse.base.eth": addressJesse,
+	})
+
+	nameResolver, err := nameresolver.New(nil, nil, ensResolver, basenamesResolver)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+		err      error
+	}{
+		{
+			name:     "ENS",
+			input:    "vitalik.eth",
+			expected: addressVitalik.String(),
+		},
+		{
+			name:     "ENSCaseInsensitive",
+			input:    "Vitalik.ETH",
+			expected: addressVitalik.String(),
+		},
+		{
+			name:     "LongestSuffix",
+			input:    "jesse.base.eth",
+			expected: addressJesse.String(),
+		},
+		{
+			name:  "Unregistered",
+			input: "qwerfdsazxcv.eth",
+			err:   nameresolver.ErrorUnregisteredName,
+		},
+		{
+			name:  "UnsupportedSuffix",
+			input: "vitalik.sol",
+			err:   nameresolver.ErrorUnsupportedName,
+		},
+		{
+			name:  "SuffixOnly",
+			input: "eth",
+			err:   nameresolver.ErrorUnsupportedName,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			address, err := nameResolver.Resolve(context.Background(), tc.input)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, address)
+		})
+	}
+}
+
+func TestNameResolver_Register(t *testing.T) {
+	t.Parallel()
+
+	nameResolver, err := nameresolver.New(nil, nil, nameresolver.NewStaticResolver(nameresolver.NameServiceENS, []string{"eth"}, nil))
+	require.NoError(t, err)
+
+	err = nameResolver.Register(nameresolver.NewStaticResolver(nameresolver.NameServiceBasenames, []string{"base.eth", "ETH"}, map[string]common.Address{
+		"jesse.base.eth": addressJesse,
+	}))
+	require.Error(t, err)
+
+	// The resolver is not registered for any of its suffixes if one of them is taken,
+	// so the name is still resolved by the resolver of ENS.
+	address, err := nameResolver.Resolve(context.Background(), "jesse.base.eth")
+	require.ErrorIs(t, err, nameresolver.ErrorUnregisteredName)
+	assert.Empty(t, address)
+}
+
+func TestNameResolver_Cache(t *testing.T) {
+	t.Parallel()
+
+	cacheClient := newMemoryCacheClient()
+	cacheConfig := &config.NameResolver{
+		TTL:         10 * time.Minute,
+		NegativeTTL: time.Minute,
+	}
+
+	ensResolver := nameresolver.NewStaticResolver(nameresolver.NameServiceENS, []string{"eth"}, map[string]common.Address{
+		"vitalik.eth": addressVitalik,
+	})
+
+	nameResolver, err := nameresolver.New(cacheClient, cacheConfig, ensResolver)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		address, err := nameResolver.Resolve(context.Background(), "vitalik.eth")
+		require.NoError(t, err)
+		assert.Equal(t, addressVitalik.String(), address)
+
+		_, err = nameResolver.Resolve(context.Background(), "qwerfdsazxcv.eth")
+		require.ErrorIs(t, err, nameresolver.ErrorUnregisteredName)
+
+		primaryNames, err := nameResolver.ReverseResolve(context.Background(), addressVitalik)
+		require.NoError(t, err)
+		assert.Len(t, primaryNames, 1)
+	}
+
+	// Only the first round is resolved by the resolver, the rest are served from the cache.
+	assert.Equal(t, int64(3), ensResolver.Resolutions())
+
+	assert.Equal(t, cacheConfig.TTL, cacheClient.expirations["name:service:vitalik.eth"])
+	assert.Equal(t, cacheConfig.NegativeTTL, cacheClient.expirations["name:service:qwerfdsazxcv.eth"])
+	assert.Equal(t, cacheConfig.TTL, cacheClient.expirations["name:reverse:eth:0xd8da6bf26964af9d7eed9e03e53415d37aa96045"])
+}
+
+func TestNameResolver_CacheDisabled(t *testing.T) {
+	t.Parallel()
+
+	cacheClient := newMemoryCacheClient()
+
+	ensResolver := nameresolver.NewStaticResolver(nameresolver.NameServiceENS, []string{"eth"}, map[string]common.Address{
+		"vitalik.eth": addressVitalik,
+	})
+
+	nameResolver, err := nameresolver.New(cacheClient, &config.NameResolver{}, ensResolver)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err := nameResolver.Resolve(context.Background(), "vitalik.eth")
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int64(2), ensResolver.Resolutions())
+	assert.Empty(t, cacheClient.values)
+}
+
+func TestNameResolver_ReverseResolve(t *testing.T) {
+	t.Parallel()
+
+	ensResolver := nameresolver.NewStaticResolver(nameresolver.NameServiceENS, []string{"eth"}, map[string]common.Address{
+		"vitalik.eth":     addressVitalik,
+		"buterin.eth":     addressVitalik,
+		"jessepollak.eth": addressJesse,
+	})
+
+	basenamesResolver := nameresolver.NewStaticResolver(nameresolver.NameServiceBasenames, []string{"base.eth"}, map[string]common.Address{
+		"jesse.base.eth": addressJesse,
+	})
+
+	testCases := []struct {
+		name         string
+		resolvers    []nameresolver.Resolver
+		address      common.Address
+		nameServices []nameresolver.NameService
+		expected     []*nameresolver.PrimaryName
+		err          bool
+	}{
+		{
+			name:      "AllNameServices",
+			resolvers: []nameresolver.Resolver{ensResolver, basenamesResolver},
+			address:   addressJesse,
+			expected: []*nameresolver.PrimaryName{
+				{NameService: nameresolver.NameServiceENS, Name: "jessepollak.eth"},
+				{NameService: nameresolver.NameServiceBasenames, Name: "jesse.base.eth"},
+			},
+		},
+		{
+			name:         "GivenNameServices",
+			resolvers:    []nameresolver.Resolver{ensResolver, basenamesResolver},
+			address:      addressJesse,
+			nameServices: []nameresolver.NameService{nameresolver.NameServiceBasenames},
+			expected: []*nameresolver.PrimaryName{
+				{NameService: nameresolver.NameServiceBasenames, Name: "jesse.base.eth"},
+			},
+		},
+		{
+			name:      "LexicallyFirstName",
+			resolvers: []nameresolver.Resolver{ensResolver, basenamesResolver},
+			address:   addressVitalik,
+			expected: []*nameresolver.PrimaryName{
+				{NameService: nameresolver.NameServiceENS, Name: "buterin.eth"},
+			},
+		},
+		{
+			name:      "NoPrimaryName",
+			resolvers: []nameresolver.Resolver{ensResolver, basenamesResolver},
+			address:   common.HexToAddress("0x0000000000000000000000000000000000000001"),
+			expected:  []*nameresolver.PrimaryName{},
+		},
+		{
+			name:      "PartialFailure",
+			resolvers: []nameresolver.Resolver{ensResolver, &failingResolver{nameService: nameresolver.NameServiceUnstoppableDomains}},
+			address:   addressVitalik,
+			expected: []*nameresolver.PrimaryName{
+				{NameService: nameresolver.NameServiceENS, Name: "buterin.eth"},
+			},
+		},
+		{
+			name:      "Failure",
+			resolvers: []nameresolver.Resolver{basenamesResolver, &failingResolver{nameService: nameresolver.NameServiceUnstoppableDomains}},
+			address:   addressVitalik,
+			err:       true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			nameResolver, err := nameresolver.New(nil, nil, tc.resolvers...)
+			require.NoError(t, err)
+
+			primaryNames, err := nameResolver.ReverseResolve(context.Background(), tc.address, tc.nameServices...)
+			if tc.err {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, primaryNames)
+		})
+	}
+}
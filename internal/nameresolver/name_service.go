@@ -4,9 +4,12 @@ package nameresolver
 type NameService int
 
 const (
-	NameServiceUnknown   NameService = iota // unknown
-	NameServiceENS                          // eth
-	NameServiceCSB                          // csb
-	NameServiceLens                         // lens
-	NameServiceFarcaster                    // fc
+	NameServiceUnknown            NameService = iota // unknown
+	NameServiceENS                                   // eth
+	NameServiceCSB                                   // csb
+	NameServiceLens                                  // lens
+	NameServiceFarcaster                             // fc
+	NameServiceSpaceID                               // bnb
+	NameServiceBasenames                             // base.eth
+	NameServiceUnstoppableDomains                    // ud
 )
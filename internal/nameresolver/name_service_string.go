@@ -9,11 +9,11 @@ import (
 	"strings"
 )
 
-const _NameServiceName = "unknownethcsblensfc"
+const _NameServiceName = "unknownethcsblensfcbnbbase.ethud"
 
-var _NameServiceIndex = [...]uint8{0, 7, 10, 13, 17, 19}
+var _NameServiceIndex = [...]uint8{0, 7, 10, 13, 17, 19, 22, 30, 32}
 
-const _NameServiceLowerName = "unknownethcsblensfc"
+const _NameServiceLowerName = "unknownethcsblensfcbnbbase.ethud"
 
 func (i NameService) String() string {
 	if i < 0 || i >= NameService(len(_NameServiceIndex)-1) {
@@ -35,9 +35,12 @@ func _NameServiceNoOp() {
 	_ = x[NameServiceCSB-(2)]
 	_ = x[NameServiceLens-(3)]
 	_ = x[NameServiceFarcaster-(4)]
+	_ = x[NameServiceSpaceID-(5)]
+	_ = x[NameServiceBasenames-(6)]
+	_ = x[NameServiceUnstoppableDomains-(7)]
 }
 
-var _NameServiceValues = []NameService{NameServiceUnknown, NameServiceENS, NameServiceCSB, NameServiceLens, NameServiceFarcaster}
+var _NameServiceValues = []NameService{NameServiceUnknown, NameServiceENS, NameServiceCSB, NameServiceLens, NameServiceFarcaster, NameServiceSpaceID, NameServiceBasenames, NameServiceUnstoppableDomains}
 
 var _NameServiceNameToValueMap = map[string]NameService{
 	_NameServiceName[0:7]:        NameServiceUnknown,
@@ -50,6 +53,12 @@ var _NameServiceNameToValueMap = map[string]NameService{
 	_NameServiceLowerName[13:17]: NameServiceLens,
 	_NameServiceName[17:19]:      NameServiceFarcaster,
 	_NameServiceLowerName[17:19]: NameServiceFarcaster,
+	_NameServiceName[19:22]:      NameServiceSpaceID,
+	_NameServiceLowerName[19:22]: NameServiceSpaceID,
+	_NameServiceName[22:30]:      NameServiceBasenames,
+	_NameServiceLowerName[22:30]: NameServiceBasenames,
+	_NameServiceName[30:32]:      NameServiceUnstoppableDomains,
+	_NameServiceLowerName[30:32]: NameServiceUnstoppableDomains,
 }
 
 var _NameServiceNames = []string{
@@ -58,6 +67,9 @@ var _NameServiceNames = []string{
 	_NameServiceName[10:13],
 	_NameServiceName[13:17],
 	_NameServiceName[17:19],
+	_NameServiceName[19:22],
+	_NameServiceName[22:30],
+	_NameServiceName[30:32],
 }
 
 // NameServiceString retrieves an enum value from the enum constants string name.
@@ -2,20 +2,18 @@ package nameresolver
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"net/url"
 	"strings"
-	"time"
+	"sync"
 
-	"github.com/avast/retry-go/v4"
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
-	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/rss3-network/global-indexer/contract/crossbell"
-	"github.com/rss3-network/global-indexer/contract/lens"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/redis/go-redis/v9"
+	"github.com/rss3-network/global-indexer/internal/cache"
 	"github.com/rss3-network/global-indexer/internal/config"
-	goens "github.com/wealdtech/go-ens/v3"
+	"github.com/samber/lo"
+	"github.com/sourcegraph/conc/pool"
 	"go.uber.org/zap"
 )
 
@@ -24,217 +22,298 @@ const (
 	ErrUnSupportName  = "unsupport name service resolution"
 )
 
-type NameResolver struct {
-	ensEthClient       *ethclient.Client
-	csbHandleContract  *crossbell.Character
-	lensHandleContract *lens.LensHandle
-	fcClient           *fcClient
+var (
+	// ErrorUnregisteredName is returned if the name, or the primary name of the address, is not registered in the name service.
+	ErrorUnregisteredName = errors.New(ErrUnregisterName)
+	// ErrorUnsupportedName is returned if no resolver is registered for the suffix of the name.
+	ErrorUnsupportedName = errors.New(ErrUnSupportName)
+)
+
+// Resolver resolves the names of a name service.
+type Resolver interface {
+	// NameService returns the name service of the resolver.
+	NameService() NameService
+	// Suffixes returns the suffixes of the names resolved by the resolver, such as "eth" or "base.eth".
+	Suffixes() []string
+	// Resolve returns the address of the name, or ErrorUnregisteredName if the name is not registered.
+	Resolve(ctx context.Context, name string) (string, error)
 }
 
-type fcClient struct {
-	endpointURL *url.URL
-	httpClient  *http.Client
+// ReverseResolver is a Resolver which also resolves the primary names of the addresses.
+type ReverseResolver interface {
+	Resolver
+	// ReverseResolve returns the primary name of the address, or ErrorUnregisteredName if the address has none.
+	ReverseResolve(ctx context.Context, address common.Address) (string, error)
 }
 
-func (n *NameResolver) Resolve(ctx context.Context, input string) (string, error) {
-	splits := strings.Split(input, ".")
+// PrimaryName is the primary name of an address in a name service.
+type PrimaryName struct {
+	NameService NameService `json:"name_service"`
+	Name        string      `json:"name"`
+}
 
-	var (
-		address string
-		err     error
-	)
+// NameResolver resolves the names by the resolvers registered for their suffixes,
+// the resolutions are cached if a cache client is set.
+type NameResolver struct {
+	locker           sync.RWMutex
+	resolvers        map[string]Resolver
+	reverseResolvers []ReverseResolver
+
+	cacheClient cache.Client
+	cacheConfig *config.NameResolver
+}
 
-	suffix := splits[len(splits)-1]
+// Register registers the resolver for its suffixes, a suffix can only be registered once.
+func (n *NameResolver) Register(resolver Resolver) error {
+	n.locker.Lock()
+	defer n.locker.Unlock()
 
-	switch {
-	case suffix == NameServiceENS.String() && n.ensEthClient != nil:
-		address, err = n.resolveENS(ctx, input)
-	case suffix == NameServiceCSB.String() && n.csbHandleContract != nil:
-		address, err = n.resolveCSB(ctx, input)
-	case suffix == NameServiceLens.String() && n.lensHandleContract != nil:
-		address, err = n.resolveLens(ctx, input)
-	case suffix == NameServiceFarcaster.String() && n.fcClient != nil:
-		address, err = n.resolveFarcaster(ctx, input)
-	default:
-		err = fmt.Errorf("%s:%s", ErrUnSupportName, input)
+	for _, suffix := range resolver.Suffixes() {
+		if registered, exists := n.resolvers[strings.ToLower(suffix)]; exists {
+			return fmt.Errorf("suffix %s is already registered by %s", suffix, registered.NameService())
+		}
 	}
 
-	return address, err
-}
-
-func (n *NameResolver) resolveCSB(_ context.Context, domain string) (string, error) {
-	cData, err := n.csbHandleContract.GetCharacterByHandle(&bind.CallOpts{}, strings.TrimSuffix(domain, ".csb"))
-	if err != nil {
-		return "", fmt.Errorf("failed to get crossbell character by handle: %w", err)
+	for _, suffix := range resolver.Suffixes() {
+		n.resolvers[strings.ToLower(suffix)] = resolver
 	}
 
-	characterOwner, err := n.csbHandleContract.OwnerOf(&bind.CallOpts{}, cData.CharacterId)
-	if err != nil {
-		return "", fmt.Errorf("%s", ErrUnregisterName)
+	if reverseResolver, ok := resolver.(ReverseResolver); ok {
+		n.reverseResolvers = append(n.reverseResolvers, reverseResolver)
 	}
 
-	return characterOwner.String(), nil
+	return nil
 }
 
-func (n *NameResolver) resolveENS(_ context.Context, domain string) (string, error) {
-	address, err := goens.Resolve(n.ensEthClient, domain)
-	if err != nil {
-		return "", err
+// Resolve returns the address of the name, by the resolver registered for the longest suffix of the name.
+func (n *NameResolver) Resolve(ctx context.Context, input string) (string, error) {
+	resolver := n.lookup(input)
+	if resolver == nil {
+		return "", fmt.Errorf("%w:%s", ErrorUnsupportedName, input)
 	}
 
-	return address.String(), nil
+	return n.cached(ctx, buildNameKey(input), func() (string, error) {
+		return resolver.Resolve(ctx, input)
+	})
 }
 
-func (n *NameResolver) resolveLens(_ context.Context, domain string) (string, error) {
-	label := strings.Split(domain, "."+NameServiceLens.String())[0]
-	tokenID, err := n.lensHandleContract.GetTokenId(&bind.CallOpts{}, label)
+// ReverseResolve returns the primary names of the address in the name services, all the name services are queried if none is given.
+// The name services in which the address has no primary name are left out.
+func (n *NameResolver) ReverseResolve(ctx context.Context, address common.Address, nameServices ...NameService) ([]*PrimaryName, error) {
+	n.locker.RLock()
 
-	if err != nil {
-		return "", fmt.Errorf("failed to get lens token id by handle: %w", err)
-	}
+	reverseResolvers := lo.Filter(n.reverseResolvers, func(reverseResolver ReverseResolver, _ int) bool {
+		return len(nameServices) == 0 || lo.Contains(nameServices, reverseResolver.NameService())
+	})
 
-	owner, err := n.lensHandleContract.OwnerOf(&bind.CallOpts{}, tokenID)
-	if err != nil {
-		return "", fmt.Errorf("%s", ErrUnregisterName)
-	}
+	n.locker.RUnlock()
 
-	return owner.String(), nil
-}
+	primaryNames := make([]*PrimaryName, len(reverseResolvers))
 
-type UserNameProof struct {
-	Timestamp uint32 `json:"timestamp"`
-	Name      string `json:"name"`
-	Owner     string `json:"owner"`
-	Signature string `json:"signature"`
-	Fid       uint64 `json:"fid"`
-	Type      string `json:"type"`
-}
+	resolvePool := pool.New().WithContext(ctx)
 
-func (n *NameResolver) resolveFarcaster(ctx context.Context, domain string) (string, error) {
 	var (
-		response UserNameProof
-		err      error
+		locker sync.Mutex
+		errs   []error
 	)
 
-	fName := strings.Split(domain, "."+NameServiceFarcaster.String())[0]
+	for i, reverseResolver := range reverseResolvers {
+		i, reverseResolver := i, reverseResolver
+
+		resolvePool.Go(func(ctx context.Context) error {
+			name, err := n.cached(ctx, buildReverseKey(reverseResolver.NameService(), address), func() (string, error) {
+				return reverseResolver.ReverseResolve(ctx, address)
+			})
+
+			switch {
+			case err == nil:
+				primaryNames[i] = &PrimaryName{
+					NameService: reverseResolver.NameService(),
+					Name:        name,
+				}
+			case !errors.Is(err, ErrorUnregisteredName):
+				zap.L().Error("reverse resolve address", zap.Stringer("name_service", reverseResolver.NameService()), zap.String("address", address.String()), zap.Error(err))
+
+				locker.Lock()
+				errs = append(errs, fmt.Errorf("%s: %w", reverseResolver.NameService(), err))
+				locker.Unlock()
+			}
+
+			return nil
+		})
+	}
 
-	params := url.Values{}
+	_ = resolvePool.Wait()
 
-	params.Add("name", fName)
+	result := make([]*PrimaryName, 0, len(primaryNames))
 
-	str := fmt.Sprintf("/v1/userNameProofByName?%s", params.Encode())
+	for _, primaryName := range primaryNames {
+		if primaryName != nil {
+			result = append(result, primaryName)
+		}
+	}
 
-	onRetry := retry.OnRetry(func(n uint, err error) {
-		zap.L().Error("fetch farcaster name", zap.Error(err), zap.Uint("attempts", n))
-	})
+	// The names resolved are returned even if some of the name services failed.
+	if len(result) == 0 && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
 
-	retryIf := retry.RetryIf(func(err error) bool {
-		return err.Error() != fmt.Errorf("%s", ErrUnregisterName).Error()
-	})
+	return result, nil
+}
+
+// lookup returns the resolver registered for the longest suffix of the name.
+func (n *NameResolver) lookup(input string) Resolver {
+	labels := strings.Split(strings.ToLower(input), ".")
 
-	if err = retry.Do(func() error { return n.call(ctx, str, &response) }, retry.Delay(time.Second), retry.Attempts(10), onRetry, retryIf); err != nil {
-		return "", err
+	n.locker.RLock()
+	defer n.locker.RUnlock()
+
+	// The first label is the name itself, which is not a suffix.
+	for i := 1; i < len(labels); i++ {
+		if resolver, exists := n.resolvers[strings.Join(labels[i:], ".")]; exists {
+			return resolver
+		}
 	}
 
-	return response.Owner, nil
+	return nil
 }
 
-func (n *NameResolver) call(ctx context.Context, url string, result any) error {
-	url = fmt.Sprintf("%s%s", n.fcClient.endpointURL, url)
-
-	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return fmt.Errorf("create request: %w", err)
+// cached returns the resolution cached by the key, or resolves and caches it.
+// An unregistered name is cached as an empty value for the negative TTL.
+func (n *NameResolver) cached(ctx context.Context, key string, resolve func() (string, error)) (string, error) {
+	if n.cacheClient == nil || n.cacheConfig == nil || n.cacheConfig.TTL == 0 {
+		return resolve()
 	}
 
-	response, err := n.fcClient.httpClient.Do(request)
-	if err != nil {
-		return fmt.Errorf("do request: %w", err)
+	var value string
+
+	err := n.cacheClient.Get(ctx, key, &value)
+
+	switch {
+	case err == nil:
+		if value == "" {
+			return "", ErrorUnregisteredName
+		}
+
+		return value, nil
+	case !errors.Is(err, redis.Nil):
+		zap.L().Warn("get the cached resolution", zap.String("key", key), zap.Error(err))
 	}
 
-	defer func() {
-		_ = response.Body.Close()
-	}()
+	value, resolveErr := resolve()
+
+	ttl := n.cacheConfig.TTL
+
+	switch {
+	case resolveErr == nil:
+	case errors.Is(resolveErr, ErrorUnregisteredName) && n.cacheConfig.NegativeTTL > 0:
+		value, ttl = "", n.cacheConfig.NegativeTTL
+	default:
+		return "", resolveErr
+	}
 
-	if response.StatusCode == http.StatusBadRequest {
-		return fmt.Errorf("%s", ErrUnregisterName)
+	if err := n.cacheClient.Set(ctx, key, value, ttl); err != nil {
+		zap.L().Warn("cache the resolution", zap.String("key", key), zap.Error(err))
 	}
 
-	if response.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status: %s", response.Status)
+	return value, resolveErr
+}
+
+// buildNameKey builds the key of the cached address of a name.
+func buildNameKey(name string) string {
+	return fmt.Sprintf("name:service:%s", strings.ToLower(name))
+}
+
+// buildReverseKey builds the key of the cached primary name of an address in a name service.
+func buildReverseKey(nameService NameService, address common.Address) string {
+	return fmt.Sprintf("name:reverse:%s:%s", nameService, strings.ToLower(address.String()))
+}
+
+// New creates a NameResolver of the resolvers, the resolutions are not cached if the cache client is nil.
+func New(cacheClient cache.Client, cacheConfig *config.NameResolver, resolvers ...Resolver) (*NameResolver, error) {
+	nameResolver := &NameResolver{
+		resolvers:   make(map[string]Resolver),
+		cacheClient: cacheClient,
+		cacheConfig: cacheConfig,
 	}
 
-	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
-		return fmt.Errorf("decode response: %w", err)
+	for _, resolver := range resolvers {
+		if err := nameResolver.Register(resolver); err != nil {
+			return nil, err
+		}
 	}
 
-	return nil
+	return nameResolver, nil
 }
 
-func NewNameResolver(ctx context.Context, config *config.RPCNetwork) (*NameResolver, error) {
-	var (
-		err                error
-		ensEthClient       *ethclient.Client
-		characterContract  *crossbell.Character
-		lensHandleContract *lens.LensHandle
-		farcasterClient    *fcClient
-	)
+// NewNameResolver creates a NameResolver of the name services set in the config.
+func NewNameResolver(ctx context.Context, config *config.RPCNetwork, cacheClient cache.Client, cacheConfig *config.NameResolver) (*NameResolver, error) {
+	var resolvers []Resolver
 
 	if config.Ethereum != nil {
-		ensEthClient, err = ethclient.DialContext(ctx, config.Ethereum.Endpoint)
+		resolver, err := newENSResolver(ctx, config.Ethereum.Endpoint)
 		if err != nil {
-			return nil, fmt.Errorf("dial ens ethereum client: %w", err)
+			return nil, fmt.Errorf("new ens resolver: %w", err)
 		}
+
+		resolvers = append(resolvers, resolver)
 	}
 
 	if config.Crossbell != nil {
-		csbEthClient, err := ethclient.DialContext(ctx, config.Crossbell.Endpoint)
+		resolver, err := newCrossbellResolver(ctx, config.Crossbell.Endpoint)
 		if err != nil {
-			return nil, fmt.Errorf("dial csb ethereum client: %w", err)
+			return nil, fmt.Errorf("new crossbell resolver: %w", err)
 		}
 
-		characterContract, err = crossbell.NewCharacter(crossbell.AddressCharacter, csbEthClient)
-		if err != nil {
-			return nil, fmt.Errorf("failed to connect to crossbell character contract: %w", err)
-		}
+		resolvers = append(resolvers, resolver)
 	}
 
 	if config.Polygon != nil {
-		lensEthClient, err := ethclient.DialContext(ctx, config.Polygon.Endpoint)
+		resolver, err := newLensResolver(ctx, config.Polygon.Endpoint)
 		if err != nil {
-			return nil, fmt.Errorf("dial lens ethereum client: %w", err)
+			return nil, fmt.Errorf("new lens resolver: %w", err)
 		}
 
-		lensHandleContract, err = lens.NewLensHandle(lens.AddressLensHandle, lensEthClient)
+		resolvers = append(resolvers, resolver)
+	}
+
+	if config.Farcaster != nil {
+		resolver, err := newFarcasterResolver(config.Farcaster.Endpoint, config.Farcaster.APIkey)
 		if err != nil {
-			return nil, fmt.Errorf("failed to connect to lens handle contract: %w", err)
+			return nil, fmt.Errorf("new farcaster resolver: %w", err)
 		}
+
+		resolvers = append(resolvers, resolver)
 	}
 
-	if config.Farcaster != nil {
-		farcasterClient = &fcClient{}
+	if config.BSC != nil {
+		resolver, err := newSpaceIDResolver(ctx, config.BSC.Endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("new space id resolver: %w", err)
+		}
+
+		resolvers = append(resolvers, resolver)
+	}
 
-		if farcasterClient.endpointURL, err = url.Parse(config.Farcaster.Endpoint); err != nil {
-			return nil, fmt.Errorf("parse farcaster endpoint: %w", err)
+	if config.Base != nil {
+		resolver, err := newBasenamesResolver(ctx, config.Base.Endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("new basenames resolver: %w", err)
 		}
 
-		var httpClient http.Client
+		resolvers = append(resolvers, resolver)
+	}
 
-		if config.Farcaster.APIkey != "" {
-			httpClient.Transport = NewAuthenticationTransport(config.Farcaster.APIkey)
-		} else {
-			httpClient = *http.DefaultClient
+	if config.UnstoppableDomains != nil {
+		resolver, err := NewUnstoppableDomainsResolver(config.UnstoppableDomains.Endpoint, config.UnstoppableDomains.APIkey)
+		if err != nil {
+			return nil, fmt.Errorf("new unstoppable domains resolver: %w", err)
 		}
 
-		farcasterClient.httpClient = &httpClient
+		resolvers = append(resolvers, resolver)
 	}
 
-	return &NameResolver{
-		ensEthClient:       ensEthClient,
-		csbHandleContract:  characterContract,
-		lensHandleContract: lensHandleContract,
-		fcClient:           farcasterClient,
-	}, nil
+	return New(cacheClient, cacheConfig, resolvers...)
 }
 
 type AuthenticationTransport struct {
@@ -0,0 +1,76 @@
+package nameresolver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/rss3-network/global-indexer/contract/crossbell"
+)
+
+var _ ReverseResolver = (*crossbellResolver)(nil)
+
+// crossbellResolver resolves the handles of the characters of Crossbell.
+type crossbellResolver struct {
+	characterContract *crossbell.Character
+}
+
+func (r *crossbellResolver) NameService() NameService {
+	return NameServiceCSB
+}
+
+func (r *crossbellResolver) Suffixes() []string {
+	return []string{NameServiceCSB.String()}
+}
+
+func (r *crossbellResolver) Resolve(ctx context.Context, domain string) (string, error) {
+	cData, err := r.characterContract.GetCharacterByHandle(&bind.CallOpts{Context: ctx}, strings.TrimSuffix(domain, "."+NameServiceCSB.String()))
+	if err != nil {
+		return "", fmt.Errorf("failed to get crossbell character by handle: %w", err)
+	}
+
+	characterOwner, err := r.characterContract.OwnerOf(&bind.CallOpts{Context: ctx}, cData.CharacterId)
+	if err != nil {
+		return "", ErrorUnregisteredName
+	}
+
+	return characterOwner.String(), nil
+}
+
+// ReverseResolve returns the handle of the primary character of the address.
+func (r *crossbellResolver) ReverseResolve(ctx context.Context, address common.Address) (string, error) {
+	characterID, err := r.characterContract.GetPrimaryCharacterId(&bind.CallOpts{Context: ctx}, address)
+	if err != nil {
+		return "", fmt.Errorf("failed to get crossbell primary character: %w", err)
+	}
+
+	if characterID == nil || characterID.Sign() == 0 {
+		return "", ErrorUnregisteredName
+	}
+
+	handle, err := r.characterContract.GetHandle(&bind.CallOpts{Context: ctx}, characterID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get crossbell handle of character %s: %w", characterID, err)
+	}
+
+	return fmt.Sprintf("%s.%s", handle, NameServiceCSB), nil
+}
+
+func newCrossbellResolver(ctx context.Context, endpoint string) (*crossbellResolver, error) {
+	csbEthClient, err := ethclient.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("dial csb ethereum client: %w", err)
+	}
+
+	characterContract, err := crossbell.NewCharacter(crossbell.AddressCharacter, csbEthClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to crossbell character contract: %w", err)
+	}
+
+	return &crossbellResolver{
+		characterContract: characterContract,
+	}, nil
+}
@@ -0,0 +1,121 @@
+package nameresolver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"go.uber.org/zap"
+)
+
+var _ Resolver = (*farcasterResolver)(nil)
+
+// farcasterResolver resolves the fnames of Farcaster by a hub.
+type farcasterResolver struct {
+	endpointURL *url.URL
+	httpClient  *http.Client
+}
+
+type UserNameProof struct {
+	Timestamp uint32 `json:"timestamp"`
+	Name      string `json:"name"`
+	Owner     string `json:"owner"`
+	Signature string `json:"signature"`
+	Fid       uint64 `json:"fid"`
+	Type      string `json:"type"`
+}
+
+func (r *farcasterResolver) NameService() NameService {
+	return NameServiceFarcaster
+}
+
+func (r *farcasterResolver) Suffixes() []string {
+	return []string{NameServiceFarcaster.String()}
+}
+
+func (r *farcasterResolver) Resolve(ctx context.Context, domain string) (string, error) {
+	var (
+		response UserNameProof
+		err      error
+	)
+
+	fName := strings.Split(domain, "."+NameServiceFarcaster.String())[0]
+
+	params := url.Values{}
+
+	params.Add("name", fName)
+
+	str := fmt.Sprintf("/v1/userNameProofByName?%s", params.Encode())
+
+	onRetry := retry.OnRetry(func(n uint, err error) {
+		zap.L().Error("fetch farcaster name", zap.Error(err), zap.Uint("attempts", n))
+	})
+
+	retryIf := retry.RetryIf(func(err error) bool {
+		return !errors.Is(err, ErrorUnregisteredName)
+	})
+
+	if err = retry.Do(func() error { return r.call(ctx, str, &response) }, retry.Delay(time.Second), retry.Attempts(10), onRetry, retryIf, retry.LastErrorOnly(true)); err != nil {
+		return "", err
+	}
+
+	return response.Owner, nil
+}
+
+func (r *farcasterResolver) call(ctx context.Context, url string, result any) error {
+	url = fmt.Sprintf("%s%s", r.endpointURL, url)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	response, err := r.httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	if response.StatusCode == http.StatusBadRequest {
+		return ErrorUnregisteredName
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status: %s", response.Status)
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+
+	return nil
+}
+
+func newFarcasterResolver(endpoint, apiKey string) (*farcasterResolver, error) {
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("parse farcaster endpoint: %w", err)
+	}
+
+	var httpClient http.Client
+
+	if apiKey != "" {
+		httpClient.Transport = NewAuthenticationTransport(apiKey)
+	} else {
+		httpClient = *http.DefaultClient
+	}
+
+	return &farcasterResolver{
+		endpointURL: endpointURL,
+		httpClient:  &httpClient,
+	}, nil
+}
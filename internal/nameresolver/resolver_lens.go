@@ -0,0 +1,58 @@
+package nameresolver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/rss3-network/global-indexer/contract/lens"
+)
+
+var _ Resolver = (*lensResolver)(nil)
+
+// lensResolver resolves the handles of Lens.
+type lensResolver struct {
+	handleContract *lens.LensHandle
+}
+
+func (r *lensResolver) NameService() NameService {
+	return NameServiceLens
+}
+
+func (r *lensResolver) Suffixes() []string {
+	return []string{NameServiceLens.String()}
+}
+
+func (r *lensResolver) Resolve(ctx context.Context, domain string) (string, error) {
+	label := strings.Split(domain, "."+NameServiceLens.String())[0]
+
+	tokenID, err := r.handleContract.GetTokenId(&bind.CallOpts{Context: ctx}, label)
+	if err != nil {
+		return "", fmt.Errorf("failed to get lens token id by handle: %w", err)
+	}
+
+	owner, err := r.handleContract.OwnerOf(&bind.CallOpts{Context: ctx}, tokenID)
+	if err != nil {
+		return "", ErrorUnregisteredName
+	}
+
+	return owner.String(), nil
+}
+
+func newLensResolver(ctx context.Context, endpoint string) (*lensResolver, error) {
+	lensEthClient, err := ethclient.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("dial lens ethereum client: %w", err)
+	}
+
+	handleContract, err := lens.NewLensHandle(lens.AddressLensHandle, lensEthClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to lens handle contract: %w", err)
+	}
+
+	return &lensResolver{
+		handleContract: handleContract,
+	}, nil
+}
@@ -0,0 +1,167 @@
+package nameresolver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	goens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/go-ens/v3/contracts/registry"
+	"github.com/wealdtech/go-ens/v3/contracts/resolver"
+)
+
+var (
+	// AddressENSRegistry is the registry of ENS on Ethereum.
+	AddressENSRegistry = common.HexToAddress("0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e")
+	// AddressSpaceIDRegistry is the registry of the .bnb names of SPACE ID on BNB Smart Chain.
+	AddressSpaceIDRegistry = common.HexToAddress("0x08CEd32a7f3eeC915Ba84415e9C07a7286977956")
+	// AddressBasenamesRegistry is the registry of Basenames on Base.
+	AddressBasenamesRegistry = common.HexToAddress("0xB94704422c2a1E396835A571837Aa5AE53285a95")
+)
+
+const (
+	// reverseNodeENS is the parent node of the reverse records of ENS and SPACE ID.
+	reverseNodeENS = "addr.reverse"
+	// reverseNodeBasenames is the parent node of the reverse records of Basenames,
+	// which is named after the coin type of Base under ENSIP-19.
+	reverseNodeBasenames = "80002105.reverse"
+)
+
+var _ ReverseResolver = (*registryResolver)(nil)
+
+// registryResolver resolves the names of a name service compatible with the registry and the resolvers of ENS.
+type registryResolver struct {
+	nameService NameService
+	suffixes    []string
+	caller      bind.ContractCaller
+	registry    *registry.ContractCaller
+	// reverseNode is the parent node of the reverse records of the addresses.
+	reverseNode string
+}
+
+func (r *registryResolver) NameService() NameService {
+	return r.nameService
+}
+
+func (r *registryResolver) Suffixes() []string {
+	return r.suffixes
+}
+
+func (r *registryResolver) Resolve(ctx context.Context, name string) (string, error) {
+	nameResolver, node, err := r.resolver(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	address, err := nameResolver.Addr(&bind.CallOpts{Context: ctx}, node)
+	if err != nil {
+		return "", fmt.Errorf("get address of %s: %w", name, err)
+	}
+
+	if address == (common.Address{}) {
+		return "", ErrorUnregisteredName
+	}
+
+	return address.String(), nil
+}
+
+// ReverseResolve returns the name of the reverse record of the address, only if the name resolves back to the address.
+func (r *registryResolver) ReverseResolve(ctx context.Context, address common.Address) (string, error) {
+	reverseName := fmt.Sprintf("%x.%s", address.Bytes(), r.reverseNode)
+
+	reverseResolver, node, err := r.resolver(ctx, reverseName)
+	if err != nil {
+		return "", err
+	}
+
+	name, err := reverseResolver.Name(&bind.CallOpts{Context: ctx}, node)
+	if err != nil {
+		return "", fmt.Errorf("get name of %s: %w", reverseName, err)
+	}
+
+	if name == "" {
+		return "", ErrorUnregisteredName
+	}
+
+	// The reverse record is set by the address itself, so the name may be owned by another address.
+	resolvedAddress, err := r.Resolve(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("resolve %s: %w", name, err)
+	}
+
+	if !strings.EqualFold(resolvedAddress, address.String()) {
+		return "", ErrorUnregisteredName
+	}
+
+	return name, nil
+}
+
+// resolver returns the resolver of the name set in the registry, and the node of the name.
+func (r *registryResolver) resolver(ctx context.Context, name string) (*resolver.ContractCaller, [32]byte, error) {
+	node, err := goens.NameHash(name)
+	if err != nil {
+		return nil, node, fmt.Errorf("hash name %s: %w", name, err)
+	}
+
+	resolverAddress, err := r.registry.Resolver(&bind.CallOpts{Context: ctx}, node)
+	if err != nil {
+		return nil, node, fmt.Errorf("get resolver of %s: %w", name, err)
+	}
+
+	if resolverAddress == (common.Address{}) {
+		return nil, node, ErrorUnregisteredName
+	}
+
+	nameResolver, err := resolver.NewContractCaller(resolverAddress, r.caller)
+	if err != nil {
+		return nil, node, fmt.Errorf("new resolver of %s: %w", name, err)
+	}
+
+	return nameResolver, node, nil
+}
+
+// newRegistryResolver creates a resolver of the registry deployed at the address.
+func newRegistryResolver(nameService NameService, suffixes []string, caller bind.ContractCaller, address common.Address, reverseNode string) (*registryResolver, error) {
+	registryCaller, err := registry.NewContractCaller(address, caller)
+	if err != nil {
+		return nil, fmt.Errorf("new registry: %w", err)
+	}
+
+	return &registryResolver{
+		nameService: nameService,
+		suffixes:    suffixes,
+		caller:      caller,
+		registry:    registryCaller,
+		reverseNode: reverseNode,
+	}, nil
+}
+
+func newENSResolver(ctx context.Context, endpoint string) (*registryResolver, error) {
+	ethereumClient, err := ethclient.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("dial ens ethereum client: %w", err)
+	}
+
+	return newRegistryResolver(NameServiceENS, []string{NameServiceENS.String()}, ethereumClient, AddressENSRegistry, reverseNodeENS)
+}
+
+func newSpaceIDResolver(ctx context.Context, endpoint string) (*registryResolver, error) {
+	bscClient, err := ethclient.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("dial space id bsc client: %w", err)
+	}
+
+	return newRegistryResolver(NameServiceSpaceID, []string{NameServiceSpaceID.String()}, bscClient, AddressSpaceIDRegistry, reverseNodeENS)
+}
+
+func newBasenamesResolver(ctx context.Context, endpoint string) (*registryResolver, error) {
+	baseClient, err := ethclient.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("dial basenames base client: %w", err)
+	}
+
+	return newRegistryResolver(NameServiceBasenames, []string{NameServiceBasenames.String()}, baseClient, AddressBasenamesRegistry, reverseNodeBasenames)
+}
@@ -0,0 +1,76 @@
+package nameresolver
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var _ ReverseResolver = (*StaticResolver)(nil)
+
+// StaticResolver resolves the names of a fixed set, it stands in for a name service in tests and local deployments.
+type StaticResolver struct {
+	nameService NameService
+	suffixes    []string
+	addresses   map[string]string
+	names       map[common.Address]string
+	// resolutions counts the forward and the reverse resolutions.
+	resolutions atomic.Int64
+}
+
+func (r *StaticResolver) NameService() NameService {
+	return r.nameService
+}
+
+func (r *StaticResolver) Suffixes() []string {
+	return r.suffixes
+}
+
+func (r *StaticResolver) Resolve(_ context.Context, name string) (string, error) {
+	r.resolutions.Add(1)
+
+	address, exists := r.addresses[strings.ToLower(name)]
+	if !exists {
+		return "", ErrorUnregisteredName
+	}
+
+	return address, nil
+}
+
+func (r *StaticResolver) ReverseResolve(_ context.Context, address common.Address) (string, error) {
+	r.resolutions.Add(1)
+
+	name, exists := r.names[address]
+	if !exists {
+		return "", ErrorUnregisteredName
+	}
+
+	return name, nil
+}
+
+// Resolutions returns the number of the resolutions made by the resolver.
+func (r *StaticResolver) Resolutions() int64 {
+	return r.resolutions.Load()
+}
+
+// NewStaticResolver creates a resolver of the names, the primary name of an address is the first of its names in lexical order.
+func NewStaticResolver(nameService NameService, suffixes []string, names map[string]common.Address) *StaticResolver {
+	resolver := &StaticResolver{
+		nameService: nameService,
+		suffixes:    suffixes,
+		addresses:   make(map[string]string, len(names)),
+		names:       make(map[common.Address]string, len(names)),
+	}
+
+	for name, address := range names {
+		resolver.addresses[strings.ToLower(name)] = address.String()
+
+		if primaryName, exists := resolver.names[address]; !exists || name < primaryName {
+			resolver.names[address] = name
+		}
+	}
+
+	return resolver
+}
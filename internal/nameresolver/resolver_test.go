@@ -30,7 +30,7 @@ func Test_Resolve(t *testing.T) {
 		},
 	}
 
-	nr, _ := nameresolver.NewNameResolver(context.Background(), resolverConfig.RPCNetwork)
+	nr, _ := nameresolver.NewNameResolver(context.Background(), resolverConfig.RPCNetwork, nil, nil)
 
 	type arguments struct {
 		ns string
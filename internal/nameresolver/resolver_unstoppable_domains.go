@@ -0,0 +1,130 @@
+package nameresolver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// UnstoppableDomainsSuffixes are the top level domains of Unstoppable Domains.
+var UnstoppableDomainsSuffixes = []string{
+	"crypto", "nft", "x", "wallet", "bitcoin", "dao", "888", "zil", "blockchain", "polygon", "unstoppable",
+	"klever", "hi", "kresus", "anime", "manga", "binanceus", "go", "pudgy", "austin", "bitget", "pog", "clay",
+}
+
+// unstoppableDomainsRecordETHAddress is the record of the Ethereum address of a domain.
+const unstoppableDomainsRecordETHAddress = "crypto.ETH.address"
+
+var _ ReverseResolver = (*UnstoppableDomainsResolver)(nil)
+
+// UnstoppableDomainsResolver resolves the domains of Unstoppable Domains by its Resolution API.
+type UnstoppableDomainsResolver struct {
+	endpointURL *url.URL
+	apiKey      string
+	httpClient  *http.Client
+}
+
+type unstoppableDomainsResponse struct {
+	Meta struct {
+		Domain string `json:"domain"`
+		Owner  string `json:"owner"`
+	} `json:"meta"`
+	Records map[string]string `json:"records"`
+}
+
+func (r *UnstoppableDomainsResolver) NameService() NameService {
+	return NameServiceUnstoppableDomains
+}
+
+func (r *UnstoppableDomainsResolver) Suffixes() []string {
+	return UnstoppableDomainsSuffixes
+}
+
+// Resolve returns the Ethereum address record of the domain, or the owner of the domain if the record is not set.
+func (r *UnstoppableDomainsResolver) Resolve(ctx context.Context, domain string) (string, error) {
+	var response unstoppableDomainsResponse
+
+	if err := r.call(ctx, "/domains/"+url.PathEscape(strings.ToLower(domain)), &response); err != nil {
+		return "", err
+	}
+
+	address := response.Records[unstoppableDomainsRecordETHAddress]
+	if address == "" {
+		address = response.Meta.Owner
+	}
+
+	if !common.IsHexAddress(address) || common.HexToAddress(address) == (common.Address{}) {
+		return "", ErrorUnregisteredName
+	}
+
+	return common.HexToAddress(address).String(), nil
+}
+
+func (r *UnstoppableDomainsResolver) ReverseResolve(ctx context.Context, address common.Address) (string, error) {
+	var response unstoppableDomainsResponse
+
+	if err := r.call(ctx, "/reverse/"+strings.ToLower(address.String()), &response); err != nil {
+		return "", err
+	}
+
+	if response.Meta.Domain == "" {
+		return "", ErrorUnregisteredName
+	}
+
+	return response.Meta.Domain, nil
+}
+
+func (r *UnstoppableDomainsResolver) call(ctx context.Context, path string, result any) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(r.endpointURL.String(), "/")+path, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	if r.apiKey != "" {
+		request.Header.Set("Authorization", "Bearer "+r.apiKey)
+	}
+
+	response, err := r.httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	if response.StatusCode == http.StatusNotFound {
+		return ErrorUnregisteredName
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status: %s", response.Status)
+	}
+
+	// Use a limited reader to avoid reading too much data.
+	if err := json.NewDecoder(io.LimitReader(response.Body, 1<<20)).Decode(result); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+
+	return nil
+}
+
+// NewUnstoppableDomainsResolver creates a resolver of the Resolution API served at the endpoint.
+func NewUnstoppableDomainsResolver(endpoint, apiKey string) (*UnstoppableDomainsResolver, error) {
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("parse unstoppable domains endpoint: %w", err)
+	}
+
+	return &UnstoppableDomainsResolver{
+		endpointURL: endpointURL,
+		apiKey:      apiKey,
+		httpClient:  http.DefaultClient,
+	}, nil
+}
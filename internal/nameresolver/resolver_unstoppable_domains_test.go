@@ -0,0 +1,115 @@
+package nameresolver_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rss3-network/global-indexer/internal/nameresolver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newUnstoppableDomainsEndpoint starts an endpoint of the Resolution API which serves the responses by the paths.
+func newUnstoppableDomainsEndpoint(t *testing.T, apiKey string, responses map[string]string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Header.Get("Authorization") != "Bearer "+apiKey {
+			writer.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		response, exists := responses[request.URL.Path]
+		if !exists {
+			writer.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		writer.Header().Set("Content-Type", "application/json")
+		_, _ = writer.Write([]byte(response))
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestUnstoppableDomainsResolver(t *testing.T) {
+	t.Parallel()
+
+	server := newUnstoppableDomainsEndpoint(t, "key", map[string]string{
+		"/domains/brad.crypto": `{"meta":{"domain":"brad.crypto","owner":"0x8aad44321a86b170879d7a244c1e8d360c99dda8"},"records":{"crypto.ETH.address":"0x8aad44321a86b170879d7a244c1e8d360c99dda8"}}`,
+		"/domains/owner.x":     `{"meta":{"domain":"owner.x","owner":"0xd8da6bf26964af9d7eed9e03e53415d37aa96045"},"records":{}}`,
+		"/domains/burned.nft":  `{"meta":{"domain":"burned.nft","owner":"0x0000000000000000000000000000000000000000"},"records":{}}`,
+		"/reverse/0x8aad44321a86b170879d7a244c1e8d360c99dda8": `{"meta":{"domain":"brad.crypto"}}`,
+	})
+
+	resolver, err := nameresolver.NewUnstoppableDomainsResolver(server.URL, "key")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+		err      error
+	}{
+		{
+			name:     "AddressRecord",
+			input:    "Brad.crypto",
+			expected: common.HexToAddress("0x8aad44321a86b170879d7a244c1e8d360c99dda8").String(),
+		},
+		{
+			name:     "Owner",
+			input:    "owner.x",
+			expected: common.HexToAddress("0xd8da6bf26964af9d7eed9e03e53415d37aa96045").String(),
+		},
+		{
+			name:  "ZeroOwner",
+			input: "burned.nft",
+			err:   nameresolver.ErrorUnregisteredName,
+		},
+		{
+			name:  "NotFound",
+			input: "qwerfdsazxcv.crypto",
+			err:   nameresolver.ErrorUnregisteredName,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			address, err := resolver.Resolve(context.Background(), tc.input)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, address)
+		})
+	}
+
+	name, err := resolver.ReverseResolve(context.Background(), common.HexToAddress("0x8aad44321a86b170879d7a244c1e8d360c99dda8"))
+	require.NoError(t, err)
+	assert.Equal(t, "brad.crypto", name)
+
+	_, err = resolver.ReverseResolve(context.Background(), common.HexToAddress("0xd8da6bf26964af9d7eed9e03e53415d37aa96045"))
+	require.ErrorIs(t, err, nameresolver.ErrorUnregisteredName)
+
+	// The requests without the API key are rejected by the endpoint.
+	unauthorizedResolver, err := nameresolver.NewUnstoppableDomainsResolver(server.URL, "")
+	require.NoError(t, err)
+
+	_, err = unauthorizedResolver.Resolve(context.Background(), "brad.crypto")
+	require.Error(t, err)
+	require.NotErrorIs(t, err, nameresolver.ErrorUnregisteredName)
+}
@@ -8,10 +8,10 @@ import (
 )
 
 func ProvideEthereumMultiChainClient(configFile *config.File) (*ethereum.MultiChainClient, error) {
-	endpoints := []string{
-		configFile.RSS3Chain.EndpointL1,
-		configFile.RSS3Chain.EndpointL2,
+	endpoints := [][]string{
+		append([]string{configFile.RSS3Chain.EndpointL1}, configFile.RSS3Chain.FallbackEndpointsL1...),
+		append([]string{configFile.RSS3Chain.EndpointL2}, configFile.RSS3Chain.FallbackEndpointsL2...),
 	}
 
-	return ethereum.Dial(context.TODO(), endpoints)
+	return ethereum.Dial(context.TODO(), endpoints, configFile.RSS3Chain.Quorum, configFile.RSS3Chain.HealthCheckInterval)
 }
@@ -3,10 +3,12 @@ package provider
 import (
 	"context"
 
+	"github.com/redis/go-redis/v9"
+	"github.com/rss3-network/global-indexer/internal/cache"
 	"github.com/rss3-network/global-indexer/internal/config"
 	"github.com/rss3-network/global-indexer/internal/nameresolver"
 )
 
-func ProvideNameResolver(configFile *config.File) (*nameresolver.NameResolver, error) {
-	return nameresolver.NewNameResolver(context.TODO(), configFile.RPC.RPCNetwork)
+func ProvideNameResolver(configFile *config.File, redisClient *redis.Client) (*nameresolver.NameResolver, error) {
+	return nameresolver.NewNameResolver(context.TODO(), configFile.RPC.RPCNetwork, cache.New(redisClient), configFile.NameResolver)
 }
@@ -1,20 +1,24 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"time"
 
+	"github.com/redis/go-redis/v9"
 	gicrypto "github.com/rss3-network/global-indexer/common/crypto"
 	"github.com/rss3-network/global-indexer/common/txmgr"
 	"github.com/rss3-network/global-indexer/internal/client/ethereum"
 	"github.com/rss3-network/global-indexer/internal/config"
 	"github.com/rss3-network/global-indexer/internal/config/flag"
+	"github.com/rss3-network/global-indexer/internal/database"
+	"github.com/shopspring/decimal"
 	"github.com/spf13/viper"
 )
 
-func ProvideTxManager(config *config.File, ethereumMultiChainClient *ethereum.MultiChainClient) (*txmgr.SimpleTxManager, error) {
-	signerFactory, from, err := gicrypto.NewSignerFactory(config.Settler.PrivateKey, config.Settler.SignerEndpoint, config.Settler.WalletAddress)
+func ProvideTxManager(config *config.File, ethereumMultiChainClient *ethereum.MultiChainClient, databaseClient database.Client, redisClient *redis.Client) (*txmgr.SimpleTxManager, error) {
+	signerFactory, from, err := gicrypto.NewSignerFactory(signerOptions(config.Settler))
 	if err != nil {
 		return nil, fmt.Errorf("create signer: %w", err)
 	}
@@ -28,6 +32,19 @@ func ProvideTxManager(config *config.File, ethereumMultiChainClient *ethereum.Mu
 		ReceiptQueryInterval:      500 * time.Millisecond,
 		NumConfirmations:          5,
 		SafeAbortNonceTooLowCount: 3,
+		EpochInterval:             time.Duration(config.Settler.EpochIntervalInHours) * time.Hour,
+		CraftRetryInterval:        2 * time.Second,
+		CraftRetryAttempts:        30,
+	}
+
+	if policy := config.Settler.Policy; policy != nil {
+		defaultTxConfig.MaxFeePerGas = toWei(policy.MaxFeePerGas, 9)
+		defaultTxConfig.GasLimitMargin = policy.GasLimitMargin
+		defaultTxConfig.EpochBudget = toWei(policy.EpochBudget, 18)
+		defaultTxConfig.DailyBudget = toWei(policy.DailyBudget, 18)
+		defaultTxConfig.MinBalance = toWei(policy.MinBalance, 18)
+		defaultTxConfig.CraftRetryInterval = policy.DeferInterval
+		defaultTxConfig.CraftRetryAttempts = policy.DeferAttempts
 	}
 
 	chainID := new(big.Int).SetUint64(viper.GetUint64(flag.KeyChainIDL2))
@@ -37,10 +54,44 @@ func ProvideTxManager(config *config.File, ethereumMultiChainClient *ethereum.Mu
 		return nil, fmt.Errorf("load l2 ethereum client: %w", err)
 	}
 
-	txManager, err := txmgr.NewSimpleTxManager(defaultTxConfig, chainID, nil, ethereumClient, from, signerFactory(chainID))
+	txManager, err := txmgr.NewSimpleTxManager(defaultTxConfig, chainID, nil, ethereumClient, from, signerFactory(chainID), databaseClient, txmgr.NewSendQueue(redisClient, chainID, from))
 	if err != nil {
 		return nil, fmt.Errorf("create tx manager %w", err)
 	}
 
+	if err := txManager.Reconcile(context.TODO()); err != nil {
+		return nil, fmt.Errorf("reconcile transactions: %w", err)
+	}
+
 	return txManager, nil
 }
+
+// toWei converts an amount in a unit of the decimals to wei, a zero amount is nil.
+func toWei(amount float64, decimals int32) *big.Int {
+	if amount == 0 {
+		return nil
+	}
+
+	return decimal.NewFromFloat(amount).Shift(decimals).BigInt()
+}
+
+func signerOptions(settler *config.Settler) gicrypto.SignerOptions {
+	options := gicrypto.SignerOptions{
+		PrivateKey: settler.PrivateKey,
+		Endpoint:   settler.SignerEndpoint,
+		Address:    settler.WalletAddress,
+	}
+
+	if settler.Keystore != nil {
+		options.KeystorePath = settler.Keystore.Path
+		options.KeystorePassphraseFile = settler.Keystore.PassphraseFile
+		options.KeystorePassphraseEnv = settler.Keystore.PassphraseEnv
+	}
+
+	if settler.Plugin != nil {
+		options.Plugin = settler.Plugin.Name
+		options.PluginOptions = settler.Plugin.Options
+	}
+
+	return options
+}
@@ -0,0 +1,19 @@
+package admin
+
+import (
+	"github.com/rss3-network/global-indexer/internal/database"
+	"github.com/rss3-network/global-indexer/internal/service/settler"
+)
+
+// Admin serves the admin API, which is only accessible with the access token of the admin config.
+type Admin struct {
+	databaseClient      database.Client
+	settlementPreviewer *settler.Previewer
+}
+
+func NewAdmin(databaseClient database.Client, settlementPreviewer *settler.Previewer) *Admin {
+	return &Admin{
+		databaseClient:      databaseClient,
+		settlementPreviewer: settlementPreviewer,
+	}
+}
@@ -0,0 +1,35 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rss3-network/global-indexer/internal/service/hub/model/admin"
+	"github.com/rss3-network/global-indexer/internal/service/hub/model/errorx"
+	"go.uber.org/zap"
+)
+
+// GetSettlementPreview computes the Settlement data of an epoch without submitting it on chain.
+func (a *Admin) GetSettlementPreview(c echo.Context) error {
+	var request admin.SettlementPreviewRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	preview, err := a.settlementPreviewer.Preview(c.Request().Context(), *request.Epoch)
+	if err != nil {
+		zap.L().Error("preview settlement failed", zap.Uint64("epoch", *request.Epoch), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	return c.JSON(http.StatusOK, admin.Response{
+		Data: preview,
+	})
+}
@@ -0,0 +1,56 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/creasty/defaults"
+	"github.com/labstack/echo/v4"
+	"github.com/rss3-network/global-indexer/internal/service/hub/model/admin"
+	"github.com/rss3-network/global-indexer/internal/service/hub/model/errorx"
+	"github.com/rss3-network/global-indexer/schema"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+)
+
+// GetSettlementFailures returns the failed settlement transactions, along with the actions taken on them by the operators.
+func (a *Admin) GetSettlementFailures(c echo.Context) error {
+	var request admin.SettlementFailuresRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := defaults.Set(&request); err != nil {
+		return errorx.BadRequestError(c, fmt.Errorf("set default failed: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	ctx := c.Request().Context()
+
+	failures, err := a.databaseClient.FindSettlementFailures(ctx, schema.SettlementFailuresQuery{
+		Service:  request.Service,
+		Statuses: request.Status,
+		Limit:    lo.ToPtr(request.Limit),
+	})
+	if err != nil {
+		zap.L().Error("find settlement failures failed", zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	for _, failure := range failures {
+		if failure.Audits, err = a.databaseClient.FindSettlementFailureAudits(ctx, failure.ID); err != nil {
+			zap.L().Error("find settlement failure audits failed", zap.Uint64("id", failure.ID), zap.Error(err))
+
+			return errorx.InternalError(c)
+		}
+	}
+
+	return c.JSON(http.StatusOK, admin.Response{
+		Data: failures,
+	})
+}
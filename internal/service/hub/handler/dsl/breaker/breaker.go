@@ -0,0 +1,404 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/redis/go-redis/v9"
+	"github.com/rss3-network/global-indexer/internal/cache"
+	"github.com/rss3-network/global-indexer/internal/config"
+	"github.com/rss3-network/global-indexer/schema"
+	"go.uber.org/zap"
+)
+
+const (
+	StateClosed   = "closed"
+	StateOpen     = "open"
+	StateHalfOpen = "half_open"
+)
+
+var (
+	// OpenNodesCacheKey is the cache key for the sorted set of the Nodes with a tripped breaker,
+	// scored by the time in milliseconds the breaker opened.
+	OpenNodesCacheKey = "breaker:open"
+	// ConsecutiveFailuresCacheKey is the prefix used for cache keys related to storing the consecutive failures of a Node.
+	ConsecutiveFailuresCacheKey = "breaker:failures:consecutive"
+	// WindowRequestsCacheKey is the prefix used for cache keys related to storing the requests of a Node in a window.
+	WindowRequestsCacheKey = "breaker:requests:window"
+	// WindowFailuresCacheKey is the prefix used for cache keys related to storing the failures of a Node in a window.
+	WindowFailuresCacheKey = "breaker:failures:window"
+	// HalfOpenSuccessesCacheKey is the prefix used for cache keys related to storing the successful probes of a half-open Node.
+	HalfOpenSuccessesCacheKey = "breaker:successes:half_open"
+	// HalfOpenProbeCacheKey is the prefix used for cache keys related to storing the probe in flight to a half-open Node.
+	HalfOpenProbeCacheKey = "breaker:probe:half_open"
+)
+
+// CircuitBreaker is a per-Node circuit breaker, with its state kept in Redis to be shared by the hub replicas.
+//
+// A closed breaker lets the requests through, and opens when the Node fails ConsecutiveFailures requests in a row,
+// or when its failure rate within a window reaches ErrorRate.
+// An open breaker skips the Node for OpenDuration, after which it turns half-open and the Node is probed again.
+// A half-open breaker lets a single probe through at a time, and skips the Node for the other requests.
+// It closes after HalfOpenSuccesses successful probes, and opens again on any failure.
+// The window counters are reset on every transition, so that the failures counted before do not trip the breaker again.
+type CircuitBreaker struct {
+	cacheClient cache.Client
+	config      *config.CircuitBreaker
+}
+
+// New creates a CircuitBreaker, it returns nil if the circuit breaker is not enabled.
+// All methods of a nil CircuitBreaker are no-ops, which never skip any Node.
+func New(cacheClient cache.Client, conf *config.CircuitBreaker) *CircuitBreaker {
+	if conf == nil || !conf.Enabled {
+		return nil
+	}
+
+	return &CircuitBreaker{
+		cacheClient: cacheClient,
+		config:      conf,
+	}
+}
+
+// Record records the outcome of a request to the Node, and transitions its breaker accordingly.
+func (b *CircuitBreaker) Record(ctx context.Context, address common.Address, success bool) error {
+	if b == nil {
+		return nil
+	}
+
+	now := time.Now()
+
+	openedAt, err := b.getOpenedAt(ctx, address)
+	if err != nil {
+		return err
+	}
+
+	switch b.stateAt(openedAt, now) {
+	case StateOpen:
+		// The outcome of a request sent before the breaker opened, ignore it.
+		return nil
+	case StateHalfOpen:
+		if !success {
+			return b.open(ctx, address, now)
+		}
+
+		return b.recordHalfOpenSuccess(ctx, address, now)
+	default:
+		return b.recordClosed(ctx, address, success, now)
+	}
+}
+
+// OpenNodes returns the addresses of the Nodes which should be skipped, the Nodes with an open breaker
+// and the half-open Nodes with a probe in flight. No probe is taken over, as the Nodes returned may never be requested,
+// the probe of a half-open Node is only taken over by Acquire once a request is sent to it.
+func (b *CircuitBreaker) OpenNodes(ctx context.Context) (map[string]struct{}, error) {
+	if b == nil {
+		return nil, nil
+	}
+
+	members, err := b.cacheClient.ZRevRangeWithScores(ctx, OpenNodesCacheKey, 0, -1)
+	if err != nil {
+		return nil, fmt.Errorf("get open nodes: %w", err)
+	}
+
+	now := time.Now()
+	openNodes := make(map[string]struct{}, len(members))
+	halfOpenNodes := make([]string, 0, len(members))
+
+	for _, member := range members {
+		switch b.stateAt(int64(member.Score), now) {
+		case StateOpen:
+			openNodes[member.Member.(string)] = struct{}{}
+		case StateHalfOpen:
+			halfOpenNodes = append(halfOpenNodes, member.Member.(string))
+		}
+	}
+
+	if len(halfOpenNodes) == 0 {
+		return openNodes, nil
+	}
+
+	pipe := b.cacheClient.Pipeline(ctx)
+
+	probeCmds := make([]*redis.IntCmd, 0, len(halfOpenNodes))
+
+	for _, address := range halfOpenNodes {
+		probeCmds = append(probeCmds, pipe.Exists(ctx, formatCacheKey(HalfOpenProbeCacheKey, common.HexToAddress(address))))
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		// The half-open Nodes are skipped if their probes are unknown.
+		for _, address := range halfOpenNodes {
+			openNodes[address] = struct{}{}
+		}
+
+		return openNodes, fmt.Errorf("get probes of half-open nodes: %w", err)
+	}
+
+	for i, address := range halfOpenNodes {
+		if probeCmds[i].Val() > 0 {
+			openNodes[address] = struct{}{}
+		}
+	}
+
+	return openNodes, nil
+}
+
+// Acquire is called right before a request is sent to the Node, it returns false if the request should not be sent,
+// and whether the request probes a half-open Node. The probe is released once the outcome of the request is recorded,
+// by Release if the request ends without an outcome, or after OpenDuration.
+func (b *CircuitBreaker) Acquire(ctx context.Context, address common.Address) (bool, bool, error) {
+	if b == nil {
+		return true, false, nil
+	}
+
+	openedAt, err := b.getOpenedAt(ctx, address)
+	if err != nil {
+		return false, false, err
+	}
+
+	switch b.stateAt(openedAt, time.Now()) {
+	case StateOpen:
+		return false, false, nil
+	case StateHalfOpen:
+		pipe := b.cacheClient.Pipeline(ctx)
+
+		probeCmd := pipe.SetNX(ctx, formatCacheKey(HalfOpenProbeCacheKey, address), time.Now().UnixMilli(), b.config.OpenDuration)
+
+		if _, err := pipe.Exec(ctx); err != nil {
+			return false, false, fmt.Errorf("take over probe of node %s: %w", address, err)
+		}
+
+		return probeCmd.Val(), probeCmd.Val(), nil
+	default:
+		return true, false, nil
+	}
+}
+
+// Release releases the probe of a half-open Node taken over by a request which ends without an outcome,
+// so that the next request probes the Node again.
+func (b *CircuitBreaker) Release(ctx context.Context, address common.Address) error {
+	if b == nil {
+		return nil
+	}
+
+	pipe := b.cacheClient.Pipeline(ctx)
+
+	pipe.Del(ctx, formatCacheKey(HalfOpenProbeCacheKey, address))
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("release probe of node %s: %w", address, err)
+	}
+
+	return nil
+}
+
+// Status returns the state of the breaker of the Node.
+func (b *CircuitBreaker) Status(ctx context.Context, address common.Address) (*schema.NodeCircuitBreaker, error) {
+	if b == nil {
+		return nil, nil
+	}
+
+	now := time.Now()
+	window := b.window(now)
+
+	pipe := b.cacheClient.Pipeline(ctx)
+
+	openedAtCmd := pipe.ZScore(ctx, OpenNodesCacheKey, address.String())
+	consecutiveFailuresCmd := pipe.Get(ctx, formatCacheKey(ConsecutiveFailuresCacheKey, address))
+	windowRequestsCmd := pipe.Get(ctx, formatWindowCacheKey(WindowRequestsCacheKey, address, window))
+	windowFailuresCmd := pipe.Get(ctx, formatWindowCacheKey(WindowFailuresCacheKey, address, window))
+
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("get circuit breaker of node %s: %w", address, err)
+	}
+
+	status := &schema.NodeCircuitBreaker{
+		ConsecutiveFailures: int64OrZero(consecutiveFailuresCmd),
+		WindowRequests:      int64OrZero(windowRequestsCmd),
+		WindowFailures:      int64OrZero(windowFailuresCmd),
+	}
+
+	if openedAt, err := openedAtCmd.Result(); err == nil {
+		status.OpenedAt = int64(openedAt)
+	}
+
+	status.State = b.stateAt(status.OpenedAt, now)
+
+	return status, nil
+}
+
+// recordClosed counts the outcome of a request to a Node with a closed breaker, and opens the breaker if it trips.
+func (b *CircuitBreaker) recordClosed(ctx context.Context, address common.Address, success bool, now time.Time) error {
+	var (
+		window = b.window(now)
+
+		consecutiveFailuresKey = formatCacheKey(ConsecutiveFailuresCacheKey, address)
+		windowRequestsKey      = formatWindowCacheKey(WindowRequestsCacheKey, address, window)
+		windowFailuresKey      = formatWindowCacheKey(WindowFailuresCacheKey, address, window)
+	)
+
+	pipe := b.cacheClient.Pipeline(ctx)
+
+	windowRequestsCmd := pipe.Incr(ctx, windowRequestsKey)
+	pipe.Expire(ctx, windowRequestsKey, 2*b.config.Window)
+
+	var consecutiveFailuresCmd, windowFailuresCmd *redis.IntCmd
+
+	if success {
+		pipe.Set(ctx, consecutiveFailuresKey, 0, 0)
+	} else {
+		consecutiveFailuresCmd = pipe.Incr(ctx, consecutiveFailuresKey)
+		windowFailuresCmd = pipe.Incr(ctx, windowFailuresKey)
+		pipe.Expire(ctx, windowFailuresKey, 2*b.config.Window)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("record request of node %s: %w", address, err)
+	}
+
+	if success {
+		return nil
+	}
+
+	if b.shouldOpen(consecutiveFailuresCmd.Val(), windowRequestsCmd.Val(), windowFailuresCmd.Val()) {
+		return b.open(ctx, address, now)
+	}
+
+	return nil
+}
+
+// recordHalfOpenSuccess counts a successful probe of a Node with a half-open breaker, and closes the breaker once enough probes succeed.
+// The probe is released, so that the next request probes the Node again.
+func (b *CircuitBreaker) recordHalfOpenSuccess(ctx context.Context, address common.Address, now time.Time) error {
+	halfOpenSuccessesKey := formatCacheKey(HalfOpenSuccessesCacheKey, address)
+
+	pipe := b.cacheClient.Pipeline(ctx)
+
+	halfOpenSuccessesCmd := pipe.Incr(ctx, halfOpenSuccessesKey)
+	pipe.Expire(ctx, halfOpenSuccessesKey, b.config.OpenDuration)
+	pipe.Del(ctx, formatCacheKey(HalfOpenProbeCacheKey, address))
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("record probe of node %s: %w", address, err)
+	}
+
+	if halfOpenSuccessesCmd.Val() < b.config.HalfOpenSuccesses {
+		return nil
+	}
+
+	return b.close(ctx, address, now)
+}
+
+// open opens the breaker of the Node and resets its counters.
+func (b *CircuitBreaker) open(ctx context.Context, address common.Address, now time.Time) error {
+	pipe := b.cacheClient.Pipeline(ctx)
+
+	pipe.ZAdd(ctx, OpenNodesCacheKey, redis.Z{Member: address.String(), Score: float64(now.UnixMilli())})
+	pipe.Del(ctx, b.counterKeys(address, now)...)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("open circuit breaker of node %s: %w", address, err)
+	}
+
+	zap.L().Warn("circuit breaker opened", zap.String("node", address.String()))
+
+	transitionCounter.WithLabelValues(StateOpen).Inc()
+
+	return nil
+}
+
+// close closes the breaker of the Node and resets its counters.
+func (b *CircuitBreaker) close(ctx context.Context, address common.Address, now time.Time) error {
+	pipe := b.cacheClient.Pipeline(ctx)
+
+	pipe.ZRem(ctx, OpenNodesCacheKey, address.String())
+	pipe.Del(ctx, b.counterKeys(address, now)...)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("close circuit breaker of node %s: %w", address, err)
+	}
+
+	zap.L().Info("circuit breaker closed", zap.String("node", address.String()))
+
+	transitionCounter.WithLabelValues(StateClosed).Inc()
+
+	return nil
+}
+
+// counterKeys returns the keys of the counters of the Node, which are reset on every transition of its breaker.
+func (b *CircuitBreaker) counterKeys(address common.Address, now time.Time) []string {
+	window := b.window(now)
+
+	return []string{
+		formatCacheKey(ConsecutiveFailuresCacheKey, address),
+		formatCacheKey(HalfOpenSuccessesCacheKey, address),
+		formatCacheKey(HalfOpenProbeCacheKey, address),
+		formatWindowCacheKey(WindowRequestsCacheKey, address, window),
+		formatWindowCacheKey(WindowFailuresCacheKey, address, window),
+	}
+}
+
+// getOpenedAt returns the time in milliseconds the breaker of the Node opened, or 0 if it is closed.
+func (b *CircuitBreaker) getOpenedAt(ctx context.Context, address common.Address) (int64, error) {
+	pipe := b.cacheClient.Pipeline(ctx)
+
+	openedAtCmd := pipe.ZScore(ctx, OpenNodesCacheKey, address.String())
+
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return 0, fmt.Errorf("get circuit breaker of node %s: %w", address, err)
+	}
+
+	openedAt, err := openedAtCmd.Result()
+	if err != nil {
+		return 0, nil
+	}
+
+	return int64(openedAt), nil
+}
+
+// stateAt returns the state at the time of a breaker opened at openedAt in milliseconds, a zero openedAt means the breaker is closed.
+func (b *CircuitBreaker) stateAt(openedAt int64, now time.Time) string {
+	switch {
+	case openedAt == 0:
+		return StateClosed
+	case now.Before(time.UnixMilli(openedAt).Add(b.config.OpenDuration)):
+		return StateOpen
+	default:
+		return StateHalfOpen
+	}
+}
+
+// shouldOpen checks if a closed breaker trips on the consecutive failures or the failure rate of the window.
+func (b *CircuitBreaker) shouldOpen(consecutiveFailures, windowRequests, windowFailures int64) bool {
+	if consecutiveFailures >= b.config.ConsecutiveFailures {
+		return true
+	}
+
+	return windowRequests >= b.config.MinRequests && float64(windowFailures)/float64(windowRequests) >= b.config.ErrorRate
+}
+
+// window returns the index of the fixed window the time belongs to.
+func (b *CircuitBreaker) window(now time.Time) int64 {
+	return now.UnixMilli() / b.config.Window.Milliseconds()
+}
+
+func formatCacheKey(key string, address common.Address) string {
+	return fmt.Sprintf("%s:%s", key, address.String())
+}
+
+func formatWindowCacheKey(key string, address common.Address, window int64) string {
+	return fmt.Sprintf("%s:%s:%d", key, address.String(), window)
+}
+
+// int64OrZero returns the value of the command, or 0 if the key does not exist.
+func int64OrZero(cmd *redis.StringCmd) int64 {
+	value, err := cmd.Int64()
+	if err != nil {
+		return 0
+	}
+
+	return value
+}
@@ -0,0 +1,286 @@
+package breaker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/redis/go-redis/v9"
+	"github.com/rss3-network/global-indexer/internal/cache"
+	"github.com/rss3-network/global-indexer/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var circuitBreakerConfig = &config.CircuitBreaker{
+	Enabled:             true,
+	ConsecutiveFailures: 5,
+	ErrorRate:           0.5,
+	MinRequests:         20,
+	Window:              time.Minute,
+	OpenDuration:        30 * time.Second,
+	HalfOpenSuccesses:   3,
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, New(nil, nil))
+	assert.Nil(t, New(nil, &config.CircuitBreaker{Enabled: false}))
+	assert.NotNil(t, New(nil, circuitBreakerConfig))
+}
+
+func TestStateAt(t *testing.T) {
+	t.Parallel()
+
+	b := New(nil, circuitBreakerConfig)
+	now := time.Now()
+
+	testCases := []struct {
+		name     string
+		openedAt int64
+		expected string
+	}{
+		{
+			name:     "Closed",
+			openedAt: 0,
+			expected: StateClosed,
+		},
+		{
+			name:     "Open",
+			openedAt: now.Add(-10 * time.Second).UnixMilli(),
+			expected: StateOpen,
+		},
+		{
+			name:     "HalfOpen",
+			openedAt: now.Add(-time.Minute).UnixMilli(),
+			expected: StateHalfOpen,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, b.stateAt(tc.openedAt, now))
+		})
+	}
+}
+
+func TestShouldOpen(t *testing.T) {
+	t.Parallel()
+
+	b := New(nil, circuitBreakerConfig)
+
+	testCases := []struct {
+		name                string
+		consecutiveFailures int64
+		windowRequests      int64
+		windowFailures      int64
+		expected            bool
+	}{
+		{
+			name:                "Healthy",
+			consecutiveFailures: 1,
+			windowRequests:      100,
+			windowFailures:      10,
+			expected:            false,
+		},
+		{
+			name:                "ConsecutiveFailures",
+			consecutiveFailures: 5,
+			windowRequests:      5,
+			windowFailures:      5,
+			expected:            true,
+		},
+		{
+			name:                "ErrorRate",
+			consecutiveFailures: 1,
+			windowRequests:      40,
+			windowFailures:      20,
+			expected:            true,
+		},
+		{
+			name:                "ErrorRateBelowMinRequests",
+			consecutiveFailures: 4,
+			windowRequests:      10,
+			windowFailures:      8,
+			expected:            false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, b.shouldOpen(tc.consecutiveFailures, tc.windowRequests, tc.windowFailures))
+		})
+	}
+}
+
+// newTestCircuitBreaker creates a CircuitBreaker on an in-memory Redis.
+func newTestCircuitBreaker(t *testing.T) *CircuitBreaker {
+	t.Helper()
+
+	redisServer := miniredis.RunT(t)
+
+	return New(cache.New(redis.NewClient(&redis.Options{Addr: redisServer.Addr()})), circuitBreakerConfig)
+}
+
+// turnHalfOpen moves the time the breaker of the Node opened back by OpenDuration, so that it turns half-open.
+func turnHalfOpen(t *testing.T, b *CircuitBreaker, address common.Address) {
+	t.Helper()
+
+	openedAt := time.Now().Add(-circuitBreakerConfig.OpenDuration).UnixMilli()
+
+	require.NoError(t, b.cacheClient.ZAdd(context.Background(), OpenNodesCacheKey, redis.Z{Member: address.String(), Score: float64(openedAt)}))
+}
+
+func TestCircuitBreakerHalfOpenProbe(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b := newTestCircuitBreaker(t)
+	address := common.Address{1}
+
+	for i := int64(0); i < circuitBreakerConfig.ConsecutiveFailures; i++ {
+		require.NoError(t, b.Record(ctx, address, false))
+	}
+
+	openNodes, err := b.OpenNodes(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, openNodes, address.String())
+
+	turnHalfOpen(t, b, address)
+
+	for i := int64(0); i < circuitBreakerConfig.HalfOpenSuccesses; i++ {
+		// Retrieving the Nodes takes over no probe.
+		openNodes, err = b.OpenNodes(ctx)
+		require.NoError(t, err)
+		assert.NotContains(t, openNodes, address.String())
+
+		// The first request sent takes over the probe, and the others skip the Node until the probe is recorded.
+		acquired, probe, err := b.Acquire(ctx, address)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+		assert.True(t, probe)
+
+		acquired, _, err = b.Acquire(ctx, address)
+		require.NoError(t, err)
+		assert.False(t, acquired)
+
+		openNodes, err = b.OpenNodes(ctx)
+		require.NoError(t, err)
+		assert.Contains(t, openNodes, address.String())
+
+		require.NoError(t, b.Record(ctx, address, true))
+	}
+
+	status, err := b.Status(ctx, address)
+	require.NoError(t, err)
+	assert.Equal(t, StateClosed, status.State)
+
+	openNodes, err = b.OpenNodes(ctx)
+	require.NoError(t, err)
+	assert.NotContains(t, openNodes, address.String())
+
+	acquired, probe, err := b.Acquire(ctx, address)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+	assert.False(t, probe)
+}
+
+func TestCircuitBreakerHalfOpenProbeRelease(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b := newTestCircuitBreaker(t)
+	address := common.Address{1}
+
+	turnHalfOpen(t, b, address)
+
+	acquired, probe, err := b.Acquire(ctx, address)
+	require.NoError(t, err)
+	require.True(t, acquired)
+	require.True(t, probe)
+
+	// A probe which ends without an outcome is released, so the next request probes the Node again.
+	require.NoError(t, b.Release(ctx, address))
+
+	openNodes, err := b.OpenNodes(ctx)
+	require.NoError(t, err)
+	assert.NotContains(t, openNodes, address.String())
+
+	acquired, probe, err = b.Acquire(ctx, address)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+	assert.True(t, probe)
+}
+
+func TestCircuitBreakerHalfOpenProbeFailure(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b := newTestCircuitBreaker(t)
+	address := common.Address{1}
+
+	turnHalfOpen(t, b, address)
+
+	acquired, _, err := b.Acquire(ctx, address)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	require.NoError(t, b.Record(ctx, address, false))
+
+	// The breaker opens again, and the Node is skipped.
+	acquired, _, err = b.Acquire(ctx, address)
+	require.NoError(t, err)
+	assert.False(t, acquired)
+
+	status, err := b.Status(ctx, address)
+	require.NoError(t, err)
+	assert.Equal(t, StateOpen, status.State)
+}
+
+func TestCircuitBreakerResetsWindow(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b := newTestCircuitBreaker(t)
+	address := common.Address{1}
+
+	// Trip the breaker on the error rate, without consecutive failures.
+	for i := int64(0); i < circuitBreakerConfig.MinRequests; i++ {
+		require.NoError(t, b.Record(ctx, address, i%2 == 0))
+	}
+
+	status, err := b.Status(ctx, address)
+	require.NoError(t, err)
+	require.Equal(t, StateOpen, status.State)
+	assert.Zero(t, status.WindowRequests)
+	assert.Zero(t, status.WindowFailures)
+
+	turnHalfOpen(t, b, address)
+
+	for i := int64(0); i < circuitBreakerConfig.HalfOpenSuccesses; i++ {
+		_, _, err = b.Acquire(ctx, address)
+		require.NoError(t, err)
+
+		require.NoError(t, b.Record(ctx, address, true))
+	}
+
+	// The failures counted before the breaker opened do not trip it again.
+	require.NoError(t, b.Record(ctx, address, false))
+
+	status, err = b.Status(ctx, address)
+	require.NoError(t, err)
+	assert.Equal(t, StateClosed, status.State)
+	assert.Equal(t, int64(1), status.WindowRequests)
+	assert.Equal(t, int64(1), status.WindowFailures)
+}
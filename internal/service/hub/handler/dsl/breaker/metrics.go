@@ -0,0 +1,14 @@
+package breaker
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var transitionCounter = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "dsl_circuit_breaker_transitions_total",
+		Help: "Total number of node circuit breakers transitioned to a state",
+	},
+	[]string{"state"},
+)
@@ -0,0 +1,180 @@
+package dsl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/model"
+	"github.com/rss3-network/global-indexer/internal/service/hub/model/dsl"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+)
+
+const (
+	// responseCacheKeyPrefix is the cache key prefix for the cached DSL responses.
+	responseCacheKeyPrefix = "dsl:response:"
+
+	cacheHeader  = "X-Cache"
+	cacheHit     = "HIT"
+	cacheMiss    = "MISS"
+	cacheBypass  = "BYPASS"
+	cacheNoCache = "no-cache"
+)
+
+// distributeData distributes the request to the Nodes through the response cache.
+// Requests carrying `Cache-Control: no-cache` skip the cached response, but still refresh it.
+func (d *DSL) distributeData(c echo.Context, requestType, component string, request interface{}, params url.Values, workers, networks []string) ([]byte, error) {
+	ctx := c.Request().Context()
+
+	ttl := d.responseCacheTTL(requestType, request)
+	if ttl == 0 {
+		return d.distributor.DistributeData(ctx, requestType, component, request, params, workers, networks)
+	}
+
+	requestMeta, err := d.distributor.BuildRequestMeta(requestType, component, request, params)
+	if err != nil {
+		return nil, fmt.Errorf("build request meta: %w", err)
+	}
+
+	key := buildResponseCacheKey(requestMeta)
+	status := cacheMiss
+
+	if strings.Contains(strings.ToLower(c.Request().Header.Get(echo.HeaderCacheControl)), cacheNoCache) {
+		status = cacheBypass
+	} else {
+		var data json.RawMessage
+
+		if err = d.cacheClient.Get(ctx, key, &data); err == nil {
+			c.Response().Header().Set(cacheHeader, cacheHit)
+			responseCacheCounter.WithLabelValues(requestType, cacheHit).Inc()
+
+			return data, nil
+		} else if !errors.Is(err, redis.Nil) {
+			zap.L().Warn("get cached response", zap.Error(err), zap.String("key", key))
+		}
+	}
+
+	c.Response().Header().Set(cacheHeader, status)
+	responseCacheCounter.WithLabelValues(requestType, status).Inc()
+
+	data, err := d.distributor.DistributeData(ctx, requestType, component, request, params, workers, networks)
+	if err != nil {
+		return nil, err
+	}
+
+	if isCacheableResponse(data) {
+		if err = d.cacheClient.Set(ctx, key, json.RawMessage(data), ttl); err != nil {
+			zap.L().Warn("set cached response", zap.Error(err), zap.String("key", key))
+		}
+	}
+
+	return data, nil
+}
+
+// responseCacheTTL returns the time-to-live of the cached response for the request,
+// a zero value means the response should not be cached.
+func (d *DSL) responseCacheTTL(requestType string, request interface{}) time.Duration {
+	if d.responseCacheConfig == nil || !d.responseCacheConfig.Enabled {
+		return 0
+	}
+
+	// The responses of mutable platforms may change at any time.
+	if lo.SomeBy(requestPlatforms(request), isMutablePlatform) {
+		return 0
+	}
+
+	switch requestType {
+	case model.DistributorRequestActivity:
+		return d.responseCacheConfig.Activity
+	case model.DistributorRequestAccountActivities:
+		return d.responseCacheConfig.AccountActivities
+	case model.DistributorRequestBatchAccountActivities:
+		return d.responseCacheConfig.BatchAccountActivities
+	case model.DistributorRequestNetworkActivities:
+		return d.responseCacheConfig.NetworkActivities
+	case model.DistributorRequestPlatformActivities:
+		return d.responseCacheConfig.PlatformActivities
+	default:
+		return 0
+	}
+}
+
+// requestPlatforms returns the platforms requested.
+func requestPlatforms(request interface{}) []string {
+	switch req := request.(type) {
+	case dsl.ActivitiesRequest:
+		return req.Platform
+	case dsl.AccountsActivitiesRequest:
+		return req.Platform
+	case dsl.NetworkActivitiesRequest:
+		return req.Platform
+	case dsl.PlatformActivitiesRequest:
+		return []string{req.Platform}
+	default:
+		return nil
+	}
+}
+
+// isMutablePlatform checks if the platform is listed in the MutablePlatformMap.
+func isMutablePlatform(platform string) bool {
+	for mutablePlatform := range model.MutablePlatformMap {
+		if strings.EqualFold(platform, mutablePlatform) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isCacheableResponse checks if the response is non-null, complete, and contains no activity of mutable platforms.
+// The partial responses missing the activities of failed shards are not cached, so that the next request retries them.
+func isCacheableResponse(data []byte) bool {
+	type activity struct {
+		Platform string `json:"platform"`
+	}
+
+	var response struct {
+		Data json.RawMessage   `json:"data"`
+		Meta *model.MetaCursor `json:"meta"`
+	}
+
+	if err := json.Unmarshal(data, &response); err != nil || len(response.Data) == 0 || string(response.Data) == "null" {
+		return false
+	}
+
+	if response.Meta != nil && response.Meta.Partial {
+		return false
+	}
+
+	var activities []*activity
+
+	if err := json.Unmarshal(response.Data, &activities); err != nil {
+		var single activity
+
+		if err = json.Unmarshal(response.Data, &single); err != nil {
+			return false
+		}
+
+		activities = append(activities, &single)
+	}
+
+	return !lo.SomeBy(activities, func(activity *activity) bool {
+		return isMutablePlatform(activity.Platform)
+	})
+}
+
+// buildResponseCacheKey builds the cache key of the response from the normalized request meta.
+func buildResponseCacheKey(requestMeta *model.RequestMeta) string {
+	bodyHash := sha256.Sum256(requestMeta.Body)
+	requestHash := sha256.Sum256([]byte(fmt.Sprintf("%s\n%s\n%s", requestMeta.Method, requestMeta.Endpoint, hex.EncodeToString(bodyHash[:]))))
+
+	return responseCacheKeyPrefix + hex.EncodeToString(requestHash[:])
+}
@@ -0,0 +1,207 @@
+package dsl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+	"github.com/rss3-network/global-indexer/internal/cache"
+	"github.com/rss3-network/global-indexer/internal/config"
+	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/distributor"
+	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/model"
+	"github.com/rss3-network/global-indexer/internal/service/hub/model/dsl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildResponseCacheKey(t *testing.T) {
+	t.Parallel()
+
+	d := &distributor.Distributor{}
+	request := dsl.ActivitiesRequest{Account: "0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045"}
+
+	meta1, err := d.BuildRequestMeta(model.DistributorRequestAccountActivities, model.ComponentDecentralized, request, url.Values{
+		"tag":     {"social", "collectible"},
+		"network": {"ethereum"},
+	})
+	require.NoError(t, err)
+
+	meta2, err := d.BuildRequestMeta(model.DistributorRequestAccountActivities, model.ComponentDecentralized, request, url.Values{
+		"network": {"ethereum"},
+		"tag":     {"collectible", "social"},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, buildResponseCacheKey(meta1), buildResponseCacheKey(meta2))
+
+	meta3, err := d.BuildRequestMeta(model.DistributorRequestAccountActivities, model.ComponentFederated, request, url.Values{
+		"network": {"ethereum"},
+		"tag":     {"collectible", "social"},
+	})
+	require.NoError(t, err)
+
+	require.NotEqual(t, buildResponseCacheKey(meta1), buildResponseCacheKey(meta3))
+}
+
+func TestIsCacheableResponse(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		data     string
+		expected bool
+	}{
+		{
+			name:     "NullData",
+			data:     `{"data":null}`,
+			expected: false,
+		},
+		{
+			name:     "ErrorResponse",
+			data:     `{"error":"it is error","error_code":"internal_error"}`,
+			expected: false,
+		},
+		{
+			name:     "ImmutableActivity",
+			data:     `{"data":{"id":"0x1","network":"ethereum","platform":"Uniswap"}}`,
+			expected: true,
+		},
+		{
+			name:     "MutableActivity",
+			data:     `{"data":{"id":"0x1","network":"farcaster","platform":"Farcaster"}}`,
+			expected: false,
+		},
+		{
+			name:     "ActivitiesWithMutablePlatform",
+			data:     `{"data":[{"id":"0x1","platform":"Uniswap"},{"id":"0x2","platform":"Farcaster"}],"meta":{"cursor":"0x2:farcaster"}}`,
+			expected: false,
+		},
+		{
+			name:     "PartialActivities",
+			data:     `{"data":[{"id":"0x1","platform":"Uniswap"}],"meta":{"partial":true}}`,
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, isCacheableResponse([]byte(tc.data)))
+		})
+	}
+}
+
+// cacheTestDistributor is a distributor returning the preset responses in turn, and counting the distributed requests.
+type cacheTestDistributor struct {
+	*distributor.Distributor
+
+	responses []string
+	requests  int
+}
+
+func (d *cacheTestDistributor) DistributeData(_ context.Context, _, _ string, _ interface{}, _ url.Values, _, _ []string) ([]byte, error) {
+	response := d.responses[min(d.requests, len(d.responses)-1)]
+	d.requests++
+
+	return []byte(response), nil
+}
+
+type cacheTestValidator struct {
+	validate *validator.Validate
+}
+
+func (v *cacheTestValidator) Validate(i interface{}) error {
+	return v.validate.Struct(i)
+}
+
+func TestDistributeDataResponseCache(t *testing.T) {
+	t.Parallel()
+
+	const (
+		activity        = `{"data":{"id":"0x1","network":"ethereum","platform":"Uniswap"}}`
+		partialActivity = `{"data":{"id":"0x1","network":"ethereum","platform":"Uniswap"},"meta":{"partial":true}}`
+	)
+
+	testCases := []struct {
+		name      string
+		responses []string
+		// cacheControl is the Cache-Control header of each request in turn.
+		cacheControl []string
+		expected     []string
+		requests     int
+	}{
+		{
+			name:         "HitAfterMiss",
+			responses:    []string{activity},
+			cacheControl: []string{"", ""},
+			expected:     []string{cacheMiss, cacheHit},
+			requests:     1,
+		},
+		{
+			name:         "NoCacheBypass",
+			responses:    []string{activity},
+			cacheControl: []string{"", "no-cache", ""},
+			expected:     []string{cacheMiss, cacheBypass, cacheHit},
+			requests:     2,
+		},
+		{
+			name:         "PartialResponseNotCached",
+			responses:    []string{partialActivity, activity},
+			cacheControl: []string{"", "", ""},
+			expected:     []string{cacheMiss, cacheMiss, cacheHit},
+			requests:     2,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			redisServer := miniredis.RunT(t)
+
+			dataDistributor := &cacheTestDistributor{Distributor: &distributor.Distributor{}, responses: tc.responses}
+			d := &DSL{
+				distributor: dataDistributor,
+				cacheClient: cache.New(redis.NewClient(&redis.Options{Addr: redisServer.Addr()})),
+				responseCacheConfig: &config.ResponseCache{
+					Enabled:  true,
+					Activity: time.Hour,
+				},
+			}
+
+			e := echo.New()
+			e.Validator = &cacheTestValidator{validate: validator.New()}
+
+			for index, cacheControl := range tc.cacheControl {
+				request := httptest.NewRequest(http.MethodGet, "/decentralized/tx/0x1", nil)
+				if cacheControl != "" {
+					request.Header.Set(echo.HeaderCacheControl, cacheControl)
+				}
+
+				recorder := httptest.NewRecorder()
+
+				c := e.NewContext(request, recorder)
+				c.SetParamNames("id")
+				c.SetParamValues("0x1")
+
+				require.NoError(t, d.GetDecentralizedActivity(c))
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assert.Equal(t, tc.expected[index], recorder.Header().Get(cacheHeader), "request %d", index)
+			}
+
+			assert.Equal(t, tc.requests, dataDistributor.requests)
+		})
+	}
+}
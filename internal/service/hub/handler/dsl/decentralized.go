@@ -6,12 +6,9 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
-	"strings"
-	"time"
 
 	"github.com/creasty/defaults"
 	"github.com/labstack/echo/v4"
-	"github.com/redis/go-redis/v9"
 	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/model"
 	"github.com/rss3-network/global-indexer/internal/service/hub/model/dsl"
 	"github.com/rss3-network/global-indexer/internal/service/hub/model/errorx"
@@ -41,7 +38,7 @@ func (d *DSL) GetDecentralizedActivity(c echo.Context) (err error) {
 
 	requestCounter.WithLabelValues("GetDecentralizedActivity").Inc()
 
-	activity, err := d.distributor.DistributeData(c.Request().Context(), model.DistributorRequestActivity, model.ComponentDecentralized, request, c.QueryParams(), nil, nil)
+	activity, err := d.distributeData(c, model.DistributorRequestActivity, model.ComponentDecentralized, request, c.QueryParams(), nil, nil)
 	if err != nil {
 		if errors.Is(err, errorx.ErrNoNodesAvailable) {
 			return errorx.ServiceUnavailableError(c, err)
@@ -89,7 +86,7 @@ func (d *DSL) GetDecentralizedAccountActivities(c echo.Context) (err error) {
 
 	incrementRequestCounter("GetDecentralizedAccountActivities", request.Network, request.Tag, request.Platform)
 
-	activities, err := d.distributor.DistributeData(c.Request().Context(), model.DistributorRequestAccountActivities, model.ComponentDecentralized, request, c.QueryParams(), workers, networks)
+	activities, err := d.distributeData(c, model.DistributorRequestAccountActivities, model.ComponentDecentralized, request, c.QueryParams(), workers, networks)
 	if err != nil {
 		if errors.Is(err, errorx.ErrNoNodesAvailable) {
 			return errorx.ServiceUnavailableError(c, err)
@@ -136,7 +133,7 @@ func (d *DSL) BatchGetDecentralizedAccountsActivities(c echo.Context) (err error
 
 	incrementRequestCounter("BatchGetDecentralizedAccountsActivities", request.Network, request.Tag, request.Platform)
 
-	activities, err := d.distributor.DistributeData(c.Request().Context(), model.DistributorRequestBatchAccountActivities, model.ComponentDecentralized, request, nil, workers, networks)
+	activities, err := d.distributeData(c, model.DistributorRequestBatchAccountActivities, model.ComponentDecentralized, request, nil, workers, networks)
 	if err != nil {
 		if errors.Is(err, errorx.ErrNoNodesAvailable) {
 			return errorx.ServiceUnavailableError(c, err)
@@ -176,7 +173,7 @@ func (d *DSL) GetDecentralizedNetworkActivities(c echo.Context) (err error) {
 
 	incrementRequestCounter("GetDecentralizedNetworkActivities", []string{request.Network}, request.Tag, request.Platform)
 
-	activities, err := d.distributor.DistributeData(c.Request().Context(), model.DistributorRequestNetworkActivities, model.ComponentDecentralized, request, c.QueryParams(), workers, networks)
+	activities, err := d.distributeData(c, model.DistributorRequestNetworkActivities, model.ComponentDecentralized, request, c.QueryParams(), workers, networks)
 	if err != nil {
 		if errors.Is(err, errorx.ErrNoNodesAvailable) {
 			return errorx.ServiceUnavailableError(c, err)
@@ -216,7 +213,7 @@ func (d *DSL) GetDecentralizedPlatformActivities(c echo.Context) (err error) {
 
 	incrementRequestCounter("GetDecentralizedPlatformActivities", request.Network, request.Tag, []string{request.Platform})
 
-	activities, err := d.distributor.DistributeData(c.Request().Context(), model.DistributorRequestPlatformActivities, model.ComponentDecentralized, request, c.QueryParams(), workers, networks)
+	activities, err := d.distributeData(c, model.DistributorRequestPlatformActivities, model.ComponentDecentralized, request, c.QueryParams(), workers, networks)
 	if err != nil {
 		if errors.Is(err, errorx.ErrNoNodesAvailable) {
 			return errorx.ServiceUnavailableError(c, err)
@@ -251,42 +248,16 @@ func (d *DSL) transformAccounts(ctx context.Context, accounts []string) error {
 	return nsPool.Wait()
 }
 
+// getEVMAddress resolves the name to an EVM address, the resolutions are cached by the name service.
 func (d *DSL) getEVMAddress(ctx context.Context, account string) (string, error) {
-	var (
-		err error
-
-		key = buildNameServiceKey(account)
-	)
-	// Try to get the resolved address from Redis cache first
-	if err = d.cacheClient.Get(ctx, key, &account); err == nil {
-		return account, nil
-	}
-
-	// If not found in cache, resolve the name to an EVM address
-	if errors.Is(err, redis.Nil) {
-		// Cache miss, need to resolve the name
-		account, err = d.nameService.Resolve(ctx, account)
-		if err != nil {
-			zap.L().Error("name service resolve error", zap.Error(err), zap.String("account", account))
-
-			return "", err
-		}
-
-		// Cache the resolved address, with a TTL of 10 minutes
-		// Fixme: The TTL should be configurable
-		if err = d.cacheClient.Set(ctx, key, account, 10*time.Minute); err != nil {
-			return "", err
-		}
+	address, err := d.nameService.Resolve(ctx, account)
+	if err != nil {
+		zap.L().Error("name service resolve error", zap.Error(err), zap.String("account", account))
 
-		return account, nil
+		return "", err
 	}
 
-	return "", err
-}
-
-// buildNameServiceKey builds the key for the name service cache.
-func buildNameServiceKey(account string) string {
-	return fmt.Sprintf("name:service:%s", strings.ToLower(account))
+	return address, nil
 }
 
 // validEvmAddress checks if the address is a valid EVM address.
@@ -0,0 +1,224 @@
+package distributor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/model"
+	"github.com/rss3-network/global-indexer/internal/service/hub/model/dsl"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+)
+
+// shardActivity holds the fields of an Activity required to merge the shard responses.
+// The Activity itself is kept raw, so that no field is lost during the merge.
+type shardActivity struct {
+	ID        string `json:"id"`
+	Owner     string `json:"owner"`
+	Network   string `json:"network"`
+	Timestamp uint64 `json:"timestamp"`
+
+	raw json.RawMessage
+}
+
+// shardActivitiesResponse is the ActivitiesResponse of a shard with raw Activities.
+type shardActivitiesResponse struct {
+	Data []json.RawMessage `json:"data"`
+	Meta *model.MetaCursor `json:"meta,omitempty"`
+}
+
+// shardResult is the result of a shard request.
+type shardResult struct {
+	index int
+	data  []byte
+	err   error
+}
+
+// shouldShardAccounts checks if the batch account activities request should be sharded across the Nodes.
+func (d *Distributor) shouldShardAccounts(component string, request dsl.AccountsActivitiesRequest, nodes []*model.NodeEndpointCache) bool {
+	return component == model.ComponentDecentralized &&
+		d.accountsPerShard > 0 &&
+		len(nodes) > 1 &&
+		len(request.Accounts) > d.accountsPerShard
+}
+
+// shardNodeCount is the number of Nodes a shard is sent to, so that the responses of every shard are still verified against each other.
+const shardNodeCount = 2
+
+// distributeAccountsActivitiesShards splits the accounts into shards and sends each shard to its own Nodes of the qualified Nodes,
+// so that the shards are spread across the Nodes instead of each being sent to all of them.
+// The shard responses are merged, re-sorted by timestamp and returned as a single response.
+func (d *Distributor) distributeAccountsActivitiesShards(ctx context.Context, request dsl.AccountsActivitiesRequest, nodes []*model.NodeEndpointCache, processor responseProcessor) ([]byte, error) {
+	shards := lo.Chunk(request.Accounts, d.accountsPerShard)
+	results := make(chan *shardResult, len(shards))
+
+	for i, accounts := range shards {
+		go func(index int, accounts []string) {
+			shardRequest := request
+			shardRequest.Accounts = accounts
+
+			data, err := d.distributeAccountsActivitiesShard(ctx, shardRequest, shardNodes(nodes, index), processor)
+
+			results <- &shardResult{index: index, data: data, err: err}
+		}(i, accounts)
+	}
+
+	shardResults := make([]*shardResult, 0, len(shards))
+
+	for range shards {
+		select {
+		case result := <-results:
+			shardResults = append(shardResults, result)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return mergeShardResults(shardResults, request.Limit)
+}
+
+// distributeAccountsActivitiesShard sends the shard to the Nodes, as an unsharded request is sent.
+func (d *Distributor) distributeAccountsActivitiesShard(ctx context.Context, request dsl.AccountsActivitiesRequest, nodes []*model.NodeEndpointCache, processor responseProcessor) ([]byte, error) {
+	nodeMap, err := d.generatePath(model.DistributorRequestBatchAccountActivities, model.ComponentDecentralized, request, nil, nodes)
+	if err != nil {
+		return nil, fmt.Errorf("generate path: %w", err)
+	}
+
+	nodeResponse, err := d.simpleRouter.DistributeRequest(ctx, nodeMap, processor)
+	if err != nil {
+		return nil, fmt.Errorf("distribute request: %w", err)
+	}
+
+	if nodeResponse.Err != nil {
+		return nil, nodeResponse.Err
+	}
+
+	return nodeResponse.Data, nil
+}
+
+// shardNodes returns the Nodes the shard of the index is sent to, the shards start from different Nodes to spread the load.
+func shardNodes(nodes []*model.NodeEndpointCache, index int) []*model.NodeEndpointCache {
+	count := min(shardNodeCount, len(nodes))
+	result := make([]*model.NodeEndpointCache, 0, count)
+
+	for i := 0; i < count; i++ {
+		result = append(result, nodes[(index+i)%len(nodes)])
+	}
+
+	return result
+}
+
+// mergeShardResults merges the Activities of the shards which succeeded, the failed shards are left out of the response,
+// which is then marked as partial without a cursor. An error is returned only if all the shards failed.
+func mergeShardResults(results []*shardResult, limit int) ([]byte, error) {
+	var (
+		activities = make([]*shardActivity, 0, limit*len(results))
+		seen       = make(map[string]struct{})
+		errs       = make([]error, 0, len(results))
+		// hasMore is true if any shard has more Activities than returned.
+		hasMore bool
+	)
+
+	for _, result := range results {
+		shardActivities, cursor, err := result.activities()
+		if err != nil {
+			zap.L().Warn("shard request failed, leave it out of the response", zap.Int("shard", result.index), zap.Error(err))
+
+			errs = append(errs, fmt.Errorf("shard %d: %w", result.index, err))
+
+			continue
+		}
+
+		hasMore = hasMore || cursor != ""
+
+		for _, activity := range shardActivities {
+			key := fmt.Sprintf("%s-%s-%s", activity.ID, activity.Network, activity.Owner)
+
+			if _, exists := seen[key]; !exists {
+				seen[key] = struct{}{}
+				activities = append(activities, activity)
+			}
+		}
+	}
+
+	if len(errs) == len(results) {
+		return nil, errors.Join(errs...)
+	}
+
+	zap.L().Info("merge shard responses", zap.Int("shards", len(results)), zap.Int("failed", len(errs)), zap.Int("activities", len(activities)))
+
+	return mergeShardActivities(activities, limit, hasMore, len(errs) > 0)
+}
+
+// activities parses the Activities and the cursor of the shard response, or returns the error of the shard request.
+func (r *shardResult) activities() ([]*shardActivity, string, error) {
+	if r.err != nil {
+		return nil, "", r.err
+	}
+
+	return parseShardActivities(r.data)
+}
+
+// parseShardActivities parses the Activities and the cursor of a shard response.
+func parseShardActivities(data []byte) ([]*shardActivity, string, error) {
+	var response shardActivitiesResponse
+
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, "", fmt.Errorf("unmarshal activities response: %w", err)
+	}
+
+	activities := make([]*shardActivity, 0, len(response.Data))
+
+	for _, raw := range response.Data {
+		activity := &shardActivity{raw: raw}
+
+		if err := json.Unmarshal(raw, activity); err != nil {
+			return nil, "", fmt.Errorf("unmarshal activity: %w", err)
+		}
+
+		activities = append(activities, activity)
+	}
+
+	var cursor string
+
+	if response.Meta != nil {
+		cursor = response.Meta.Cursor
+	}
+
+	return activities, cursor, nil
+}
+
+// mergeShardActivities sorts the Activities by timestamp in descending order and keeps the first limit of them.
+// The merged cursor points to the last Activity returned, if there are more Activities to fetch and the response is not partial.
+func mergeShardActivities(activities []*shardActivity, limit int, hasMore, partial bool) ([]byte, error) {
+	sort.SliceStable(activities, func(i, j int) bool {
+		return activities[i].Timestamp > activities[j].Timestamp
+	})
+
+	if len(activities) > limit {
+		activities = activities[:limit]
+		hasMore = true
+	}
+
+	response := shardActivitiesResponse{
+		Data: lo.Map(activities, func(activity *shardActivity, _ int) json.RawMessage {
+			return activity.raw
+		}),
+	}
+
+	switch {
+	case partial:
+		response.Meta = &model.MetaCursor{Partial: true}
+	case hasMore && len(activities) > 0:
+		last := activities[len(activities)-1]
+
+		response.Meta = &model.MetaCursor{
+			Cursor: fmt.Sprintf("%s:%s", last.ID, last.Network),
+		}
+	}
+
+	return json.Marshal(response)
+}
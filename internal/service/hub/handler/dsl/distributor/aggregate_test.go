@@ -0,0 +1,144 @@
+package distributor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/model"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeShardActivities(t *testing.T) {
+	t.Parallel()
+
+	shard0 := `{"data":[{"id":"0x3","owner":"0xa","network":"ethereum","timestamp":300,"success":true},{"id":"0x1","owner":"0xa","network":"ethereum","timestamp":100,"success":true}],"meta":{"cursor":"0x1:ethereum"}}`
+	shard1 := `{"data":[{"id":"0x4","owner":"0xb","network":"arbitrum","timestamp":400,"success":true},{"id":"0x2","owner":"0xb","network":"arbitrum","timestamp":200,"success":true}]}`
+
+	activities0, cursor0, err := parseShardActivities([]byte(shard0))
+	require.NoError(t, err)
+	assert.Equal(t, "0x1:ethereum", cursor0)
+
+	activities1, cursor1, err := parseShardActivities([]byte(shard1))
+	require.NoError(t, err)
+	assert.Empty(t, cursor1)
+
+	testCases := []struct {
+		name     string
+		limit    int
+		hasMore  bool
+		expected string
+	}{
+		{
+			name:     "Truncated",
+			limit:    3,
+			hasMore:  false,
+			expected: `{"data":[{"id":"0x4","owner":"0xb","network":"arbitrum","timestamp":400,"success":true},{"id":"0x3","owner":"0xa","network":"ethereum","timestamp":300,"success":true},{"id":"0x2","owner":"0xb","network":"arbitrum","timestamp":200,"success":true}],"meta":{"cursor":"0x2:arbitrum"}}`,
+		},
+		{
+			name:     "Complete",
+			limit:    10,
+			hasMore:  false,
+			expected: `{"data":[{"id":"0x4","owner":"0xb","network":"arbitrum","timestamp":400,"success":true},{"id":"0x3","owner":"0xa","network":"ethereum","timestamp":300,"success":true},{"id":"0x2","owner":"0xb","network":"arbitrum","timestamp":200,"success":true},{"id":"0x1","owner":"0xa","network":"ethereum","timestamp":100,"success":true}]}`,
+		},
+		{
+			name:     "ShardHasMore",
+			limit:    10,
+			hasMore:  true,
+			expected: `{"data":[{"id":"0x4","owner":"0xb","network":"arbitrum","timestamp":400,"success":true},{"id":"0x3","owner":"0xa","network":"ethereum","timestamp":300,"success":true},{"id":"0x2","owner":"0xb","network":"arbitrum","timestamp":200,"success":true},{"id":"0x1","owner":"0xa","network":"ethereum","timestamp":100,"success":true}],"meta":{"cursor":"0x1:ethereum"}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			activities := append(append([]*shardActivity{}, activities0...), activities1...)
+
+			data, err := mergeShardActivities(activities, tc.limit, tc.hasMore, false)
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.expected, string(data))
+		})
+	}
+}
+
+func TestMergeShardResults(t *testing.T) {
+	t.Parallel()
+
+	shard0 := `{"data":[{"id":"0x3","owner":"0xa","network":"ethereum","timestamp":300,"success":true}]}`
+	shard1 := `{"data":[{"id":"0x4","owner":"0xb","network":"arbitrum","timestamp":400,"success":true}]}`
+
+	testCases := []struct {
+		name     string
+		results  []*shardResult
+		expected string
+		err      bool
+	}{
+		{
+			name: "AllSucceeded",
+			results: []*shardResult{
+				{index: 0, data: []byte(shard0)},
+				{index: 1, data: []byte(shard1)},
+			},
+			expected: `{"data":[{"id":"0x4","owner":"0xb","network":"arbitrum","timestamp":400,"success":true},{"id":"0x3","owner":"0xa","network":"ethereum","timestamp":300,"success":true}]}`,
+		},
+		{
+			name: "PartiallyFailed",
+			results: []*shardResult{
+				{index: 0, data: []byte(`{"data":[{"id":"0x3","owner":"0xa","network":"ethereum","timestamp":300,"success":true}],"meta":{"cursor":"0x3:ethereum"}}`)},
+				{index: 1, err: errors.New("no valid response")},
+				{index: 2, data: []byte(`{"data":`)},
+			},
+			expected: `{"data":[{"id":"0x3","owner":"0xa","network":"ethereum","timestamp":300,"success":true}],"meta":{"partial":true}}`,
+		},
+		{
+			name: "AllFailed",
+			results: []*shardResult{
+				{index: 0, err: errors.New("no valid response")},
+				{index: 1, err: errors.New("no valid response")},
+			},
+			err: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := mergeShardResults(tc.results, 10)
+			if tc.err {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.expected, string(data))
+		})
+	}
+}
+
+func TestShardNodes(t *testing.T) {
+	t.Parallel()
+
+	nodes := []*model.NodeEndpointCache{{Address: "0x1"}, {Address: "0x2"}, {Address: "0x3"}}
+
+	addresses := func(nodes []*model.NodeEndpointCache) []string {
+		return lo.Map(nodes, func(node *model.NodeEndpointCache, _ int) string {
+			return node.Address
+		})
+	}
+
+	assert.Equal(t, []string{"0x1", "0x2"}, addresses(shardNodes(nodes, 0)))
+	assert.Equal(t, []string{"0x2", "0x3"}, addresses(shardNodes(nodes, 1)))
+	assert.Equal(t, []string{"0x3", "0x1"}, addresses(shardNodes(nodes, 2)))
+	assert.Equal(t, []string{"0x1", "0x2"}, addresses(shardNodes(nodes, 3)))
+
+	// A single Node serves every shard.
+	assert.Equal(t, []string{"0x1"}, addresses(shardNodes(nodes[:1], 1)))
+}
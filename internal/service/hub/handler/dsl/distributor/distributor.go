@@ -7,6 +7,7 @@ import (
 	"math/big"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -16,11 +17,13 @@ import (
 	"github.com/rss3-network/global-indexer/internal/cache"
 	"github.com/rss3-network/global-indexer/internal/config"
 	"github.com/rss3-network/global-indexer/internal/database"
+	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/breaker"
 	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/enforcer"
 	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/model"
 	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/router"
 	"github.com/rss3-network/global-indexer/internal/service/hub/model/dsl"
 	"github.com/rss3-network/global-indexer/internal/service/hub/model/errorx"
+	"github.com/samber/lo"
 	"go.uber.org/zap"
 )
 
@@ -29,6 +32,8 @@ type Distributor struct {
 	simpleRouter   *router.SimpleRouter
 	databaseClient database.Client
 	cacheClient    cache.Client
+	// accountsPerShard is the number of accounts in a single request of the batch account activities.
+	accountsPerShard int
 }
 
 // DistributeAIData distributes AI requests to qualified Nodes.
@@ -129,6 +134,11 @@ func (d *Distributor) DistributeData(ctx context.Context, requestType, component
 		return nil, errorx.ErrNoNodesAvailable
 	}
 
+	// Large batches of accounts are sharded into smaller requests, so that a single slow shard does not time out the whole batch.
+	if accountsRequest, ok := request.(dsl.AccountsActivitiesRequest); ok && d.shouldShardAccounts(component, accountsRequest, nodes) {
+		return d.distributeAccountsActivitiesShards(ctx, accountsRequest, nodes, processor)
+	}
+
 	nodeMap, err := d.generatePath(requestType, component, request, params, nodes)
 	if err != nil {
 		return nil, fmt.Errorf("generate path: %w", err)
@@ -249,6 +259,48 @@ func extractFediverseAddress(activityURL string) (string, error) {
 
 // generatePath builds the path for distributor requests.
 func (d *Distributor) generatePath(requestType, component string, request interface{}, params url.Values, nodes []*model.NodeEndpointCache) (map[common.Address]model.RequestMeta, error) {
+	method, path, body, err := buildRequest(requestType, component, request)
+	if err != nil {
+		return nil, err
+	}
+
+	endpointMap, err := d.simpleRouter.BuildPath(method, path, params, nodes, body)
+	if err != nil {
+		return nil, fmt.Errorf("build path: %w", err)
+	}
+
+	return endpointMap, nil
+}
+
+// BuildRequestMeta builds the Node independent request meta for distributor requests.
+// The endpoint is the path with the query parameters sorted, so equivalent requests share the same request meta.
+func (d *Distributor) BuildRequestMeta(requestType, component string, request interface{}, params url.Values) (*model.RequestMeta, error) {
+	method, path, body, err := buildRequest(requestType, component, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if method == http.MethodGet && len(params) > 0 {
+		sortedParams := make(url.Values, len(params))
+
+		for key, values := range params {
+			sortedParams[key] = lo.Uniq(values)
+			sort.Strings(sortedParams[key])
+		}
+
+		// Encode sorts the parameters by key.
+		path = fmt.Sprintf("%s?%s", path, sortedParams.Encode())
+	}
+
+	return &model.RequestMeta{
+		Method:   method,
+		Endpoint: path,
+		Body:     body,
+	}, nil
+}
+
+// buildRequest returns the method, path and body of distributor requests.
+func buildRequest(requestType, component string, request interface{}) (string, string, []byte, error) {
 	var (
 		path   string
 		method = http.MethodGet
@@ -268,36 +320,32 @@ func (d *Distributor) generatePath(requestType, component string, request interf
 		body, err = json.Marshal(req)
 
 		if err != nil {
-			return nil, fmt.Errorf("marshal request data: %w", err)
+			return "", "", nil, fmt.Errorf("marshal request data: %w", err)
 		}
 	case dsl.NetworkActivitiesRequest:
 		path = fmt.Sprintf("/%s/network/%s", component, req.Network)
 	case dsl.PlatformActivitiesRequest:
 		path = fmt.Sprintf("/%s/platform/%s", component, req.Platform)
 	default:
-		return nil, fmt.Errorf("invalid request type: %s", requestType)
-	}
-
-	endpointMap, err := d.simpleRouter.BuildPath(method, path, params, nodes, body)
-	if err != nil {
-		return nil, fmt.Errorf("build path: %w", err)
+		return "", "", nil, fmt.Errorf("invalid request type: %s", requestType)
 	}
 
-	return endpointMap, nil
+	return method, path, body, nil
 }
 
 // NewDistributor creates a new distributor.
-func NewDistributor(ctx context.Context, database database.Client, cache cache.Client, httpClient httputil.Client, stakingContract *l2.StakingV2MulticallClient, networkParamsContract *l2.NetworkParams, txManager *txmgr.SimpleTxManager, settlerConfig *config.Settler, chainID *big.Int) (*Distributor, error) {
-	simpleEnforcer, err := enforcer.NewSimpleEnforcer(ctx, database, cache, stakingContract, networkParamsContract, httpClient, txManager, settlerConfig, chainID, true)
+func NewDistributor(ctx context.Context, database database.Client, cache cache.Client, httpClient httputil.Client, stakingContract *l2.StakingV2MulticallClient, networkParamsContract *l2.NetworkParams, txManager *txmgr.SimpleTxManager, settlerConfig *config.Settler, distributorConfig *config.Distributor, chainID *big.Int) (*Distributor, error) {
+	simpleEnforcer, err := enforcer.NewSimpleEnforcer(ctx, database, cache, stakingContract, networkParamsContract, httpClient, txManager, settlerConfig, distributorConfig, chainID, true)
 
 	if err != nil {
 		return nil, err
 	}
 
 	return &Distributor{
-		simpleEnforcer: simpleEnforcer,
-		simpleRouter:   router.NewSimpleRouter(httpClient),
-		databaseClient: database,
-		cacheClient:    cache,
+		simpleEnforcer:   simpleEnforcer,
+		simpleRouter:     router.NewSimpleRouter(httpClient, distributorConfig.Hedge, breaker.New(cache, distributorConfig.CircuitBreaker)),
+		databaseClient:   database,
+		cacheClient:      cache,
+		accountsPerShard: distributorConfig.AccountsPerShard,
 	}, nil
 }
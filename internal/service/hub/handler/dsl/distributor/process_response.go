@@ -11,6 +11,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/rss3-network/global-indexer/internal/database"
 	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/model"
+	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/verification"
 	"github.com/rss3-network/global-indexer/schema"
 	"github.com/samber/lo"
 	"go.uber.org/zap"
@@ -31,7 +32,7 @@ func (d *Distributor) processRSSHubResponses(_ []*model.DataResponse) {
 
 // processAIResponses processes responses for AI requests.
 func (d *Distributor) processAIResponses(responses []*model.DataResponse) {
-	if err := d.simpleEnforcer.VerifyResponses(context.Background(), responses, false); err != nil {
+	if err := d.simpleEnforcer.VerifyResponses(context.Background(), verification.RequestAI, responses); err != nil {
 		zap.L().Error("fail to verify ai responses", zap.Any("responses", len(responses)))
 
 		return
@@ -42,7 +43,7 @@ func (d *Distributor) processAIResponses(responses []*model.DataResponse) {
 
 // processDecentralizedActivityResponses processes responses for Decentralized Activity requests.
 func (d *Distributor) processDecentralizedActivityResponses(responses []*model.DataResponse) {
-	if err := d.simpleEnforcer.VerifyResponses(context.Background(), responses, true); err != nil {
+	if err := d.simpleEnforcer.VerifyResponses(context.Background(), verification.RequestDecentralizedActivity, responses); err != nil {
 		zap.L().Error("fail to verify activity id responses ", zap.Any("responses", len(responses)))
 	} else {
 		_ = d.processNodeInvalidResponse(context.Background(), responses)
@@ -55,7 +56,7 @@ func (d *Distributor) processDecentralizedActivityResponses(responses []*model.D
 func (d *Distributor) processDecentralizedActivitiesResponses(responses []*model.DataResponse) {
 	ctx := context.Background()
 
-	if err := d.simpleEnforcer.VerifyResponses(ctx, responses, true); err != nil {
+	if err := d.simpleEnforcer.VerifyResponses(ctx, verification.RequestDecentralizedActivities, responses); err != nil {
 		zap.L().Error("fail to verify activity responses", zap.Any("responses", len(responses)))
 
 		return
@@ -74,7 +75,7 @@ func (d *Distributor) processDecentralizedActivitiesResponses(responses []*model
 
 // processFederatedActivityResponses processes responses for Federated Activity requests.
 func (d *Distributor) processFederatedActivityResponses(responses []*model.DataResponse) {
-	if err := d.simpleEnforcer.VerifyResponses(context.Background(), responses, false); err != nil {
+	if err := d.simpleEnforcer.VerifyResponses(context.Background(), verification.RequestFederatedActivity, responses); err != nil {
 		zap.L().Error("fail to verify federated activity responses", zap.Any("responses", len(responses)))
 
 		return
@@ -85,7 +86,7 @@ func (d *Distributor) processFederatedActivityResponses(responses []*model.DataR
 
 // processFederatedActivitiesResponses processes responses for Federated Activities requests.
 func (d *Distributor) processFederatedActivitiesResponses(responses []*model.DataResponse) {
-	if err := d.simpleEnforcer.VerifyResponses(context.Background(), responses, false); err != nil {
+	if err := d.simpleEnforcer.VerifyResponses(context.Background(), verification.RequestFederatedActivities, responses); err != nil {
 		zap.L().Error("fail to verify federated activities responses", zap.Any("responses", len(responses)))
 
 		return
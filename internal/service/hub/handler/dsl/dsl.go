@@ -3,6 +3,7 @@ package dsl
 import (
 	"context"
 	"math/big"
+	"net/url"
 
 	"github.com/rss3-network/global-indexer/common/httputil"
 	"github.com/rss3-network/global-indexer/common/txmgr"
@@ -12,17 +13,28 @@ import (
 	"github.com/rss3-network/global-indexer/internal/database"
 	"github.com/rss3-network/global-indexer/internal/nameresolver"
 	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/distributor"
+	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/model"
 )
 
+// dataDistributor distributes the requests to the Nodes, it is implemented by distributor.Distributor.
+type dataDistributor interface {
+	DistributeData(ctx context.Context, requestType, component string, request interface{}, params url.Values, workers, networks []string) ([]byte, error)
+	DistributeAIData(ctx context.Context, path, query string) ([]byte, error)
+	DistributeRSSHubData(ctx context.Context, path, query string) ([]byte, error)
+	BuildRequestMeta(requestType, component string, request interface{}, params url.Values) (*model.RequestMeta, error)
+}
+
 type DSL struct {
-	distributor    *distributor.Distributor
+	distributor    dataDistributor
 	databaseClient database.Client
 	cacheClient    cache.Client
 	nameService    *nameresolver.NameResolver
+
+	responseCacheConfig *config.ResponseCache
 }
 
-func NewDSL(ctx context.Context, databaseClient database.Client, cacheClient cache.Client, nameService *nameresolver.NameResolver, stakingContract *l2.StakingV2MulticallClient, networkParamsContract *l2.NetworkParams, httpClient httputil.Client, txManager *txmgr.SimpleTxManager, settlerConfig *config.Settler, chainID *big.Int) (*DSL, error) {
-	distributorService, err := distributor.NewDistributor(ctx, databaseClient, cacheClient, httpClient, stakingContract, networkParamsContract, txManager, settlerConfig, chainID)
+func NewDSL(ctx context.Context, databaseClient database.Client, cacheClient cache.Client, nameService *nameresolver.NameResolver, stakingContract *l2.StakingV2MulticallClient, networkParamsContract *l2.NetworkParams, httpClient httputil.Client, txManager *txmgr.SimpleTxManager, settlerConfig *config.Settler, distributorConfig *config.Distributor, chainID *big.Int) (*DSL, error) {
+	distributorService, err := distributor.NewDistributor(ctx, databaseClient, cacheClient, httpClient, stakingContract, networkParamsContract, txManager, settlerConfig, distributorConfig, chainID)
 	if err != nil {
 		return nil, err
 	}
@@ -32,5 +44,7 @@ func NewDSL(ctx context.Context, databaseClient database.Client, cacheClient cac
 		databaseClient: databaseClient,
 		cacheClient:    cacheClient,
 		nameService:    nameService,
+
+		responseCacheConfig: distributorConfig.ResponseCache,
 	}, nil
 }
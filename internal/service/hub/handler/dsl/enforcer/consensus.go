@@ -0,0 +1,121 @@
+package enforcer
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/model"
+	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/verification"
+)
+
+// cluster is a group of responses with equivalent content.
+type cluster struct {
+	responses []*model.DataResponse
+}
+
+// representative returns the response the other responses are compared with.
+func (c *cluster) representative() *model.DataResponse {
+	return c.responses[0]
+}
+
+// valid reports whether the responses of the cluster carry non-null data.
+func (c *cluster) valid() bool {
+	return c.representative().Valid
+}
+
+// lowestAddress returns the lowest Node address in the cluster, which is used to break ties.
+func (c *cluster) lowestAddress() []byte {
+	lowest := c.representative().Address.Bytes()
+
+	for _, response := range c.responses[1:] {
+		if bytes.Compare(response.Address.Bytes(), lowest) < 0 {
+			lowest = response.Address.Bytes()
+		}
+	}
+
+	return lowest
+}
+
+// updatePointsBasedOnConsensus updates both points based on the majority consensus of any number of responses.
+// The non-error responses are clustered by content equivalence, the majority cluster is given valid points,
+// and the clusters smaller than the majority are given invalid points.
+func updatePointsBasedOnConsensus(policies *verification.Policies, responses []*model.DataResponse) {
+	countAndMarkErrorResponse(responses)
+
+	clusters := clusterResponses(policies, responses)
+	if len(clusters) == 0 {
+		return
+	}
+
+	rankClusters(clusters)
+
+	majority := clusters[0]
+
+	for _, response := range majority.responses {
+		response.ValidPoint = validPointUnit
+	}
+
+	// As in the comparison of three responses, the first response is rewarded once more if others agree with it.
+	if len(majority.responses) > 1 && majority.responses[0] == responses[0] {
+		responses[0].ValidPoint += validPointUnit
+	}
+
+	// The clusters as large as the majority only lost a tie, they are neither rewarded nor punished.
+	for _, minority := range clusters[1:] {
+		if len(minority.responses) >= len(majority.responses) {
+			continue
+		}
+
+		for _, response := range minority.responses {
+			response.InvalidPoint = invalidPointUnit
+		}
+	}
+}
+
+// clusterResponses groups the non-error responses by content equivalence,
+// a response joins the first cluster whose representative it is identical to.
+func clusterResponses(policies *verification.Policies, responses []*model.DataResponse) []*cluster {
+	var clusters []*cluster
+
+	for _, response := range responses {
+		if response.Err != nil {
+			continue
+		}
+
+		matched := false
+
+		for _, c := range clusters {
+			if c.valid() == response.Valid && isResponseIdentical(policies, c.representative().Data, response.Data) {
+				c.responses = append(c.responses, response)
+				matched = true
+
+				break
+			}
+		}
+
+		if !matched {
+			clusters = append(clusters, &cluster{responses: []*model.DataResponse{response}})
+		}
+	}
+
+	return clusters
+}
+
+// rankClusters sorts the clusters so that the majority comes first.
+// Larger clusters rank above smaller ones. Among clusters of the same size, the ones with non-null data
+// rank above null ones, as Nodes that have not indexed the data yet return null,
+// and the remaining ties are broken by the lowest Node address,
+// so that the ranking does not depend on the order in which the responses are returned.
+func rankClusters(clusters []*cluster) {
+	sort.SliceStable(clusters, func(i, j int) bool {
+		if len(clusters[i].responses) != len(clusters[j].responses) {
+			return len(clusters[i].responses) > len(clusters[j].responses)
+		}
+
+		if clusters[i].valid() != clusters[j].valid() {
+			return clusters[i].valid()
+		}
+
+		return bytes.Compare(clusters[i].lowestAddress(), clusters[j].lowestAddress()) < 0
+	})
+}
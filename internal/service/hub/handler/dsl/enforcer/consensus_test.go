@@ -0,0 +1,124 @@
+package enforcer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdatePointsBasedOnConsensus(t *testing.T) {
+	t.Parallel()
+
+	var (
+		node0 = common.HexToAddress("0x0000000000000000000000000000000000000010")
+		node1 = common.HexToAddress("0x0000000000000000000000000000000000000020")
+		node2 = common.HexToAddress("0x0000000000000000000000000000000000000030")
+		node3 = common.HexToAddress("0x0000000000000000000000000000000000000040")
+		node4 = common.HexToAddress("0x0000000000000000000000000000000000000050")
+	)
+
+	testCases := []struct {
+		name            string
+		responses       []*model.DataResponse
+		requests        []int
+		invalidRequests []int
+	}{
+		{
+			name: "Majority",
+			responses: []*model.DataResponse{
+				{Address: node0, Data: []byte(activityResponseData0), Valid: true},
+				{Address: node1, Data: []byte(activityResponseData1), Valid: true},
+				{Address: node2, Data: []byte(activityResponseData0), Valid: true},
+				{Address: node3, Data: []byte(activityResponseData1), Valid: true},
+				{Address: node4, Data: []byte(activityResponseData0), Valid: true},
+			},
+			requests:        []int{2, 0, 1, 0, 1},
+			invalidRequests: []int{0, 1, 0, 1, 0},
+		},
+		{
+			name: "FirstResponseInMinority",
+			responses: []*model.DataResponse{
+				{Address: node0, Data: []byte(activityResponseData1), Valid: true},
+				{Address: node1, Data: []byte(activityResponseData0), Valid: true},
+				{Address: node2, Data: []byte(activityResponseData0), Valid: true},
+				{Address: node3, Data: []byte(activityResponseData0), Valid: true},
+			},
+			requests:        []int{0, 1, 1, 1},
+			invalidRequests: []int{1, 0, 0, 0},
+		},
+		{
+			name: "TieBrokenByLowestAddress",
+			responses: []*model.DataResponse{
+				{Address: node3, Data: []byte(activityResponseData1), Valid: true},
+				{Address: node1, Data: []byte(activityResponseData0), Valid: true},
+				{Address: node2, Data: []byte(activityResponseData1), Valid: true},
+				{Address: node0, Data: []byte(activityResponseData0), Valid: true},
+				{Address: node4, Data: []byte(activityResponseData2), Valid: true},
+			},
+			requests:        []int{0, 1, 0, 1, 0},
+			invalidRequests: []int{0, 0, 0, 0, 1},
+		},
+		{
+			name: "NullMajority",
+			responses: []*model.DataResponse{
+				{Address: node0, Data: []byte(activityResponseData0), Valid: true},
+				{Address: node1, Data: []byte(nullData)},
+				{Address: node2, Data: []byte(nullData)},
+				{Address: node3, Data: []byte(nullData)},
+			},
+			requests:        []int{0, 1, 1, 1},
+			invalidRequests: []int{1, 0, 0, 0},
+		},
+		{
+			name: "NullTieBrokenByData",
+			responses: []*model.DataResponse{
+				{Address: node0, Data: []byte(nullData)},
+				{Address: node1, Data: []byte(activityResponseData0), Valid: true},
+				{Address: node2, Data: []byte(nullData)},
+				{Address: node3, Data: []byte(activityResponseData0), Valid: true},
+			},
+			requests:        []int{0, 1, 0, 1},
+			invalidRequests: []int{0, 0, 0, 0},
+		},
+		{
+			name: "ErrorResponses",
+			responses: []*model.DataResponse{
+				{Address: node0, Data: []byte(activityResponseData0), Valid: true},
+				{Address: node1, Data: []byte(activityResponseData0), Valid: true},
+				{Address: node2, Err: errors.New("error1")},
+				{Address: node3, Err: errors.New("error2")},
+			},
+			requests:        []int{2, 1, 0, 0},
+			invalidRequests: []int{0, 0, 1, 1},
+		},
+		{
+			name: "AllErrors",
+			responses: []*model.DataResponse{
+				{Address: node0, Err: errors.New("error1")},
+				{Address: node1, Err: errors.New("error2")},
+				{Address: node2, Err: errors.New("error3")},
+				{Address: node3, Err: errors.New("error4")},
+			},
+			requests:        []int{0, 0, 0, 0},
+			invalidRequests: []int{1, 1, 1, 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			updatePointsBasedOnConsensus(defaultPolicies(t), tc.responses)
+
+			for i, result := range tc.responses {
+				assert.Equal(t, tc.requests[i], result.ValidPoint)
+				assert.Equal(t, tc.invalidRequests[i], result.InvalidPoint)
+			}
+		})
+	}
+}
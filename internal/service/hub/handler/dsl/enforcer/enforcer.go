@@ -12,15 +12,20 @@ import (
 	"github.com/rss3-network/global-indexer/contract/l2"
 	"github.com/rss3-network/global-indexer/internal/cache"
 	"github.com/rss3-network/global-indexer/internal/config"
+	"github.com/rss3-network/global-indexer/internal/config/flag"
 	"github.com/rss3-network/global-indexer/internal/database"
+	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/breaker"
 	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/model"
+	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/verification"
+	"github.com/rss3-network/global-indexer/internal/service/settler/failure"
 	"github.com/rss3-network/global-indexer/schema"
 	"github.com/samber/lo"
+	"github.com/spf13/viper"
 	"go.uber.org/zap"
 )
 
 type Enforcer interface {
-	VerifyResponses(ctx context.Context, responses []*model.DataResponse, verify bool) error
+	VerifyResponses(ctx context.Context, request string, responses []*model.DataResponse) error
 	VerifyPartialResponses(ctx context.Context, epochID uint64, responses []*model.DataResponse)
 	MaintainReliabilityScore(ctx context.Context) error
 	MaintainEpochData(ctx context.Context, epoch int64) error
@@ -39,11 +44,15 @@ type SimpleEnforcer struct {
 	aiNodeScoreMaintainer   *ScoreMaintainer
 	txManager               txmgr.TxManager
 	settlerConfig           *config.Settler
+	distributorConfig       *config.Distributor
+	circuitBreaker          *breaker.CircuitBreaker
+	verificationPolicies    *verification.Registry
+	failureHandler          *failure.Handler
 	chainID                 *big.Int
 }
 
-// VerifyResponses verifies the responses from the Nodes.
-func (e *SimpleEnforcer) VerifyResponses(ctx context.Context, responses []*model.DataResponse, verify bool) error {
+// VerifyResponses verifies the responses from the Nodes under the verification policies of the request type.
+func (e *SimpleEnforcer) VerifyResponses(ctx context.Context, request string, responses []*model.DataResponse) error {
 	if len(responses) == 0 {
 		return fmt.Errorf("no response returned from nodes")
 	}
@@ -51,13 +60,21 @@ func (e *SimpleEnforcer) VerifyResponses(ctx context.Context, responses []*model
 	// non-error and non-null results are always put in front of the list
 	sortResponseByValidity(responses)
 
-	if verify {
+	policies := e.verificationPolicies.Policies(request)
+
+	switch policies.Request().Mode {
+	case verification.ModeIdentity:
 		// update requests based on data compare
-		updatePointsBasedOnIdentity(responses)
-	} else {
+		updatePointsBasedOnIdentity(policies, responses)
+	case verification.ModeMajority:
+		// update requests based on the majority of identical data
+		updatePointsBasedOnConsensus(policies, responses)
+	default:
 		// update requests based on data
 		updatePointsBasedOnData(responses)
 	}
+
+	applyQuorum(policies.Request().Quorum, responses)
 	// update the cache request
 	e.updateCacheRequest(ctx, responses)
 	// update the score maintainer
@@ -165,13 +182,20 @@ func (e *SimpleEnforcer) RetrieveQualifiedNodes(ctx context.Context, key string)
 		err        error
 	)
 
+	// The Nodes with an open circuit breaker are skipped, the half-open ones are only probed once they are requested,
+	// a failure to retrieve them should not prevent the request from being served.
+	openNodes, err := e.circuitBreaker.OpenNodes(ctx)
+	if err != nil {
+		zap.L().Error("get open nodes from circuit breaker", zap.Error(err))
+	}
+
 	switch key {
 	case model.RssNodeCacheKey:
-		nodesCache, err = e.rssNodeScoreMaintainer.retrieveQualifiedNodes(ctx, key, model.RequiredQualifiedNodeCount)
+		nodesCache, err = e.rssNodeScoreMaintainer.retrieveQualifiedNodes(ctx, key, model.RequiredQualifiedNodeCount, openNodes)
 	case model.FullNodeCacheKey:
-		nodesCache, err = e.fullNodeScoreMaintainer.retrieveQualifiedNodes(ctx, key, model.RequiredQualifiedNodeCount)
+		nodesCache, err = e.fullNodeScoreMaintainer.retrieveQualifiedNodes(ctx, key, model.RequiredQualifiedNodeCount, openNodes)
 	case model.AINodeCacheKey:
-		nodesCache, err = e.aiNodeScoreMaintainer.retrieveQualifiedNodes(ctx, key, model.RequiredQualifiedNodeCount)
+		nodesCache, err = e.aiNodeScoreMaintainer.retrieveQualifiedNodes(ctx, key, model.RequiredQualifiedNodeCount, openNodes)
 	default:
 		return nil, fmt.Errorf("unknown cache key: %s", key)
 	}
@@ -184,7 +208,17 @@ func (e *SimpleEnforcer) RetrieveQualifiedNodes(ctx context.Context, key string)
 	return nodesCache, err
 }
 
-func NewSimpleEnforcer(ctx context.Context, databaseClient database.Client, cacheClient cache.Client, stakingContract *l2.StakingV2MulticallClient, networkParamsContract *l2.NetworkParams, httpClient httputil.Client, txManager *txmgr.SimpleTxManager, settlerConfig *config.Settler, chainID *big.Int, initCacheData bool) (*SimpleEnforcer, error) {
+func NewSimpleEnforcer(ctx context.Context, databaseClient database.Client, cacheClient cache.Client, stakingContract *l2.StakingV2MulticallClient, networkParamsContract *l2.NetworkParams, httpClient httputil.Client, txManager *txmgr.SimpleTxManager, settlerConfig *config.Settler, distributorConfig *config.Distributor, chainID *big.Int, initCacheData bool) (*SimpleEnforcer, error) {
+	var circuitBreakerConfig *config.CircuitBreaker
+	if distributorConfig != nil {
+		circuitBreakerConfig = distributorConfig.CircuitBreaker
+	}
+
+	verificationPolicies, err := verification.New(distributorConfig)
+	if err != nil {
+		return nil, fmt.Errorf("load verification policies: %w", err)
+	}
+
 	enforcer := &SimpleEnforcer{
 		databaseClient:        databaseClient,
 		cacheClient:           cacheClient,
@@ -193,9 +227,16 @@ func NewSimpleEnforcer(ctx context.Context, databaseClient database.Client, cach
 		httpClient:            httpClient,
 		txManager:             txManager,
 		settlerConfig:         settlerConfig,
+		distributorConfig:     distributorConfig,
+		circuitBreaker:        breaker.New(cacheClient, circuitBreakerConfig),
+		verificationPolicies:  verificationPolicies,
 		chainID:               chainID,
 	}
 
+	if settlerConfig != nil {
+		enforcer.failureHandler = failure.NewHandler(failure.ServiceEnforcer, databaseClient, httpClient, settlerConfig.Failure)
+	}
+
 	if initCacheData {
 		if err := enforcer.initWorkerMap(ctx); err != nil {
 			return nil, err
@@ -206,6 +247,9 @@ func NewSimpleEnforcer(ctx context.Context, databaseClient database.Client, cach
 		}
 
 		subscribeNodeCacheUpdate(ctx, cacheClient, databaseClient, enforcer.fullNodeScoreMaintainer, enforcer.rssNodeScoreMaintainer, enforcer.aiNodeScoreMaintainer)
+
+		// The responses are only verified by the hub, which reloads the verification policies on SIGHUP.
+		verificationPolicies.ReloadOnSignal(ctx, viper.GetString(flag.KeyConfig))
 	}
 
 	return enforcer, nil
@@ -301,5 +345,15 @@ func (e *SimpleEnforcer) initScoreMaintainer(ctx context.Context, nodeType strin
 		return nil, err
 	}
 
-	return newScoreMaintainer(ctx, nodeType, nodeStats, e.cacheClient)
+	var selectionConfig *config.NodeSelection
+	if e.distributorConfig != nil {
+		selectionConfig = e.distributorConfig.NodeSelection[nodeType]
+	}
+
+	selectionStrategy, err := newSelectionStrategy(selectionConfig, e.databaseClient)
+	if err != nil {
+		return nil, fmt.Errorf("new selection strategy for %s: %w", nodeType, err)
+	}
+
+	return newScoreMaintainer(ctx, nodeType, nodeStats, e.cacheClient, selectionStrategy)
 }
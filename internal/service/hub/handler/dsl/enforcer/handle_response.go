@@ -12,6 +12,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/model"
+	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/verification"
 	"github.com/rss3-network/global-indexer/schema"
 	"github.com/rss3-network/node/v2/schema/worker/decentralized"
 	"github.com/samber/lo"
@@ -137,6 +138,8 @@ func (e *SimpleEnforcer) verifyPartialActivities(ctx context.Context, epochID ui
 	platformMap := make(map[string]struct{}, model.RequiredVerificationCount)
 	// statMap is used to store the stats that have been verified
 	statMap := make(map[string]struct{})
+	// the activities are verified by fetching them one by one from other Nodes
+	policies := e.verificationPolicies.Policies(verification.RequestDecentralizedActivity)
 
 	nodeInvalidResponse := &schema.NodeInvalidResponse{
 		EpochID:       epochID,
@@ -151,10 +154,10 @@ func (e *SimpleEnforcer) verifyPartialActivities(ctx context.Context, epochID ui
 			continue
 		}
 
-		toleranceTime := time.Now().Unix() - int64(model.ToleranceSeconds)
+		policy := policies.Activity(activity)
 
 		// This usually indicates that the activity timestamp is too new to be verified.
-		if activity.Timestamp > uint64(toleranceTime) {
+		if !policy.IsSettled(activity, time.Now()) {
 			continue
 		}
 
@@ -173,7 +176,7 @@ func (e *SimpleEnforcer) verifyPartialActivities(ctx context.Context, epochID ui
 			continue
 		}
 
-		e.verifyActivityByStats(ctx, activity, stats, statMap, platformMap, nodeInvalidResponse)
+		e.verifyActivityByStats(ctx, policy, activity, stats, statMap, platformMap, nodeInvalidResponse)
 
 		// If the platform count reaches the RequiredVerificationCount, exit the verification loop.
 		if _, exists := platformMap[activity.Platform]; !exists {
@@ -234,14 +237,14 @@ func excludeWorkingNodes(indexers []*schema.Worker, workingNodes []common.Addres
 }
 
 // verifyActivityByStats verifies the activity based on stats nodes that meet specific criteria.
-func (e *SimpleEnforcer) verifyActivityByStats(ctx context.Context, activity *model.Activity, stats []*schema.Stat, statMap, platformMap map[string]struct{}, nodeInvalidResponse *schema.NodeInvalidResponse) {
+func (e *SimpleEnforcer) verifyActivityByStats(ctx context.Context, policy *verification.Policy, activity *model.Activity, stats []*schema.Stat, statMap, platformMap map[string]struct{}, nodeInvalidResponse *schema.NodeInvalidResponse) {
 	for _, stat := range stats {
 		if _, exists := statMap[stat.Address.String()]; !exists {
 			statMap[stat.Address.String()] = struct{}{}
 
 			activityFetched, err := e.fetchActivityByTxID(ctx, stat.Endpoint, stat.AccessToken, activity.ID)
 
-			if err != nil || activityFetched.Data == nil || !isActivityIdentical(activity, activityFetched.Data) {
+			if err != nil || activityFetched.Data == nil || !policy.IsActivityIdentical(activity, activityFetched.Data) {
 				stat.EpochInvalidRequest += invalidPointUnit
 
 				nodeInvalidResponse.Type = lo.Ternary(err != nil, schema.NodeInvalidResponseTypeError, schema.NodeInvalidResponseTypeInconsistent)
@@ -316,15 +319,42 @@ func sortResponseByValidity(responses []*model.DataResponse) {
 }
 
 // updatePointsBasedOnIdentity updates both  based on responses identity.
-func updatePointsBasedOnIdentity(responses []*model.DataResponse) {
+// The comparison is built around three responses, more responses are scored by the majority consensus.
+func updatePointsBasedOnIdentity(policies *verification.Policies, responses []*model.DataResponse) {
+	if len(responses) > 3 {
+		updatePointsBasedOnConsensus(policies, responses)
+
+		return
+	}
+
 	errResponseCount := countAndMarkErrorResponse(responses)
 
-	if len(responses) == model.RequiredQualifiedNodeCount-1 {
-		handleTwoResponses(responses)
-	} else if len(responses) == model.RequiredQualifiedNodeCount-2 {
+	switch len(responses) {
+	case 1:
 		handleSingleResponse(responses)
-	} else {
-		handleFullResponses(responses, errResponseCount)
+	case 2:
+		handleTwoResponses(policies, responses)
+	default:
+		handleFullResponses(policies, responses, errResponseCount)
+	}
+}
+
+// applyQuorum withdraws the points of the non-error responses if fewer responses than the quorum are valid,
+// as the responses are inconclusive.
+func applyQuorum(quorum int, responses []*model.DataResponse) {
+	validCount := lo.CountBy(responses, func(response *model.DataResponse) bool {
+		return response.ValidPoint > 0
+	})
+
+	if validCount >= quorum {
+		return
+	}
+
+	for i := range responses {
+		if responses[i].Err == nil {
+			responses[i].ValidPoint = 0
+			responses[i].InvalidPoint = 0
+		}
 	}
 }
 
@@ -375,8 +405,8 @@ func isValidAIResponse(data []byte) bool {
 	return false
 }
 
-// isResponseIdentical returns true if two byte slices (responses) are identical.
-func isResponseIdentical(src, des []byte) bool {
+// isResponseIdentical returns true if two byte slices (responses) are identical under the verification policies.
+func isResponseIdentical(policies *verification.Policies, src, des []byte) bool {
 	srcActivity := &model.ActivityResponse{}
 	desActivity := &model.ActivityResponse{}
 
@@ -385,11 +415,11 @@ func isResponseIdentical(src, des []byte) bool {
 		if srcActivity.Data == nil && desActivity.Data == nil {
 			return true
 		} else if srcActivity.Data != nil && desActivity.Data != nil {
-			if _, exist := model.MutablePlatformMap[srcActivity.Data.Platform]; !exist {
-				toleranceTime := time.Now().Unix() - int64(model.ToleranceSeconds)
+			if policy := policies.Activity(srcActivity.Data); !policy.IsSkipped() {
+				now := time.Now()
 
-				if srcActivity.Data.Timestamp <= uint64(toleranceTime) && desActivity.Data.Timestamp <= uint64(toleranceTime) {
-					return isActivityIdentical(srcActivity.Data, desActivity.Data)
+				if policy.IsSettled(srcActivity.Data, now) && policy.IsSettled(desActivity.Data, now) {
+					return policy.IsActivityIdentical(srcActivity.Data, desActivity.Data)
 				}
 			}
 
@@ -404,10 +434,10 @@ func isResponseIdentical(src, des []byte) bool {
 		if srcActivities.Data == nil && desActivities.Data == nil {
 			return true
 		} else if srcActivities.Data != nil && desActivities.Data != nil {
-			// exclude the mutable platforms
-			srcActivity, desActivity := excludeMutableActivity(srcActivities.Data), excludeMutableActivity(desActivities.Data)
+			// exclude the activities skipped by the policies, such as the ones of the mutable platforms
+			srcActivity, desActivity := excludeSkippedActivity(policies, srcActivities.Data), excludeSkippedActivity(policies, desActivities.Data)
 
-			return checkActivities(srcActivity, desActivity)
+			return checkActivities(policies, srcActivity, desActivity)
 		}
 	}
 
@@ -415,8 +445,8 @@ func isResponseIdentical(src, des []byte) bool {
 }
 
 // checkActivities checks if the activities are identical.
-func checkActivities(srcActivities, desActivities []*model.Activity) bool {
-	srcFilterActivities, desFilterActivities := filterToleranceActivity(srcActivities), filterToleranceActivity(desActivities)
+func checkActivities(policies *verification.Policies, srcActivities, desActivities []*model.Activity) bool {
+	srcFilterActivities, desFilterActivities := filterToleranceActivity(policies, srcActivities), filterToleranceActivity(policies, desActivities)
 
 	// Check if the original activities are empty.
 	if (len(srcActivities) == 0 && len(desFilterActivities) > 0) || (len(desActivities) == 0 && len(srcFilterActivities) > 0) {
@@ -446,7 +476,7 @@ func checkActivities(srcActivities, desActivities []*model.Activity) bool {
 		act := activity
 
 		p.Go(func(_ context.Context) error {
-			if matchedActivity, exist := desActivitiesMap[fmt.Sprintf("%s-%s-%s", act.ID, act.Network, act.Owner)]; !exist || !isActivityIdentical(act, matchedActivity) {
+			if matchedActivity, exist := desActivitiesMap[fmt.Sprintf("%s-%s-%s", act.ID, act.Network, act.Owner)]; !exist || !policies.Activity(act).IsActivityIdentical(act, matchedActivity) {
 				return fmt.Errorf("activities are not identical")
 			}
 
@@ -463,14 +493,14 @@ func checkActivities(srcActivities, desActivities []*model.Activity) bool {
 	return true
 }
 
-// filterToleranceActivity filters the activities based on the tolerance time.
-func filterToleranceActivity(activities []*model.Activity) []*model.Activity {
-	toleranceTime := time.Now().Unix() - int64(model.ToleranceSeconds)
+// filterToleranceActivity filters the activities based on the tolerance time of their policies.
+func filterToleranceActivity(policies *verification.Policies, activities []*model.Activity) []*model.Activity {
+	now := time.Now()
 
 	filterActivities := make([]*model.Activity, 0, len(activities))
 
 	for i := range activities {
-		if activities[i].Timestamp <= uint64(toleranceTime) {
+		if policies.Activity(activities[i]).IsSettled(activities[i], now) {
 			filterActivities = append(filterActivities, activities[i])
 		}
 	}
@@ -478,12 +508,12 @@ func filterToleranceActivity(activities []*model.Activity) []*model.Activity {
 	return filterActivities
 }
 
-// excludeMutableActivity excludes the mutable platforms from the activities.
-func excludeMutableActivity(activities []*model.Activity) []*model.Activity {
+// excludeSkippedActivity excludes the activities skipped by their policies.
+func excludeSkippedActivity(policies *verification.Policies, activities []*model.Activity) []*model.Activity {
 	var newActivities []*model.Activity
 
 	for i := range activities {
-		if _, exist := model.MutablePlatformMap[activities[i].Platform]; !exist {
+		if !policies.Activity(activities[i]).IsSkipped() {
 			newActivities = append(newActivities, activities[i])
 		}
 	}
@@ -491,35 +521,6 @@ func excludeMutableActivity(activities []*model.Activity) []*model.Activity {
 	return newActivities
 }
 
-// isActivityIdentical returns true if two Activity are identical.
-func isActivityIdentical(src, des *model.Activity) bool {
-	if src.ID != des.ID ||
-		src.Network != des.Network ||
-		src.Index != des.Index ||
-		src.From != des.From ||
-		src.To != des.To ||
-		src.Tag != des.Tag ||
-		src.Type != des.Type ||
-		src.Platform != des.Platform ||
-		len(src.Actions) != len(des.Actions) {
-		return false
-	}
-
-	// check if the inner actions are identical
-	if len(src.Actions) > 0 {
-		for i := range des.Actions {
-			if src.Actions[i].From != des.Actions[i].From ||
-				src.Actions[i].To != des.Actions[i].To ||
-				src.Actions[i].Tag != des.Actions[i].Tag ||
-				src.Actions[i].Type != des.Actions[i].Type {
-				return false
-			}
-		}
-	}
-
-	return true
-}
-
 // isDataValid returns true if the data is valid.
 func isDataValid(data []byte, target any) bool {
 	if err := json.Unmarshal(data, target); err == nil {
@@ -542,9 +543,9 @@ func countAndMarkErrorResponse(responses []*model.DataResponse) (errResponseCoun
 }
 
 // handleTwoResponses handles the case when there are two responses.
-func handleTwoResponses(responses []*model.DataResponse) {
+func handleTwoResponses(policies *verification.Policies, responses []*model.DataResponse) {
 	if responses[0].Err == nil && responses[1].Err == nil {
-		updateRequestBasedOnComparison(responses)
+		updateRequestBasedOnComparison(policies, responses)
 	} else {
 		markErrorResponse(responses...)
 	}
@@ -560,15 +561,15 @@ func handleSingleResponse(responses []*model.DataResponse) {
 }
 
 // handleFullResponses handles the case when there are more than two results.
-func handleFullResponses(responses []*model.DataResponse, errResponseCount int) {
+func handleFullResponses(policies *verification.Policies, responses []*model.DataResponse, errResponseCount int) {
 	if errResponseCount < len(responses) {
-		compareAndAssignPoints(responses, errResponseCount)
+		compareAndAssignPoints(policies, responses, errResponseCount)
 	}
 }
 
 // updateRequestBasedOnComparison updates the requests based on the comparison of the data.
-func updateRequestBasedOnComparison(responses []*model.DataResponse) {
-	if isResponseIdentical(responses[0].Data, responses[1].Data) {
+func updateRequestBasedOnComparison(policies *verification.Policies, responses []*model.DataResponse) {
+	if isResponseIdentical(policies, responses[0].Data, responses[1].Data) {
 		responses[0].ValidPoint = 2 * validPointUnit
 		responses[1].ValidPoint = validPointUnit
 	} else {
@@ -588,9 +589,9 @@ func markErrorResponse(responses ...*model.DataResponse) {
 }
 
 // compareAndAssignPoints compares the data for identity and assigns corresponding points.
-func compareAndAssignPoints(responses []*model.DataResponse, errResponseCount int) {
+func compareAndAssignPoints(policies *verification.Policies, responses []*model.DataResponse, errResponseCount int) {
 	d0, d1, d2 := responses[0].Data, responses[1].Data, responses[2].Data
-	diff01, diff02, diff12 := isResponseIdentical(d0, d1), isResponseIdentical(d0, d2), isResponseIdentical(d1, d2)
+	diff01, diff02, diff12 := isResponseIdentical(policies, d0, d1), isResponseIdentical(policies, d0, d2), isResponseIdentical(policies, d1, d2)
 
 	switch errResponseCount {
 	// responses contain 2 errors
@@ -5,7 +5,9 @@ import (
 	"testing"
 
 	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/model"
+	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/verification"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -92,7 +94,7 @@ func TestCompareData(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
-			result := isResponseIdentical(tc.src, tc.des)
+			result := isResponseIdentical(defaultPolicies(t), tc.src, tc.des)
 			assert.Equal(t, tc.expected, result)
 		})
 	}
@@ -267,7 +269,7 @@ func TestUpdateRequestsBasedOnDataCompare(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
-			updatePointsBasedOnIdentity(tc.responses)
+			updatePointsBasedOnIdentity(defaultPolicies(t), tc.responses)
 
 			for i, result := range tc.responses {
 				assert.Equal(t, tc.requests[i], result.ValidPoint)
@@ -276,3 +278,63 @@ func TestUpdateRequestsBasedOnDataCompare(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyQuorum(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		quorum          int
+		responses       []*model.DataResponse
+		requests        []int
+		invalidRequests []int
+	}{
+		{
+			name:   "QuorumReached",
+			quorum: 2,
+			responses: []*model.DataResponse{
+				{ValidPoint: 2},
+				{ValidPoint: 1},
+				{InvalidPoint: 1},
+			},
+			requests:        []int{2, 1, 0},
+			invalidRequests: []int{0, 0, 1},
+		},
+		{
+			name:   "QuorumNotReached",
+			quorum: 2,
+			responses: []*model.DataResponse{
+				{ValidPoint: 1},
+				{},
+				{Err: errors.New("error"), InvalidPoint: 1},
+			},
+			requests:        []int{0, 0, 0},
+			invalidRequests: []int{0, 0, 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			applyQuorum(tc.quorum, tc.responses)
+
+			for i, result := range tc.responses {
+				assert.Equal(t, tc.requests[i], result.ValidPoint)
+				assert.Equal(t, tc.invalidRequests[i], result.InvalidPoint)
+			}
+		})
+	}
+}
+
+// defaultPolicies returns the built-in verification policies of the decentralized activities.
+func defaultPolicies(t *testing.T) *verification.Policies {
+	t.Helper()
+
+	registry, err := verification.New(nil)
+	require.NoError(t, err)
+
+	return registry.Policies(verification.RequestDecentralizedActivities)
+}
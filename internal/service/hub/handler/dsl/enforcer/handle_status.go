@@ -1,11 +1,15 @@
 package enforcer
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"math/big"
+	"net/http"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
@@ -14,6 +18,8 @@ import (
 	"github.com/rss3-network/global-indexer/common/ethereum"
 	"github.com/rss3-network/global-indexer/common/txmgr"
 	"github.com/rss3-network/global-indexer/contract/l2"
+	ethereumclient "github.com/rss3-network/global-indexer/internal/client/ethereum"
+	"github.com/rss3-network/global-indexer/internal/service/settler/failure"
 	"github.com/rss3-network/global-indexer/schema"
 	"github.com/rss3-network/node/v2/schema/worker"
 	"github.com/samber/lo"
@@ -36,7 +42,7 @@ func (e *SimpleEnforcer) maintainNodeStatus(ctx context.Context) error {
 	}
 
 	// retrieve the node info from the VSL
-	nodeVSLInfo, err := e.stakingContract.GetNodes(&bind.CallOpts{}, lo.Map(nodes, func(node *schema.Node, _ int) common.Address {
+	nodeVSLInfo, err := e.stakingContract.GetNodes(&bind.CallOpts{Context: ethereumclient.WithQuorum(ctx)}, lo.Map(nodes, func(node *schema.Node, _ int) common.Address {
 		return node.Address
 	}))
 	if err != nil {
@@ -88,7 +94,9 @@ func (e *SimpleEnforcer) maintainNodeStatus(ctx context.Context) error {
 				if newStatus == schema.NodeStatusOffline {
 					responseValue, _ := json.Marshal(fmt.Sprintf(`{"error_message": "%s"}`, errPath))
 
-					e.saveOfflineStatusToInvalidResponse(ctx, uint64(currentEpoch), nodes[i].Address, fmt.Sprintf("%s/%s", nodes[i].Endpoint, errPath), responseValue)
+					e.saveOfflineStatusToInvalidResponse(ctx, uint64(currentEpoch), nodes[i], fmt.Sprintf("%s/%s", nodes[i].Endpoint, errPath), responseValue)
+
+					go e.alertNodeOffline(context.WithoutCancel(ctx), nodes[i], errPath)
 				}
 			}
 		// Handle cases for Online and Exiting statuses
@@ -101,7 +109,10 @@ func (e *SimpleEnforcer) maintainNodeStatus(ctx context.Context) error {
 				// reasons = append(reasons, "offline")
 				// reporters = append(reporters, ethereum.AddressGenesis)
 				responseValue, _ := json.Marshal(fmt.Sprintf(`{"error_message": "%s"}`, "heartbeat"))
-				e.saveOfflineStatusToInvalidResponse(ctx, uint64(currentEpoch), nodes[i].Address, "", responseValue)
+				e.saveOfflineStatusToInvalidResponse(ctx, uint64(currentEpoch), nodes[i], "", responseValue)
+
+				go e.alertNodeOffline(context.WithoutCancel(ctx), nodes[i], "heartbeat")
+
 				updatedNodes = append(updatedNodes, nodes[i])
 			}
 		}
@@ -192,15 +203,22 @@ func (e *SimpleEnforcer) updateNodeStatuses(ctx context.Context, updatedNodes []
 	return e.updateNodeStatusAndSubmitDemotionToVSL(ctx, nodeAddresses, nodeStatusList, demotionNodeAddresses, reasons, reporters)
 }
 
-// saveOfflineStatusToInvalidResponse saves the offline status to the invalid response table
-func (e *SimpleEnforcer) saveOfflineStatusToInvalidResponse(ctx context.Context, epochID uint64, nodeAddress common.Address, request string, response json.RawMessage) {
+// saveOfflineStatusToInvalidResponse saves the offline status to the invalid response table,
+// nothing is saved for a node under maintenance
+func (e *SimpleEnforcer) saveOfflineStatusToInvalidResponse(ctx context.Context, epochID uint64, node *schema.Node, request string, response json.RawMessage) {
+	if node.Preferences.InMaintenance(time.Now()) {
+		zap.L().Info("skip the offline status of the node under maintenance", zap.String("address", node.Address.String()))
+
+		return
+	}
+
 	nodeInvalidResponse := &schema.NodeInvalidResponse{
 		EpochID:          epochID,
 		Type:             schema.NodeInvalidResponseTypeOffline,
 		VerifierNodes:    []common.Address{ethereum.AddressGenesis},
 		Request:          request,
 		VerifierResponse: json.RawMessage{},
-		Node:             nodeAddress,
+		Node:             node.Address,
 		Response:         response,
 	}
 
@@ -209,6 +227,45 @@ func (e *SimpleEnforcer) saveOfflineStatusToInvalidResponse(ctx context.Context,
 	}
 }
 
+// nodeAlert is posted to the alert webhooks of a Node.
+type nodeAlert struct {
+	Node      common.Address    `json:"node"`
+	Status    schema.NodeStatus `json:"status"`
+	Reason    string            `json:"reason"`
+	Timestamp int64             `json:"timestamp"`
+}
+
+// alertNodeOffline notifies the alert webhooks of a Node that it has gone offline,
+// nothing is sent for a node under maintenance, and errors are logged as the alerts are best effort.
+func (e *SimpleEnforcer) alertNodeOffline(ctx context.Context, node *schema.Node, reason string) {
+	if node.Preferences == nil || len(node.Preferences.AlertWebhooks) == 0 || node.Preferences.InMaintenance(time.Now()) {
+		return
+	}
+
+	body, err := json.Marshal(nodeAlert{
+		Node:      node.Address,
+		Status:    schema.NodeStatusOffline,
+		Reason:    reason,
+		Timestamp: time.Now().Unix(),
+	})
+	if err != nil {
+		zap.L().Error("marshal node alert", zap.Error(err))
+
+		return
+	}
+
+	for _, webhook := range node.Preferences.AlertWebhooks {
+		response, err := e.httpClient.FetchWithMethod(ctx, http.MethodPost, webhook, "", bytes.NewReader(body))
+		if err != nil {
+			zap.L().Error("send node alert", zap.String("address", node.Address.String()), zap.String("webhook", webhook), zap.Error(err))
+
+			continue
+		}
+
+		lo.Try(response.Close)
+	}
+}
+
 // updateNodeStatusAndSubmitDemotionToVSL updates node statuses and submits demotion information to VSL
 func (e *SimpleEnforcer) updateNodeStatusAndSubmitDemotionToVSL(ctx context.Context, nodeAddresses []common.Address, nodeStatusList []uint8, demotionNodeAddresses []common.Address, reasons []string, reporters []common.Address) error {
 	data, err := prepareSetNodeStatusAndSubmitDemotionsData(nodeAddresses, nodeStatusList, demotionNodeAddresses, reasons, reporters)
@@ -217,6 +274,11 @@ func (e *SimpleEnforcer) updateNodeStatusAndSubmitDemotionToVSL(ctx context.Cont
 		return err
 	}
 
+	// Wait for the failed transactions left by a previous run to be resolved before submitting new statuses
+	if err = e.failureHandler.Resume(ctx, e.resendSettlementFailure); err != nil {
+		return fmt.Errorf("resume settlement failures: %w", err)
+	}
+
 	if err = e.invokeSettlementMultiContract(ctx, data); err != nil {
 		return fmt.Errorf("invoke settlement contract: %w", err)
 	}
@@ -258,35 +320,56 @@ func (e *SimpleEnforcer) invokeSettlementMultiContract(ctx context.Context, data
 		return fmt.Errorf("encode input: %w", err)
 	}
 
-	if err = e.sendTransaction(ctx, input); err != nil {
+	_, err = e.sendTransaction(ctx, input)
+
+	var failedErr *failure.TransactionFailedError
+	if !errors.As(err, &failedErr) {
 		return err
 	}
 
-	return nil
+	epoch, err := e.getCurrentEpoch(ctx)
+	if err != nil {
+		return fmt.Errorf("get current epoch: %w", err)
+	}
+
+	// Block until an operator retries, skips or aborts the failed transaction
+	_, err = e.failureHandler.Handle(ctx, uint64(epoch), input, nil, failedErr, e.resendSettlementFailure)
+	if errors.Is(err, failure.ErrorSkipped) {
+		zap.L().Warn("node status transaction skipped by operator", zap.Int64("epoch", epoch))
+
+		return nil
+	}
+
+	return err
+}
+
+// resendSettlementFailure resends a failed transaction to the VSL
+func (e *SimpleEnforcer) resendSettlementFailure(ctx context.Context, settlementFailure *schema.SettlementFailure) (*types.Receipt, error) {
+	return e.sendTransaction(ctx, settlementFailure.Input)
 }
 
 // sendTransaction sends a transaction to the VSL
-func (e *SimpleEnforcer) sendTransaction(ctx context.Context, input []byte) error {
+func (e *SimpleEnforcer) sendTransaction(ctx context.Context, input []byte) (*types.Receipt, error) {
 	txCandidate := txmgr.TxCandidate{
 		TxData:   input,
 		To:       lo.ToPtr(l2.ContractMap[e.chainID.Uint64()].AddressSettlementProxy),
 		GasLimit: e.settlerConfig.GasLimit,
 		Value:    big.NewInt(0),
+		Priority: txmgr.PriorityDemotion,
 	}
 
 	receipt, err := e.txManager.Send(ctx, txCandidate)
 	if err != nil {
-		return fmt.Errorf("failed to send tx: %w", err)
+		return nil, fmt.Errorf("failed to send tx: %w", err)
 	}
 
 	if receipt.Status != types.ReceiptStatusSuccessful {
 		zap.L().Error("received an invalid transaction receipt", zap.String("tx", receipt.TxHash.String()))
 
-		// Fixme: retry logic and error handling
-		select {}
+		return nil, &failure.TransactionFailedError{Receipt: receipt}
 	}
 
-	return nil
+	return receipt, nil
 }
 
 // getNodeMinVersion retrieves the minimum node version from the network params contract
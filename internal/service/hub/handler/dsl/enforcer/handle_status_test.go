@@ -3,12 +3,15 @@ package enforcer
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/hashicorp/go-version"
+	"github.com/rss3-network/global-indexer/internal/database"
 	"github.com/rss3-network/global-indexer/schema"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -160,6 +163,118 @@ func TestDetermineStatus(t *testing.T) {
 	}
 }
 
+// invalidResponseDatabaseClient records the invalid responses saved.
+type invalidResponseDatabaseClient struct {
+	database.Client
+
+	responses []*schema.NodeInvalidResponse
+}
+
+func (c *invalidResponseDatabaseClient) SaveNodeInvalidResponses(_ context.Context, responses []*schema.NodeInvalidResponse) error {
+	c.responses = append(c.responses, responses...)
+
+	return nil
+}
+
+func TestSaveOfflineStatusToInvalidResponse(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().Unix()
+
+	tests := []struct {
+		name          string
+		preferences   *schema.NodePreferences
+		expectedSaved bool
+	}{
+		{
+			name:          "NoPreferences",
+			expectedSaved: true,
+		},
+		{
+			name: "MaintenanceEnded",
+			preferences: &schema.NodePreferences{
+				MaintenanceWindows: []*schema.NodeMaintenanceWindow{{StartAt: now - 7200, Duration: 3600}},
+			},
+			expectedSaved: true,
+		},
+		{
+			name: "UnderMaintenance",
+			preferences: &schema.NodePreferences{
+				MaintenanceWindows: []*schema.NodeMaintenanceWindow{{StartAt: now - 60, Duration: 3600}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			databaseClient := &invalidResponseDatabaseClient{}
+			enforcer := &SimpleEnforcer{databaseClient: databaseClient}
+
+			enforcer.saveOfflineStatusToInvalidResponse(context.Background(), 1, &schema.Node{
+				Address:     common.Address{1},
+				Preferences: tt.preferences,
+			}, "", json.RawMessage(`"heartbeat"`))
+
+			assert.Equal(t, tt.expectedSaved, len(databaseClient.responses) == 1)
+		})
+	}
+}
+
+func TestAlertNodeOffline(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().Unix()
+	webhooks := []string{"https://alerts.example.com/1", "https://alerts.example.com/2"}
+
+	tests := []struct {
+		name          string
+		preferences   *schema.NodePreferences
+		expectedSends int
+	}{
+		{
+			name: "NoPreferences",
+		},
+		{
+			name:          "Webhooks",
+			preferences:   &schema.NodePreferences{AlertWebhooks: webhooks},
+			expectedSends: 2,
+		},
+		{
+			name: "UnderMaintenance",
+			preferences: &schema.NodePreferences{
+				AlertWebhooks:      webhooks,
+				MaintenanceWindows: []*schema.NodeMaintenanceWindow{{StartAt: now - 60, Duration: 3600}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockClient := new(MockHTTPClient)
+			mockClient.On("FetchWithMethod", mock.Anything, "https://alerts.example.com/1").Return(io.NopCloser(bytes.NewReader(nil)), errors.New("unreachable"))
+			mockClient.On("FetchWithMethod", mock.Anything, "https://alerts.example.com/2").Return(io.NopCloser(bytes.NewReader(nil)), nil)
+
+			enforcer := &SimpleEnforcer{httpClient: mockClient}
+
+			enforcer.alertNodeOffline(context.Background(), &schema.Node{
+				Address:     common.Address{1},
+				Preferences: tt.preferences,
+			}, "heartbeat")
+
+			// A failed webhook does not prevent the others from being notified.
+			mockClient.AssertNumberOfCalls(t, "FetchWithMethod", tt.expectedSends)
+		})
+	}
+}
+
 func setupMockClient(workerStatus string) *MockHTTPClient {
 	mockClient := new(MockHTTPClient)
 	if workerStatus == "" {
@@ -12,6 +12,7 @@ import (
 	"github.com/redis/go-redis/v9"
 	v2 "github.com/rss3-network/global-indexer/contract/l2/staking/v2"
 	"github.com/rss3-network/global-indexer/internal/cache"
+	"github.com/rss3-network/global-indexer/internal/client/ethereum"
 	"github.com/rss3-network/global-indexer/internal/database"
 	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/model"
 	"github.com/rss3-network/global-indexer/schema"
@@ -49,7 +50,7 @@ func (e *SimpleEnforcer) maintainNodeWorker(ctx context.Context, epoch int64, st
 
 	// get node info from chain
 	eg.Go(func() error {
-		nodeVSLInfo, err = e.stakingContract.GetNodes(&bind.CallOpts{}, addresses)
+		nodeVSLInfo, err = e.stakingContract.GetNodes(&bind.CallOpts{Context: ethereum.WithQuorum(ctx)}, addresses)
 		return err
 	})
 
@@ -129,7 +130,7 @@ func (e *SimpleEnforcer) filterNodeStatus(ctx context.Context, stats []*schema.S
 		return nil, nil, fmt.Errorf("find alpha nodes: %w", err)
 	}
 
-	alphaNodesVSLInfo, err := e.stakingContract.GetNodes(&bind.CallOpts{}, lo.Map(alphaNodes, func(node *schema.Node, _ int) common.Address {
+	alphaNodesVSLInfo, err := e.stakingContract.GetNodes(&bind.CallOpts{Context: ethereum.WithQuorum(ctx)}, lo.Map(alphaNodes, func(node *schema.Node, _ int) common.Address {
 		return node.Address
 	}))
 
@@ -0,0 +1,61 @@
+package enforcer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/model"
+	"github.com/rss3-network/global-indexer/schema"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+)
+
+// MaintainNodeMaintenance updates the sorted sets of the Nodes when a maintenance window of a Node starts or ends,
+// so the Nodes under maintenance are not routed to, and rejoin the sorted sets once their windows end.
+func (e *SimpleEnforcer) MaintainNodeMaintenance(ctx context.Context) error {
+	nodes, err := e.databaseClient.FindNodes(ctx, schema.FindNodesQuery{})
+	if err != nil {
+		return fmt.Errorf("find nodes: %w", err)
+	}
+
+	maintenanceNodes := findMaintenanceNodes(nodes, time.Now())
+
+	var previousMaintenanceNodes []string
+	if err := e.cacheClient.Get(ctx, model.MaintenanceNodeCacheKey, &previousMaintenanceNodes); err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("get maintenance nodes from cache: %w", err)
+	}
+
+	if slices.Equal(previousMaintenanceNodes, maintenanceNodes) {
+		return nil
+	}
+
+	epoch, err := e.getCurrentEpoch(ctx)
+	if err != nil {
+		return fmt.Errorf("get current epoch: %w", err)
+	}
+
+	// Setting the epoch again also makes the hubs update their qualified Nodes.
+	if err := e.updateNodeCache(ctx, epoch); err != nil {
+		return fmt.Errorf("update node cache: %w", err)
+	}
+
+	zap.L().Info("nodes under maintenance changed", zap.Strings("previous", previousMaintenanceNodes), zap.Strings("current", maintenanceNodes))
+
+	return e.cacheClient.Set(ctx, model.MaintenanceNodeCacheKey, maintenanceNodes, 0)
+}
+
+// findMaintenanceNodes returns the sorted addresses of the Nodes under maintenance at the time.
+func findMaintenanceNodes(nodes []*schema.Node, now time.Time) []string {
+	maintenanceNodes := lo.FilterMap(nodes, func(node *schema.Node, _ int) (string, bool) {
+		return node.Address.String(), node.Preferences.InMaintenance(now)
+	})
+
+	sort.Strings(maintenanceNodes)
+
+	return maintenanceNodes
+}
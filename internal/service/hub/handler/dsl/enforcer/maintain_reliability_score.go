@@ -13,6 +13,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/redis/go-redis/v9"
 	stakingv2 "github.com/rss3-network/global-indexer/contract/l2/staking/v2"
+	"github.com/rss3-network/global-indexer/internal/client/ethereum"
 	"github.com/rss3-network/global-indexer/internal/database"
 	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/model"
 	"github.com/rss3-network/global-indexer/schema"
@@ -99,7 +100,7 @@ func (e *SimpleEnforcer) updateNodeStats(ctx context.Context, stats []*schema.St
 	nodeAddresses := extractNodeAddresses(stats)
 
 	// Retrieve node information from the blockchain.
-	nodesInfo, err := e.getNodesInfoFromBlockchain(nodeAddresses)
+	nodesInfo, err := e.getNodesInfoFromBlockchain(ctx, nodeAddresses)
 	if err != nil {
 		return err
 	}
@@ -127,8 +128,8 @@ func (e *SimpleEnforcer) updateNodeStats(ctx context.Context, stats []*schema.St
 	return e.updateStatsInPool(ctx, stats, nodesInfo, nodes, reset)
 }
 
-func (e *SimpleEnforcer) getNodesInfoFromBlockchain(nodeAddresses []common.Address) ([]stakingv2.Node, error) {
-	return e.stakingContract.GetNodes(&bind.CallOpts{}, nodeAddresses)
+func (e *SimpleEnforcer) getNodesInfoFromBlockchain(ctx context.Context, nodeAddresses []common.Address) ([]stakingv2.Node, error) {
+	return e.stakingContract.GetNodes(&bind.CallOpts{Context: ethereum.WithQuorum(ctx)}, nodeAddresses)
 }
 
 func (e *SimpleEnforcer) getNodesInfoFromDatabase(ctx context.Context, nodeAddresses []common.Address) ([]*schema.Node, error) {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/rss3-network/global-indexer/internal/database"
@@ -32,7 +33,7 @@ func retrieveNodeStatsFromDB(ctx context.Context, key string, databaseClient dat
 	}
 
 	// Get qualified full or rss nodes.
-	nodeStats, err := fetchQualifiedNodeStats(ctx, query, databaseClient)
+	nodeStats, err := fetchQualifiedNodeStats(ctx, key, query, databaseClient)
 	if err != nil {
 		return nil, err
 	}
@@ -42,7 +43,7 @@ func retrieveNodeStatsFromDB(ctx context.Context, key string, databaseClient dat
 		query.IsRssNode = nil
 		query.IsFullNode = nil
 		query.IsAINode = nil
-		nodeStats, err = fetchQualifiedNodeStats(ctx, query, databaseClient)
+		nodeStats, err = fetchQualifiedNodeStats(ctx, key, query, databaseClient)
 
 		if err != nil {
 			return nil, err
@@ -62,7 +63,7 @@ func retrieveNodeStatsFromDB(ctx context.Context, key string, databaseClient dat
 }
 
 // fetchQualifiedNodeStats fetches the qualified node stats from the database.
-func fetchQualifiedNodeStats(ctx context.Context, query schema.StatQuery, databaseClient database.Client) ([]*schema.Stat, error) {
+func fetchQualifiedNodeStats(ctx context.Context, key string, query schema.StatQuery, databaseClient database.Client) ([]*schema.Stat, error) {
 	var nodeStats []*schema.Stat
 
 	for {
@@ -75,7 +76,7 @@ func fetchQualifiedNodeStats(ctx context.Context, query schema.StatQuery, databa
 			break
 		}
 
-		qualifiedNodeStats, err := getQualifiedNodes(ctx, tempNodeStats, databaseClient)
+		qualifiedNodeStats, err := getQualifiedNodes(ctx, key, tempNodeStats, databaseClient)
 		if err != nil {
 			return nil, err
 		}
@@ -91,8 +92,9 @@ func fetchQualifiedNodeStats(ctx context.Context, query schema.StatQuery, databa
 	return nodeStats, nil
 }
 
-// getQualifiedNodes filters the qualified nodes.
-func getQualifiedNodes(ctx context.Context, stats []*schema.Stat, databaseClient database.Client) ([]*schema.Stat, error) {
+// getQualifiedNodes filters the qualified nodes, the nodes opted out of the routing of the cache key
+// or under maintenance are excluded.
+func getQualifiedNodes(ctx context.Context, key string, stats []*schema.Stat, databaseClient database.Client) ([]*schema.Stat, error) {
 	nodeAddresses := extractNodeAddresses(stats)
 
 	// Retrieve the online Nodes from the database.
@@ -105,7 +107,11 @@ func getQualifiedNodes(ctx context.Context, stats []*schema.Stat, databaseClient
 		return nil, err
 	}
 
-	nodeMap := lo.SliceToMap(nodes, func(node *schema.Node) (common.Address, struct{}) {
+	now := time.Now()
+
+	nodeMap := lo.SliceToMap(lo.Reject(nodes, func(node *schema.Node, _ int) bool {
+		return isOptedOut(node, key) || node.Preferences.InMaintenance(now)
+	}), func(node *schema.Node) (common.Address, struct{}) {
 		return node.Address, struct{}{}
 	})
 
@@ -121,6 +127,22 @@ func getQualifiedNodes(ctx context.Context, stats []*schema.Stat, databaseClient
 	return qualifiedNodes, nil
 }
 
+// isOptedOut returns whether the operator of the node opted out of the routing of the cache key.
+func isOptedOut(node *schema.Node, key string) bool {
+	if node.Preferences == nil {
+		return false
+	}
+
+	switch key {
+	case model.RssNodeCacheKey:
+		return node.Preferences.OptOutRSS
+	case model.AINodeCacheKey:
+		return node.Preferences.OptOutAI
+	default:
+		return false
+	}
+}
+
 // extractNodeAddresses returns all Node addresses from stats.
 func extractNodeAddresses(stats []*schema.Stat) []common.Address {
 	return lo.Map(stats, func(stat *schema.Stat, _ int) common.Address {
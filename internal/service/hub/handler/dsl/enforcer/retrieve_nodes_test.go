@@ -0,0 +1,103 @@
+package enforcer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/model"
+	"github.com/rss3-network/global-indexer/schema"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetQualifiedNodesPreferences(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().Unix()
+
+	databaseClient := &nodeLocationDatabaseClient{
+		nodes: []*schema.Node{
+			{Address: common.Address{1}},
+			{Address: common.Address{2}, Preferences: &schema.NodePreferences{OptOutAI: true}},
+			{Address: common.Address{3}, Preferences: &schema.NodePreferences{OptOutRSS: true}},
+			{Address: common.Address{5}, Preferences: &schema.NodePreferences{
+				MaintenanceWindows: []*schema.NodeMaintenanceWindow{{StartAt: now - 60, Duration: 3600}},
+			}},
+			{Address: common.Address{6}, Preferences: &schema.NodePreferences{
+				MaintenanceWindows: []*schema.NodeMaintenanceWindow{{StartAt: now - 7200, Duration: 3600}, {StartAt: now + 3600, Duration: 3600}},
+			}},
+		},
+	}
+
+	stats := []*schema.Stat{
+		{Address: common.Address{1}},
+		{Address: common.Address{2}},
+		{Address: common.Address{3}},
+		// The offline nodes are not returned by the database.
+		{Address: common.Address{4}},
+		{Address: common.Address{5}},
+		{Address: common.Address{6}},
+	}
+
+	testCases := []struct {
+		name string
+		key  string
+		want []common.Address
+	}{
+		{
+			name: "Full",
+			key:  model.FullNodeCacheKey,
+			want: []common.Address{{1}, {2}, {3}, {6}},
+		},
+		{
+			name: "RSS",
+			key:  model.RssNodeCacheKey,
+			want: []common.Address{{1}, {2}, {6}},
+		},
+		{
+			name: "AI",
+			key:  model.AINodeCacheKey,
+			want: []common.Address{{1}, {3}, {6}},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			qualifiedNodes, err := getQualifiedNodes(context.Background(), tc.key, stats, databaseClient)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.want, lo.Map(qualifiedNodes, func(stat *schema.Stat, _ int) common.Address {
+				return stat.Address
+			}))
+		})
+	}
+}
+
+func TestFindMaintenanceNodes(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	nodes := []*schema.Node{
+		{Address: common.Address{3}, Preferences: &schema.NodePreferences{
+			MaintenanceWindows: []*schema.NodeMaintenanceWindow{{StartAt: now.Unix(), Duration: 60}},
+		}},
+		{Address: common.Address{1}, Preferences: &schema.NodePreferences{
+			MaintenanceWindows: []*schema.NodeMaintenanceWindow{{StartAt: now.Unix() - 60, Duration: 3600}},
+		}},
+		// The window has ended.
+		{Address: common.Address{2}, Preferences: &schema.NodePreferences{
+			MaintenanceWindows: []*schema.NodeMaintenanceWindow{{StartAt: now.Unix() - 60, Duration: 60}},
+		}},
+		{Address: common.Address{4}},
+	}
+
+	assert.Equal(t, []string{common.Address{1}.String(), common.Address{3}.String()}, findMaintenanceNodes(nodes, now))
+}
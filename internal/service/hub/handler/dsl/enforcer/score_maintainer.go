@@ -27,6 +27,8 @@ type ScoreMaintainer struct {
 	// It can only be updated or reduced, not increased.
 	nodeEndpointCaches map[string]*EndpointCache
 	lock               sync.RWMutex
+	// selectionStrategy selects the qualified Nodes from the sorted set.
+	selectionStrategy SelectionStrategy
 }
 
 type EndpointCache struct {
@@ -57,30 +59,45 @@ func (sm *ScoreMaintainer) addOrUpdateScore(ctx context.Context, setKey string,
 	})
 }
 
-// retrieveQualifiedNodes returns the top n NodeEndpointCaches from the sorted set.
-func (sm *ScoreMaintainer) retrieveQualifiedNodes(ctx context.Context, setKey string, n int) ([]*model.NodeEndpointCache, error) {
-	// Get the top n nodes from the sorted set.
-	result, err := sm.cacheClient.ZRevRangeWithScores(ctx, setKey, 0, int64(n-1))
+// retrieveQualifiedNodes returns n NodeEndpointCaches from the sorted set, selected by the selection strategy.
+// The Nodes in skippedNodes are left out, unless all the selected Nodes are in it.
+func (sm *ScoreMaintainer) retrieveQualifiedNodes(ctx context.Context, setKey string, n int, skippedNodes map[string]struct{}) ([]*model.NodeEndpointCache, error) {
+	// Select extra nodes from the sorted set to make up for the skipped ones.
+	result, err := sm.selectionStrategy.Select(ctx, sm.cacheClient, setKey, n+len(skippedNodes))
 	if err != nil {
 		return nil, err
 	}
 
 	qualifiedNodes := make([]*model.NodeEndpointCache, 0, n)
+	skippedQualifiedNodes := make([]*model.NodeEndpointCache, 0, n)
 
 	sm.lock.RLock()
 	defer sm.lock.RUnlock()
 
 	for _, item := range result {
 		if endpointCache, ok := sm.nodeEndpointCaches[item.Member.(string)]; ok {
-			qualifiedNodes = append(qualifiedNodes, &model.NodeEndpointCache{
+			node := &model.NodeEndpointCache{
 				Address:     item.Member.(string),
 				Endpoint:    endpointCache.Endpoint,
 				AccessToken: endpointCache.AccessToken,
-			})
+			}
+
+			if _, skipped := skippedNodes[node.Address]; skipped {
+				skippedQualifiedNodes = append(skippedQualifiedNodes, node)
+
+				continue
+			}
+
+			qualifiedNodes = append(qualifiedNodes, node)
 		}
 	}
 
-	return qualifiedNodes, nil
+	// Fall back to the skipped Nodes rather than failing the request.
+	if len(qualifiedNodes) == 0 {
+		qualifiedNodes = skippedQualifiedNodes
+	}
+
+	return qualifiedNodes[:min(n, len(qualifiedNodes))], nil
 }
 
 // updateQualifiedNodesMap replaces the current nodeEndpointCaches.
@@ -120,7 +137,7 @@ func (sm *ScoreMaintainer) updateQualifiedNodesMap(ctx context.Context, nodeStat
 }
 
 // newScoreMaintainer creates a new ScoreMaintainer with the nodeEndpointCaches and redis sorted set.
-func newScoreMaintainer(ctx context.Context, setKey string, nodeStats []*schema.Stat, cacheClient cache.Client) (*ScoreMaintainer, error) {
+func newScoreMaintainer(ctx context.Context, setKey string, nodeStats []*schema.Stat, cacheClient cache.Client, selectionStrategy SelectionStrategy) (*ScoreMaintainer, error) {
 	// Prepare the node caches and members for the sorted set.
 	nodeEndpointCaches, newMembers, err := prepareNodeCachesAndMembers(ctx, nodeStats, cacheClient)
 	if err != nil {
@@ -135,6 +152,7 @@ func newScoreMaintainer(ctx context.Context, setKey string, nodeStats []*schema.
 	return &ScoreMaintainer{
 		cacheClient:        cacheClient,
 		nodeEndpointCaches: nodeEndpointCaches,
+		selectionStrategy:  selectionStrategy,
 	}, nil
 }
 
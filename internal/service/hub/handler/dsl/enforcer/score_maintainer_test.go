@@ -83,11 +83,11 @@ func TestScoreMaintainer(t *testing.T) {
 			TotalRequest:        400,
 		},
 	}
-	sm, err := newScoreMaintainer(context.Background(), setKey, nodeStats, cacheClient)
+	sm, err := newScoreMaintainer(context.Background(), setKey, nodeStats, cacheClient, &topNStrategy{})
 	require.NoError(t, err)
 
 	// Retrieve qualified nodes
-	nodes, err := sm.retrieveQualifiedNodes(context.Background(), setKey, 3)
+	nodes, err := sm.retrieveQualifiedNodes(context.Background(), setKey, 3, nil)
 	require.NoError(t, err)
 	assert.Equal(t, 3, len(nodes))
 	assert.Equal(t, nodeStats[3].Address.String(), nodes[0].Address)
@@ -110,7 +110,7 @@ func TestScoreMaintainer(t *testing.T) {
 	})
 	require.NoError(t, err)
 	assert.Equal(t, 4, len(sm.nodeEndpointCaches))
-	nodes, err = sm.retrieveQualifiedNodes(context.Background(), setKey, 10)
+	nodes, err = sm.retrieveQualifiedNodes(context.Background(), setKey, 10, nil)
 	require.NoError(t, err)
 	assert.Equal(t, common.Address{0}.String(), nodes[0].Address)
 	//assert.Equal(t, common.Address{5}.String(), nodes[1].Address)
@@ -128,7 +128,7 @@ func TestScoreMaintainer(t *testing.T) {
 	assert.Equal(t, 4, len(sm.nodeEndpointCaches))
 
 	// Retrieve qualified nodes
-	nodes, err = sm.retrieveQualifiedNodes(context.Background(), setKey, 10)
+	nodes, err = sm.retrieveQualifiedNodes(context.Background(), setKey, 10, nil)
 	require.NoError(t, err)
 	assert.Equal(t, 3, len(nodes))
 	//assert.Equal(t, common.Address{5}.String(), nodes[0].Address)
@@ -0,0 +1,252 @@
+package enforcer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/redis/go-redis/v9"
+	"github.com/rss3-network/global-indexer/internal/cache"
+	"github.com/rss3-network/global-indexer/internal/config"
+	"github.com/rss3-network/global-indexer/internal/database"
+	"github.com/rss3-network/global-indexer/schema"
+	"github.com/samber/lo"
+)
+
+const (
+	SelectionStrategyTopN           = "top_n"
+	SelectionStrategyWeightedRandom = "weighted_random"
+	SelectionStrategyEpsilonGreedy  = "epsilon_greedy"
+	SelectionStrategyGeoProximity   = "geo_proximity"
+
+	// minSelectionWeight is the weight given to Nodes without a positive score,
+	// so that new Nodes still have a chance to be selected and build up their history.
+	minSelectionWeight = 1e-6
+	// geoLocationRefreshInterval is the interval to reload the locations of the Nodes.
+	geoLocationRefreshInterval = 10 * time.Minute
+	// earthRadiusKilometers is the mean radius of the earth.
+	earthRadiusKilometers = 6371.0
+	// defaultSelectionCandidates is the default number of the Nodes the strategies select from.
+	defaultSelectionCandidates = 100
+)
+
+// SelectionStrategy selects the Nodes to serve a request from the sorted set of a ScoreMaintainer.
+type SelectionStrategy interface {
+	// Select returns up to n members of the sorted set, in order of preference.
+	Select(ctx context.Context, cacheClient cache.Client, setKey string, n int) ([]redis.Z, error)
+}
+
+var (
+	_ SelectionStrategy = (*topNStrategy)(nil)
+	_ SelectionStrategy = (*weightedRandomStrategy)(nil)
+	_ SelectionStrategy = (*epsilonGreedyStrategy)(nil)
+	_ SelectionStrategy = (*geoProximityStrategy)(nil)
+)
+
+// topNStrategy selects the n Nodes with the highest reliability scores.
+type topNStrategy struct{}
+
+func (s *topNStrategy) Select(ctx context.Context, cacheClient cache.Client, setKey string, n int) ([]redis.Z, error) {
+	return cacheClient.ZRevRangeWithScores(ctx, setKey, 0, int64(n-1))
+}
+
+// retrieveCandidates returns the members with the highest reliability scores, which the strategies select from.
+// The range is capped, so that a request does not read the whole sorted set, but it always holds at least n members.
+func retrieveCandidates(ctx context.Context, cacheClient cache.Client, setKey string, candidates, n int) ([]redis.Z, error) {
+	return cacheClient.ZRevRangeWithScores(ctx, setKey, 0, int64(max(candidates, n)-1))
+}
+
+// weightedRandomStrategy selects n Nodes at random without replacement,
+// with the probability of each Node proportional to its reliability score.
+type weightedRandomStrategy struct {
+	candidates int
+}
+
+func (s *weightedRandomStrategy) Select(ctx context.Context, cacheClient cache.Client, setKey string, n int) ([]redis.Z, error) {
+	members, err := retrieveCandidates(ctx, cacheClient, setKey, s.candidates, n)
+	if err != nil {
+		return nil, err
+	}
+
+	// Weighted sampling without replacement (Efraimidis-Spirakis):
+	// each member gets a key u^(1/w), and the members with the largest keys are selected.
+	keys := make(map[string]float64, len(members))
+
+	for _, member := range members {
+		keys[member.Member.(string)] = math.Pow(rand.Float64(), 1/math.Max(member.Score, minSelectionWeight))
+	}
+
+	sort.SliceStable(members, func(i, j int) bool {
+		return keys[members[i].Member.(string)] > keys[members[j].Member.(string)]
+	})
+
+	return members[:min(n, len(members))], nil
+}
+
+// epsilonGreedyStrategy selects the Node with the highest reliability score for each slot,
+// except that with probability epsilon a random Node is explored instead.
+type epsilonGreedyStrategy struct {
+	epsilon    float64
+	candidates int
+}
+
+func (s *epsilonGreedyStrategy) Select(ctx context.Context, cacheClient cache.Client, setKey string, n int) ([]redis.Z, error) {
+	members, err := retrieveCandidates(ctx, cacheClient, setKey, s.candidates, n)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make([]redis.Z, 0, min(n, len(members)))
+
+	for len(selected) < n && len(members) > 0 {
+		index := 0
+
+		if rand.Float64() < s.epsilon {
+			index = rand.Intn(len(members))
+		}
+
+		selected = append(selected, members[index])
+		members = append(members[:index], members[index+1:]...)
+	}
+
+	return selected, nil
+}
+
+// geoProximityStrategy selects the n Nodes closest to the hub, based on the geolite2 locations of the Nodes.
+// Nodes at the same distance are ordered by reliability score, and Nodes without a known location come last.
+type geoProximityStrategy struct {
+	databaseClient database.Client
+	latitude       float64
+	longitude      float64
+	candidates     int
+
+	lock        sync.RWMutex
+	distances   map[string]float64
+	refreshedAt time.Time
+}
+
+func (s *geoProximityStrategy) Select(ctx context.Context, cacheClient cache.Client, setKey string, n int) ([]redis.Z, error) {
+	members, err := retrieveCandidates(ctx, cacheClient, setKey, s.candidates, n)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := lo.Map(members, func(member redis.Z, _ int) string {
+		return member.Member.(string)
+	})
+
+	distances, err := s.getDistances(ctx, addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	// The members are already ordered by score, keep the order for Nodes at the same distance.
+	sort.SliceStable(members, func(i, j int) bool {
+		return distances[members[i].Member.(string)] < distances[members[j].Member.(string)]
+	})
+
+	return members[:min(n, len(members))], nil
+}
+
+// getDistances returns the distances in kilometers between the hub and the Nodes.
+// The locations are reloaded from the database when they are stale or a Node is unknown.
+func (s *geoProximityStrategy) getDistances(ctx context.Context, addresses []string) (map[string]float64, error) {
+	s.lock.RLock()
+	fresh := time.Since(s.refreshedAt) < geoLocationRefreshInterval && lo.EveryBy(addresses, func(address string) bool {
+		_, exists := s.distances[address]
+		return exists
+	})
+	distances := s.distances
+	s.lock.RUnlock()
+
+	if fresh {
+		return distances, nil
+	}
+
+	nodes, err := s.databaseClient.FindNodes(ctx, schema.FindNodesQuery{
+		NodeAddresses: lo.Map(addresses, func(address string, _ int) common.Address {
+			return common.HexToAddress(address)
+		}),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("find nodes: %w", err)
+	}
+
+	distances = make(map[string]float64, len(addresses))
+
+	// Nodes without a known location are placed at an infinite distance.
+	for _, address := range addresses {
+		distances[address] = math.Inf(1)
+	}
+
+	for _, node := range nodes {
+		for _, location := range node.Location {
+			distance := haversineDistance(s.latitude, s.longitude, location.Latitude, location.Longitude)
+			address := node.Address.String()
+
+			distances[address] = math.Min(distances[address], distance)
+		}
+	}
+
+	s.lock.Lock()
+	s.distances = distances
+	s.refreshedAt = time.Now()
+	s.lock.Unlock()
+
+	return distances, nil
+}
+
+// haversineDistance returns the great-circle distance in kilometers between two coordinates.
+func haversineDistance(latitude1, longitude1, latitude2, longitude2 float64) float64 {
+	toRadians := func(degree float64) float64 {
+		return degree * math.Pi / 180
+	}
+
+	deltaLatitude := toRadians(latitude2 - latitude1)
+	deltaLongitude := toRadians(longitude2 - longitude1)
+
+	a := math.Pow(math.Sin(deltaLatitude/2), 2) +
+		math.Cos(toRadians(latitude1))*math.Cos(toRadians(latitude2))*math.Pow(math.Sin(deltaLongitude/2), 2)
+
+	return 2 * earthRadiusKilometers * math.Asin(math.Sqrt(a))
+}
+
+// newSelectionStrategy creates the selection strategy from the config, defaulting to the top-N strategy.
+func newSelectionStrategy(conf *config.NodeSelection, databaseClient database.Client) (SelectionStrategy, error) {
+	if conf == nil {
+		return &topNStrategy{}, nil
+	}
+
+	candidates := conf.Candidates
+	if candidates <= 0 {
+		candidates = defaultSelectionCandidates
+	}
+
+	switch conf.Strategy {
+	case "", SelectionStrategyTopN:
+		return &topNStrategy{}, nil
+	case SelectionStrategyWeightedRandom:
+		return &weightedRandomStrategy{candidates: candidates}, nil
+	case SelectionStrategyEpsilonGreedy:
+		return &epsilonGreedyStrategy{epsilon: conf.Epsilon, candidates: candidates}, nil
+	case SelectionStrategyGeoProximity:
+		// A missing location would route every request as if the hub were at (0, 0).
+		if conf.Latitude == nil || conf.Longitude == nil {
+			return nil, fmt.Errorf("latitude and longitude are required by the %s strategy", SelectionStrategyGeoProximity)
+		}
+
+		return &geoProximityStrategy{
+			databaseClient: databaseClient,
+			latitude:       *conf.Latitude,
+			longitude:      *conf.Longitude,
+			candidates:     candidates,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown selection strategy: %s", conf.Strategy)
+	}
+}
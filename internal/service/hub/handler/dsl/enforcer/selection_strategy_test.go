@@ -0,0 +1,228 @@
+package enforcer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/redis/go-redis/v9"
+	"github.com/rss3-network/global-indexer/internal/cache"
+	"github.com/rss3-network/global-indexer/internal/config"
+	"github.com/rss3-network/global-indexer/internal/database"
+	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/model"
+	"github.com/rss3-network/global-indexer/schema"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sortedSetCacheClient serves ZRevRangeWithScores from fixed members sorted by score in descending order.
+type sortedSetCacheClient struct {
+	cache.Client
+
+	members []redis.Z
+}
+
+func (c *sortedSetCacheClient) ZRevRangeWithScores(_ context.Context, _ string, start, stop int64) ([]redis.Z, error) {
+	if stop < 0 || stop >= int64(len(c.members)) {
+		stop = int64(len(c.members)) - 1
+	}
+
+	return append([]redis.Z{}, c.members[start:stop+1]...), nil
+}
+
+// nodeLocationDatabaseClient serves FindNodes from fixed Nodes.
+type nodeLocationDatabaseClient struct {
+	database.Client
+
+	nodes []*schema.Node
+}
+
+func (c *nodeLocationDatabaseClient) FindNodes(_ context.Context, _ schema.FindNodesQuery) ([]*schema.Node, error) {
+	return c.nodes, nil
+}
+
+var selectionMembers = []redis.Z{
+	{Member: common.Address{1}.String(), Score: 4},
+	{Member: common.Address{2}.String(), Score: 3},
+	{Member: common.Address{3}.String(), Score: 2},
+	{Member: common.Address{4}.String(), Score: 0},
+}
+
+func memberAddresses(members []redis.Z) []string {
+	return lo.Map(members, func(member redis.Z, _ int) string {
+		return member.Member.(string)
+	})
+}
+
+func TestSelectionStrategy(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cacheClient := &sortedSetCacheClient{members: selectionMembers}
+
+	testCases := []struct {
+		name     string
+		strategy SelectionStrategy
+		expected []string
+	}{
+		{
+			name:     "TopN",
+			strategy: &topNStrategy{},
+			expected: memberAddresses(selectionMembers[:3]),
+		},
+		{
+			name:     "EpsilonGreedyWithoutExploration",
+			strategy: &epsilonGreedyStrategy{epsilon: 0},
+			expected: memberAddresses(selectionMembers[:3]),
+		},
+		{
+			name: "GeoProximity",
+			strategy: &geoProximityStrategy{
+				// Frankfurt
+				latitude:   50.11,
+				longitude:  8.68,
+				candidates: defaultSelectionCandidates,
+				databaseClient: &nodeLocationDatabaseClient{
+					nodes: []*schema.Node{
+						// New York
+						{Address: common.Address{1}, Location: []*schema.NodeLocation{{Latitude: 40.71, Longitude: -74.01}}},
+						// Singapore
+						{Address: common.Address{2}, Location: []*schema.NodeLocation{{Latitude: 1.35, Longitude: 103.82}}},
+						// Paris
+						{Address: common.Address{4}, Location: []*schema.NodeLocation{{Latitude: 48.86, Longitude: 2.35}}},
+					},
+				},
+			},
+			// The Node without a location comes last.
+			expected: []string{common.Address{4}.String(), common.Address{1}.String(), common.Address{2}.String()},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			members, err := tc.strategy.Select(ctx, cacheClient, setKey, 3)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, memberAddresses(members))
+		})
+	}
+}
+
+func TestRandomSelectionStrategy(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cacheClient := &sortedSetCacheClient{members: selectionMembers}
+
+	for _, strategy := range []SelectionStrategy{&weightedRandomStrategy{}, &epsilonGreedyStrategy{epsilon: 1}} {
+		for i := 0; i < 20; i++ {
+			members, err := strategy.Select(ctx, cacheClient, setKey, 3)
+			require.NoError(t, err)
+
+			addresses := memberAddresses(members)
+			assert.Len(t, addresses, 3)
+			assert.Len(t, lo.Uniq(addresses), 3)
+		}
+	}
+}
+
+func TestSelectionStrategyCandidates(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cacheClient := &sortedSetCacheClient{members: selectionMembers}
+
+	for _, strategy := range []SelectionStrategy{&weightedRandomStrategy{candidates: 2}, &epsilonGreedyStrategy{epsilon: 1, candidates: 2}} {
+		for i := 0; i < 20; i++ {
+			members, err := strategy.Select(ctx, cacheClient, setKey, 1)
+			require.NoError(t, err)
+			assert.Subset(t, memberAddresses(selectionMembers[:2]), memberAddresses(members))
+		}
+
+		// The candidates are extended to serve n members.
+		members, err := strategy.Select(ctx, cacheClient, setKey, 3)
+		require.NoError(t, err)
+		assert.Len(t, members, 3)
+	}
+}
+
+func TestNewSelectionStrategy(t *testing.T) {
+	t.Parallel()
+
+	strategy, err := newSelectionStrategy(&config.NodeSelection{Strategy: SelectionStrategyWeightedRandom}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, &weightedRandomStrategy{candidates: defaultSelectionCandidates}, strategy)
+
+	_, err = newSelectionStrategy(&config.NodeSelection{Strategy: SelectionStrategyGeoProximity, Latitude: lo.ToPtr(50.11)}, nil)
+	require.Error(t, err)
+
+	strategy, err = newSelectionStrategy(&config.NodeSelection{Strategy: SelectionStrategyGeoProximity, Latitude: lo.ToPtr(50.11), Longitude: lo.ToPtr(8.68), Candidates: 10}, nil)
+	require.NoError(t, err)
+	assert.InDelta(t, 8.68, strategy.(*geoProximityStrategy).longitude, 0)
+	assert.Equal(t, 10, strategy.(*geoProximityStrategy).candidates)
+}
+
+func TestHaversineDistance(t *testing.T) {
+	t.Parallel()
+
+	// London to Paris is about 344 km.
+	assert.InDelta(t, 344, haversineDistance(51.5074, -0.1278, 48.8566, 2.3522), 5)
+	assert.Zero(t, haversineDistance(10, 10, 10, 10))
+}
+
+func TestRetrieveQualifiedNodesWithSkippedNodes(t *testing.T) {
+	t.Parallel()
+
+	sm := &ScoreMaintainer{
+		cacheClient:       &sortedSetCacheClient{members: selectionMembers},
+		selectionStrategy: &topNStrategy{},
+		nodeEndpointCaches: lo.SliceToMap(selectionMembers, func(member redis.Z) (string, *EndpointCache) {
+			return member.Member.(string), &EndpointCache{Endpoint: member.Member.(string)}
+		}),
+	}
+
+	nodeAddresses := func(nodes []*model.NodeEndpointCache) []string {
+		return lo.Map(nodes, func(node *model.NodeEndpointCache, _ int) string {
+			return node.Address
+		})
+	}
+
+	testCases := []struct {
+		name         string
+		skippedNodes map[string]struct{}
+		expected     []string
+	}{
+		{
+			name:     "NoSkippedNodes",
+			expected: memberAddresses(selectionMembers[:3]),
+		},
+		{
+			name:         "SkippedNodes",
+			skippedNodes: map[string]struct{}{common.Address{1}.String(): {}},
+			expected:     memberAddresses(selectionMembers[1:4]),
+		},
+		{
+			name: "AllNodesSkipped",
+			skippedNodes: lo.SliceToMap(selectionMembers, func(member redis.Z) (string, struct{}) {
+				return member.Member.(string), struct{}{}
+			}),
+			expected: memberAddresses(selectionMembers[:3]),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			nodes, err := sm.retrieveQualifiedNodes(context.Background(), setKey, 3, tc.skippedNodes)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, nodeAddresses(nodes))
+		})
+	}
+}
@@ -30,7 +30,7 @@ func (d *DSL) GetFederatedActivity(c echo.Context) (err error) {
 
 	requestCounter.WithLabelValues("GetFederatedActivity").Inc()
 
-	activity, err := d.distributor.DistributeData(c.Request().Context(), model.DistributorRequestActivity, model.ComponentFederated, request, c.QueryParams(), nil, nil)
+	activity, err := d.distributeData(c, model.DistributorRequestActivity, model.ComponentFederated, request, c.QueryParams(), nil, nil)
 	if err != nil {
 		if errors.Is(err, errorx.ErrNoNodesAvailable) {
 			return errorx.ServiceUnavailableError(c, err)
@@ -65,7 +65,7 @@ func (d *DSL) GetFederatedAccountActivities(c echo.Context) (err error) {
 
 	incrementRequestCounter("GetFederatedAccountActivities", request.Network, request.Tag, request.Platform)
 
-	activities, err := d.distributor.DistributeData(c.Request().Context(), model.DistributorRequestAccountActivities, model.ComponentFederated, request, c.QueryParams(), nil, nil)
+	activities, err := d.distributeData(c, model.DistributorRequestAccountActivities, model.ComponentFederated, request, c.QueryParams(), nil, nil)
 	if err != nil {
 		if errors.Is(err, errorx.ErrNoNodesAvailable) {
 			return errorx.ServiceUnavailableError(c, err)
@@ -103,7 +103,7 @@ func (d *DSL) BatchGetFederatedAccountsActivities(c echo.Context) (err error) {
 
 	incrementRequestCounter("BatchGetFederatedAccountsActivities", request.Network, request.Tag, request.Platform)
 
-	activities, err := d.distributor.DistributeData(c.Request().Context(), model.DistributorRequestBatchAccountActivities, model.ComponentFederated, request, nil, nil, request.Network)
+	activities, err := d.distributeData(c, model.DistributorRequestBatchAccountActivities, model.ComponentFederated, request, nil, nil, request.Network)
 	if err != nil {
 		if errors.Is(err, errorx.ErrNoNodesAvailable) {
 			return errorx.ServiceUnavailableError(c, err)
@@ -138,7 +138,7 @@ func (d *DSL) GetFederatedNetworkActivities(c echo.Context) (err error) {
 
 	incrementRequestCounter("GetFederatedNetworkActivities", []string{request.Network}, request.Tag, request.Platform)
 
-	activities, err := d.distributor.DistributeData(c.Request().Context(), model.DistributorRequestNetworkActivities, model.ComponentFederated, request, c.QueryParams(), nil, []string{request.Network})
+	activities, err := d.distributeData(c, model.DistributorRequestNetworkActivities, model.ComponentFederated, request, c.QueryParams(), nil, []string{request.Network})
 	if err != nil {
 		if errors.Is(err, errorx.ErrNoNodesAvailable) {
 			return errorx.ServiceUnavailableError(c, err)
@@ -173,7 +173,7 @@ func (d *DSL) GetFederatedPlatformActivities(c echo.Context) (err error) {
 
 	incrementRequestCounter("GetFederatedPlatformActivities", request.Network, request.Tag, []string{request.Platform})
 
-	activities, err := d.distributor.DistributeData(c.Request().Context(), model.DistributorRequestPlatformActivities, model.ComponentFederated, request, c.QueryParams(), nil, request.Network)
+	activities, err := d.distributeData(c, model.DistributorRequestPlatformActivities, model.ComponentFederated, request, c.QueryParams(), nil, request.Network)
 	if err != nil {
 		if errors.Is(err, errorx.ErrNoNodesAvailable) {
 			return errorx.ServiceUnavailableError(c, err)
@@ -35,6 +35,13 @@ var (
 		},
 		[]string{"endpoint", "platform"},
 	)
+	responseCacheCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dsl_response_cache_total",
+			Help: "Total number of cacheable DSL requests by cache status",
+		},
+		[]string{"request", "status"},
+	)
 )
 
 func incrementRequestCounter(endpoint string, networks []string, tags []string, platforms []string) {
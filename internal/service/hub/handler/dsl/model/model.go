@@ -30,6 +30,8 @@ var (
 	RssNodeCacheKey = "nodes:rss"
 	// FullNodeCacheKey is the cache key for the full nodes.
 	FullNodeCacheKey = "nodes:full"
+	// MaintenanceNodeCacheKey is the cache key for the nodes under maintenance when the sorted sets were last updated.
+	MaintenanceNodeCacheKey = "nodes:maintenance"
 	// FederatedHandlesPrefixCacheKey is the cache key prefix for the handles of federated nodes.
 	FederatedHandlesPrefixCacheKey = "federated:handles:"
 
@@ -107,6 +109,8 @@ type RequestMeta struct {
 	Endpoint    string
 	AccessToken string
 	Body        []byte
+	// Rank is the position of the Node in the qualified Nodes, the lower the better.
+	Rank int
 }
 
 type ErrResponse struct {
@@ -126,7 +130,10 @@ type ActivitiesResponse struct {
 }
 
 type MetaCursor struct {
-	Cursor string `json:"cursor"`
+	Cursor string `json:"cursor,omitempty"`
+	// Partial is set if the Activities of some accounts could not be fetched, a partial response has no cursor,
+	// as paginating from it would skip the missing Activities.
+	Partial bool `json:"partial,omitempty"`
 }
 
 // Activity represents an activity.
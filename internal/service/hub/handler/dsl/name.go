@@ -0,0 +1,49 @@
+package dsl
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rss3-network/global-indexer/internal/nameresolver"
+	"github.com/rss3-network/global-indexer/internal/service/hub/model/dsl"
+	"github.com/rss3-network/global-indexer/internal/service/hub/model/errorx"
+	"go.uber.org/zap"
+)
+
+// GetPrimaryNames returns the primary names of an address in the name services, all the name services are queried if none is given.
+func (d *DSL) GetPrimaryNames(c echo.Context) (err error) {
+	var request dsl.PrimaryNamesRequest
+
+	if err = c.Bind(&request); err != nil {
+		return errorx.BadRequestError(c, err)
+	}
+
+	if err = c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, err)
+	}
+
+	nameServices := make([]nameresolver.NameService, 0, len(request.NameService))
+
+	for _, value := range request.NameService {
+		nameService, parseErr := nameresolver.NameServiceString(value)
+		if parseErr != nil || nameService == nameresolver.NameServiceUnknown {
+			return errorx.BadParamsError(c, fmt.Errorf("invalid name service: %s", value))
+		}
+
+		nameServices = append(nameServices, nameService)
+	}
+
+	requestCounter.WithLabelValues("GetPrimaryNames").Inc()
+
+	primaryNames, err := d.nameService.ReverseResolve(c.Request().Context(), request.Address, nameServices...)
+	if err != nil {
+		zap.L().Error("reverse resolve address error", zap.String("address", request.Address.String()), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	return c.JSON(http.StatusOK, dsl.PrimaryNamesResponse{
+		Data: primaryNames,
+	})
+}
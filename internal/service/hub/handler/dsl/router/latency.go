@@ -0,0 +1,88 @@
+package router
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// minLatencySamples is the number of samples required before the p95 latency of a Node is trusted.
+const minLatencySamples = 10
+
+// latencyTracker records the recent response latencies of Nodes,
+// and derives the hedging budget of each Node from its p95 latency.
+type latencyTracker struct {
+	windowSize    int
+	defaultBudget time.Duration
+	minBudget     time.Duration
+	maxBudget     time.Duration
+
+	lock    sync.RWMutex
+	windows map[common.Address]*latencyWindow
+}
+
+// latencyWindow is a ring buffer of the most recent latencies of a Node.
+type latencyWindow struct {
+	samples []time.Duration
+	next    int
+}
+
+// observe records a response latency of the Node.
+func (t *latencyTracker) observe(address common.Address, latency time.Duration) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	window, ok := t.windows[address]
+	if !ok {
+		window = &latencyWindow{samples: make([]time.Duration, 0, t.windowSize)}
+		t.windows[address] = window
+	}
+
+	if len(window.samples) < t.windowSize {
+		window.samples = append(window.samples, latency)
+	} else {
+		window.samples[window.next] = latency
+	}
+
+	window.next = (window.next + 1) % t.windowSize
+}
+
+// budget returns how long to wait for the Node before hedging to the next one.
+// It is the p95 latency of the Node clamped to [minBudget, maxBudget],
+// or the default budget if the Node has not built up enough history.
+func (t *latencyTracker) budget(address common.Address) time.Duration {
+	t.lock.RLock()
+
+	window, ok := t.windows[address]
+	if !ok || len(window.samples) < minLatencySamples {
+		t.lock.RUnlock()
+
+		return t.defaultBudget
+	}
+
+	samples := make([]time.Duration, len(window.samples))
+	copy(samples, window.samples)
+
+	t.lock.RUnlock()
+
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i] < samples[j]
+	})
+
+	p95 := samples[int(math.Ceil(float64(len(samples))*0.95))-1]
+
+	return min(max(p95, t.minBudget), t.maxBudget)
+}
+
+func newLatencyTracker(windowSize int, defaultBudget, minBudget, maxBudget time.Duration) *latencyTracker {
+	return &latencyTracker{
+		windowSize:    windowSize,
+		defaultBudget: defaultBudget,
+		minBudget:     minBudget,
+		maxBudget:     maxBudget,
+		windows:       make(map[common.Address]*latencyWindow),
+	}
+}
@@ -0,0 +1,13 @@
+package router
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var hedgeCounter = promauto.NewCounter(
+	prometheus.CounterOpts{
+		Name: "dsl_hedged_requests_total",
+		Help: "Total number of requests hedged to the next node after the latency budget ran out",
+	},
+)
@@ -7,14 +7,21 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/rss3-network/global-indexer/common/httputil"
+	"github.com/rss3-network/global-indexer/internal/config"
+	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/breaker"
 	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/model"
+	"github.com/rss3-network/global-indexer/internal/service/hub/model/errorx"
+	"github.com/samber/lo"
 	"go.uber.org/zap"
 )
 
@@ -27,6 +34,11 @@ type Router interface {
 
 type SimpleRouter struct {
 	httpClient httputil.Client
+	// hedgeConfig enables the hedged distribution, the request is fanned out to all Nodes if it is nil.
+	hedgeConfig    *config.Hedge
+	latencyTracker *latencyTracker
+	// circuitBreaker is fed with the outcome of every request, it is nil if the circuit breaker is not enabled.
+	circuitBreaker *breaker.CircuitBreaker
 }
 
 func (r *SimpleRouter) BuildPath(method, path string, query url.Values, nodes []*model.NodeEndpointCache, body []byte) (map[common.Address]model.RequestMeta, error) {
@@ -36,7 +48,7 @@ func (r *SimpleRouter) BuildPath(method, path string, query url.Values, nodes []
 
 	urls := make(map[common.Address]model.RequestMeta, len(nodes))
 
-	for _, node := range nodes {
+	for rank, node := range nodes {
 		fullURL := buildFullURL(node.Endpoint, path)
 
 		if method != http.MethodPost {
@@ -53,6 +65,7 @@ func (r *SimpleRouter) BuildPath(method, path string, query url.Values, nodes []
 			Endpoint:    fullURL,
 			AccessToken: node.AccessToken,
 			Body:        body,
+			Rank:        rank,
 		}
 	}
 
@@ -76,8 +89,17 @@ func (r *SimpleRouter) DistributeRequest(ctx context.Context, nodeMap map[common
 	// firstResponse is a channel that will be used to send the first response
 	var firstResponse = make(chan model.DataResponse, 1)
 
-	// Distribute the request to the Nodes
-	r.distribute(ctx, nodeMap, processResponses, firstResponse)
+	if r.shouldHedge(nodeMap) {
+		// The remaining Nodes are hedged in the background, so that the first valid response is returned without waiting for the slower ones.
+		// The hedged requests still in flight are canceled once the first response is served or the client's request is canceled.
+		hedgeCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		go r.hedge(hedgeCtx, nodeMap, processResponses, firstResponse)
+	} else {
+		// Distribute the request to the Nodes
+		r.distribute(ctx, nodeMap, processResponses, firstResponse)
+	}
 
 	select {
 	case response := <-firstResponse:
@@ -88,6 +110,16 @@ func (r *SimpleRouter) DistributeRequest(ctx context.Context, nodeMap map[common
 	}
 }
 
+// shouldHedge returns true if the request should be hedged instead of being fanned out to all Nodes.
+// A fraction of the requests is always fanned out, so that the responses can still be verified against each other.
+func (r *SimpleRouter) shouldHedge(nodeMap map[common.Address]model.RequestMeta) bool {
+	if r.hedgeConfig == nil || !r.hedgeConfig.Enabled || len(nodeMap) < 2 {
+		return false
+	}
+
+	return rand.Float64() >= r.hedgeConfig.VerificationRate
+}
+
 // distribute sends the request to the Nodes and processes the responses
 func (r *SimpleRouter) distribute(ctx context.Context, nodeMap map[common.Address]model.RequestMeta, processResponses func([]*model.DataResponse), firstResponse chan<- model.DataResponse) {
 	var (
@@ -105,85 +137,276 @@ func (r *SimpleRouter) distribute(ctx context.Context, nodeMap map[common.Addres
 
 	defer cancel()
 
-	for address, requestMeta := range nodeMap {
+	// probes holds the Nodes the request is sent to, and whether the request probes their half-open breakers.
+	probes := make(map[common.Address]bool, len(nodeMap))
+
+	for address := range nodeMap {
+		if acquired, probe := r.acquire(ctx, address); acquired {
+			probes[address] = probe
+		}
+	}
+
+	if len(probes) == 0 {
+		firstResponse <- model.DataResponse{Err: errorx.ErrNoNodesAvailable}
+
+		return
+	}
+
+	for address, probe := range probes {
 		waitGroup.Add(1)
 
-		go func(address common.Address, requestMeta model.RequestMeta) {
+		go func(address common.Address, requestMeta model.RequestMeta, probe bool) {
 			defer waitGroup.Done()
 
-			response := &model.DataResponse{Address: address, Endpoint: requestMeta.Endpoint}
-			// Fetch the data from the Node.
-			body, err := r.httpClient.FetchWithMethod(ctx, requestMeta.Method, requestMeta.Endpoint, requestMeta.AccessToken, bytes.NewReader(requestMeta.Body))
+			response := r.fetch(ctx, address, requestMeta)
 
-			if err != nil {
-				zap.L().Error("failed to fetch request", zap.String("node", address.String()), zap.Error(err))
+			r.recordOutcome(ctx, response, probe)
 
-				response.Err = err
-			} else {
-				// Read the response body.
-				data, readErr := io.ReadAll(body)
+			sendResponse(&mu, &responses, response, &responseSent, firstResponse, len(probes))
+		}(address, nodeMap[address], probe)
+	}
 
-				zap.L().Info("fetch request", zap.String("node", address.String()), zap.String("endpoint", requestMeta.Endpoint), zap.String("method", requestMeta.Method))
+	waitGroup.Wait()
 
-				if readErr != nil {
-					zap.L().Error("failed to read response body", zap.String("node", address.String()), zap.Error(readErr))
+	r.processResponses(responses, processResponses)
+}
 
-					response.Err = readErr
-				} else {
-					var v interface{}
-					err = json.Unmarshal(data, &v)
+// hedge sends the request to the best ranked Node first,
+// and only sends it to the next Node when the latency budget of the previous one runs out or it fails.
+// The hedging stops as soon as a valid response is received.
+func (r *SimpleRouter) hedge(ctx context.Context, nodeMap map[common.Address]model.RequestMeta, processResponses func([]*model.DataResponse), firstResponse chan<- model.DataResponse) {
+	var (
+		waitGroup sync.WaitGroup
+		mu        sync.Mutex
 
-					if err != nil {
-						zap.L().Error("failed to unmarshal response body", zap.String("node", address.String()), zap.Error(err))
+		// responses contains all the returned responses
+		responses []*model.DataResponse
+		// responseSent is used to ensure that the first response is sent only once
+		responseSent bool
+	)
 
-						response.Err = fmt.Errorf("invalid data")
-					}
+	var cancel context.CancelFunc
+	ctx, cancel = context.WithTimeout(ctx, httputil.DefaultTimeout)
 
-					if _, ok := v.([]interface{}); ok {
-						zap.L().Info("response is an array", zap.String("node", address.String()))
+	defer cancel()
 
-						response.Data = data
-					} else {
-						activity := &model.ActivityResponse{}
-						activities := &model.ActivitiesResponse{}
+	addresses := lo.Keys(nodeMap)
+	sort.Slice(addresses, func(i, j int) bool {
+		return nodeMap[addresses[i]].Rank < nodeMap[addresses[j]].Rank
+	})
 
-						// Check if the Node's data is valid.
-						if !validateData(data, activity) && !validateData(data, activities) {
-							zap.L().Error("failed to parse response", zap.String("node", address.String()))
+	// received notifies the hedging loop of every response
+	received := make(chan *model.DataResponse, len(addresses))
 
-							response.Err = fmt.Errorf("invalid data")
-						} else {
-							// If the data is non-null, set the result as valid.
-							if activity.Data != nil || activities.Data != nil {
-								response.Valid = true
-							}
+hedging:
+	for i, address := range addresses {
+		// The Nodes skipped by their circuit breakers are passed over at once.
+		acquired, probe := r.acquire(ctx, address)
+		if !acquired {
+			continue
+		}
 
-							response.Data = data
-						}
-					}
-				}
-			}
+		waitGroup.Add(1)
+
+		go func(address common.Address, requestMeta model.RequestMeta, probe bool) {
+			defer waitGroup.Done()
+
+			response := r.fetch(ctx, address, requestMeta)
+
+			r.recordOutcome(ctx, response, probe)
 
 			sendResponse(&mu, &responses, response, &responseSent, firstResponse, len(nodeMap))
-		}(address, requestMeta)
+
+			received <- response
+		}(address, nodeMap[address], probe)
+
+		if i == len(addresses)-1 {
+			break
+		}
+
+		timer := time.NewTimer(r.latencyTracker.budget(address))
+
+		// Wait for the budget of the Node to run out, or for any in-flight Node to respond.
+		for waiting := true; waiting; {
+			select {
+			case response := <-received:
+				if response.Err == nil && response.Valid {
+					timer.Stop()
+
+					break hedging
+				}
+
+				// An invalid response from the latest Node means that the next Node should be tried at once.
+				if response.Address == address {
+					timer.Stop()
+
+					waiting = false
+				}
+			case <-timer.C:
+				hedgeCounter.Inc()
+
+				waiting = false
+			case <-ctx.Done():
+				timer.Stop()
+
+				break hedging
+			}
+		}
 	}
 
 	waitGroup.Wait()
 
-	canceled := false
-	// If the context is canceled manually, do not process the responses
+	// The hedging may stop before all Nodes are requested, send the best response if none has been sent yet.
+	mu.Lock()
+	if !responseSent {
+		if len(responses) > 0 {
+			firstResponse <- selectFallbackResponse(responses)
+		} else {
+			firstResponse <- model.DataResponse{Err: errorx.ErrNoNodesAvailable}
+		}
+
+		responseSent = true
+	}
+	mu.Unlock()
+
+	// The requests canceled once the first response is served say nothing about their Nodes,
+	// and a single response cannot be verified against any other, so it is not scored.
+	completed := lo.Reject(responses, func(response *model.DataResponse, _ int) bool {
+		return errors.Is(response.Err, context.Canceled) || errors.Is(response.Err, httputil.ErrorManuallyCanceled)
+	})
+
+	if len(completed) < 2 {
+		return
+	}
+
+	r.processResponses(completed, processResponses)
+}
+
+// fetch sends the request to a Node and validates the response.
+func (r *SimpleRouter) fetch(ctx context.Context, address common.Address, requestMeta model.RequestMeta) *model.DataResponse {
+	response := &model.DataResponse{Address: address, Endpoint: requestMeta.Endpoint}
+
+	startTime := time.Now()
+
+	// Fetch the data from the Node.
+	body, err := r.httpClient.FetchWithMethod(ctx, requestMeta.Method, requestMeta.Endpoint, requestMeta.AccessToken, bytes.NewReader(requestMeta.Body))
+
+	if err != nil {
+		zap.L().Error("failed to fetch request", zap.String("node", address.String()), zap.Error(err))
+
+		response.Err = err
+
+		return response
+	}
+
+	// Read the response body.
+	data, readErr := io.ReadAll(body)
+
+	zap.L().Info("fetch request", zap.String("node", address.String()), zap.String("endpoint", requestMeta.Endpoint), zap.String("method", requestMeta.Method))
+
+	if readErr != nil {
+		zap.L().Error("failed to read response body", zap.String("node", address.String()), zap.Error(readErr))
+
+		response.Err = readErr
+
+		return response
+	}
+
+	// Only the latencies of successful requests are learned from.
+	if r.latencyTracker != nil {
+		r.latencyTracker.observe(address, time.Since(startTime))
+	}
+
+	var v interface{}
+	err = json.Unmarshal(data, &v)
+
+	if err != nil {
+		zap.L().Error("failed to unmarshal response body", zap.String("node", address.String()), zap.Error(err))
+
+		response.Err = fmt.Errorf("invalid data")
+	}
+
+	if _, ok := v.([]interface{}); ok {
+		zap.L().Info("response is an array", zap.String("node", address.String()))
+
+		response.Data = data
+	} else {
+		activity := &model.ActivityResponse{}
+		activities := &model.ActivitiesResponse{}
+
+		// Check if the Node's data is valid.
+		if !validateData(data, activity) && !validateData(data, activities) {
+			zap.L().Error("failed to parse response", zap.String("node", address.String()))
+
+			response.Err = fmt.Errorf("invalid data")
+		} else {
+			// If the data is non-null, set the result as valid.
+			if activity.Data != nil || activities.Data != nil {
+				response.Valid = true
+			}
+
+			response.Data = data
+		}
+	}
+
+	return response
+}
+
+// acquire checks the circuit breaker of the Node right before the request is sent to it,
+// it returns whether the request should be sent, and whether it probes the half-open breaker of the Node.
+// A failure to check the circuit breaker should not prevent the request from being sent.
+func (r *SimpleRouter) acquire(ctx context.Context, address common.Address) (bool, bool) {
+	acquired, probe, err := r.circuitBreaker.Acquire(ctx, address)
+	if err != nil {
+		zap.L().Error("failed to acquire circuit breaker", zap.String("node", address.String()), zap.Error(err))
+
+		return true, false
+	}
+
+	return acquired, probe
+}
+
+// recordOutcome records the outcome of the request in the circuit breaker of the Node in the background.
+// Requests canceled by the caller say nothing about the health of the Node, so they are not recorded,
+// and the probe they took over is released instead.
+func (r *SimpleRouter) recordOutcome(ctx context.Context, response *model.DataResponse, probe bool) {
+	if r.circuitBreaker == nil {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+
+	if errors.Is(response.Err, context.Canceled) || errors.Is(response.Err, httputil.ErrorManuallyCanceled) {
+		if probe {
+			go func() {
+				if err := r.circuitBreaker.Release(ctx, response.Address); err != nil {
+					zap.L().Error("failed to release probe in circuit breaker", zap.String("node", response.Address.String()), zap.Error(err))
+				}
+			}()
+		}
+
+		return
+	}
+
+	go func() {
+		if err := r.circuitBreaker.Record(ctx, response.Address, response.Err == nil); err != nil {
+			zap.L().Error("failed to record request in circuit breaker", zap.String("node", response.Address.String()), zap.Error(err))
+		}
+	}()
+}
+
+// processResponses processes the responses to calculate the actual request of each node,
+// unless the context is canceled manually.
+func (r *SimpleRouter) processResponses(responses []*model.DataResponse, processResponses func([]*model.DataResponse)) {
 	for _, response := range responses {
 		if errors.Is(response.Err, httputil.ErrorManuallyCanceled) {
-			canceled = true
-			break
+			return
 		}
 	}
 
-	if !canceled {
-		zap.L().Info("begin to process responses", zap.Any("responses", len(responses)))
-		// Process the responses to calculate the actual request of each node
-		go processResponses(responses)
-	}
+	zap.L().Info("begin to process responses", zap.Any("responses", len(responses)))
+
+	go processResponses(responses)
 }
 
 // sendResponse sends the first valid response to the firstResponse channel
@@ -206,19 +429,24 @@ func sendResponse(mu *sync.Mutex, responses *[]*model.DataResponse, response *mo
 
 		// If all the results have been received
 		if len(*responses) == nodesRequested {
-			for _, res := range *responses {
-				if res.Err != nil {
-					continue
-				}
+			firstResponse <- selectFallbackResponse(*responses)
 
-				firstResponse <- *res
-
-				return
-			}
+			*responseSent = true
+		}
+	}
+}
 
-			firstResponse <- *(*responses)[0]
+// selectFallbackResponse returns the first non-error response, or the first response if all of them are errors.
+func selectFallbackResponse(responses []*model.DataResponse) model.DataResponse {
+	for _, res := range responses {
+		if res.Err != nil {
+			continue
 		}
+
+		return *res
 	}
+
+	return *responses[0]
 }
 
 func validateData(data []byte, target any) bool {
@@ -234,8 +462,16 @@ func validateData(data []byte, target any) bool {
 	return false
 }
 
-func NewSimpleRouter(httpClient httputil.Client) *SimpleRouter {
-	return &SimpleRouter{
-		httpClient: httpClient,
+func NewSimpleRouter(httpClient httputil.Client, hedgeConfig *config.Hedge, circuitBreaker *breaker.CircuitBreaker) *SimpleRouter {
+	router := &SimpleRouter{
+		httpClient:     httpClient,
+		hedgeConfig:    hedgeConfig,
+		circuitBreaker: circuitBreaker,
 	}
+
+	if hedgeConfig != nil && hedgeConfig.Enabled {
+		router.latencyTracker = newLatencyTracker(hedgeConfig.WindowSize, hedgeConfig.DefaultBudget, hedgeConfig.MinBudget, hedgeConfig.MaxBudget)
+	}
+
+	return router
 }
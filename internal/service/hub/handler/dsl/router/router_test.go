@@ -7,10 +7,19 @@ import (
 	"fmt"
 	"io"
 	"testing"
+	"time"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/redis/go-redis/v9"
+	"github.com/rss3-network/global-indexer/common/httputil"
+	"github.com/rss3-network/global-indexer/internal/cache"
+	"github.com/rss3-network/global-indexer/internal/config"
+	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/breaker"
 	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/model"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -36,6 +45,33 @@ var (
 			Body:     nil,
 		},
 	}
+
+	hedgeNodeMap = map[common.Address]model.RequestMeta{
+		common.HexToAddress("0x123"): {
+			Method:   "GET",
+			Endpoint: "http://localhost:8070",
+			Rank:     0,
+		},
+		common.HexToAddress("0x234"): {
+			Method:   "GET",
+			Endpoint: "http://localhost:8080",
+			Rank:     1,
+		},
+		common.HexToAddress("0x567"): {
+			Method:   "GET",
+			Endpoint: "http://localhost:8090",
+			Rank:     2,
+		},
+	}
+
+	hedgeConfig = &config.Hedge{
+		Enabled:          true,
+		VerificationRate: 0,
+		DefaultBudget:    5 * time.Second,
+		MinBudget:        time.Millisecond,
+		MaxBudget:        10 * time.Second,
+		WindowSize:       20,
+	}
 )
 
 type MockHTTPClient struct {
@@ -214,6 +250,135 @@ func TestDistributeRequest(t *testing.T) {
 	}
 }
 
+func TestDistributeRequestWithHedge(t *testing.T) {
+	t.Parallel()
+
+	mockClient := new(MockHTTPClient)
+	r := NewSimpleRouter(mockClient, hedgeConfig, nil)
+
+	mockClient.On("FetchWithMethod", mock.Anything, "http://localhost:8070").Return(io.NopCloser(bytes.NewBufferString(validActivityData)), nil)
+	mockClient.On("FetchWithMethod", mock.Anything, "http://localhost:8080").Return(io.NopCloser(bytes.NewBufferString(validActivityData)), nil)
+	mockClient.On("FetchWithMethod", mock.Anything, "http://localhost:8090").Return(io.NopCloser(bytes.NewBufferString(validActivityData)), nil)
+
+	response, err := r.DistributeRequest(context.Background(), hedgeNodeMap, process)
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	if !response.Valid {
+		t.Errorf("Expected 'true', got %v", response.Valid)
+	}
+
+	if response.Address != common.HexToAddress("0x123") {
+		t.Errorf("Expected '0x123', got %v", response.Address.String())
+	}
+
+	mockClient.AssertNotCalled(t, "FetchWithMethod", mock.Anything, "http://localhost:8080")
+	mockClient.AssertNotCalled(t, "FetchWithMethod", mock.Anything, "http://localhost:8090")
+}
+
+func TestDistributeRequestWithHedgeFailover(t *testing.T) {
+	t.Parallel()
+
+	mockClient := new(MockHTTPClient)
+	r := NewSimpleRouter(mockClient, hedgeConfig, nil)
+
+	mockClient.On("FetchWithMethod", mock.Anything, "http://localhost:8070").Return(io.NopCloser(bytes.NewBufferString(errResponse)), errors.New("error 8070"))
+	mockClient.On("FetchWithMethod", mock.Anything, "http://localhost:8080").Return(io.NopCloser(bytes.NewBufferString(validActivitiesData)), nil)
+	mockClient.On("FetchWithMethod", mock.Anything, "http://localhost:8090").Return(io.NopCloser(bytes.NewBufferString(validActivitiesData)), nil)
+
+	response, err := r.DistributeRequest(context.Background(), hedgeNodeMap, process)
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	if err = response.Err; err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+
+	if response.Address != common.HexToAddress("0x234") {
+		t.Errorf("Expected '0x234', got %v", response.Address.String())
+	}
+
+	mockClient.AssertNotCalled(t, "FetchWithMethod", mock.Anything, "http://localhost:8090")
+}
+
+func TestDistributeRequestWithHedgeCancellation(t *testing.T) {
+	t.Parallel()
+
+	mockClient := new(MockHTTPClient)
+	r := NewSimpleRouter(mockClient, &config.Hedge{
+		Enabled:       true,
+		DefaultBudget: 10 * time.Millisecond,
+		MinBudget:     time.Millisecond,
+		MaxBudget:     time.Second,
+		WindowSize:    20,
+	}, nil)
+
+	canceled := make(chan struct{})
+
+	// The best ranked Node hangs until its request is canceled.
+	mockClient.On("FetchWithMethod", mock.Anything, "http://localhost:8070").Run(func(args mock.Arguments) {
+		<-args.Get(0).(context.Context).Done()
+		close(canceled)
+	}).Return(io.NopCloser(bytes.NewBufferString("")), httputil.ErrorManuallyCanceled)
+	mockClient.On("FetchWithMethod", mock.Anything, "http://localhost:8080").Return(io.NopCloser(bytes.NewBufferString(validActivitiesData)), nil)
+
+	processed := make(chan []*model.DataResponse, 1)
+
+	response, err := r.DistributeRequest(context.Background(), hedgeNodeMap, func(responses []*model.DataResponse) {
+		processed <- responses
+	})
+	require.NoError(t, err)
+	require.NoError(t, response.Err)
+	assert.Equal(t, common.HexToAddress("0x234"), response.Address)
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("the hedged request is not canceled once the first response is served")
+	}
+
+	// The only completed response cannot be verified against any other, so it is not processed.
+	select {
+	case responses := <-processed:
+		t.Fatalf("expected no responses to be processed, got %d", len(responses))
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	mockClient.AssertNotCalled(t, "FetchWithMethod", mock.Anything, "http://localhost:8090")
+}
+
+func TestLatencyTrackerBudget(t *testing.T) {
+	t.Parallel()
+
+	tracker := newLatencyTracker(20, time.Second, 10*time.Millisecond, 500*time.Millisecond)
+	address := common.HexToAddress("0x123")
+
+	if budget := tracker.budget(address); budget != time.Second {
+		t.Errorf("Expected default budget, got %v", budget)
+	}
+
+	for i := 1; i <= 20; i++ {
+		tracker.observe(address, time.Duration(i)*10*time.Millisecond)
+	}
+
+	// the p95 of 10ms..200ms is 190ms
+	if budget := tracker.budget(address); budget != 190*time.Millisecond {
+		t.Errorf("Expected '190ms', got %v", budget)
+	}
+
+	for i := 0; i < 20; i++ {
+		tracker.observe(address, time.Second)
+	}
+
+	if budget := tracker.budget(address); budget != 500*time.Millisecond {
+		t.Errorf("Expected '500ms', got %v", budget)
+	}
+}
+
 func process(responses []*model.DataResponse) {
 	fmt.Println(len(responses))
 
@@ -221,3 +386,42 @@ func process(responses []*model.DataResponse) {
 		fmt.Printf("address: %s,valid:%v\n", res.Address.String(), res.Valid)
 	}
 }
+
+func TestDistributeRequestWithHedgeSkipsOpenNodes(t *testing.T) {
+	t.Parallel()
+
+	redisServer := miniredis.RunT(t)
+	cacheClient := cache.New(redis.NewClient(&redis.Options{Addr: redisServer.Addr()}))
+
+	circuitBreakerConfig := &config.CircuitBreaker{
+		Enabled:             true,
+		ConsecutiveFailures: 5,
+		ErrorRate:           0.5,
+		MinRequests:         20,
+		Window:              time.Minute,
+		OpenDuration:        30 * time.Second,
+		HalfOpenSuccesses:   3,
+	}
+
+	// The breaker of the best ranked Node is open, and the breaker of the worst ranked one is half-open.
+	require.NoError(t, cacheClient.ZAdd(context.Background(), breaker.OpenNodesCacheKey,
+		redis.Z{Member: common.HexToAddress("0x123").String(), Score: float64(time.Now().UnixMilli())},
+		redis.Z{Member: common.HexToAddress("0x567").String(), Score: float64(time.Now().Add(-time.Minute).UnixMilli())},
+	))
+
+	mockClient := new(MockHTTPClient)
+	r := NewSimpleRouter(mockClient, hedgeConfig, breaker.New(cacheClient, circuitBreakerConfig))
+
+	mockClient.On("FetchWithMethod", mock.Anything, "http://localhost:8080").Return(io.NopCloser(bytes.NewBufferString(validActivitiesData)), nil)
+
+	response, err := r.DistributeRequest(context.Background(), hedgeNodeMap, process)
+	require.NoError(t, err)
+	require.NoError(t, response.Err)
+	assert.Equal(t, common.HexToAddress("0x234"), response.Address)
+
+	mockClient.AssertNotCalled(t, "FetchWithMethod", mock.Anything, "http://localhost:8070")
+	mockClient.AssertNotCalled(t, "FetchWithMethod", mock.Anything, "http://localhost:8090")
+
+	// The half-open Node which is never requested keeps its probe for the next request.
+	assert.False(t, redisServer.Exists(fmt.Sprintf("%s:%s", breaker.HalfOpenProbeCacheKey, common.HexToAddress("0x567").String())))
+}
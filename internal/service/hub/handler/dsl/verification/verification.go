@@ -0,0 +1,429 @@
+package verification
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/rss3-network/global-indexer/internal/config"
+	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/model"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+)
+
+// The request types a policy can be set for.
+const (
+	RequestAI                      = "ai"
+	RequestDecentralizedActivity   = "decentralized_activity"
+	RequestDecentralizedActivities = "decentralized_activities"
+	RequestFederatedActivity       = "federated_activity"
+	RequestFederatedActivities     = "federated_activities"
+)
+
+const (
+	// ModeIdentity compares the responses with each other.
+	ModeIdentity = "identity"
+	// ModeData only checks that the responses carry data.
+	ModeData = "data"
+	// ModeMajority compares any number of responses, and rewards the majority of identical responses.
+	ModeMajority = "majority"
+)
+
+const (
+	// MutableSkip leaves the activities out of the comparison.
+	MutableSkip = "skip"
+	// MutableCompare compares the activities as any other.
+	MutableCompare = "compare"
+)
+
+// FieldActions is the activity field comparing the actions, the fields of each action are set by the ActionFields of a policy.
+const FieldActions = "actions"
+
+var (
+	// activityFields are the activity fields a policy can require to match.
+	activityFields = map[string]func(src, des *model.Activity) bool{
+		"id":        func(src, des *model.Activity) bool { return src.ID == des.ID },
+		"owner":     func(src, des *model.Activity) bool { return src.Owner == des.Owner },
+		"network":   func(src, des *model.Activity) bool { return src.Network == des.Network },
+		"index":     func(src, des *model.Activity) bool { return src.Index == des.Index },
+		"from":      func(src, des *model.Activity) bool { return src.From == des.From },
+		"to":        func(src, des *model.Activity) bool { return src.To == des.To },
+		"tag":       func(src, des *model.Activity) bool { return src.Tag == des.Tag },
+		"type":      func(src, des *model.Activity) bool { return src.Type == des.Type },
+		"platform":  func(src, des *model.Activity) bool { return src.Platform == des.Platform },
+		"timestamp": func(src, des *model.Activity) bool { return src.Timestamp == des.Timestamp },
+		FieldActions: func(src, des *model.Activity) bool {
+			return len(src.Actions) == len(des.Actions)
+		},
+	}
+
+	// actionFields are the action fields a policy can require to match.
+	actionFields = map[string]func(src, des *model.Action) bool{
+		"from":     func(src, des *model.Action) bool { return src.From == des.From },
+		"to":       func(src, des *model.Action) bool { return src.To == des.To },
+		"tag":      func(src, des *model.Action) bool { return src.Tag == des.Tag },
+		"type":     func(src, des *model.Action) bool { return src.Type == des.Type },
+		"platform": func(src, des *model.Action) bool { return src.Platform == des.Platform },
+	}
+
+	defaultFields       = []string{"id", "network", "index", "from", "to", "tag", "type", "platform", FieldActions}
+	defaultActionFields = []string{"from", "to", "tag", "type"}
+
+	// defaultModes are the modes of the request types, only the decentralized activities are compared by default.
+	defaultModes = map[string]string{
+		RequestAI:                      ModeData,
+		RequestDecentralizedActivity:   ModeIdentity,
+		RequestDecentralizedActivities: ModeIdentity,
+		RequestFederatedActivity:       ModeData,
+		RequestFederatedActivities:     ModeData,
+	}
+)
+
+// Policy is a resolved verification policy.
+type Policy struct {
+	Mode         string
+	Fields       []string
+	ActionFields []string
+	Tolerance    time.Duration
+	Quorum       int
+	Mutable      string
+}
+
+// IsActivityIdentical returns true if the fields of two activities required by the policy are identical.
+func (p *Policy) IsActivityIdentical(src, des *model.Activity) bool {
+	for _, field := range p.Fields {
+		if !activityFields[field](src, des) {
+			return false
+		}
+	}
+
+	// check if the inner actions are identical
+	if !lo.Contains(p.Fields, FieldActions) {
+		return true
+	}
+
+	for i := range des.Actions {
+		for _, field := range p.ActionFields {
+			if !actionFields[field](src.Actions[i], des.Actions[i]) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// IsSettled returns true if the activity is older than the tolerance of the policy.
+// Newer activities may not be indexed by all Nodes yet, and are not compared.
+func (p *Policy) IsSettled(activity *model.Activity, now time.Time) bool {
+	return activity.Timestamp <= uint64(now.Add(-p.Tolerance).Unix())
+}
+
+// IsSkipped returns true if the activities are left out of the comparison by the policy.
+func (p *Policy) IsSkipped() bool {
+	return p.Mutable == MutableSkip
+}
+
+// Policies are the verification policies of a request type.
+type Policies struct {
+	request   *Policy
+	networks  map[string]*config.VerificationPolicy
+	platforms map[string]*config.VerificationPolicy
+}
+
+// Request returns the policy of the request type.
+func (p *Policies) Request() *Policy {
+	return p.request
+}
+
+// Activity returns the policy of an activity, the policy of the request type overridden by the policies of its network and its platform.
+func (p *Policies) Activity(activity *model.Activity) *Policy {
+	network, hasNetwork := p.networks[strings.ToLower(activity.Network)]
+	platform, hasPlatform := p.platforms[strings.ToLower(activity.Platform)]
+
+	if !hasNetwork && !hasPlatform {
+		return p.request
+	}
+
+	policy := *p.request
+
+	if hasNetwork {
+		override(&policy, network)
+	}
+
+	if hasPlatform {
+		override(&policy, platform)
+	}
+
+	return &policy
+}
+
+// snapshot is a set of policies loaded from a config.
+type snapshot struct {
+	requests  map[string]*Policy
+	networks  map[string]*config.VerificationPolicy
+	platforms map[string]*config.VerificationPolicy
+}
+
+// Registry holds the verification policies of the DSL responses, the policies can be reloaded while they are being used.
+type Registry struct {
+	snapshot atomic.Pointer[snapshot]
+}
+
+// New creates a Registry from the config of the distributor, the built-in policies are used if the config is not set.
+func New(conf *config.Distributor) (*Registry, error) {
+	registry := &Registry{}
+
+	if err := registry.Reload(conf); err != nil {
+		return nil, err
+	}
+
+	return registry, nil
+}
+
+// Reload replaces the policies with the ones from the config, the policies are left untouched if the config is invalid.
+func (r *Registry) Reload(conf *config.Distributor) error {
+	snapshot, err := load(conf)
+	if err != nil {
+		return err
+	}
+
+	r.snapshot.Store(snapshot)
+
+	return nil
+}
+
+// Policies returns the current policies of the request type.
+func (r *Registry) Policies(request string) *Policies {
+	snapshot := r.snapshot.Load()
+
+	policy, exists := snapshot.requests[request]
+	if !exists {
+		policy = snapshot.requests[""]
+	}
+
+	return &Policies{
+		request:   policy,
+		networks:  snapshot.networks,
+		platforms: snapshot.platforms,
+	}
+}
+
+// ReloadOnSignal reloads the policies from the config file whenever the process receives a SIGHUP.
+func (r *Registry) ReloadOnSignal(ctx context.Context, configFilePath string) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(signals)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+			}
+
+			file, err := config.Load(configFilePath)
+			if err != nil {
+				zap.L().Error("load config file for verification policies", zap.String("path", configFilePath), zap.Error(err))
+
+				continue
+			}
+
+			if err := r.Reload(file.Distributor); err != nil {
+				zap.L().Error("reload verification policies", zap.Error(err))
+
+				continue
+			}
+
+			zap.L().Info("verification policies reloaded", zap.String("path", configFilePath))
+		}
+	}()
+}
+
+// load resolves the policies of all request types from the config.
+func load(conf *config.Distributor) (*snapshot, error) {
+	var verification config.Verification
+
+	base := Policy{
+		Fields:       defaultFields,
+		ActionFields: defaultActionFields,
+		Tolerance:    time.Duration(model.ToleranceSeconds) * time.Second,
+		Quorum:       1,
+		Mutable:      MutableCompare,
+	}
+
+	if conf != nil {
+		base.Tolerance = time.Duration(conf.ToleranceSeconds) * time.Second
+
+		if conf.Verification != nil {
+			verification = *conf.Verification
+		}
+	}
+
+	snapshot := &snapshot{
+		requests:  make(map[string]*Policy, len(defaultModes)+1),
+		networks:  make(map[string]*config.VerificationPolicy, len(verification.Networks)),
+		platforms: make(map[string]*config.VerificationPolicy, len(model.MutablePlatformMap)+len(verification.Platforms)),
+	}
+
+	// The activities of the mutable platforms are skipped, unless their platform policies say otherwise.
+	for platform := range model.MutablePlatformMap {
+		snapshot.platforms[strings.ToLower(platform)] = &config.VerificationPolicy{Mutable: MutableSkip}
+	}
+
+	for _, policies := range []map[string]*config.VerificationPolicy{verification.Networks, verification.Platforms} {
+		for key, policy := range policies {
+			if err := validate(policy); err != nil {
+				return nil, fmt.Errorf("verification policy of %s: %w", key, err)
+			}
+		}
+	}
+
+	for network, policy := range verification.Networks {
+		snapshot.networks[strings.ToLower(network)] = policy
+	}
+
+	// The policy of a mutable platform is merged onto its built-in policy, so that its activities are still skipped unless mutable is set.
+	for platform, policy := range verification.Platforms {
+		key := strings.ToLower(platform)
+
+		if builtIn, exists := snapshot.platforms[key]; exists {
+			policy = merge(builtIn, policy)
+		}
+
+		snapshot.platforms[key] = policy
+	}
+
+	if err := validate(verification.Default); err != nil {
+		return nil, fmt.Errorf("default verification policy: %w", err)
+	}
+
+	override(&base, verification.Default)
+
+	for request := range verification.Requests {
+		if _, exists := defaultModes[request]; !exists {
+			return nil, fmt.Errorf("verification policy of unknown request type: %s", request)
+		}
+	}
+
+	// The empty request type holds the default policy, for the request types without a policy.
+	for _, request := range append(lo.Keys(defaultModes), "") {
+		policy := base
+
+		if policy.Mode == "" {
+			policy.Mode = lo.ValueOr(defaultModes, request, ModeIdentity)
+		}
+
+		if err := validate(verification.Requests[request]); err != nil {
+			return nil, fmt.Errorf("verification policy of %s: %w", request, err)
+		}
+
+		override(&policy, verification.Requests[request])
+
+		snapshot.requests[request] = &policy
+	}
+
+	return snapshot, nil
+}
+
+// validate checks that the mode, the mutable option and the fields of the policy are known.
+func validate(policy *config.VerificationPolicy) error {
+	if policy == nil {
+		return nil
+	}
+
+	if policy.Mode != "" && !lo.Contains([]string{ModeIdentity, ModeMajority, ModeData}, policy.Mode) {
+		return fmt.Errorf("unknown mode: %s", policy.Mode)
+	}
+
+	if policy.Mutable != "" && !lo.Contains([]string{MutableSkip, MutableCompare}, policy.Mutable) {
+		return fmt.Errorf("unknown mutable option: %s", policy.Mutable)
+	}
+
+	for _, field := range policy.Fields {
+		if _, exists := activityFields[field]; !exists {
+			return fmt.Errorf("unknown activity field: %s", field)
+		}
+	}
+
+	for _, field := range policy.ActionFields {
+		if _, exists := actionFields[field]; !exists {
+			return fmt.Errorf("unknown action field: %s", field)
+		}
+	}
+
+	return nil
+}
+
+// merge returns a copy of the config policy, with the fields set by the other config policy.
+func merge(policy, conf *config.VerificationPolicy) *config.VerificationPolicy {
+	merged := *policy
+
+	if conf == nil {
+		return &merged
+	}
+
+	if conf.Mode != "" {
+		merged.Mode = conf.Mode
+	}
+
+	if len(conf.Fields) > 0 {
+		merged.Fields = conf.Fields
+	}
+
+	if len(conf.ActionFields) > 0 {
+		merged.ActionFields = conf.ActionFields
+	}
+
+	if conf.Tolerance != nil {
+		merged.Tolerance = conf.Tolerance
+	}
+
+	if conf.Quorum > 0 {
+		merged.Quorum = conf.Quorum
+	}
+
+	if conf.Mutable != "" {
+		merged.Mutable = conf.Mutable
+	}
+
+	return &merged
+}
+
+// override sets the fields of the policy that are set by the config.
+func override(policy *Policy, conf *config.VerificationPolicy) {
+	if conf == nil {
+		return
+	}
+
+	if conf.Mode != "" {
+		policy.Mode = conf.Mode
+	}
+
+	if len(conf.Fields) > 0 {
+		policy.Fields = conf.Fields
+	}
+
+	if len(conf.ActionFields) > 0 {
+		policy.ActionFields = conf.ActionFields
+	}
+
+	if conf.Tolerance != nil {
+		policy.Tolerance = *conf.Tolerance
+	}
+
+	if conf.Quorum > 0 {
+		policy.Quorum = conf.Quorum
+	}
+
+	if conf.Mutable != "" {
+		policy.Mutable = conf.Mutable
+	}
+}
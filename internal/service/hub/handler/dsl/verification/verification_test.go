@@ -0,0 +1,191 @@
+package verification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rss3-network/global-indexer/internal/config"
+	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/model"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicies(t *testing.T) {
+	t.Parallel()
+
+	conf := &config.Distributor{
+		ToleranceSeconds: 1200,
+		Verification: &config.Verification{
+			Default: &config.VerificationPolicy{
+				Quorum: 2,
+			},
+			Requests: map[string]*config.VerificationPolicy{
+				RequestAI: {Mode: ModeIdentity},
+			},
+			Networks: map[string]*config.VerificationPolicy{
+				"ethereum": {Tolerance: lo.ToPtr(time.Minute)},
+			},
+			Platforms: map[string]*config.VerificationPolicy{
+				"Uniswap":   {Fields: []string{"id", "platform"}},
+				"Farcaster": {Quorum: 3},
+			},
+		},
+	}
+
+	registry, err := New(conf)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		request  string
+		activity *model.Activity
+		expected *Policy
+	}{
+		{
+			name:     "DefaultRequest",
+			request:  RequestFederatedActivity,
+			activity: &model.Activity{Network: "mastodon"},
+			expected: &Policy{Mode: ModeData, Fields: defaultFields, ActionFields: defaultActionFields, Tolerance: 20 * time.Minute, Quorum: 2, Mutable: MutableCompare},
+		},
+		{
+			name:     "RequestOverride",
+			request:  RequestAI,
+			activity: &model.Activity{Network: "polygon"},
+			expected: &Policy{Mode: ModeIdentity, Fields: defaultFields, ActionFields: defaultActionFields, Tolerance: 20 * time.Minute, Quorum: 2, Mutable: MutableCompare},
+		},
+		{
+			name:     "NetworkOverride",
+			request:  RequestDecentralizedActivities,
+			activity: &model.Activity{Network: "ethereum"},
+			expected: &Policy{Mode: ModeIdentity, Fields: defaultFields, ActionFields: defaultActionFields, Tolerance: time.Minute, Quorum: 2, Mutable: MutableCompare},
+		},
+		{
+			name:     "PlatformOverride",
+			request:  RequestDecentralizedActivities,
+			activity: &model.Activity{Network: "ethereum", Platform: "Uniswap"},
+			expected: &Policy{Mode: ModeIdentity, Fields: []string{"id", "platform"}, ActionFields: defaultActionFields, Tolerance: time.Minute, Quorum: 2, Mutable: MutableCompare},
+		},
+		{
+			name:     "MutablePlatform",
+			request:  RequestDecentralizedActivity,
+			activity: &model.Activity{Network: "farcaster", Platform: "Farcaster"},
+			expected: &Policy{Mode: ModeIdentity, Fields: defaultFields, ActionFields: defaultActionFields, Tolerance: 20 * time.Minute, Quorum: 3, Mutable: MutableSkip},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, registry.Policies(tc.request).Activity(tc.activity))
+		})
+	}
+}
+
+func TestReload(t *testing.T) {
+	t.Parallel()
+
+	registry, err := New(nil)
+	require.NoError(t, err)
+
+	// An invalid config leaves the policies untouched.
+	err = registry.Reload(&config.Distributor{
+		Verification: &config.Verification{
+			Default: &config.VerificationPolicy{Fields: []string{"metadata"}},
+		},
+	})
+	require.Error(t, err)
+	assert.Equal(t, defaultFields, registry.Policies(RequestDecentralizedActivity).Request().Fields)
+
+	for _, policy := range []*config.VerificationPolicy{{Mode: "majorty"}, {Mutable: "skipped"}} {
+		err = registry.Reload(&config.Distributor{
+			Verification: &config.Verification{
+				Platforms: map[string]*config.VerificationPolicy{"Farcaster": policy},
+			},
+		})
+		require.Error(t, err)
+	}
+
+	err = registry.Reload(&config.Distributor{
+		Verification: &config.Verification{
+			Default: &config.VerificationPolicy{Fields: []string{"id"}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id"}, registry.Policies(RequestDecentralizedActivity).Request().Fields)
+}
+
+func TestIsActivityIdentical(t *testing.T) {
+	t.Parallel()
+
+	src := &model.Activity{
+		ID:       "0x1",
+		Network:  "ethereum",
+		Tag:      "transaction",
+		Type:     "transfer",
+		Platform: "Uniswap",
+		Actions:  []*model.Action{{Tag: "transaction", Type: "transfer", From: "0xa", To: "0xb"}},
+	}
+
+	testCases := []struct {
+		name     string
+		policy   *Policy
+		des      *model.Activity
+		expected bool
+	}{
+		{
+			name:     "Identical",
+			policy:   &Policy{Fields: defaultFields, ActionFields: defaultActionFields},
+			des:      src,
+			expected: true,
+		},
+		{
+			name:   "DifferentField",
+			policy: &Policy{Fields: defaultFields, ActionFields: defaultActionFields},
+			des: &model.Activity{
+				ID: "0x1", Network: "ethereum", Tag: "transaction", Type: "swap", Platform: "Uniswap",
+				Actions: src.Actions,
+			},
+			expected: false,
+		},
+		{
+			name:   "DifferentFieldNotRequired",
+			policy: &Policy{Fields: []string{"id", "network", FieldActions}, ActionFields: defaultActionFields},
+			des: &model.Activity{
+				ID: "0x1", Network: "ethereum", Tag: "transaction", Type: "swap", Platform: "Uniswap",
+				Actions: src.Actions,
+			},
+			expected: true,
+		},
+		{
+			name:   "DifferentAction",
+			policy: &Policy{Fields: defaultFields, ActionFields: defaultActionFields},
+			des: &model.Activity{
+				ID: "0x1", Network: "ethereum", Tag: "transaction", Type: "transfer", Platform: "Uniswap",
+				Actions: []*model.Action{{Tag: "transaction", Type: "transfer", From: "0xa", To: "0xc"}},
+			},
+			expected: false,
+		},
+		{
+			name:   "DifferentActionNotRequired",
+			policy: &Policy{Fields: []string{"id", "network"}, ActionFields: defaultActionFields},
+			des: &model.Activity{
+				ID: "0x1", Network: "ethereum",
+			},
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, tc.policy.IsActivityIdentical(src, tc.des))
+		})
+	}
+}
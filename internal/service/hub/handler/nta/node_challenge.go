@@ -1,13 +1,25 @@
 package nta
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"math/big"
+	"net"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
 	"github.com/rss3-network/global-indexer/internal/service/hub/model/errorx"
 	"github.com/rss3-network/global-indexer/internal/service/hub/model/nta"
+	"go.uber.org/zap"
 )
 
 var (
@@ -15,6 +27,103 @@ var (
 	hideTaxRateMessage  = "I, %s, am signing this message for registering my intention to hide the tax rate on Explorer for my RSS3 Node."
 )
 
+const (
+	ChallengeTypeRegistration = ""
+	ChallengeTypeHeartbeat    = "heartbeat"
+	ChallengeTypeHideTaxRate  = "hideTaxRate"
+	ChallengeTypePreferences  = "preferences"
+	ChallengeTypeMaintenance  = "maintenance"
+)
+
+var (
+	ErrChallengeNotFound = errors.New("challenge not found or already used")
+	ErrChallengeRequired = errors.New("a nonce challenge is required")
+	ErrChallengeLimited  = errors.New("too many challenges issued")
+)
+
+// challengeMessage is the EIP-191 form of a nonce challenge.
+var challengeMessage = `%s wants you to sign this challenge with your RSS3 Node:
+%s
+
+Action: %s
+Chain ID: %d
+Nonce: %s
+Issued At: %s
+Expiration Time: %s`
+
+// nodeChallenge is a challenge issued to a Node, which is stored until it is used or expired.
+type nodeChallenge struct {
+	Address   common.Address `json:"address"`
+	Type      string         `json:"type"`
+	Domain    string         `json:"domain"`
+	ChainID   uint64         `json:"chain_id"`
+	Nonce     string         `json:"nonce"`
+	IssuedAt  int64          `json:"issued_at"`
+	ExpiresAt int64          `json:"expires_at"`
+}
+
+// challengeAction is the name of the challenge type shown to the signer.
+func challengeAction(challengeType string) string {
+	if challengeType == ChallengeTypeRegistration {
+		return "registration"
+	}
+
+	return challengeType
+}
+
+func (c *nodeChallenge) message() string {
+	return fmt.Sprintf(challengeMessage,
+		c.Domain,
+		strings.ToLower(c.Address.String()),
+		challengeAction(c.Type),
+		c.ChainID,
+		c.Nonce,
+		time.Unix(c.IssuedAt, 0).UTC().Format(time.RFC3339),
+		time.Unix(c.ExpiresAt, 0).UTC().Format(time.RFC3339),
+	)
+}
+
+// typedDataDomainType is the type of the EIP-712 domain of the data signed by the Nodes.
+var typedDataDomainType = []apitypes.Type{
+	{Name: "name", Type: "string"},
+	{Name: "version", Type: "string"},
+	{Name: "chainId", Type: "uint256"},
+}
+
+func typedDataDomain(chainID uint64) apitypes.TypedDataDomain {
+	return apitypes.TypedDataDomain{
+		Name:    "RSS3 Global Indexer",
+		Version: "1",
+		ChainId: (*math.HexOrDecimal256)(new(big.Int).SetUint64(chainID)),
+	}
+}
+
+func (c *nodeChallenge) typedData() apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": typedDataDomainType,
+			"NodeChallenge": {
+				{Name: "node", Type: "address"},
+				{Name: "action", Type: "string"},
+				{Name: "domain", Type: "string"},
+				{Name: "nonce", Type: "string"},
+				{Name: "issuedAt", Type: "uint256"},
+				{Name: "expiresAt", Type: "uint256"},
+			},
+		},
+		PrimaryType: "NodeChallenge",
+		Domain:      typedDataDomain(c.ChainID),
+		Message: apitypes.TypedDataMessage{
+			"node":      strings.ToLower(c.Address.String()),
+			"action":    challengeAction(c.Type),
+			"domain":    c.Domain,
+			"nonce":     c.Nonce,
+			"issuedAt":  fmt.Sprint(c.IssuedAt),
+			"expiresAt": fmt.Sprint(c.ExpiresAt),
+		},
+	}
+}
+
 func (n *NTA) GetNodeChallenge(c echo.Context) error {
 	var request nta.NodeChallengeRequest
 
@@ -29,9 +138,9 @@ func (n *NTA) GetNodeChallenge(c echo.Context) error {
 	var data nta.NodeChallengeResponseData
 
 	switch request.Type {
-	case "":
+	case ChallengeTypeRegistration:
 		data = nta.NodeChallengeResponseData(fmt.Sprintf(registrationMessage, strings.ToLower(request.NodeAddress.String())))
-	case "hideTaxRate":
+	case ChallengeTypeHideTaxRate:
 		data = nta.NodeChallengeResponseData(fmt.Sprintf(hideTaxRateMessage, strings.ToLower(request.NodeAddress.String())))
 	default:
 		return errorx.BadRequestError(c, fmt.Errorf("invalid challenge type: %s", request.Type))
@@ -41,3 +150,184 @@ func (n *NTA) GetNodeChallenge(c echo.Context) error {
 		Data: data,
 	})
 }
+
+// PostNodeChallenge issues a nonce challenge, which is consumed by the first request signed with it.
+func (n *NTA) PostNodeChallenge(c echo.Context) error {
+	var request nta.NodeChallengeRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	switch request.Type {
+	case ChallengeTypeRegistration, ChallengeTypeHeartbeat, ChallengeTypeHideTaxRate, ChallengeTypePreferences, ChallengeTypeMaintenance:
+	default:
+		return errorx.BadRequestError(c, fmt.Errorf("invalid challenge type: %s", request.Type))
+	}
+
+	ip, err := n.parseRequestIP(c)
+	if err != nil {
+		zap.L().Error("parse request ip", zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	if err := n.limitChallenges(c.Request().Context(), ip); err != nil {
+		if errors.Is(err, ErrChallengeLimited) {
+			return errorx.BadRequestError(c, err)
+		}
+
+		zap.L().Error("limit challenges", zap.Stringer("ip", ip), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	challenge, err := n.issueChallenge(c.Request().Context(), request.NodeAddress, request.Type)
+	if err != nil {
+		zap.L().Error("issue challenge", zap.String("address", request.NodeAddress.String()), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	return c.JSON(http.StatusOK, nta.Response{
+		Data: nta.NodeNonceChallengeResponseData{
+			Nonce:     challenge.Nonce,
+			Message:   challenge.message(),
+			TypedData: challenge.typedData(),
+			ExpiresAt: challenge.ExpiresAt,
+		},
+	})
+}
+
+// limitChallenges counts the challenges issued to a client IP within the expiry of a challenge, and fails once MaxChallengesPerIP are issued,
+// so the challenges stored by a client are bounded without refusing the challenges of a Node to the other clients.
+func (n *NTA) limitChallenges(ctx context.Context, ip net.IP) error {
+	limit := n.configFile.NodeChallenge
+	if limit.MaxChallengesPerIP == 0 {
+		return nil
+	}
+
+	key := n.buildChallengeCountKey(ip)
+
+	// The counter expires with the first challenge counted in it.
+	pipe := n.cacheClient.Pipeline(ctx)
+
+	pipe.SetNX(ctx, key, 0, limit.Expiry)
+	count := pipe.Incr(ctx, key)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("count challenges: %w", err)
+	}
+
+	if count.Val() > int64(limit.MaxChallengesPerIP) {
+		return fmt.Errorf("%w: %d challenges within %s", ErrChallengeLimited, limit.MaxChallengesPerIP, limit.Expiry)
+	}
+
+	return nil
+}
+
+// issueChallenge creates a challenge with a random nonce, and stores it until it expires.
+func (n *NTA) issueChallenge(ctx context.Context, address common.Address, challengeType string) (*nodeChallenge, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+
+	issuedAt := time.Now()
+	expiry := n.configFile.NodeChallenge.Expiry
+
+	challenge := nodeChallenge{
+		Address:   address,
+		Type:      challengeType,
+		Domain:    n.configFile.NodeChallenge.Domain,
+		ChainID:   n.chainL2ID,
+		Nonce:     hex.EncodeToString(nonce),
+		IssuedAt:  issuedAt.Unix(),
+		ExpiresAt: issuedAt.Add(expiry).Unix(),
+	}
+
+	if err := n.cacheClient.Set(ctx, n.buildNodeChallengeKey(address, challenge.Nonce), challenge, expiry); err != nil {
+		return nil, fmt.Errorf("save challenge: %w", err)
+	}
+
+	return &challenge, nil
+}
+
+// verifyChallenge verifies the signature of the challenge of the nonce, and consumes the challenge so it cannot be replayed.
+// The signature of the static message is verified instead if the nonce is not set and the legacy deadline is not passed.
+func (n *NTA) verifyChallenge(ctx context.Context, address common.Address, challengeType, nonce, signature string) error {
+	if nonce == "" {
+		return n.verifyLegacyChallenge(ctx, address, challengeType, signature)
+	}
+
+	challenge, err := n.consumeChallenge(ctx, address, challengeType, nonce)
+	if err != nil {
+		return err
+	}
+
+	// The signature is of either the EIP-191 or the EIP-712 form of the challenge.
+	if err := n.checkSignature(ctx, address, challenge.message(), signature); err == nil {
+		return nil
+	}
+
+	hash, _, err := apitypes.TypedDataAndHash(challenge.typedData())
+	if err != nil {
+		return fmt.Errorf("hash typed data: %w", err)
+	}
+
+	return checkHashSignature(address, hash, signature)
+}
+
+// consumeChallenge gets and deletes the challenge of the nonce, the challenge must be of the type and not expired.
+func (n *NTA) consumeChallenge(ctx context.Context, address common.Address, challengeType, nonce string) (*nodeChallenge, error) {
+	var challenge nodeChallenge
+
+	if err := n.cacheClient.GetDel(ctx, n.buildNodeChallengeKey(address, nonce), &challenge); err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrChallengeNotFound
+		}
+
+		return nil, fmt.Errorf("get challenge: %w", err)
+	}
+
+	if challenge.Type != challengeType {
+		return nil, fmt.Errorf("challenge is for %s, expected %s", challengeAction(challenge.Type), challengeAction(challengeType))
+	}
+
+	if time.Now().Unix() > challenge.ExpiresAt {
+		return nil, fmt.Errorf("challenge expired at %s", time.Unix(challenge.ExpiresAt, 0).UTC().Format(time.RFC3339))
+	}
+
+	return &challenge, nil
+}
+
+func (n *NTA) verifyLegacyChallenge(ctx context.Context, address common.Address, challengeType, signature string) error {
+	if deadline := n.configFile.NodeChallenge.LegacyDeadline; deadline != nil && time.Now().After(*deadline) {
+		return ErrChallengeRequired
+	}
+
+	message := registrationMessage
+	if challengeType == ChallengeTypeHideTaxRate {
+		message = hideTaxRateMessage
+	}
+
+	if err := n.checkSignature(ctx, address, fmt.Sprintf(message, strings.ToLower(address.String())), signature); err != nil {
+		return err
+	}
+
+	zap.L().Warn("node signed a static challenge", zap.String("address", address.String()), zap.String("type", challengeType))
+
+	return nil
+}
+
+func (n *NTA) buildNodeChallengeKey(address common.Address, nonce string) string {
+	return fmt.Sprintf("node::%s::challenge::%s", strings.ToLower(address.String()), nonce)
+}
+
+func (n *NTA) buildChallengeCountKey(ip net.IP) string {
+	return fmt.Sprintf("challenges::ip::%s", ip.String())
+}
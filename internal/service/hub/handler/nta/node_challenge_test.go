@@ -0,0 +1,253 @@
+package nta
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/redis/go-redis/v9"
+	"github.com/rss3-network/global-indexer/internal/cache"
+	"github.com/rss3-network/global-indexer/internal/config"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryCacheClient serves Set and GetDel from memory, the expiration is ignored.
+type memoryCacheClient struct {
+	cache.Client
+
+	locker sync.Mutex
+	values map[string][]byte
+}
+
+func (c *memoryCacheClient) Set(_ context.Context, key string, value interface{}, _ time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	c.locker.Lock()
+	defer c.locker.Unlock()
+
+	c.values[key] = data
+
+	return nil
+}
+
+func (c *memoryCacheClient) GetDel(_ context.Context, key string, dest interface{}) error {
+	c.locker.Lock()
+	defer c.locker.Unlock()
+
+	data, exists := c.values[key]
+	if !exists {
+		return redis.Nil
+	}
+
+	delete(c.values, key)
+
+	return json.Unmarshal(data, dest)
+}
+
+func newChallengeNTA(legacyDeadline *time.Time) *NTA {
+	return &NTA{
+		cacheClient: &memoryCacheClient{values: make(map[string][]byte)},
+		configFile: &config.File{
+			NodeChallenge: &config.NodeChallenge{
+				Domain:         "gi.rss3.io",
+				Expiry:         time.Minute,
+				LegacyDeadline: legacyDeadline,
+			},
+		},
+		chainL2ID: 12553,
+	}
+}
+
+// signMessage signs the message in the EIP-191 form with a V of 27 or 28, as the wallets do.
+func signMessage(t *testing.T, key *ecdsa.PrivateKey, message string) string {
+	t.Helper()
+
+	data := fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(message), message)
+
+	signature, err := crypto.Sign(crypto.Keccak256([]byte(data)), key)
+	require.NoError(t, err)
+
+	signature[crypto.RecoveryIDOffset] += 27
+
+	return hexutil.Encode(signature)
+}
+
+func signTypedData(t *testing.T, key *ecdsa.PrivateKey, typedData apitypes.TypedData) string {
+	t.Helper()
+
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	require.NoError(t, err)
+
+	signature, err := crypto.Sign(hash, key)
+	require.NoError(t, err)
+
+	return hexutil.Encode(signature)
+}
+
+func TestVerifyChallenge(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	testCases := []struct {
+		name string
+		// sign returns the challenge type, the nonce and the signature sent to the hub.
+		sign      func(t *testing.T, n *NTA) (string, string, string)
+		deadline  *time.Time
+		replay    bool
+		wantError error
+	}{
+		{
+			name: "EIP191",
+			sign: func(t *testing.T, n *NTA) (string, string, string) {
+				challenge, err := n.issueChallenge(context.Background(), address, ChallengeTypeHeartbeat)
+				require.NoError(t, err)
+
+				return ChallengeTypeHeartbeat, challenge.Nonce, signMessage(t, key, challenge.message())
+			},
+		},
+		{
+			name: "EIP712",
+			sign: func(t *testing.T, n *NTA) (string, string, string) {
+				challenge, err := n.issueChallenge(context.Background(), address, ChallengeTypeRegistration)
+				require.NoError(t, err)
+
+				return ChallengeTypeRegistration, challenge.Nonce, signTypedData(t, key, challenge.typedData())
+			},
+		},
+		{
+			name: "Replay",
+			sign: func(t *testing.T, n *NTA) (string, string, string) {
+				challenge, err := n.issueChallenge(context.Background(), address, ChallengeTypeHeartbeat)
+				require.NoError(t, err)
+
+				return ChallengeTypeHeartbeat, challenge.Nonce, signMessage(t, key, challenge.message())
+			},
+			replay:    true,
+			wantError: ErrChallengeNotFound,
+		},
+		{
+			name: "UnknownNonce",
+			sign: func(t *testing.T, _ *NTA) (string, string, string) {
+				return ChallengeTypeHeartbeat, "00", signMessage(t, key, "")
+			},
+			wantError: ErrChallengeNotFound,
+		},
+		{
+			name: "AnotherType",
+			sign: func(t *testing.T, n *NTA) (string, string, string) {
+				challenge, err := n.issueChallenge(context.Background(), address, ChallengeTypeHideTaxRate)
+				require.NoError(t, err)
+
+				return ChallengeTypeHeartbeat, challenge.Nonce, signMessage(t, key, challenge.message())
+			},
+			wantError: assert.AnError,
+		},
+		{
+			name: "AnotherKey",
+			sign: func(t *testing.T, n *NTA) (string, string, string) {
+				challenge, err := n.issueChallenge(context.Background(), address, ChallengeTypeHeartbeat)
+				require.NoError(t, err)
+
+				anotherKey, err := crypto.GenerateKey()
+				require.NoError(t, err)
+
+				return ChallengeTypeHeartbeat, challenge.Nonce, signMessage(t, anotherKey, challenge.message())
+			},
+			wantError: assert.AnError,
+		},
+		{
+			name: "LegacyWithinTransition",
+			sign: func(t *testing.T, _ *NTA) (string, string, string) {
+				message := fmt.Sprintf(registrationMessage, strings.ToLower(address.String()))
+
+				return ChallengeTypeHeartbeat, "", signMessage(t, key, message)
+			},
+			deadline: lo.ToPtr(time.Now().Add(time.Hour)),
+		},
+		{
+			name: "LegacyAfterTransition",
+			sign: func(t *testing.T, _ *NTA) (string, string, string) {
+				message := fmt.Sprintf(registrationMessage, strings.ToLower(address.String()))
+
+				return ChallengeTypeHeartbeat, "", signMessage(t, key, message)
+			},
+			deadline:  lo.ToPtr(time.Now().Add(-time.Hour)),
+			wantError: ErrChallengeRequired,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			n := newChallengeNTA(tc.deadline)
+
+			challengeType, nonce, signature := tc.sign(t, n)
+
+			err := n.verifyChallenge(context.Background(), address, challengeType, nonce, signature)
+			if tc.replay {
+				require.NoError(t, err)
+
+				err = n.verifyChallenge(context.Background(), address, challengeType, nonce, signature)
+			}
+
+			switch tc.wantError {
+			case nil:
+				require.NoError(t, err)
+			case assert.AnError:
+				require.Error(t, err)
+			default:
+				require.ErrorIs(t, err, tc.wantError)
+			}
+		})
+	}
+}
+
+func TestLimitChallenges(t *testing.T) {
+	t.Parallel()
+
+	redisServer := miniredis.RunT(t)
+
+	n := newChallengeNTA(nil)
+	n.cacheClient = cache.New(redis.NewClient(&redis.Options{Addr: redisServer.Addr()}))
+	n.configFile.NodeChallenge.MaxChallengesPerIP = 2
+
+	var (
+		ctx   = context.Background()
+		ip    = net.ParseIP("192.0.2.1")
+		other = net.ParseIP("2001:db8::1")
+	)
+
+	require.NoError(t, n.limitChallenges(ctx, ip))
+	require.NoError(t, n.limitChallenges(ctx, ip))
+	require.ErrorIs(t, n.limitChallenges(ctx, ip), ErrChallengeLimited)
+
+	// The challenges of the other clients are counted apart, whichever Nodes they are issued to.
+	require.NoError(t, n.limitChallenges(ctx, other))
+
+	// The limit is lifted once the challenges have expired.
+	redisServer.FastForward(n.configFile.NodeChallenge.Expiry)
+
+	require.NoError(t, n.limitChallenges(ctx, ip))
+}
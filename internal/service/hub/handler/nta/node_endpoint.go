@@ -0,0 +1,203 @@
+package nta
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/creasty/defaults"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/labstack/echo/v4"
+	"github.com/rss3-network/global-indexer/internal/database"
+	"github.com/rss3-network/global-indexer/internal/service/hub/model/errorx"
+	"github.com/rss3-network/global-indexer/internal/service/hub/model/nta"
+	"github.com/rss3-network/global-indexer/schema"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+)
+
+// ownershipMessage is signed by a Node with the token issued by the hub, and served from its new endpoint.
+var ownershipMessage = "I, %s, am signing the token %s to prove that my RSS3 Node is served at %s."
+
+// ownershipPath is the path of the endpoint of a Node serving the signed token.
+var ownershipPath = "/operators/ownership"
+
+var (
+	ErrEndpointChangeLimited  = errors.New("endpoint changed too often")
+	ErrEndpointChangeConflict = errors.New("endpoint changed by a concurrent registration")
+)
+
+func (n *NTA) GetNodeEndpointHistory(c echo.Context) error {
+	var request nta.NodeEndpointHistoryRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := defaults.Set(&request); err != nil {
+		return errorx.BadRequestError(c, fmt.Errorf("set default failed: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	histories, err := n.databaseClient.FindNodeEndpointHistories(c.Request().Context(), schema.NodeEndpointHistoryQuery{
+		Address: lo.ToPtr(request.NodeAddress),
+		Cursor:  request.Cursor,
+		Limit:   lo.ToPtr(request.Limit),
+	})
+	if err != nil {
+		zap.L().Error("get Node endpoint history failed", zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	var cursor string
+
+	if len(histories) > 0 && len(histories) == request.Limit {
+		last, _ := lo.Last(histories)
+		cursor = fmt.Sprint(last.ID)
+	}
+
+	return c.JSON(http.StatusOK, nta.Response{
+		Data:   nta.NodeEndpointHistoryResponseData(histories),
+		Cursor: cursor,
+	})
+}
+
+// verifyEndpointChange limits the endpoint changes of the Node, and verifies that the Node owns its new endpoint.
+// It returns whether the ownership of the endpoint is verified, which is only required on an endpoint change.
+func (n *NTA) verifyEndpointChange(ctx context.Context, previousNode *schema.Node, request *nta.RegisterNodeRequest) (bool, error) {
+	if !isEndpointChange(previousNode, request) {
+		return false, nil
+	}
+
+	if err := n.limitEndpointChanges(ctx, n.databaseClient, request.Address); err != nil {
+		return false, err
+	}
+
+	if err := n.proveEndpointOwnership(ctx, request); err != nil {
+		return false, fmt.Errorf("prove endpoint ownership: %w", err)
+	}
+
+	return true, nil
+}
+
+// isEndpointChange returns whether the registration changes the endpoint of a registered Node.
+// The Nodes indexed from the VSL before their first registration have a placeholder endpoint, which is not changed but set.
+func isEndpointChange(previousNode *schema.Node, request *nta.RegisterNodeRequest) bool {
+	if previousNode == nil || isPlaceholderEndpoint(previousNode) {
+		return false
+	}
+
+	return previousNode.Endpoint != request.Endpoint && request.Type != schema.NodeTypeAlpha.String()
+}
+
+// isPlaceholderEndpoint returns whether the endpoint of the Node is empty, or the address of the Node set when it is indexed.
+func isPlaceholderEndpoint(node *schema.Node) bool {
+	return node.Endpoint == "" || strings.EqualFold(node.Endpoint, node.Address.String())
+}
+
+// limitEndpointChanges checks the endpoint changes of the Node within the window against the limit.
+func (n *NTA) limitEndpointChanges(ctx context.Context, client database.Client, address common.Address) error {
+	limit := n.configFile.NodeEndpoint
+	if limit.MaxChanges == 0 {
+		return nil
+	}
+
+	changes, err := client.CountNodeEndpointChanges(ctx, address, time.Now().Add(-limit.Window))
+	if err != nil {
+		return fmt.Errorf("count endpoint changes: %w", err)
+	}
+
+	if changes >= int64(limit.MaxChanges) {
+		return fmt.Errorf("%w: %d changes within %s", ErrEndpointChangeLimited, changes, limit.Window)
+	}
+
+	return nil
+}
+
+// proveEndpointOwnership issues a token to the new endpoint of the Node, which must serve back the token signed by the Node.
+func (n *NTA) proveEndpointOwnership(ctx context.Context, request *nta.RegisterNodeRequest) error {
+	endpoint, err := n.parseEndpoint(ctx, request.Endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to parse endpoint: %w", err)
+	}
+
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return fmt.Errorf("generate token: %w", err)
+	}
+
+	tokenHex := hex.EncodeToString(token)
+
+	response, err := n.httpClient.FetchWithMethod(ctx, http.MethodGet, strings.TrimSuffix(endpoint, "/")+ownershipPath+"?token="+url.QueryEscape(tokenHex), "", nil)
+	if err != nil {
+		return fmt.Errorf("failed to fetch node endpoint %s: %w", endpoint, err)
+	}
+
+	defer lo.Try(response.Close)
+
+	var ownership nta.NodeEndpointOwnershipResponse
+
+	// Use a limited reader to avoid reading too much data.
+	if err := json.NewDecoder(io.LimitReader(response, 4096)).Decode(&ownership); err != nil {
+		return fmt.Errorf("failed to parse node response: %w", err)
+	}
+
+	message := fmt.Sprintf(ownershipMessage, strings.ToLower(request.Address.String()), tokenHex, request.Endpoint)
+
+	return n.checkSignature(ctx, request.Address, message, ownership.Signature)
+}
+
+// saveEndpointHistory records the changes of the endpoint and the settings of the Node, nothing is recorded if they are unchanged.
+// It is called with the row of the Node locked, so an endpoint change is checked against the limit again,
+// along with the changes recorded by the concurrent registrations.
+func (n *NTA) saveEndpointHistory(ctx context.Context, client database.Client, previousNode *schema.Node, request *nta.RegisterNodeRequest, verified bool) error {
+	history := schema.NodeEndpointHistory{
+		Address:            request.Address,
+		Endpoint:           request.Endpoint,
+		Stream:             request.Stream,
+		Config:             request.Config,
+		AccessTokenChanged: previousNode == nil,
+		Verified:           verified,
+	}
+
+	if previousNode != nil {
+		history.AccessTokenChanged = previousNode.AccessToken != fmt.Sprintf("Bearer %s", request.AccessToken)
+
+		if previousNode.Endpoint == request.Endpoint &&
+			bytes.Equal(previousNode.Stream, request.Stream) &&
+			bytes.Equal(previousNode.Config, request.Config) &&
+			!history.AccessTokenChanged {
+			return nil
+		}
+
+		// The placeholder endpoint is not recorded, so setting the endpoint is not counted as a change.
+		if !isPlaceholderEndpoint(previousNode) {
+			history.PreviousEndpoint = previousNode.Endpoint
+		}
+	}
+
+	if isEndpointChange(previousNode, request) {
+		if !verified {
+			return ErrEndpointChangeConflict
+		}
+
+		if err := n.limitEndpointChanges(ctx, client, request.Address); err != nil {
+			return err
+		}
+	}
+
+	return client.SaveNodeEndpointHistory(ctx, &history)
+}
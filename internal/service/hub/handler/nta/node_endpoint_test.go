@@ -0,0 +1,269 @@
+package nta
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rss3-network/global-indexer/internal/config"
+	"github.com/rss3-network/global-indexer/internal/database"
+	"github.com/rss3-network/global-indexer/internal/service/hub/model/nta"
+	"github.com/rss3-network/global-indexer/schema"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ownershipHTTPClient serves the ownership token signed with the key, as the endpoint of a Node does.
+type ownershipHTTPClient struct {
+	t   *testing.T
+	key *ecdsa.PrivateKey
+}
+
+func (c *ownershipHTTPClient) FetchWithMethod(_ context.Context, _, path, _ string, _ io.Reader) (io.ReadCloser, error) {
+	u, err := url.Parse(path)
+	require.NoError(c.t, err)
+	require.Equal(c.t, ownershipPath, u.Path)
+
+	endpoint := fmt.Sprintf("%s://%s", u.Scheme, u.Host)
+	message := fmt.Sprintf(ownershipMessage, strings.ToLower(crypto.PubkeyToAddress(c.key.PublicKey).String()), u.Query().Get("token"), endpoint)
+
+	data, err := json.Marshal(nta.NodeEndpointOwnershipResponse{
+		Signature: signMessage(c.t, c.key, message),
+	})
+	require.NoError(c.t, err)
+
+	return io.NopCloser(strings.NewReader(string(data))), nil
+}
+
+// endpointChangesDatabaseClient serves CountNodeEndpointChanges with a fixed count, and records the saved histories.
+type endpointChangesDatabaseClient struct {
+	database.Client
+
+	changes   int64
+	histories []*schema.NodeEndpointHistory
+}
+
+func (c *endpointChangesDatabaseClient) SaveNodeEndpointHistory(_ context.Context, history *schema.NodeEndpointHistory) error {
+	c.histories = append(c.histories, history)
+
+	return nil
+}
+
+func (c *endpointChangesDatabaseClient) CountNodeEndpointChanges(_ context.Context, _ common.Address, _ time.Time) (int64, error) {
+	return c.changes, nil
+}
+
+func TestVerifyEndpointChange(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	anotherKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	testCases := []struct {
+		name         string
+		previousNode *schema.Node
+		endpoint     string
+		nodeType     string
+		signer       *ecdsa.PrivateKey
+		changes      int64
+		wantVerified bool
+		wantError    error
+	}{
+		{
+			name:     "FirstRegistration",
+			endpoint: "https://node.example.com",
+			signer:   anotherKey,
+		},
+		{
+			name:         "PlaceholderEndpoint",
+			previousNode: &schema.Node{Address: address, Endpoint: address.String()},
+			endpoint:     "https://node.example.com",
+			signer:       anotherKey,
+			changes:      3,
+		},
+		{
+			name:         "EmptyEndpoint",
+			previousNode: &schema.Node{Address: address},
+			endpoint:     "https://node.example.com",
+			signer:       anotherKey,
+			changes:      3,
+		},
+		{
+			name:         "Unchanged",
+			previousNode: &schema.Node{Endpoint: "https://node.example.com"},
+			endpoint:     "https://node.example.com",
+			signer:       anotherKey,
+		},
+		{
+			name:         "AlphaNode",
+			previousNode: &schema.Node{Endpoint: "https://node.example.com"},
+			endpoint:     "https://new.example.com",
+			nodeType:     schema.NodeTypeAlpha.String(),
+			signer:       anotherKey,
+		},
+		{
+			name:         "Proven",
+			previousNode: &schema.Node{Endpoint: "https://node.example.com"},
+			endpoint:     "https://new.example.com",
+			signer:       key,
+			changes:      2,
+			wantVerified: true,
+		},
+		{
+			name:         "SignedByAnotherKey",
+			previousNode: &schema.Node{Endpoint: "https://node.example.com"},
+			endpoint:     "https://new.example.com",
+			signer:       anotherKey,
+			wantError:    assert.AnError,
+		},
+		{
+			name:         "Limited",
+			previousNode: &schema.Node{Endpoint: "https://node.example.com"},
+			endpoint:     "https://new.example.com",
+			signer:       key,
+			changes:      3,
+			wantError:    ErrEndpointChangeLimited,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			n := &NTA{
+				databaseClient: &endpointChangesDatabaseClient{changes: tc.changes},
+				httpClient:     &ownershipHTTPClient{t: t, key: tc.signer},
+				configFile: &config.File{
+					NodeEndpoint: &config.NodeEndpoint{
+						MaxChanges: 3,
+						Window:     24 * time.Hour,
+					},
+				},
+			}
+
+			verified, err := n.verifyEndpointChange(context.Background(), tc.previousNode, &nta.RegisterNodeRequest{
+				Address:  address,
+				Endpoint: tc.endpoint,
+				Type:     tc.nodeType,
+			})
+
+			switch tc.wantError {
+			case nil:
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantVerified, verified)
+			case assert.AnError:
+				require.Error(t, err)
+			default:
+				require.ErrorIs(t, err, tc.wantError)
+			}
+		})
+	}
+}
+
+func TestSaveEndpointHistory(t *testing.T) {
+	t.Parallel()
+
+	address := common.HexToAddress("0x3B6D02A24Df681FFdf621D35D70ABa7adaAc07c1")
+
+	testCases := []struct {
+		name                 string
+		previousNode         *schema.Node
+		verified             bool
+		changes              int64
+		wantPreviousEndpoint *string
+		wantError            error
+	}{
+		{
+			name:                 "FirstRegistration",
+			wantPreviousEndpoint: lo.ToPtr(""),
+		},
+		{
+			name:                 "PlaceholderEndpoint",
+			previousNode:         &schema.Node{Address: address, Endpoint: address.String()},
+			changes:              3,
+			wantPreviousEndpoint: lo.ToPtr(""),
+		},
+		{
+			name:         "Unchanged",
+			previousNode: &schema.Node{Address: address, Endpoint: "https://new.example.com", AccessToken: "Bearer token"},
+		},
+		{
+			name:                 "Changed",
+			previousNode:         &schema.Node{Address: address, Endpoint: "https://node.example.com"},
+			verified:             true,
+			changes:              2,
+			wantPreviousEndpoint: lo.ToPtr("https://node.example.com"),
+		},
+		{
+			name:         "LimitedConcurrently",
+			previousNode: &schema.Node{Address: address, Endpoint: "https://node.example.com"},
+			verified:     true,
+			changes:      3,
+			wantError:    ErrEndpointChangeLimited,
+		},
+		{
+			name:         "ChangedConcurrently",
+			previousNode: &schema.Node{Address: address, Endpoint: "https://node.example.com"},
+			wantError:    ErrEndpointChangeConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			databaseClient := &endpointChangesDatabaseClient{changes: tc.changes}
+
+			n := &NTA{
+				databaseClient: databaseClient,
+				configFile: &config.File{
+					NodeEndpoint: &config.NodeEndpoint{
+						MaxChanges: 3,
+						Window:     24 * time.Hour,
+					},
+				},
+			}
+
+			err := n.saveEndpointHistory(context.Background(), databaseClient, tc.previousNode, &nta.RegisterNodeRequest{
+				Address:     address,
+				Endpoint:    "https://new.example.com",
+				AccessToken: "token",
+			}, tc.verified)
+			if tc.wantError != nil {
+				require.ErrorIs(t, err, tc.wantError)
+				assert.Empty(t, databaseClient.histories)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			if tc.wantPreviousEndpoint == nil {
+				assert.Empty(t, databaseClient.histories)
+
+				return
+			}
+
+			require.Len(t, databaseClient.histories, 1)
+			assert.Equal(t, *tc.wantPreviousEndpoint, databaseClient.histories[0].PreviousEndpoint)
+		})
+	}
+}
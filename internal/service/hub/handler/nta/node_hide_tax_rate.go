@@ -23,9 +23,7 @@ func (n *NTA) PostNodeHideTaxRate(c echo.Context) error {
 		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
 	}
 
-	message := fmt.Sprintf(hideTaxRateMessage, strings.ToLower(request.NodeAddress.String()))
-
-	if err := n.checkSignature(c.Request().Context(), request.NodeAddress, message, request.Signature); err != nil {
+	if err := n.verifyChallenge(c.Request().Context(), request.NodeAddress, ChallengeTypeHideTaxRate, request.Nonce, request.Signature); err != nil {
 		return errorx.ValidationFailedError(c, fmt.Errorf("check signature: %w", err))
 	}
 
@@ -178,6 +178,10 @@ func (n *NTA) getNode(ctx context.Context, address common.Address) (*schema.Node
 	node.ReliabilityScore = reliabilityScore
 	node.Status = schema.NodeStatus(nodeInfo.Status)
 
+	if node.CircuitBreaker, err = n.circuitBreaker.Status(ctx, address); err != nil {
+		return nil, fmt.Errorf("get Node circuit breaker %s: %w", address, err)
+	}
+
 	return node, nil
 }
 
@@ -0,0 +1,137 @@
+package nta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/labstack/echo/v4"
+	"github.com/rss3-network/global-indexer/internal/database"
+	"github.com/rss3-network/global-indexer/internal/service/hub/model/errorx"
+	"github.com/rss3-network/global-indexer/internal/service/hub/model/nta"
+	"github.com/rss3-network/global-indexer/schema"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+)
+
+// PostNodeMaintenance schedules a maintenance window of the Node, the request is signed in the EIP-712 form with the nonce of a maintenance challenge.
+// Within the window the Node is not routed to, and its offline status is not counted as an invalid response.
+func (n *NTA) PostNodeMaintenance(c echo.Context) error {
+	var request nta.PostNodeMaintenanceRequest
+
+	ctx := c.Request().Context()
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	node, err := n.updateNodePreferences(ctx, request.NodeAddress, func(node *schema.Node) error {
+		now := time.Now()
+
+		preferences := scheduleMaintenanceWindow(node.Preferences, &schema.NodeMaintenanceWindow{
+			StartAt:  request.StartAt,
+			Duration: request.Duration,
+		}, now)
+
+		if err := n.validatePreferences(&nta.NodePreferences{
+			AlertWebhooks:      preferences.AlertWebhooks,
+			MaintenanceWindows: preferences.MaintenanceWindows,
+		}); err != nil {
+			return &preferencesRejectedError{err: fmt.Errorf("validate maintenance window: %w", err)}
+		}
+
+		if err := n.recordMaintenanceWindows(node.Preferences, preferences, now); err != nil {
+			return &preferencesRejectedError{err: fmt.Errorf("validate maintenance window: %w", err)}
+		}
+
+		if err := n.verifyMaintenance(ctx, &request); err != nil {
+			return &preferencesRejectedError{err: fmt.Errorf("check signature: %w", err)}
+		}
+
+		node.Preferences = preferences
+
+		return nil
+	})
+	if err != nil {
+		var rejected *preferencesRejectedError
+
+		switch {
+		case errors.Is(err, database.ErrorRowNotFound):
+			return errorx.BadParamsError(c, fmt.Errorf("node %s not found", request.NodeAddress.String()))
+		case errors.As(err, &rejected):
+			return errorx.ValidationFailedError(c, rejected.err)
+		}
+
+		zap.L().Error("update node preferences", zap.String("address", request.NodeAddress.String()), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	return c.JSON(http.StatusOK, nta.Response{
+		Data: nta.NewNodePreferences(node),
+	})
+}
+
+// scheduleMaintenanceWindow returns a copy of the preferences with the window added, the windows which have ended are dropped.
+func scheduleMaintenanceWindow(preferences *schema.NodePreferences, window *schema.NodeMaintenanceWindow, now time.Time) *schema.NodePreferences {
+	var result schema.NodePreferences
+
+	if preferences != nil {
+		result = *preferences
+	}
+
+	result.MaintenanceWindows = append(lo.Reject(result.MaintenanceWindows, func(window *schema.NodeMaintenanceWindow, _ int) bool {
+		return window.Ended(now)
+	}), window)
+
+	return &result
+}
+
+// verifyMaintenance verifies the EIP-712 signature of the maintenance window, and consumes the challenge of the nonce so it cannot be replayed.
+func (n *NTA) verifyMaintenance(ctx context.Context, request *nta.PostNodeMaintenanceRequest) error {
+	challenge, err := n.consumeChallenge(ctx, request.NodeAddress, ChallengeTypeMaintenance, request.Nonce)
+	if err != nil {
+		return err
+	}
+
+	hash, _, err := apitypes.TypedDataAndHash(maintenanceTypedData(request.NodeAddress, request.StartAt, request.Duration, challenge))
+	if err != nil {
+		return fmt.Errorf("hash typed data: %w", err)
+	}
+
+	return checkHashSignature(request.NodeAddress, hash, request.Signature)
+}
+
+// maintenanceTypedData is the EIP-712 form of the maintenance window, which is bound to the hub and the nonce of the challenge.
+func maintenanceTypedData(address common.Address, startAt, duration int64, challenge *nodeChallenge) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": typedDataDomainType,
+			"NodeMaintenance": {
+				{Name: "node", Type: "address"},
+				{Name: "startAt", Type: "uint256"},
+				{Name: "duration", Type: "uint256"},
+				{Name: "domain", Type: "string"},
+				{Name: "nonce", Type: "string"},
+			},
+		},
+		PrimaryType: "NodeMaintenance",
+		Domain:      typedDataDomain(challenge.ChainID),
+		Message: apitypes.TypedDataMessage{
+			"node":     strings.ToLower(address.String()),
+			"startAt":  fmt.Sprint(startAt),
+			"duration": fmt.Sprint(duration),
+			"domain":   challenge.Domain,
+			"nonce":    challenge.Nonce,
+		},
+	}
+}
@@ -0,0 +1,108 @@
+package nta
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rss3-network/global-indexer/internal/service/hub/model/nta"
+	"github.com/rss3-network/global-indexer/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleMaintenanceWindow(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	ended := &schema.NodeMaintenanceWindow{StartAt: now.Unix() - 7200, Duration: 3600}
+	ongoing := &schema.NodeMaintenanceWindow{StartAt: now.Unix() - 60, Duration: 3600}
+	window := &schema.NodeMaintenanceWindow{StartAt: now.Unix() + 3600, Duration: 3600}
+
+	preferences := &schema.NodePreferences{
+		AlertWebhooks:      []string{"https://alerts.example.com/rss3"},
+		OptOutAI:           true,
+		MaintenanceWindows: []*schema.NodeMaintenanceWindow{ended, ongoing},
+	}
+
+	result := scheduleMaintenanceWindow(preferences, window, now)
+
+	assert.Equal(t, &schema.NodePreferences{
+		AlertWebhooks:      []string{"https://alerts.example.com/rss3"},
+		OptOutAI:           true,
+		MaintenanceWindows: []*schema.NodeMaintenanceWindow{ongoing, window},
+	}, result)
+	// The preferences of the Node are left unchanged.
+	assert.Equal(t, []*schema.NodeMaintenanceWindow{ended, ongoing}, preferences.MaintenanceWindows)
+
+	assert.Equal(t, &schema.NodePreferences{
+		MaintenanceWindows: []*schema.NodeMaintenanceWindow{window},
+	}, scheduleMaintenanceWindow(nil, window, now))
+}
+
+func TestVerifyMaintenance(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	startAt := time.Now().Unix()
+
+	testCases := []struct {
+		name      string
+		challenge string
+		duration  int64
+		wantError bool
+	}{
+		{
+			name:      "Signed",
+			challenge: ChallengeTypeMaintenance,
+			duration:  3600,
+		},
+		{
+			name:      "Tampered",
+			challenge: ChallengeTypeMaintenance,
+			duration:  7200,
+			wantError: true,
+		},
+		{
+			name:      "PreferencesChallenge",
+			challenge: ChallengeTypePreferences,
+			duration:  3600,
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			n := newChallengeNTA(nil)
+
+			challenge, err := n.issueChallenge(context.Background(), address, tc.challenge)
+			require.NoError(t, err)
+
+			// The window of an hour is signed, the duration of the request may differ.
+			err = n.verifyMaintenance(context.Background(), &nta.PostNodeMaintenanceRequest{
+				NodeAddress: address,
+				StartAt:     startAt,
+				Duration:    tc.duration,
+				Nonce:       challenge.Nonce,
+				Signature:   signTypedData(t, key, maintenanceTypedData(address, startAt, 3600, challenge)),
+			})
+
+			if tc.wantError {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package nta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/labstack/echo/v4"
+	"github.com/rss3-network/global-indexer/internal/database"
+	"github.com/rss3-network/global-indexer/internal/service/hub/model/errorx"
+	"github.com/rss3-network/global-indexer/internal/service/hub/model/nta"
+	"github.com/rss3-network/global-indexer/schema"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+)
+
+func (n *NTA) GetNodePreferences(c echo.Context) error {
+	var request nta.GetNodePreferencesRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	node, err := n.databaseClient.FindNode(c.Request().Context(), request.NodeAddress)
+	if err != nil {
+		if errors.Is(err, database.ErrorRowNotFound) {
+			return c.NoContent(http.StatusNotFound)
+		}
+
+		zap.L().Error("find the node", zap.String("address", request.NodeAddress.String()), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	return c.JSON(http.StatusOK, nta.Response{
+		Data: nta.NewNodePreferences(node),
+	})
+}
+
+// PostNodePreferences replaces the preferences of the Node, the request is signed in the EIP-712 form with the nonce of a preferences challenge.
+func (n *NTA) PostNodePreferences(c echo.Context) error {
+	var request nta.PostNodePreferencesRequest
+
+	ctx := c.Request().Context()
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	if err := n.validatePreferences(&request.Preferences); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validate preferences: %w", err))
+	}
+
+	if err := n.verifyPreferences(ctx, &request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("check signature: %w", err))
+	}
+
+	node, err := n.updateNodePreferences(ctx, request.NodeAddress, func(node *schema.Node) error {
+		preferences := schema.NodePreferences{
+			AlertWebhooks:      request.Preferences.AlertWebhooks,
+			OptOutRSS:          request.Preferences.OptOutRSS,
+			OptOutAI:           request.Preferences.OptOutAI,
+			MaintenanceWindows: request.Preferences.MaintenanceWindows,
+		}
+
+		if err := n.recordMaintenanceWindows(node.Preferences, &preferences, time.Now()); err != nil {
+			return &preferencesRejectedError{err: fmt.Errorf("validate preferences: %w", err)}
+		}
+
+		node.HideTaxRate = request.Preferences.HideTaxRate
+		node.Preferences = &preferences
+
+		return nil
+	})
+	if err != nil {
+		var rejected *preferencesRejectedError
+
+		switch {
+		case errors.Is(err, database.ErrorRowNotFound):
+			return errorx.BadParamsError(c, fmt.Errorf("node %s not found", request.NodeAddress.String()))
+		case errors.As(err, &rejected):
+			return errorx.ValidationFailedError(c, rejected.err)
+		}
+
+		zap.L().Error("update node preferences", zap.String("address", request.NodeAddress.String()), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	// The cached hide tax rate status is applied when the Node is indexed or registered again.
+	if err := n.cacheClient.Set(ctx, n.buildNodeHideTaxRateKey(request.NodeAddress), request.Preferences.HideTaxRate, 0); err != nil {
+		zap.L().Error("cache hide tax value", zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	return c.JSON(http.StatusOK, nta.Response{
+		Data: nta.NewNodePreferences(node),
+	})
+}
+
+// preferencesRejectedError is returned by an update of the preferences which are rejected, rather than failed.
+type preferencesRejectedError struct {
+	err error
+}
+
+func (e *preferencesRejectedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *preferencesRejectedError) Unwrap() error {
+	return e.err
+}
+
+// updateNodePreferences updates the preferences of the Node under the lock of its row, so the concurrent updates of the preferences are not lost.
+// The update changes the preferences and the hide tax rate status of the Node, which are then saved.
+func (n *NTA) updateNodePreferences(ctx context.Context, address common.Address, update func(node *schema.Node) error) (*schema.Node, error) {
+	var node *schema.Node
+
+	err := n.databaseClient.WithTransaction(ctx, func(ctx context.Context, client database.Client) (err error) {
+		if node, err = client.FindNodeForUpdate(ctx, address); err != nil {
+			return err
+		}
+
+		if err := update(node); err != nil {
+			return err
+		}
+
+		return client.UpdateNodePreferences(ctx, address, node.HideTaxRate, node.Preferences)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// recordMaintenanceWindows records the maintenance windows of the preferences in their history, and checks the total duration of the windows
+// ending within the maintenance period against the limit. A window is dropped from the history if it is canceled before it starts.
+func (n *NTA) recordMaintenanceWindows(previous, preferences *schema.NodePreferences, now time.Time) error {
+	limit := n.configFile.NodePreferences
+
+	var history []*schema.NodeMaintenanceWindow
+
+	if previous != nil {
+		history = lo.Filter(previous.MaintenanceHistory, func(window *schema.NodeMaintenanceWindow, _ int) bool {
+			scheduled := lo.ContainsBy(preferences.MaintenanceWindows, func(other *schema.NodeMaintenanceWindow) bool {
+				return *other == *window
+			})
+
+			return !window.Ended(now.Add(-limit.MaintenancePeriod)) && (window.StartAt <= now.Unix() || scheduled)
+		})
+	}
+
+	for _, window := range preferences.MaintenanceWindows {
+		recorded := lo.ContainsBy(history, func(other *schema.NodeMaintenanceWindow) bool {
+			return *other == *window
+		})
+
+		if !recorded {
+			history = append(history, window)
+		}
+	}
+
+	preferences.MaintenanceHistory = history
+
+	if limit.MaxMaintenanceTime == 0 {
+		return nil
+	}
+
+	total := time.Duration(lo.SumBy(history, func(window *schema.NodeMaintenanceWindow) int64 {
+		return window.Duration
+	})) * time.Second
+
+	if total > limit.MaxMaintenanceTime {
+		return fmt.Errorf("the maintenance windows last %s within %s, at most %s is allowed", total, limit.MaintenancePeriod, limit.MaxMaintenanceTime)
+	}
+
+	return nil
+}
+
+// validatePreferences checks the preferences against the limits of the config, the maintenance windows which have ended are rejected.
+func (n *NTA) validatePreferences(preferences *nta.NodePreferences) error {
+	limit := n.configFile.NodePreferences
+
+	if len(preferences.AlertWebhooks) > limit.MaxAlertWebhooks {
+		return fmt.Errorf("at most %d alert webhooks are allowed", limit.MaxAlertWebhooks)
+	}
+
+	if len(preferences.MaintenanceWindows) > limit.MaxMaintenanceWindows {
+		return fmt.Errorf("at most %d maintenance windows are allowed", limit.MaxMaintenanceWindows)
+	}
+
+	now := time.Now()
+
+	for _, window := range preferences.MaintenanceWindows {
+		if window.Duration <= 0 || time.Duration(window.Duration)*time.Second > limit.MaxMaintenanceDuration {
+			return fmt.Errorf("the duration of a maintenance window must be between 1 second and %s", limit.MaxMaintenanceDuration)
+		}
+
+		if window.Ended(now) {
+			return fmt.Errorf("the maintenance window starting at %d has ended", window.StartAt)
+		}
+	}
+
+	return nil
+}
+
+// verifyPreferences verifies the EIP-712 signature of the preferences, and consumes the challenge of the nonce so it cannot be replayed.
+func (n *NTA) verifyPreferences(ctx context.Context, request *nta.PostNodePreferencesRequest) error {
+	challenge, err := n.consumeChallenge(ctx, request.NodeAddress, ChallengeTypePreferences, request.Nonce)
+	if err != nil {
+		return err
+	}
+
+	hash, _, err := apitypes.TypedDataAndHash(preferencesTypedData(request.NodeAddress, &request.Preferences, challenge))
+	if err != nil {
+		return fmt.Errorf("hash typed data: %w", err)
+	}
+
+	return checkHashSignature(request.NodeAddress, hash, request.Signature)
+}
+
+// preferencesTypedData is the EIP-712 form of the preferences, which is bound to the hub and the nonce of the challenge.
+func preferencesTypedData(address common.Address, preferences *nta.NodePreferences, challenge *nodeChallenge) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": typedDataDomainType,
+			"NodePreferences": {
+				{Name: "node", Type: "address"},
+				{Name: "hideTaxRate", Type: "bool"},
+				{Name: "alertWebhooks", Type: "string[]"},
+				{Name: "optOutRSS", Type: "bool"},
+				{Name: "optOutAI", Type: "bool"},
+				{Name: "maintenanceWindows", Type: "MaintenanceWindow[]"},
+				{Name: "domain", Type: "string"},
+				{Name: "nonce", Type: "string"},
+			},
+			"MaintenanceWindow": {
+				{Name: "startAt", Type: "uint256"},
+				{Name: "duration", Type: "uint256"},
+			},
+		},
+		PrimaryType: "NodePreferences",
+		Domain:      typedDataDomain(challenge.ChainID),
+		Message: apitypes.TypedDataMessage{
+			"node":        strings.ToLower(address.String()),
+			"hideTaxRate": preferences.HideTaxRate,
+			"alertWebhooks": lo.Map(preferences.AlertWebhooks, func(webhook string, _ int) interface{} {
+				return webhook
+			}),
+			"optOutRSS": preferences.OptOutRSS,
+			"optOutAI":  preferences.OptOutAI,
+			"maintenanceWindows": lo.Map(preferences.MaintenanceWindows, func(window *schema.NodeMaintenanceWindow, _ int) interface{} {
+				return map[string]interface{}{
+					"startAt":  fmt.Sprint(window.StartAt),
+					"duration": fmt.Sprint(window.Duration),
+				}
+			}),
+			"domain": challenge.Domain,
+			"nonce":  challenge.Nonce,
+		},
+	}
+}
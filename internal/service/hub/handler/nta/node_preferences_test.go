@@ -0,0 +1,269 @@
+package nta
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rss3-network/global-indexer/internal/config"
+	"github.com/rss3-network/global-indexer/internal/service/hub/model/nta"
+	"github.com/rss3-network/global-indexer/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyPreferences(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	preferences := nta.NodePreferences{
+		HideTaxRate:   true,
+		AlertWebhooks: []string{"https://alerts.example.com/rss3"},
+		OptOutAI:      true,
+		MaintenanceWindows: []*schema.NodeMaintenanceWindow{
+			{StartAt: time.Now().Unix(), Duration: 3600},
+		},
+	}
+
+	testCases := []struct {
+		name string
+		// tamper changes the request after the preferences are signed.
+		tamper    func(request *nta.PostNodePreferencesRequest)
+		challenge string
+		replay    bool
+		wantError error
+	}{
+		{
+			name:      "Signed",
+			challenge: ChallengeTypePreferences,
+		},
+		{
+			name:      "Replay",
+			challenge: ChallengeTypePreferences,
+			replay:    true,
+			wantError: ErrChallengeNotFound,
+		},
+		{
+			name:      "Tampered",
+			challenge: ChallengeTypePreferences,
+			tamper: func(request *nta.PostNodePreferencesRequest) {
+				request.Preferences.OptOutRSS = true
+			},
+			wantError: assert.AnError,
+		},
+		{
+			name:      "HeartbeatChallenge",
+			challenge: ChallengeTypeHeartbeat,
+			wantError: assert.AnError,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			n := newChallengeNTA(nil)
+
+			challenge, err := n.issueChallenge(context.Background(), address, tc.challenge)
+			require.NoError(t, err)
+
+			request := nta.PostNodePreferencesRequest{
+				NodeAddress: address,
+				Preferences: preferences,
+				Nonce:       challenge.Nonce,
+				Signature:   signTypedData(t, key, preferencesTypedData(address, &preferences, challenge)),
+			}
+
+			if tc.tamper != nil {
+				tc.tamper(&request)
+			}
+
+			err = n.verifyPreferences(context.Background(), &request)
+			if tc.replay {
+				require.NoError(t, err)
+
+				err = n.verifyPreferences(context.Background(), &request)
+			}
+
+			switch tc.wantError {
+			case nil:
+				require.NoError(t, err)
+			case assert.AnError:
+				require.Error(t, err)
+			default:
+				require.ErrorIs(t, err, tc.wantError)
+			}
+		})
+	}
+}
+
+func TestValidatePreferences(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().Unix()
+
+	testCases := []struct {
+		name        string
+		preferences nta.NodePreferences
+		wantError   bool
+	}{
+		{
+			name: "Valid",
+			preferences: nta.NodePreferences{
+				AlertWebhooks:      []string{"https://alerts.example.com/1", "https://alerts.example.com/2"},
+				MaintenanceWindows: []*schema.NodeMaintenanceWindow{{StartAt: now + 60, Duration: 3600}},
+			},
+		},
+		{
+			name: "TooManyWebhooks",
+			preferences: nta.NodePreferences{
+				AlertWebhooks: []string{"https://alerts.example.com/1", "https://alerts.example.com/2", "https://alerts.example.com/3"},
+			},
+			wantError: true,
+		},
+		{
+			name: "TooLongWindow",
+			preferences: nta.NodePreferences{
+				MaintenanceWindows: []*schema.NodeMaintenanceWindow{{StartAt: now, Duration: 7 * 3600}},
+			},
+			wantError: true,
+		},
+		{
+			name: "EndedWindow",
+			preferences: nta.NodePreferences{
+				MaintenanceWindows: []*schema.NodeMaintenanceWindow{{StartAt: now - 7200, Duration: 3600}},
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			n := &NTA{
+				configFile: &config.File{
+					NodePreferences: &config.NodePreferences{
+						MaxAlertWebhooks:       2,
+						MaxMaintenanceWindows:  2,
+						MaxMaintenanceDuration: 6 * time.Hour,
+					},
+				},
+			}
+
+			err := n.validatePreferences(&tc.preferences)
+			if tc.wantError {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRecordMaintenanceWindows(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	var (
+		expired   = &schema.NodeMaintenanceWindow{StartAt: now.Add(-31 * 24 * time.Hour).Unix(), Duration: 20 * 3600}
+		ended     = &schema.NodeMaintenanceWindow{StartAt: now.Add(-5 * 24 * time.Hour).Unix(), Duration: 20 * 3600}
+		ongoing   = &schema.NodeMaintenanceWindow{StartAt: now.Add(-time.Hour).Unix(), Duration: 20 * 3600}
+		scheduled = &schema.NodeMaintenanceWindow{StartAt: now.Add(time.Hour).Unix(), Duration: 20 * 3600}
+		window    = &schema.NodeMaintenanceWindow{StartAt: now.Add(24 * time.Hour).Unix(), Duration: 6 * 3600}
+	)
+
+	testCases := []struct {
+		name        string
+		previous    *schema.NodePreferences
+		windows     []*schema.NodeMaintenanceWindow
+		wantHistory []*schema.NodeMaintenanceWindow
+		wantError   bool
+	}{
+		{
+			name:        "NoPreferences",
+			windows:     []*schema.NodeMaintenanceWindow{window},
+			wantHistory: []*schema.NodeMaintenanceWindow{window},
+		},
+		{
+			name:        "ExpiredWindow",
+			previous:    &schema.NodePreferences{MaintenanceHistory: []*schema.NodeMaintenanceWindow{expired}},
+			windows:     []*schema.NodeMaintenanceWindow{window},
+			wantHistory: []*schema.NodeMaintenanceWindow{window},
+		},
+		{
+			name:      "EndedWindow",
+			previous:  &schema.NodePreferences{MaintenanceHistory: []*schema.NodeMaintenanceWindow{ended}},
+			windows:   []*schema.NodeMaintenanceWindow{window},
+			wantError: true,
+		},
+		{
+			name: "ScheduledWindow",
+			previous: &schema.NodePreferences{
+				MaintenanceWindows: []*schema.NodeMaintenanceWindow{scheduled},
+				MaintenanceHistory: []*schema.NodeMaintenanceWindow{scheduled},
+			},
+			windows:     []*schema.NodeMaintenanceWindow{scheduled},
+			wantHistory: []*schema.NodeMaintenanceWindow{scheduled},
+		},
+		{
+			name: "CanceledBeforeStart",
+			previous: &schema.NodePreferences{
+				MaintenanceWindows: []*schema.NodeMaintenanceWindow{scheduled},
+				MaintenanceHistory: []*schema.NodeMaintenanceWindow{scheduled},
+			},
+			windows:     []*schema.NodeMaintenanceWindow{window},
+			wantHistory: []*schema.NodeMaintenanceWindow{window},
+		},
+		{
+			name: "CanceledAfterStart",
+			previous: &schema.NodePreferences{
+				MaintenanceWindows: []*schema.NodeMaintenanceWindow{ongoing},
+				MaintenanceHistory: []*schema.NodeMaintenanceWindow{ongoing},
+			},
+			windows:   []*schema.NodeMaintenanceWindow{window},
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			n := &NTA{
+				configFile: &config.File{
+					NodePreferences: &config.NodePreferences{
+						MaxMaintenanceTime: 24 * time.Hour,
+						MaintenancePeriod:  30 * 24 * time.Hour,
+					},
+				},
+			}
+
+			preferences := &schema.NodePreferences{MaintenanceWindows: tc.windows}
+
+			err := n.recordMaintenanceWindows(tc.previous, preferences, now)
+			if tc.wantError {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantHistory, preferences.MaintenanceHistory)
+		})
+	}
+}
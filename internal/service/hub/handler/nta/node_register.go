@@ -20,6 +20,7 @@ import (
 	"github.com/redis/go-redis/v9"
 	"github.com/rss3-network/global-indexer/common/ethereum"
 	stakingv2 "github.com/rss3-network/global-indexer/contract/l2/staking/v2"
+	"github.com/rss3-network/global-indexer/internal/database"
 	"github.com/rss3-network/global-indexer/internal/service/hub/model/errorx"
 	"github.com/rss3-network/global-indexer/internal/service/hub/model/nta"
 	"github.com/rss3-network/global-indexer/schema"
@@ -55,7 +56,7 @@ func (n *NTA) RegisterNode(c echo.Context) error {
 	}
 
 	// Validate signature.
-	if err = n.validateSignature(ctx, request.Address, request.Signature); err != nil {
+	if err = n.verifyChallenge(ctx, request.Address, ChallengeTypeRegistration, request.Nonce, request.Signature); err != nil {
 		return errorx.ValidationFailedError(c, fmt.Errorf("validate signature: %w", err))
 	}
 
@@ -80,8 +81,43 @@ func (n *NTA) RegisterNode(c echo.Context) error {
 		return errorx.ValidationFailedError(c, fmt.Errorf("validate endpoint: %w", err))
 	}
 
-	// Register Node.
-	if err = n.register(ctx, &request, ip.String(), nodeInfo); err != nil {
+	// Validate endpoint change.
+	previousNode, err := n.databaseClient.FindNode(ctx, request.Address)
+	if err != nil && !errors.Is(err, database.ErrorRowNotFound) {
+		zap.L().Error("find the node",
+			zap.String("address", request.Address.String()),
+			zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	verified, err := n.verifyEndpointChange(ctx, previousNode, &request)
+	if err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validate endpoint change: %w", err))
+	}
+
+	// Register Node, along with its endpoint history.
+	if err = n.databaseClient.WithTransaction(ctx, func(ctx context.Context, client database.Client) error {
+		// Lock the row of the Node, so the concurrent registrations are checked against the limit of endpoint changes one after another.
+		lockedNode, err := client.FindNodeForUpdate(ctx, request.Address)
+		if err != nil && !errors.Is(err, database.ErrorRowNotFound) {
+			return fmt.Errorf("find the node: %w", err)
+		}
+
+		if err := n.register(ctx, client, &request, ip.String(), nodeInfo); err != nil {
+			return err
+		}
+
+		if err := n.saveEndpointHistory(ctx, client, lockedNode, &request, verified); err != nil {
+			return fmt.Errorf("save endpoint history: %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		if errors.Is(err, ErrEndpointChangeLimited) || errors.Is(err, ErrEndpointChangeConflict) {
+			return errorx.ValidationFailedError(c, fmt.Errorf("validate endpoint change: %w", err))
+		}
+
 		zap.L().Error("register failed",
 			zap.String("address", request.Address.String()),
 			zap.Error(err))
@@ -117,7 +153,7 @@ func (n *NTA) NodeHeartbeat(c echo.Context) error {
 	}
 
 	// Validate signature.
-	if err = n.validateSignature(ctx, request.Address, request.Signature); err != nil {
+	if err = n.verifyChallenge(ctx, request.Address, ChallengeTypeHeartbeat, request.Nonce, request.Signature); err != nil {
 		return errorx.ValidationFailedError(c, fmt.Errorf("check signature: %w", err))
 	}
 
@@ -152,13 +188,6 @@ func (n *NTA) NodeHeartbeat(c echo.Context) error {
 	})
 }
 
-// validateSignature validates the signature.
-func (n *NTA) validateSignature(ctx context.Context, address common.Address, signature string) error {
-	message := fmt.Sprintf(registrationMessage, strings.ToLower(address.String()))
-
-	return n.checkSignature(ctx, address, message, signature)
-}
-
 // validateEndpoint validates the endpoint whether it's valid and available.
 func (n *NTA) validateEndpoint(ctx context.Context, address common.Address, nodeType, nodeVersion, endpoint string) error {
 	if nodeType == schema.NodeTypeAlpha.String() {
@@ -180,9 +209,9 @@ func (n *NTA) validateEndpoint(ctx context.Context, address common.Address, node
 }
 
 // register registers the Node to the database.
-func (n *NTA) register(ctx context.Context, request *nta.RegisterNodeRequest, requestIP string, nodeInfo stakingv2.Node) error {
+func (n *NTA) register(ctx context.Context, client database.Client, request *nta.RegisterNodeRequest, requestIP string, nodeInfo stakingv2.Node) error {
 	// Find node from the database.
-	node, err := n.databaseClient.FindNode(ctx, request.Address)
+	node, err := client.FindNode(ctx, request.Address)
 	if err != nil {
 		node = &schema.Node{
 			Address: request.Address,
@@ -221,12 +250,12 @@ func (n *NTA) register(ctx context.Context, request *nta.RegisterNodeRequest, re
 	}
 
 	// Save Node to database.
-	if err = n.databaseClient.SaveNode(ctx, node); err != nil {
+	if err = client.SaveNode(ctx, node); err != nil {
 		return fmt.Errorf("save Node: %s, %w", node.Address.String(), err)
 	}
 
 	if node.Type != schema.NodeTypeAlpha.String() {
-		if err = n.updateNodeStats(ctx, node, nodeInfo); err != nil {
+		if err = n.updateNodeStats(ctx, client, node, nodeInfo); err != nil {
 			return err
 		}
 	}
@@ -235,18 +264,18 @@ func (n *NTA) register(ctx context.Context, request *nta.RegisterNodeRequest, re
 }
 
 // updateNodeStats updates node stats on nodes registered during the non-alpha phase.
-func (n *NTA) updateNodeStats(ctx context.Context, node *schema.Node, nodeInfo stakingv2.Node) error {
-	stat, err := n.updateNodeStat(ctx, node, nodeInfo)
+func (n *NTA) updateNodeStats(ctx context.Context, client database.Client, node *schema.Node, nodeInfo stakingv2.Node) error {
+	stat, err := n.updateNodeStat(ctx, client, node, nodeInfo)
 	if err != nil {
 		return fmt.Errorf("update Node stat: %w", err)
 	}
 
-	return n.databaseClient.SaveNodeStat(ctx, stat)
+	return client.SaveNodeStat(ctx, stat)
 }
 
 // updateNodeStat updates the Node stat.
-func (n *NTA) updateNodeStat(ctx context.Context, node *schema.Node, nodeInfo stakingv2.Node) (*schema.Stat, error) {
-	stat, err := n.databaseClient.FindNodeStat(ctx, node.Address)
+func (n *NTA) updateNodeStat(ctx context.Context, client database.Client, node *schema.Node, nodeInfo stakingv2.Node) (*schema.Stat, error) {
+	stat, err := client.FindNodeStat(ctx, node.Address)
 	if err != nil {
 		return nil, fmt.Errorf("find Node stat: %w", err)
 	}
@@ -304,15 +333,23 @@ func (n *NTA) saveHeartbeat(ctx context.Context, node *schema.Node, requestIP st
 	return n.databaseClient.SaveNode(ctx, node)
 }
 
-// checkSignature checks the signature.
+// checkSignature checks the signature of the message in the EIP-191 form.
 func (n *NTA) checkSignature(_ context.Context, address common.Address, message string, param string) error {
+	data := fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(message), message)
+
+	return checkHashSignature(address, crypto.Keccak256Hash([]byte(data)).Bytes(), param)
+}
+
+// checkHashSignature checks the signature of the hash.
+func checkHashSignature(address common.Address, hash []byte, param string) error {
 	signature, err := hexutil.Decode(param)
 	if err != nil {
 		return fmt.Errorf("failed to decode signature: %w", err)
 	}
 
-	data := fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(message), message)
-	hash := crypto.Keccak256Hash([]byte(data)).Bytes()
+	if len(signature) != crypto.SignatureLength {
+		return fmt.Errorf("invalid signature length %d", len(signature))
+	}
 
 	if signature[crypto.RecoveryIDOffset] == 27 || signature[crypto.RecoveryIDOffset] == 28 {
 		signature[crypto.RecoveryIDOffset] -= 27
@@ -14,6 +14,7 @@ import (
 	"github.com/rss3-network/global-indexer/internal/cache"
 	"github.com/rss3-network/global-indexer/internal/config"
 	"github.com/rss3-network/global-indexer/internal/database"
+	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/breaker"
 )
 
 type NTA struct {
@@ -26,6 +27,7 @@ type NTA struct {
 	httpClient              httputil.Client
 	erc20TokenMap           map[common.Address]*bindings.GovernanceToken
 	configFile              *config.File
+	circuitBreaker          *breaker.CircuitBreaker
 	chainL1ID               uint64
 	chainL2ID               uint64
 }
@@ -50,6 +52,7 @@ func NewNTA(_ context.Context, configFile *config.File, databaseClient database.
 		httpClient:              httpClient,
 		erc20TokenMap:           erc20TokenMap,
 		configFile:              configFile,
+		circuitBreaker:          breaker.New(cacheClient, configFile.Distributor.CircuitBreaker),
 		chainL1ID:               chainL1ID,
 		chainL2ID:               chainL2ID,
 	}
@@ -0,0 +1,46 @@
+package nta
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rss3-network/global-indexer/internal/service/hub/model/errorx"
+	"github.com/rss3-network/global-indexer/internal/service/hub/model/nta"
+	"github.com/rss3-network/global-indexer/schema"
+	"go.uber.org/zap"
+)
+
+// GetSettlementHealth returns the numbers of the failed settlement transactions waiting for an operator to resolve them,
+// it responds with 503 Service Unavailable if there is any.
+func (n *NTA) GetSettlementHealth(c echo.Context) error {
+	failures, err := n.databaseClient.FindSettlementFailures(c.Request().Context(), schema.SettlementFailuresQuery{
+		Statuses: []schema.SettlementFailureStatus{schema.SettlementFailureStatusFailed, schema.SettlementFailureStatusRetrying},
+	})
+	if err != nil {
+		zap.L().Error("find unresolved settlement failures", zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	data := nta.GetSettlementHealthResponseData{
+		Healthy: len(failures) == 0,
+	}
+
+	for _, failure := range failures {
+		switch failure.Status {
+		case schema.SettlementFailureStatusFailed:
+			data.Failed++
+		case schema.SettlementFailureStatusRetrying:
+			data.Retrying++
+		}
+	}
+
+	statusCode := http.StatusOK
+	if !data.Healthy {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	return c.JSON(statusCode, nta.Response{
+		Data: data,
+	})
+}
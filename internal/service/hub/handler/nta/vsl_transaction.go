@@ -0,0 +1,75 @@
+package nta
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/creasty/defaults"
+	"github.com/labstack/echo/v4"
+	"github.com/rss3-network/global-indexer/internal/service/hub/model/errorx"
+	"github.com/rss3-network/global-indexer/internal/service/hub/model/nta"
+	"github.com/rss3-network/global-indexer/schema"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+)
+
+// GetVSLTransactions returns the transactions sent to the VSL by the services of the hub, along with their submissions.
+func (n *NTA) GetVSLTransactions(c echo.Context) error {
+	var request nta.GetVSLTransactionsRequest
+	if err := c.Bind(&request); err != nil {
+		return c.NoContent(http.StatusBadRequest)
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, err)
+	}
+
+	if err := defaults.Set(&request); err != nil {
+		return errorx.InternalError(c)
+	}
+
+	query := schema.VSLTransactionsQuery{
+		Cursor: request.Cursor,
+		Limit:  lo.ToPtr(request.Limit),
+	}
+
+	if request.Status != nil {
+		query.Statuses = []schema.VSLTransactionStatus{*request.Status}
+	}
+
+	transactions, err := n.databaseClient.FindVSLTransactions(c.Request().Context(), query)
+	if err != nil {
+		zap.L().Error("find vsl transactions", zap.Error(err), zap.Any("request", request))
+
+		return errorx.InternalError(c)
+	}
+
+	if len(transactions) > 0 {
+		submissions, err := n.databaseClient.FindVSLTransactionSubmissions(c.Request().Context(), lo.Map(transactions, func(transaction *schema.VSLTransaction, _ int) uint64 {
+			return transaction.ID
+		}))
+		if err != nil {
+			zap.L().Error("find vsl transaction submissions", zap.Error(err), zap.Any("request", request))
+
+			return errorx.InternalError(c)
+		}
+
+		submissionsByTransaction := lo.GroupBy(submissions, func(submission *schema.VSLTransactionSubmission) uint64 {
+			return submission.TransactionID
+		})
+
+		for _, transaction := range transactions {
+			transaction.Submissions = submissionsByTransaction[transaction.ID]
+		}
+	}
+
+	response := nta.Response{
+		Data: transactions,
+	}
+
+	if length := len(transactions); length > 0 && length == request.Limit {
+		response.Cursor = strconv.FormatUint(transactions[length-1].ID, 10)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
@@ -21,8 +21,10 @@ import (
 	"github.com/rss3-network/global-indexer/internal/config/flag"
 	"github.com/rss3-network/global-indexer/internal/database"
 	"github.com/rss3-network/global-indexer/internal/nameresolver"
+	"github.com/rss3-network/global-indexer/internal/service/hub/handler/admin"
 	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl"
 	"github.com/rss3-network/global-indexer/internal/service/hub/handler/nta"
+	"github.com/rss3-network/global-indexer/internal/service/settler"
 	"github.com/samber/lo"
 	"github.com/spf13/viper"
 )
@@ -30,6 +32,8 @@ import (
 type Hub struct {
 	dsl *dsl.DSL
 	nta *nta.NTA
+	// admin is nil if the admin API is not enabled.
+	admin *admin.Admin
 }
 
 var _ echo.Validator = (*Validator)(nil)
@@ -71,7 +75,7 @@ func NewHub(ctx context.Context, databaseClient database.Client, redisClient *re
 
 	cacheClient := cache.New(redisClient)
 
-	dslService, err := dsl.NewDSL(ctx, databaseClient, cacheClient, nameService, stakingV2MulticallClient, networkParamsContract, httpClient, txManager, config.Settler, new(big.Int).SetUint64(chainL2ID))
+	dslService, err := dsl.NewDSL(ctx, databaseClient, cacheClient, nameService, stakingV2MulticallClient, networkParamsContract, httpClient, txManager, config.Settler, config.Distributor, new(big.Int).SetUint64(chainL2ID))
 	if err != nil {
 		return nil, fmt.Errorf("new dsl: %w", err)
 	}
@@ -93,8 +97,19 @@ func NewHub(ctx context.Context, databaseClient database.Client, redisClient *re
 		l2.ContractMap[chainL2ID].AddressPowerToken:           lo.Must(bindings.NewGovernanceToken(l2.ContractMap[chainL2ID].AddressPowerToken, ethereumClient)),
 	}
 
-	return &Hub{
+	hub := &Hub{
 		dsl: dslService,
 		nta: nta.NewNTA(ctx, config, databaseClient, stakingV2MulticallClient, networkParamsContract, contractGovernanceToken, geoLite2, cacheClient, httpClient, erc20TokenMap, chainL1ID, chainL2ID),
-	}, nil
+	}
+
+	if config.Admin != nil {
+		settlementPreviewer, err := settler.NewPreviewer(databaseClient, ethereumMultiChainClient, config, httpClient)
+		if err != nil {
+			return nil, fmt.Errorf("new settlement previewer: %w", err)
+		}
+
+		hub.admin = admin.NewAdmin(databaseClient, settlementPreviewer)
+	}
+
+	return hub, nil
 }
@@ -0,0 +1,5 @@
+package admin
+
+type Response struct {
+	Data any `json:"data"`
+}
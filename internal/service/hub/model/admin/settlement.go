@@ -0,0 +1,15 @@
+package admin
+
+import "github.com/rss3-network/global-indexer/schema"
+
+type SettlementPreviewRequest struct {
+	// Epoch is a pointer, so that epoch 0 is told apart from a missing epoch.
+	Epoch *uint64 `query:"epoch" validate:"required"`
+}
+
+type SettlementFailuresRequest struct {
+	Service *string `query:"service" validate:"omitempty,oneof=settler enforcer"`
+	// Status filters the failures by status, all failures are returned if it is not set.
+	Status []schema.SettlementFailureStatus `query:"status" validate:"dive,oneof=failed retrying resolved skipped aborted"`
+	Limit  int                              `query:"limit" validate:"min=1,max=100" default:"20"`
+}
@@ -0,0 +1,17 @@
+package dsl
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rss3-network/global-indexer/internal/nameresolver"
+)
+
+// PrimaryNamesRequest represents the request for the primary names of an address.
+type PrimaryNamesRequest struct {
+	Address     common.Address `param:"address" validate:"required"`
+	NameService []string       `query:"name_service"`
+}
+
+// PrimaryNamesResponse represents the primary names of an address in the name services.
+type PrimaryNamesResponse struct {
+	Data []*nameresolver.PrimaryName `json:"data"`
+}
@@ -1,6 +1,9 @@
 package nta
 
-import "github.com/ethereum/go-ethereum/common"
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
 
 type NodeChallengeRequest struct {
 	NodeAddress common.Address `param:"node_address" validate:"required"`
@@ -8,3 +11,12 @@ type NodeChallengeRequest struct {
 }
 
 type NodeChallengeResponseData string
+
+// NodeNonceChallengeResponseData is a challenge used once, the Node signs either the Message in the EIP-191 form,
+// or the TypedData in the EIP-712 form, and sends the signature along with the Nonce.
+type NodeNonceChallengeResponseData struct {
+	Nonce     string             `json:"nonce"`
+	Message   string             `json:"message"`
+	TypedData apitypes.TypedData `json:"typed_data"`
+	ExpiresAt int64              `json:"expires_at"`
+}
@@ -0,0 +1,20 @@
+package nta
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rss3-network/global-indexer/schema"
+)
+
+type NodeEndpointHistoryRequest struct {
+	NodeAddress common.Address `param:"node_address" validate:"required"`
+	Cursor      *uint64        `query:"cursor"`
+	Limit       int            `query:"limit" validate:"min=1,max=100" default:"20"`
+}
+
+type NodeEndpointHistoryResponseData []*schema.NodeEndpointHistory
+
+// NodeEndpointOwnershipResponse is served by the endpoint of a Node to prove its ownership,
+// the signature is of the ownership message of the token in the EIP-191 form.
+type NodeEndpointOwnershipResponse struct {
+	Signature string `json:"signature"`
+}
@@ -0,0 +1,15 @@
+package nta
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// PostNodeMaintenanceRequest schedules a maintenance window of a Node, the signature is of the window in the EIP-712 form,
+// along with the nonce of a maintenance challenge.
+type PostNodeMaintenanceRequest struct {
+	NodeAddress common.Address `param:"node_address" validate:"required"`
+	StartAt     int64          `json:"start_at" validate:"required"`
+	Duration    int64          `json:"duration" validate:"required,gt=0"`
+	Nonce       string         `json:"nonce" validate:"required"`
+	Signature   string         `json:"signature" validate:"required"`
+}
@@ -0,0 +1,44 @@
+package nta
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rss3-network/global-indexer/schema"
+)
+
+type GetNodePreferencesRequest struct {
+	NodeAddress common.Address `param:"node_address" validate:"required"`
+}
+
+// PostNodePreferencesRequest replaces the preferences of a Node, the signature is of the preferences in the EIP-712 form,
+// along with the nonce of a preferences challenge.
+type PostNodePreferencesRequest struct {
+	NodeAddress common.Address  `param:"node_address" validate:"required"`
+	Preferences NodePreferences `json:"preferences"`
+	Nonce       string          `json:"nonce" validate:"required"`
+	Signature   string          `json:"signature" validate:"required"`
+}
+
+type NodePreferences struct {
+	HideTaxRate        bool                            `json:"hide_tax_rate"`
+	AlertWebhooks      []string                        `json:"alert_webhooks,omitempty" validate:"dive,url"`
+	OptOutRSS          bool                            `json:"opt_out_rss"`
+	OptOutAI           bool                            `json:"opt_out_ai"`
+	MaintenanceWindows []*schema.NodeMaintenanceWindow `json:"maintenance_windows,omitempty" validate:"dive,required"`
+}
+
+// NodePreferencesResponseData are the preferences of a Node, the alert webhooks are not served.
+type NodePreferencesResponseData NodePreferences
+
+func NewNodePreferences(node *schema.Node) NodePreferencesResponseData {
+	data := NodePreferencesResponseData{
+		HideTaxRate: node.HideTaxRate,
+	}
+
+	if node.Preferences != nil {
+		data.OptOutRSS = node.Preferences.OptOutRSS
+		data.OptOutAI = node.Preferences.OptOutAI
+		data.MaintenanceWindows = node.Preferences.MaintenanceWindows
+	}
+
+	return data
+}
@@ -9,6 +9,7 @@ import (
 type RegisterNodeRequest struct {
 	Address     common.Address  `json:"address" validate:"required"`
 	Signature   string          `json:"signature" validate:"required"`
+	Nonce       string          `json:"nonce"`
 	Endpoint    string          `json:"endpoint" validate:"required"`
 	Stream      json.RawMessage `json:"stream,omitempty"`
 	Config      json.RawMessage `json:"config,omitempty"`
@@ -20,6 +21,7 @@ type RegisterNodeRequest struct {
 type NodeHeartbeatRequest struct {
 	Address   common.Address `json:"address" validate:"required"`
 	Signature string         `json:"signature" validate:"required"`
+	Nonce     string         `json:"nonce"`
 	Endpoint  string         `json:"endpoint" validate:"required"`
 	Timestamp int64          `json:"timestamp" validate:"required"`
 }
@@ -0,0 +1,10 @@
+package nta
+
+type GetSettlementHealthResponseData struct {
+	// Healthy is false if a failed settlement transaction is waiting for an operator to resolve it.
+	Healthy bool `json:"healthy"`
+	// Failed and Retrying are the numbers of the unresolved settlement transactions by status,
+	// the details of them are served by the admin API only.
+	Failed   int `json:"failed"`
+	Retrying int `json:"retrying"`
+}
@@ -0,0 +1,9 @@
+package nta
+
+import "github.com/rss3-network/global-indexer/schema"
+
+type GetVSLTransactionsRequest struct {
+	Cursor *uint64                      `query:"cursor"`
+	Status *schema.VSLTransactionStatus `query:"status" validate:"omitempty,oneof=pending confirmed failed aborted dropped"`
+	Limit  int                          `query:"limit" default:"50" min:"1" max:"100"`
+}
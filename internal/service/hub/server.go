@@ -2,6 +2,7 @@ package hub
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
 	"net"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 	"github.com/labstack/echo-contrib/echoprometheus"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/redis/go-redis/v9"
 	"github.com/rss3-network/global-indexer/common/geolite2"
 	"github.com/rss3-network/global-indexer/common/httputil"
@@ -19,6 +21,7 @@ import (
 	"github.com/rss3-network/global-indexer/internal/database"
 	"github.com/rss3-network/global-indexer/internal/nameresolver"
 	"github.com/rss3-network/global-indexer/internal/service"
+	"github.com/rss3-network/global-indexer/internal/service/settler/failure"
 	"go.uber.org/zap"
 )
 
@@ -59,6 +62,14 @@ func NewServer(databaseClient database.Client, redisClient *redis.Client, geoLit
 		// setup prometheus metrics
 		instance.httpServer.Use(echoprometheus.NewMiddleware(Name))
 		instance.httpServer.GET("/metrics", echoprometheus.NewHandler())
+
+		// export the settlement failures recorded by the settler and the scheduler, which do not serve metrics themselves
+		prometheus.MustRegister(failure.NewCollector(databaseClient))
+
+		// export the depth of the send queue shared with the settler and the scheduler
+		if sendQueue := txManager.SendQueue(); sendQueue != nil {
+			prometheus.MustRegister(txmgr.NewQueueCollector(sendQueue))
+		}
 	}
 
 	instance.httpServer.HideBanner = true
@@ -121,10 +132,16 @@ func NewServer(databaseClient database.Client, redisClient *redis.Client, geoLit
 			nodes.GET("/:node_address", instance.hub.nta.GetNode)
 			nodes.GET("/:node_address/avatar.svg", instance.hub.nta.GetNodeAvatar)
 			nodes.GET("/:node_address/challenge", instance.hub.nta.GetNodeChallenge)
+			nodes.POST("/:node_address/challenge", instance.hub.nta.PostNodeChallenge)
 			nodes.GET("/:node_address/events", instance.hub.nta.GetNodeEvents)
+			nodes.GET("/:node_address/endpoints", instance.hub.nta.GetNodeEndpointHistory)
 			nodes.GET("/:node_address/operation/profit", instance.hub.nta.GetNodeOperationProfit)
 
+			nodes.GET("/:node_address/preferences", instance.hub.nta.GetNodePreferences)
+
 			nodes.POST("/:node_address/hide_tax_rate", instance.hub.nta.PostNodeHideTaxRate)
+			nodes.POST("/:node_address/preferences", instance.hub.nta.PostNodePreferences)
+			nodes.POST("/:node_address/maintenance", instance.hub.nta.PostNodeMaintenance)
 		}
 
 		snapshots := nta.Group("/snapshots")
@@ -157,6 +174,26 @@ func NewServer(databaseClient database.Client, redisClient *redis.Client, geoLit
 		{
 			dsl.GET("/total_requests", instance.hub.nta.GetDslTotalRequests)
 		}
+
+		settlements := nta.Group("/settlements")
+		{
+			settlements.GET("/health", instance.hub.nta.GetSettlementHealth)
+		}
+
+		vsl := nta.Group("/vsl")
+		{
+			vsl.GET("/transactions", instance.hub.nta.GetVSLTransactions)
+		}
+	}
+
+	if instance.hub.admin != nil {
+		admin := instance.httpServer.Group("/admin", middleware.KeyAuth(func(key string, _ echo.Context) (bool, error) {
+			return subtle.ConstantTimeCompare([]byte(key), []byte(config.Admin.AccessToken)) == 1, nil
+		}))
+		{
+			admin.GET("/settlements/preview", instance.hub.admin.GetSettlementPreview)
+			admin.GET("/settlements/failures", instance.hub.admin.GetSettlementFailures)
+		}
 	}
 
 	dsl := instance.httpServer.Group("")
@@ -188,6 +225,11 @@ func NewServer(databaseClient database.Client, redisClient *redis.Client, geoLit
 			federated.GET("/platform/:platform", instance.hub.dsl.GetFederatedPlatformActivities)
 			federated.POST("/accounts", instance.hub.dsl.BatchGetFederatedAccountsActivities)
 		}
+
+		names := dsl.Group("/names")
+		{
+			names.GET("/:address", instance.hub.dsl.GetPrimaryNames)
+		}
 	}
 
 	return &instance, nil
@@ -0,0 +1,70 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rss3-network/global-indexer/internal/cache"
+	"github.com/rss3-network/global-indexer/internal/client/ethereum"
+	"github.com/rss3-network/global-indexer/internal/config"
+	"github.com/rss3-network/global-indexer/internal/config/flag"
+	"github.com/rss3-network/global-indexer/internal/database"
+	"github.com/rss3-network/global-indexer/internal/service/indexer/internal"
+	"github.com/rss3-network/global-indexer/internal/service/indexer/internal/handler/l1"
+	"github.com/rss3-network/global-indexer/internal/service/indexer/internal/handler/l2"
+	"github.com/spf13/viper"
+)
+
+const (
+	ChainL1 = "l1"
+	ChainL2 = "l2"
+)
+
+// Backfill indexes the blocks from `from` to `to` of the L1 or L2 chain again, replacing the rows indexed from them,
+// without disturbing the running indexers or their checkpoints.
+func Backfill(ctx context.Context, databaseClient database.Client, redisClient *redis.Client, ethereumMultiChainClient *ethereum.MultiChainClient, config *config.File, chain string, from, to uint64) error {
+	s := Server{
+		databaseClient:           databaseClient,
+		cacheClient:              cache.New(redisClient),
+		ethereumMultiChainClient: ethereumMultiChainClient,
+		rss3ChainConfig:          config.RSS3Chain,
+	}
+
+	return s.backfill(ctx, chain, from, to)
+}
+
+func (s *Server) backfill(ctx context.Context, chain string, from, to uint64) error {
+	var (
+		chainID      uint64
+		blockThreads uint64
+	)
+
+	switch chain {
+	case ChainL1:
+		chainID, blockThreads = viper.GetUint64(flag.KeyChainIDL1), s.rss3ChainConfig.BlockThreadsL1
+	case ChainL2:
+		chainID, blockThreads = viper.GetUint64(flag.KeyChainIDL2), s.rss3ChainConfig.BlockThreadsL2
+	default:
+		return fmt.Errorf("unsupported chain %s", chain)
+	}
+
+	ethereumClient, err := s.ethereumMultiChainClient.Get(chainID)
+	if err != nil {
+		return fmt.Errorf("load ethereum client: %w", err)
+	}
+
+	var handler internal.Handler
+
+	if chain == ChainL1 {
+		handler, err = l1.NewHandler(chainID, ethereumClient, true)
+	} else {
+		handler, err = l2.NewHandler(chainID, ethereumClient, &internal.BackfillCacheClient{Client: s.cacheClient}, true)
+	}
+
+	if err != nil {
+		return fmt.Errorf("new %s handler: %w", chain, err)
+	}
+
+	return internal.Backfill(ctx, chainID, ethereumClient, s.databaseClient, handler, from, to, blockThreads)
+}
@@ -0,0 +1,198 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/rss3-network/global-indexer/internal/cache"
+	"github.com/rss3-network/global-indexer/internal/database"
+	"github.com/rss3-network/global-indexer/schema"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+)
+
+// Backfill processes the blocks from `from` to `to` again with a finalized Handler, each block in its own database transaction,
+// so the rows indexed from the blocks, finalized or not, are replaced after a fix of the Handler.
+// The checkpoint is neither moved nor saved, and only the blocks below it can be backfilled, the others are left to the running indexers.
+func Backfill(ctx context.Context, chainID uint64, ethereumClient *ethclient.Client, databaseClient database.Client, handler Handler, from, to, blockThreads uint64) error {
+	if from > to {
+		return fmt.Errorf("invalid block range from %d to %d", from, to)
+	}
+
+	checkpoint, err := databaseClient.FindCheckpoint(ctx, chainID)
+	if err != nil {
+		return fmt.Errorf("load checkpoint: %w", err)
+	}
+
+	if to > checkpoint.BlockNumber {
+		return fmt.Errorf("block %d has not been indexed yet, the checkpoint is at block %d", to, checkpoint.BlockNumber)
+	}
+
+	// Cancel the prefetching of the remaining blocks once the processing stops.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	instance := indexer{
+		ethereumClient: ethereumClient,
+	}
+
+	for result := range prefetchBlocks(ctx, from, to, blockThreads, instance.fetchBlock) {
+		if result.err != nil {
+			return result.err
+		}
+
+		if err := backfillBlock(ctx, databaseClient, handler, result); err != nil {
+			return err
+		}
+
+		zap.L().Info(
+			"backfilled block",
+			zap.Uint64("chain.id", chainID),
+			zap.Uint64("block.number", result.block.NumberU64()),
+			zap.Uint64("block.number.to", to),
+		)
+	}
+
+	return ctx.Err()
+}
+
+// backfillBlock deletes the rows indexed from a block, the finalized ones included, and processes the block again in the same database transaction.
+func backfillBlock(ctx context.Context, databaseClient database.Client, handler Handler, result *prefetchedBlock) error {
+	databaseTransaction, err := databaseClient.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin database transaction: %w", err)
+	}
+
+	defer lo.Try(databaseTransaction.Rollback)
+
+	transaction := &backfillTransaction{Client: databaseTransaction}
+
+	if err := handler.Rollback(ctx, result.block.NumberU64(), transaction); err != nil {
+		return fmt.Errorf("delete block %d: %w", result.block.NumberU64(), err)
+	}
+
+	if err := handler.Process(ctx, result.block, result.receipts, transaction); err != nil {
+		return fmt.Errorf("process block %d: %w", result.block.NumberU64(), err)
+	}
+
+	if err := databaseTransaction.Commit(); err != nil {
+		return fmt.Errorf("commit database transaction: %w", err)
+	}
+
+	return nil
+}
+
+var _ database.Client = (*backfillTransaction)(nil)
+
+// backfillTransaction is the database transaction of a backfilled block.
+// It leaves alone the current state of the Nodes and the chips, as replaying an old block would roll it back,
+// and the finalization of the rows, which is done by the finalized indexer.
+// The rows of the block are deleted whether finalized or not, except the chips, which are replaced by ID to keep their owners.
+type backfillTransaction struct {
+	database.Client
+}
+
+func (t *backfillTransaction) DeleteBridgeTransactionsByBlockNumber(ctx context.Context, chainID, blockNumber uint64) error {
+	return t.Client.PurgeBridgeTransactionsByBlockNumber(ctx, chainID, blockNumber)
+}
+
+func (t *backfillTransaction) DeleteBridgeEventsByBlockNumber(ctx context.Context, chainID, blockNumber uint64) error {
+	return t.Client.PurgeBridgeEventsByBlockNumber(ctx, chainID, blockNumber)
+}
+
+func (t *backfillTransaction) DeleteStakeTransactionsByBlockNumber(ctx context.Context, blockNumber uint64) error {
+	return t.Client.PurgeStakeTransactionsByBlockNumber(ctx, blockNumber)
+}
+
+func (t *backfillTransaction) DeleteStakeEventsByBlockNumber(ctx context.Context, blockNumber uint64) error {
+	return t.Client.PurgeStakeEventsByBlockNumber(ctx, blockNumber)
+}
+
+func (t *backfillTransaction) DeleteStakeChipsByBlockNumber(_ context.Context, _ uint64) error {
+	return nil
+}
+
+func (t *backfillTransaction) DeleteNodeEventsByBlockNumber(ctx context.Context, blockNumber uint64) error {
+	return t.Client.PurgeNodeEventsByBlockNumber(ctx, blockNumber)
+}
+
+func (t *backfillTransaction) DeleteEpochsByBlockNumber(ctx context.Context, blockNumber uint64) error {
+	return t.Client.PurgeEpochsByBlockNumber(ctx, blockNumber)
+}
+
+func (t *backfillTransaction) SaveNode(_ context.Context, _ *schema.Node) error {
+	return nil
+}
+
+func (t *backfillTransaction) BatchUpdateNodes(_ context.Context, _ []*schema.BatchUpdateNode) error {
+	return nil
+}
+
+func (t *backfillTransaction) SaveNodeStat(_ context.Context, _ *schema.Stat) error {
+	return nil
+}
+
+func (t *backfillTransaction) UpdateStakeChipsOwner(_ context.Context, _ common.Address, _ ...*big.Int) error {
+	return nil
+}
+
+// SaveStakeChips replaces the chips but keeps the owners of the existing ones, which may have been transferred since the block.
+func (t *backfillTransaction) SaveStakeChips(ctx context.Context, stakeChips ...*schema.StakeChip) error {
+	for _, stakeChip := range stakeChips {
+		existing, err := t.Client.FindStakeChip(ctx, schema.StakeChipQuery{ID: stakeChip.ID})
+		if err != nil {
+			if errors.Is(err, database.ErrorRowNotFound) {
+				continue
+			}
+
+			return fmt.Errorf("find stake chip %s: %w", stakeChip.ID, err)
+		}
+
+		stakeChip.Owner = existing.Owner
+	}
+
+	return t.Client.SaveStakeChips(ctx, stakeChips...)
+}
+
+func (t *backfillTransaction) UpdateBridgeTransactionsFinalizedByBlockNumber(_ context.Context, _, _ uint64) error {
+	return nil
+}
+
+func (t *backfillTransaction) UpdateBridgeEventsFinalizedByBlockNumber(_ context.Context, _, _ uint64) error {
+	return nil
+}
+
+func (t *backfillTransaction) UpdateStakeTransactionsFinalizedByBlockNumber(_ context.Context, _ uint64) error {
+	return nil
+}
+
+func (t *backfillTransaction) UpdateStakeEventsFinalizedByBlockNumber(_ context.Context, _ uint64) error {
+	return nil
+}
+
+func (t *backfillTransaction) UpdateStakeChipsFinalizedByBlockNumber(_ context.Context, _ uint64) error {
+	return nil
+}
+
+func (t *backfillTransaction) UpdateNodeEventsFinalizedByBlockNumber(_ context.Context, _ uint64) error {
+	return nil
+}
+
+func (t *backfillTransaction) UpdateEpochsFinalizedByBlockNumber(_ context.Context, _ uint64) error {
+	return nil
+}
+
+var _ cache.Client = (*BackfillCacheClient)(nil)
+
+// BackfillCacheClient is the cache client of a backfilling Handler, it keeps the Nodes in the cache, as the running indexers do.
+type BackfillCacheClient struct {
+	cache.Client
+}
+
+func (c *BackfillCacheClient) ZRem(_ context.Context, _ string, _ ...interface{}) error {
+	return nil
+}
@@ -0,0 +1,194 @@
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rss3-network/global-indexer/internal/database"
+	"github.com/rss3-network/global-indexer/schema"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// backfillDatabaseClient is a database client holding a checkpoint, some chips and some stake events.
+type backfillDatabaseClient struct {
+	database.Client
+
+	checkpoint  *schema.Checkpoint
+	stakeChips  map[string]*schema.StakeChip
+	saved       []*schema.StakeChip
+	stakeEvents []*schema.StakeEvent
+	committed   bool
+}
+
+func (c *backfillDatabaseClient) Begin(_ context.Context, _ ...*sql.TxOptions) (database.Client, error) {
+	return c, nil
+}
+
+func (c *backfillDatabaseClient) Commit() error {
+	c.committed = true
+
+	return nil
+}
+
+func (c *backfillDatabaseClient) Rollback() error {
+	return nil
+}
+
+func (c *backfillDatabaseClient) SaveStakeEvent(_ context.Context, stakeEvent *schema.StakeEvent) error {
+	c.stakeEvents = append(c.stakeEvents, stakeEvent)
+
+	return nil
+}
+
+func (c *backfillDatabaseClient) DeleteStakeEventsByBlockNumber(_ context.Context, blockNumber uint64) error {
+	c.stakeEvents = lo.Reject(c.stakeEvents, func(stakeEvent *schema.StakeEvent, _ int) bool {
+		return stakeEvent.BlockNumber.Uint64() == blockNumber && !stakeEvent.Finalized
+	})
+
+	return nil
+}
+
+func (c *backfillDatabaseClient) PurgeStakeEventsByBlockNumber(_ context.Context, blockNumber uint64) error {
+	c.stakeEvents = lo.Reject(c.stakeEvents, func(stakeEvent *schema.StakeEvent, _ int) bool {
+		return stakeEvent.BlockNumber.Uint64() == blockNumber
+	})
+
+	return nil
+}
+
+func (c *backfillDatabaseClient) FindCheckpoint(_ context.Context, _ uint64) (*schema.Checkpoint, error) {
+	return c.checkpoint, nil
+}
+
+func (c *backfillDatabaseClient) FindStakeChip(_ context.Context, query schema.StakeChipQuery) (*schema.StakeChip, error) {
+	stakeChip, exists := c.stakeChips[query.ID.String()]
+	if !exists {
+		return nil, database.ErrorRowNotFound
+	}
+
+	return stakeChip, nil
+}
+
+func (c *backfillDatabaseClient) SaveStakeChips(_ context.Context, stakeChips ...*schema.StakeChip) error {
+	c.saved = append(c.saved, stakeChips...)
+
+	return nil
+}
+
+// backfillHandler is a Handler indexing a stake event per block.
+type backfillHandler struct {
+	eventType schema.StakeEventType
+}
+
+func (h *backfillHandler) Process(ctx context.Context, block *types.Block, _ types.Receipts, databaseTransaction database.Client) error {
+	return databaseTransaction.SaveStakeEvent(ctx, &schema.StakeEvent{
+		Type:        h.eventType,
+		BlockNumber: block.Number(),
+		Finalized:   true,
+	})
+}
+
+func (h *backfillHandler) Rollback(ctx context.Context, blockNumber uint64, databaseTransaction database.Client) error {
+	return databaseTransaction.DeleteStakeEventsByBlockNumber(ctx, blockNumber)
+}
+
+func TestBackfill(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+		from uint64
+		to   uint64
+	}{
+		{
+			name: "InvalidRange",
+			from: 10,
+			to:   5,
+		},
+		{
+			name: "BeyondCheckpoint",
+			from: 90,
+			to:   101,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			databaseClient := &backfillDatabaseClient{
+				checkpoint: &schema.Checkpoint{BlockNumber: 100},
+			}
+
+			err := Backfill(context.Background(), 1, nil, databaseClient, nil, tc.from, tc.to, 1)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestBackfillTransactionSaveStakeChips(t *testing.T) {
+	t.Parallel()
+
+	var (
+		staker = common.HexToAddress("0x0000000000000000000000000000000000000010")
+		owner  = common.HexToAddress("0x0000000000000000000000000000000000000020")
+	)
+
+	databaseClient := &backfillDatabaseClient{
+		stakeChips: map[string]*schema.StakeChip{
+			"1": {ID: big.NewInt(1), Owner: owner},
+		},
+	}
+
+	transaction := &backfillTransaction{Client: databaseClient}
+
+	err := transaction.SaveStakeChips(context.Background(),
+		&schema.StakeChip{ID: big.NewInt(1), Owner: staker},
+		&schema.StakeChip{ID: big.NewInt(2), Owner: staker},
+	)
+	require.NoError(t, err)
+
+	require.Len(t, databaseClient.saved, 2)
+
+	// The owner of the transferred chip is kept, and the new chip is owned by the staker.
+	assert.Equal(t, owner, databaseClient.saved[0].Owner)
+	assert.Equal(t, staker, databaseClient.saved[1].Owner)
+
+	// The current state is left alone.
+	require.NoError(t, transaction.SaveNode(context.Background(), &schema.Node{}))
+	require.NoError(t, transaction.UpdateStakeChipsOwner(context.Background(), owner, big.NewInt(2)))
+}
+
+func TestBackfillBlock(t *testing.T) {
+	t.Parallel()
+
+	databaseClient := &backfillDatabaseClient{
+		stakeEvents: []*schema.StakeEvent{
+			{Type: schema.StakeEventTypeStakeStaked, BlockNumber: big.NewInt(10), Finalized: true},
+			{Type: schema.StakeEventTypeStakeStaked, BlockNumber: big.NewInt(11), Finalized: true},
+		},
+	}
+
+	handler := &backfillHandler{eventType: schema.StakeEventTypeUnstakeRequested}
+
+	result := &prefetchedBlock{
+		block: types.NewBlockWithHeader(&types.Header{Number: big.NewInt(10)}),
+	}
+
+	require.NoError(t, backfillBlock(context.Background(), databaseClient, handler, result))
+	require.True(t, databaseClient.committed)
+
+	// The finalized event of the block is replaced, and the event of the other block is left alone.
+	require.Len(t, databaseClient.stakeEvents, 2)
+	assert.Equal(t, uint64(11), databaseClient.stakeEvents[0].BlockNumber.Uint64())
+	assert.Equal(t, schema.StakeEventTypeUnstakeRequested, databaseClient.stakeEvents[1].Type)
+	assert.Equal(t, uint64(10), databaseClient.stakeEvents[1].BlockNumber.Uint64())
+}
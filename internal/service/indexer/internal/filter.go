@@ -0,0 +1,216 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/samber/lo"
+	"github.com/sourcegraph/conc/pool"
+	"go.uber.org/zap"
+)
+
+// LogFilter is implemented by the Handlers that only process the logs of some contracts,
+// so they can be fed with the logs queried by eth_getLogs instead of every block and receipt.
+type LogFilter interface {
+	// LogFilterQuery returns the addresses and topics of the logs processed by the Handler,
+	// including the logs looked up along with them in the same receipt.
+	LogFilterQuery() ethereum.FilterQuery
+}
+
+// blockRange is the adaptive size of the block ranges the logs are queried over.
+// It is halved when a query fails, as RPC endpoints reject the ranges holding too many logs,
+// and doubled after a successful query, up to its maximum.
+type blockRange struct {
+	size    uint64
+	maxSize uint64
+}
+
+func newBlockRange(maxSize uint64) *blockRange {
+	maxSize = max(maxSize, 1)
+
+	return &blockRange{
+		size:    maxSize,
+		maxSize: maxSize,
+	}
+}
+
+// end returns the last block of the range starting at `from`, which is never beyond `latest`.
+func (r *blockRange) end(from, latest uint64) uint64 {
+	return min(from+r.size-1, latest)
+}
+
+// shrink halves the range, it returns false if the range is a single block and cannot be shrunk.
+func (r *blockRange) shrink() bool {
+	if r.size <= 1 {
+		return false
+	}
+
+	r.size /= 2
+
+	return true
+}
+
+// grow doubles the range, up to its maximum.
+func (r *blockRange) grow() {
+	r.size = min(r.size*2, r.maxSize)
+}
+
+// indexLogs indexes the blocks up to the latest one by querying the logs of the Handler.
+func (i *indexer) indexLogs(ctx context.Context) error {
+	for from := i.checkpoint.BlockNumber + 1; from <= i.blockNumberLatest; {
+		if reorganized, err := i.verifyLatestBlock(ctx); err != nil || reorganized {
+			return err
+		}
+
+		to := i.logBlockRange.end(from, i.blockNumberLatest)
+
+		logs, err := i.ethereumClient.FilterLogs(ctx, i.filterQuery(from, to))
+		if err != nil {
+			if ctx.Err() == nil && i.logBlockRange.shrink() {
+				zap.L().Warn(
+					"shrink the block range of filtering logs",
+					zap.Error(err),
+					zap.Uint64("block.number.from", from),
+					zap.Uint64("block.number.to", to),
+					zap.Uint64("block.range", i.logBlockRange.size),
+				)
+
+				continue
+			}
+
+			return fmt.Errorf("filter logs from block %d to %d: %w", from, to, err)
+		}
+
+		i.logBlockRange.grow()
+
+		zap.L().Info(
+			"handing block range",
+			zap.Uint64("block.number.from", from),
+			zap.Uint64("block.number.to", to),
+			zap.Uint64("block.number.latest", i.blockNumberLatest),
+			zap.Int("logs", len(logs)),
+			zap.Bool("finalized", i.finalized),
+		)
+
+		blocks, err := i.fetchLogBlocks(ctx, from, to, logs)
+		if err != nil {
+			return err
+		}
+
+		if err := i.processBlocks(ctx, blocks); err != nil {
+			return err
+		}
+
+		from = to + 1
+	}
+
+	return ctx.Err()
+}
+
+// filterQuery returns the query of the Handler's logs in a block range.
+func (i *indexer) filterQuery(from, to uint64) ethereum.FilterQuery {
+	query := i.logFilter.LogFilterQuery()
+
+	query.FromBlock = new(big.Int).SetUint64(from)
+	query.ToBlock = new(big.Int).SetUint64(to)
+
+	return query
+}
+
+// fetchLogBlocks returns the blocks from `from` to `to` to be processed, along with the receipts rebuilt from their logs.
+// Only the blocks with logs and the last block, whose hash is saved in the checkpoint, are fetched.
+// The other blocks hold nothing but their number, as the Handler needs nothing else to process a block without receipts.
+func (i *indexer) fetchLogBlocks(ctx context.Context, from, to uint64, logs []types.Log) ([]*prefetchedBlock, error) {
+	logsByBlock := lo.GroupBy(logs, func(log types.Log) uint64 {
+		return log.BlockNumber
+	})
+
+	blocks := make([]*prefetchedBlock, 0, to-from+1)
+
+	for blockNumber := from; blockNumber <= to; blockNumber++ {
+		blocks = append(blocks, &prefetchedBlock{
+			block:    types.NewBlockWithHeader(&types.Header{Number: new(big.Int).SetUint64(blockNumber)}),
+			receipts: logReceipts(logsByBlock[blockNumber]),
+		})
+	}
+
+	errorPool := pool.New().WithContext(ctx).WithMaxGoroutines(int(max(i.blockThreads, 1))).WithCancelOnError().WithFirstError()
+
+	for index, result := range blocks {
+		blockNumber := from + uint64(index)
+		blockLogs, matched := logsByBlock[blockNumber]
+
+		if !matched && blockNumber != to {
+			continue
+		}
+
+		result := result
+
+		errorPool.Go(func(ctx context.Context) error {
+			if !matched {
+				header, err := i.ethereumClient.HeaderByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+				if err != nil {
+					return fmt.Errorf("get header by number %d: %w", blockNumber, err)
+				}
+
+				result.block = types.NewBlockWithHeader(header)
+
+				return nil
+			}
+
+			block, err := i.ethereumClient.BlockByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+			if err != nil {
+				return fmt.Errorf("get block by number %d: %w", blockNumber, err)
+			}
+
+			// The block has been reorganized since the logs were queried.
+			if block.Hash() != blockLogs[0].BlockHash {
+				return fmt.Errorf("block %d hash mismatch: %s != %s", blockNumber, block.Hash(), blockLogs[0].BlockHash)
+			}
+
+			result.block = block
+
+			return nil
+		})
+	}
+
+	if err := errorPool.Wait(); err != nil {
+		return nil, err
+	}
+
+	return blocks, nil
+}
+
+// logReceipts rebuilds the receipts of the transactions emitting the logs of a block.
+// They are the receipts of a full block less the logs not matching the filter, and the transactions without such logs.
+// Failed transactions do not emit logs, so all the receipts are successful.
+func logReceipts(logs []types.Log) types.Receipts {
+	sort.SliceStable(logs, func(i, j int) bool {
+		return logs[i].Index < logs[j].Index
+	})
+
+	receipts := make(types.Receipts, 0)
+
+	for index := range logs {
+		log := &logs[index]
+
+		if len(receipts) == 0 || receipts[len(receipts)-1].TxHash != log.TxHash {
+			receipts = append(receipts, &types.Receipt{
+				Status:           types.ReceiptStatusSuccessful,
+				TxHash:           log.TxHash,
+				BlockHash:        log.BlockHash,
+				BlockNumber:      new(big.Int).SetUint64(log.BlockNumber),
+				TransactionIndex: log.TxIndex,
+			})
+		}
+
+		receipt := receipts[len(receipts)-1]
+		receipt.Logs = append(receipt.Logs, log)
+	}
+
+	return receipts
+}
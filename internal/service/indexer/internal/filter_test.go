@@ -0,0 +1,99 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockRange(t *testing.T) {
+	t.Parallel()
+
+	blockRange := newBlockRange(8)
+
+	assert.Equal(t, uint64(107), blockRange.end(100, 1000))
+	assert.Equal(t, uint64(103), blockRange.end(100, 103))
+
+	// Shrink the range down to a single block.
+	for _, size := range []uint64{4, 2, 1} {
+		require.True(t, blockRange.shrink())
+		assert.Equal(t, size, blockRange.size)
+	}
+
+	assert.False(t, blockRange.shrink())
+	assert.Equal(t, uint64(100), blockRange.end(100, 1000))
+
+	// Grow the range back up to its maximum.
+	for _, size := range []uint64{2, 4, 8, 8} {
+		blockRange.grow()
+		assert.Equal(t, size, blockRange.size)
+	}
+
+	assert.Equal(t, uint64(1), newBlockRange(0).size)
+}
+
+func TestLogReceipts(t *testing.T) {
+	t.Parallel()
+
+	var (
+		transaction0 = common.HexToHash("0x01")
+		transaction1 = common.HexToHash("0x02")
+	)
+
+	logs := []types.Log{
+		{BlockNumber: 10, TxHash: transaction1, TxIndex: 5, Index: 7},
+		{BlockNumber: 10, TxHash: transaction0, TxIndex: 2, Index: 3},
+		{BlockNumber: 10, TxHash: transaction1, TxIndex: 5, Index: 9},
+		{BlockNumber: 10, TxHash: transaction0, TxIndex: 2, Index: 1},
+	}
+
+	receipts := logReceipts(logs)
+	require.Len(t, receipts, 2)
+
+	testCases := []struct {
+		name             string
+		receipt          *types.Receipt
+		transactionHash  common.Hash
+		transactionIndex uint
+		logIndexes       []uint
+	}{
+		{
+			name:             "FirstTransaction",
+			receipt:          receipts[0],
+			transactionHash:  transaction0,
+			transactionIndex: 2,
+			logIndexes:       []uint{1, 3},
+		},
+		{
+			name:             "SecondTransaction",
+			receipt:          receipts[1],
+			transactionHash:  transaction1,
+			transactionIndex: 5,
+			logIndexes:       []uint{7, 9},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.transactionHash, tc.receipt.TxHash)
+			assert.Equal(t, tc.transactionIndex, tc.receipt.TransactionIndex)
+			assert.Equal(t, types.ReceiptStatusSuccessful, tc.receipt.Status)
+
+			logIndexes := make([]uint, 0, len(tc.receipt.Logs))
+			for _, log := range tc.receipt.Logs {
+				logIndexes = append(logIndexes, log.Index)
+			}
+
+			assert.Equal(t, tc.logIndexes, logIndexes)
+		})
+	}
+
+	assert.Empty(t, logReceipts(nil))
+}
@@ -6,6 +6,8 @@ import (
 	"sync"
 
 	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/rss3-network/global-indexer/contract/l1"
@@ -15,7 +17,10 @@ import (
 	"go.uber.org/zap"
 )
 
-var _ internal.Handler = (*handler)(nil)
+var (
+	_ internal.Handler   = (*handler)(nil)
+	_ internal.LogFilter = (*handler)(nil)
+)
 
 type handler struct {
 	chainID                        uint64
@@ -75,6 +80,29 @@ func (h *handler) Process(ctx context.Context, block *types.Block, receipts type
 	return nil
 }
 
+func (h *handler) Rollback(ctx context.Context, blockNumber uint64, databaseTransaction database.Client) error {
+	return h.deleteUnfinalizedBlock(ctx, blockNumber, databaseTransaction)
+}
+
+func (h *handler) LogFilterQuery() ethereum.FilterQuery {
+	return ethereum.FilterQuery{
+		Addresses: []common.Address{
+			l1.ContractMap[h.chainID].AddressL1StandardBridgeProxy,
+			l1.ContractMap[h.chainID].AddressL1CrossDomainMessengerProxy,
+			l1.ContractMap[h.chainID].AddressOptimismPortalProxy,
+		},
+		Topics: [][]common.Hash{
+			{
+				l1.EventHashL1StandardBridgeERC20DepositInitiated,
+				l1.EventHashL1StandardBridgeERC20WithdrawalFinalized,
+				l1.EventHashL1CrossDomainMessengerSentMessage,
+				l1.EventHashOptimismPortalWithdrawalProven,
+				l1.EventHashOptimismPortalWithdrawalFinalized,
+			},
+		},
+	}
+}
+
 func (h *handler) deleteUnfinalizedBlock(ctx context.Context, blockNumber uint64, databaseTransaction database.Client) error {
 	if err := databaseTransaction.DeleteBridgeTransactionsByBlockNumber(ctx, h.chainID, blockNumber); err != nil {
 		return fmt.Errorf("delete bridge transactions by block number: %w", err)
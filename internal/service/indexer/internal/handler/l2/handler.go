@@ -7,6 +7,8 @@ import (
 	"sync"
 
 	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/rss3-network/global-indexer/contract/l2"
@@ -19,7 +21,10 @@ import (
 	"go.uber.org/zap"
 )
 
-var _ internal.Handler = (*handler)(nil)
+var (
+	_ internal.Handler   = (*handler)(nil)
+	_ internal.LogFilter = (*handler)(nil)
+)
 
 type handler struct {
 	chainID                        uint64
@@ -50,7 +55,7 @@ func (h *handler) Process(ctx context.Context, block *types.Block, receipts type
 
 	header := block.Header()
 
-	for transactionIndex, receipt := range receipts {
+	for _, receipt := range receipts {
 		// Discard all contract creation transactions.
 		if block.Transaction(receipt.TxHash).To() == nil {
 			continue
@@ -81,7 +86,7 @@ func (h *handler) Process(ctx context.Context, block *types.Block, receipts type
 				transaction := block.Transaction(log.TxHash)
 
 				switch {
-				case l2.IsStakingV2Deployed(big.NewInt(int64(h.chainID)), header.Number, uint(transactionIndex)): // Staking V2
+				case l2.IsStakingV2Deployed(big.NewInt(int64(h.chainID)), header.Number, receipt.TransactionIndex): // Staking V2
 					if err := h.indexStakingV2Log(ctx, header, transaction, receipt, log, databaseTransaction); err != nil {
 						return fmt.Errorf("index staking v2 log: %w", err)
 					}
@@ -101,6 +106,47 @@ func (h *handler) Process(ctx context.Context, block *types.Block, receipts type
 	return nil
 }
 
+func (h *handler) Rollback(ctx context.Context, blockNumber uint64, databaseTransaction database.Client) error {
+	return h.deleteUnfinalizedBlock(ctx, blockNumber, databaseTransaction)
+}
+
+func (h *handler) LogFilterQuery() ethereum.FilterQuery {
+	topics := []common.Hash{
+		l2.EventHashL2StandardBridgeDepositFinalized,
+		l2.EventHashL2StandardBridgeWithdrawalInitiated,
+		l2.EventHashL2CrossDomainMessengerRelayedMessage,
+		l2.EventHashL2ToL1MessagePasserMessagePassed,
+		l2.EventHashStakingV1Deposited,
+		l2.EventHashStakingV1WithdrawRequested,
+		l2.EventHashStakingV1WithdrawalClaimed,
+		l2.EventHashStakingV1Staked,
+		l2.EventHashStakingV1UnstakeRequested,
+		l2.EventHashStakingV1UnstakeClaimed,
+		l2.EventHashStakingV1RewardDistributed,
+		l2.EventHashStakingV1NodeCreated,
+		l2.EventHashStakingV1NodeUpdated,
+		l2.EventHashStakingV2ChipsMerged,
+		l2.EventHashStakingV2WithdrawalClaimed,
+		l2.EventHashNodeStatusChanged,
+	}
+
+	// The transfers of chips are only indexed once finalized.
+	if h.finalized {
+		topics = append(topics, l2.EventHashChipsTransfer)
+	}
+
+	return ethereum.FilterQuery{
+		Addresses: []common.Address{
+			l2.AddressL2StandardBridgeProxy,
+			l2.AddressL2CrossDomainMessengerProxy,
+			l2.AddressL2ToL1MessagePasser,
+			l2.ContractMap[h.chainID].AddressStakingProxy,
+			l2.ContractMap[h.chainID].AddressChipsProxy,
+		},
+		Topics: [][]common.Hash{topics},
+	}
+}
+
 func (h *handler) deleteUnfinalizedBlock(ctx context.Context, blockNumber uint64, databaseTransaction database.Client) error {
 	if err := databaseTransaction.DeleteBridgeTransactionsByBlockNumber(ctx, h.chainID, blockNumber); err != nil {
 		return fmt.Errorf("delete bridge transactions by block number: %w", err)
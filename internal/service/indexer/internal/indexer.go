@@ -11,6 +11,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/rss3-network/global-indexer/internal/client/ethereum"
 	"github.com/rss3-network/global-indexer/internal/database"
 	"github.com/rss3-network/global-indexer/schema"
 	"github.com/samber/lo"
@@ -22,6 +23,8 @@ import (
 // Handler uses to process blocks and receipts.
 type Handler interface {
 	Process(ctx context.Context, block *types.Block, receipts types.Receipts, databaseTransaction database.Client) error
+	// Rollback deletes the unfinalized data indexed from an orphaned block.
+	Rollback(ctx context.Context, blockNumber uint64, databaseTransaction database.Client) error
 }
 
 // Indexer uses to index blockchain data, it will process blocks and receipts using it Handler.
@@ -30,11 +33,18 @@ type Indexer interface {
 }
 
 type indexer struct {
-	ethereumClient    *ethclient.Client
-	databaseClient    database.Client
-	handler           Handler
-	chainID           uint64
-	finalized         bool
+	ethereumClient *ethclient.Client
+	databaseClient database.Client
+	handler        Handler
+	chainID        uint64
+	finalized      bool
+	// blockThreads is the number of blocks fetched in parallel ahead of the processing.
+	blockThreads uint64
+	// logFilter is set in log mode, where only the logs of the Handler are queried instead of every block and receipt.
+	logFilter     LogFilter
+	logBlockRange *blockRange
+	// blockWindow keeps the recent block hashes of the unfinalized indexer to detect reorgs.
+	blockWindow       *blockWindow
 	checkpoint        *schema.Checkpoint
 	blockNumberLatest uint64
 }
@@ -45,6 +55,12 @@ func (i *indexer) Run(ctx context.Context) (err error) {
 		return fmt.Errorf("load checkpoint: %w", err)
 	}
 
+	if i.blockWindow != nil {
+		if err := i.loadBlockWindow(ctx); err != nil {
+			return fmt.Errorf("load block window: %w", err)
+		}
+	}
+
 	retryableFunc := func() error {
 		for {
 			if err := i.index(ctx); err != nil {
@@ -108,25 +124,45 @@ func (i *indexer) index(ctx context.Context) (err error) {
 		return nil
 	}
 
-	blockNumber := i.checkpoint.BlockNumber + 1
+	if i.logFilter != nil {
+		return i.indexLogs(ctx)
+	}
 
-	zap.L().Info(
-		"handing block",
-		zap.Uint64("block.number.local", i.checkpoint.BlockNumber),
-		zap.Uint64("block.number.latest", i.blockNumberLatest),
-		zap.Bool("finalized", i.finalized),
-	)
+	// Cancel the prefetching of the remaining blocks once the processing stops.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-	block, err := i.ethereumClient.BlockByNumber(ctx, new(big.Int).SetUint64(blockNumber))
-	if err != nil {
-		return fmt.Errorf("get block by number %d: %w", blockNumber, err)
-	}
+	for result := range prefetchBlocks(ctx, i.checkpoint.BlockNumber+1, i.blockNumberLatest, i.blockThreads, i.fetchBlock) {
+		if result.err != nil {
+			return result.err
+		}
 
-	receipts, err := i.ethereumClient.BlockReceipts(ctx, rpc.BlockNumberOrHashWithHash(block.Hash(), false))
-	if err != nil {
-		return err
+		if reorganized, err := i.detectReorg(ctx, result.block.NumberU64(), result.block.ParentHash()); err != nil || reorganized {
+			return err
+		}
+
+		zap.L().Info(
+			"handing block",
+			zap.Uint64("block.number.local", i.checkpoint.BlockNumber),
+			zap.Uint64("block.number.latest", i.blockNumberLatest),
+			zap.Bool("finalized", i.finalized),
+		)
+
+		if err := i.processBlock(ctx, result.block, result.receipts); err != nil {
+			return err
+		}
 	}
 
+	return ctx.Err()
+}
+
+// processBlock processes a block and its receipts, and saves the checkpoint in the same database transaction.
+func (i *indexer) processBlock(ctx context.Context, block *types.Block, receipts types.Receipts) error {
+	return i.processBlocks(ctx, []*prefetchedBlock{{block: block, receipts: receipts}})
+}
+
+// processBlocks processes consecutive blocks and their receipts, and saves the checkpoint of the last block in the same database transaction.
+func (i *indexer) processBlocks(ctx context.Context, blocks []*prefetchedBlock) error {
 	// Begin a database transaction for the block.
 	databaseTransaction, err := i.databaseClient.Begin(ctx)
 	if err != nil {
@@ -135,11 +171,15 @@ func (i *indexer) index(ctx context.Context) (err error) {
 
 	defer lo.Try(databaseTransaction.Rollback)
 
-	if err := i.handler.Process(ctx, block, receipts, databaseTransaction); err != nil {
-		return fmt.Errorf("process block %d: %w", block.NumberU64(), err)
+	for _, result := range blocks {
+		if err := i.handler.Process(ctx, result.block, result.receipts, databaseTransaction); err != nil {
+			return fmt.Errorf("process block %d: %w", result.block.NumberU64(), err)
+		}
 	}
 
 	// Update and save checkpoint to memory and database.
+	block := blocks[len(blocks)-1].block
+
 	i.checkpoint.BlockHash = block.Hash()
 	i.checkpoint.BlockNumber = block.NumberU64()
 
@@ -147,12 +187,20 @@ func (i *indexer) index(ctx context.Context) (err error) {
 		if err := databaseTransaction.SaveCheckpoint(ctx, i.checkpoint); err != nil {
 			return fmt.Errorf("save checkpoint: %w", err)
 		}
+	} else {
+		if err := databaseTransaction.SaveUnfinalizedCheckpoint(ctx, i.checkpoint, blockWindowSize); err != nil {
+			return fmt.Errorf("save unfinalized checkpoint: %w", err)
+		}
 	}
 
-	if databaseTransaction.Commit() != nil {
+	if err := databaseTransaction.Commit(); err != nil {
 		return fmt.Errorf("commit database transaction: %w", err)
 	}
 
+	if i.blockWindow != nil {
+		i.blockWindow.push(block.NumberU64(), block.Hash())
+	}
+
 	return nil
 }
 
@@ -161,7 +209,7 @@ func (i *indexer) refreshLatestBlockNumber(ctx context.Context) (err error) {
 	defer span.End()
 
 	if i.finalized {
-		block, err := i.ethereumClient.BlockByNumber(ctx, big.NewInt(rpc.FinalizedBlockNumber.Int64()))
+		block, err := i.ethereumClient.BlockByNumber(ethereum.WithQuorum(ctx), big.NewInt(rpc.FinalizedBlockNumber.Int64()))
 		if err != nil {
 			return fmt.Errorf("get finalized block number: %w", err)
 		}
@@ -178,13 +226,30 @@ func (i *indexer) refreshLatestBlockNumber(ctx context.Context) (err error) {
 	return nil
 }
 
-func NewIndexer(chainID uint64, ethereumClient *ethclient.Client, databaseClient database.Client, handler Handler, finalized bool) (Indexer, error) {
+// NewIndexer creates an Indexer, which queries the logs of the Handler over block ranges of up to logBlockRange blocks,
+// or fetches every block and receipt if logBlockRange is zero.
+func NewIndexer(chainID uint64, ethereumClient *ethclient.Client, databaseClient database.Client, handler Handler, finalized bool, blockThreads, logBlockRange uint64) (Indexer, error) {
 	instance := indexer{
 		ethereumClient: ethereumClient,
 		databaseClient: databaseClient,
 		handler:        handler,
 		chainID:        chainID,
 		finalized:      finalized,
+		blockThreads:   blockThreads,
+	}
+
+	if !finalized {
+		instance.blockWindow = newBlockWindow(blockWindowSize)
+	}
+
+	if logBlockRange > 0 {
+		logFilter, ok := handler.(LogFilter)
+		if !ok {
+			return nil, fmt.Errorf("handler %T does not support log filtering", handler)
+		}
+
+		instance.logFilter = logFilter
+		instance.logBlockRange = newBlockRange(logBlockRange)
 	}
 
 	return &instance, nil
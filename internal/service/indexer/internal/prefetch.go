@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// prefetchedBlock is a block fetched ahead of processing, along with its receipts.
+type prefetchedBlock struct {
+	block    *types.Block
+	receipts types.Receipts
+	err      error
+}
+
+// fetchBlockFunc fetches a block and its receipts by number.
+type fetchBlockFunc func(ctx context.Context, blockNumber uint64) (*types.Block, types.Receipts, error)
+
+// prefetchBlocks fetches the blocks from `from` to `to` in parallel and returns them in order.
+// At most `threads` blocks are fetched ahead of the one being consumed,
+// so the fetching is held back when the blocks are processed slower than they are fetched.
+// The channel is closed after the last block, or after the first block that fails to be fetched.
+func prefetchBlocks(ctx context.Context, from, to, threads uint64, fetch fetchBlockFunc) <-chan *prefetchedBlock {
+	// Each block is delivered through its own future, the futures are queued in order of block number.
+	futures := make(chan chan *prefetchedBlock, max(threads, 1)-1)
+	blocks := make(chan *prefetchedBlock)
+
+	go func() {
+		defer close(futures)
+
+		for blockNumber := from; blockNumber <= to; blockNumber++ {
+			future := make(chan *prefetchedBlock, 1)
+
+			select {
+			case <-ctx.Done():
+				return
+			case futures <- future:
+			}
+
+			go func(blockNumber uint64) {
+				block, receipts, err := fetch(ctx, blockNumber)
+
+				future <- &prefetchedBlock{block: block, receipts: receipts, err: err}
+			}(blockNumber)
+		}
+	}()
+
+	go func() {
+		defer close(blocks)
+
+		for future := range futures {
+			var result *prefetchedBlock
+
+			select {
+			case <-ctx.Done():
+				return
+			case result = <-future:
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case blocks <- result:
+			}
+
+			if result.err != nil {
+				return
+			}
+		}
+	}()
+
+	return blocks
+}
+
+// fetchBlock fetches a block and its receipts from the chain.
+func (i *indexer) fetchBlock(ctx context.Context, blockNumber uint64) (*types.Block, types.Receipts, error) {
+	block, err := i.ethereumClient.BlockByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return nil, nil, fmt.Errorf("get block by number %d: %w", blockNumber, err)
+	}
+
+	receipts, err := i.ethereumClient.BlockReceipts(ctx, rpc.BlockNumberOrHashWithHash(block.Hash(), false))
+	if err != nil {
+		return nil, nil, fmt.Errorf("get receipts of block %d: %w", blockNumber, err)
+	}
+
+	return block, receipts, nil
+}
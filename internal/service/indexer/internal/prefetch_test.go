@@ -0,0 +1,111 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefetchBlocks(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		from     uint64
+		to       uint64
+		threads  uint64
+		failAt   uint64
+		expected []uint64
+		wantErr  bool
+	}{
+		{
+			name:     "SingleThread",
+			from:     1,
+			to:       5,
+			threads:  1,
+			expected: []uint64{1, 2, 3, 4, 5},
+		},
+		{
+			name:     "MultipleThreads",
+			from:     10,
+			to:       40,
+			threads:  8,
+			expected: []uint64{10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34, 35, 36, 37, 38, 39, 40},
+		},
+		{
+			name:     "FetchError",
+			from:     1,
+			to:       10,
+			threads:  4,
+			failAt:   4,
+			expected: []uint64{1, 2, 3},
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var inFlight, maxInFlight atomic.Int64
+
+			fetch := func(_ context.Context, blockNumber uint64) (*types.Block, types.Receipts, error) {
+				current := inFlight.Add(1)
+				defer inFlight.Add(-1)
+
+				for {
+					observed := maxInFlight.Load()
+					if current <= observed || maxInFlight.CompareAndSwap(observed, current) {
+						break
+					}
+				}
+
+				// Finish the fetches out of order.
+				time.Sleep(time.Duration(rand.Intn(5)) * time.Millisecond)
+
+				if blockNumber == tc.failAt {
+					return nil, nil, errors.New("fetch failed")
+				}
+
+				return types.NewBlockWithHeader(&types.Header{Number: new(big.Int).SetUint64(blockNumber)}), nil, nil
+			}
+
+			var (
+				blockNumbers []uint64
+				err          error
+			)
+
+			for result := range prefetchBlocks(ctx, tc.from, tc.to, tc.threads, fetch) {
+				if result.err != nil {
+					err = result.err
+
+					break
+				}
+
+				blockNumbers = append(blockNumbers, result.block.NumberU64())
+			}
+
+			if tc.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tc.expected, blockNumbers)
+			assert.LessOrEqual(t, maxInFlight.Load(), int64(tc.threads))
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rss3-network/global-indexer/schema"
+	"github.com/samber/lo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// blockWindowSize is the number of recent block hashes kept by the unfinalized indexer,
+// the reorgs deeper than the window are rolled back to its oldest block.
+const blockWindowSize = 256
+
+var (
+	reorgCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "indexer_reorgs_total",
+			Help: "Total number of chain reorganizations detected by the unfinalized indexer",
+		},
+		[]string{"chain_id"},
+	)
+
+	reorgDepth = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "indexer_reorg_depth_blocks",
+			Help:    "Number of orphaned blocks rolled back by a chain reorganization",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 9),
+		},
+		[]string{"chain_id"},
+	)
+)
+
+// recentBlock is the number and the hash of an indexed block.
+type recentBlock struct {
+	number uint64
+	hash   common.Hash
+}
+
+// blockWindow keeps the hashes of the recent indexed blocks in ascending order of block number.
+type blockWindow struct {
+	blocks []recentBlock
+	size   int
+}
+
+func newBlockWindow(size int) *blockWindow {
+	return &blockWindow{
+		blocks: make([]recentBlock, 0, size),
+		size:   size,
+	}
+}
+
+// push adds an indexed block, replacing the blocks at the same height or above, and evicting the oldest block if the window is full.
+func (w *blockWindow) push(number uint64, hash common.Hash) {
+	w.rewind(number - 1)
+
+	if len(w.blocks) == w.size {
+		w.blocks = w.blocks[1:]
+	}
+
+	w.blocks = append(w.blocks, recentBlock{number: number, hash: hash})
+}
+
+// rewind removes the blocks above a height.
+func (w *blockWindow) rewind(number uint64) {
+	for len(w.blocks) > 0 && w.blocks[len(w.blocks)-1].number > number {
+		w.blocks = w.blocks[:len(w.blocks)-1]
+	}
+}
+
+// last returns the latest indexed block.
+func (w *blockWindow) last() (recentBlock, bool) {
+	if len(w.blocks) == 0 {
+		return recentBlock{}, false
+	}
+
+	return w.blocks[len(w.blocks)-1], true
+}
+
+// isOrphaned returns true if the parent hash of a block does not match the hash of its parent in the window.
+// The blocks whose parent is not in the window cannot be verified, and are never orphaned.
+func (w *blockWindow) isOrphaned(number uint64, parentHash common.Hash) bool {
+	last, exists := w.last()
+
+	return exists && last.number+1 == number && last.hash != parentHash
+}
+
+// loadBlockWindow restores the window from the blocks saved by the unfinalized indexer,
+// and resumes the indexing from the latest one if it is ahead of the finalized checkpoint.
+func (i *indexer) loadBlockWindow(ctx context.Context) error {
+	checkpoints, err := i.databaseClient.FindUnfinalizedCheckpoints(ctx, i.chainID, blockWindowSize)
+	if err != nil {
+		return fmt.Errorf("find unfinalized checkpoints: %w", err)
+	}
+
+	for _, checkpoint := range checkpoints {
+		i.blockWindow.push(checkpoint.BlockNumber, checkpoint.BlockHash)
+	}
+
+	if last, exists := i.blockWindow.last(); exists && last.number > i.checkpoint.BlockNumber {
+		i.checkpoint = &schema.Checkpoint{
+			ChainID:     i.chainID,
+			BlockNumber: last.number,
+			BlockHash:   last.hash,
+		}
+	}
+
+	return nil
+}
+
+// detectReorg checks that the block extends the latest indexed block, and rolls the orphaned blocks back otherwise.
+// It returns true if the blocks have been rolled back, so the indexing must restart from the common ancestor.
+func (i *indexer) detectReorg(ctx context.Context, number uint64, parentHash common.Hash) (bool, error) {
+	if i.blockWindow == nil || !i.blockWindow.isOrphaned(number, parentHash) {
+		return false, nil
+	}
+
+	return true, i.rollback(ctx)
+}
+
+// verifyLatestBlock checks that the latest indexed block is still canonical, and rolls the orphaned blocks back otherwise.
+// It is used in log mode, where the blocks without logs are not fetched and their parent hashes are unknown.
+func (i *indexer) verifyLatestBlock(ctx context.Context) (bool, error) {
+	if i.blockWindow == nil {
+		return false, nil
+	}
+
+	last, exists := i.blockWindow.last()
+	if !exists {
+		return false, nil
+	}
+
+	canonical, err := i.isCanonical(ctx, last)
+	if err != nil || canonical {
+		return false, err
+	}
+
+	return true, i.rollback(ctx)
+}
+
+// rollback walks the window back to the common ancestor of the indexed blocks and the canonical chain,
+// then deletes the data of every orphaned height and rewinds the unfinalized checkpoint to the ancestor.
+// The finalized checkpoint is left untouched, as finalized blocks are never reorganized.
+func (i *indexer) rollback(ctx context.Context) error {
+	ancestor, err := i.findCommonAncestor(ctx)
+	if err != nil {
+		return fmt.Errorf("find common ancestor: %w", err)
+	}
+
+	latest := i.checkpoint.BlockNumber
+
+	databaseTransaction, err := i.databaseClient.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin database transaction: %w", err)
+	}
+
+	defer lo.Try(databaseTransaction.Rollback)
+
+	if err := databaseTransaction.DeleteUnfinalizedCheckpoints(ctx, i.chainID, ancestor.number); err != nil {
+		return fmt.Errorf("delete unfinalized checkpoints: %w", err)
+	}
+
+	for blockNumber := ancestor.number + 1; blockNumber <= latest; blockNumber++ {
+		if err := i.handler.Rollback(ctx, blockNumber, databaseTransaction); err != nil {
+			return fmt.Errorf("rollback block %d data: %w", blockNumber, err)
+		}
+	}
+
+	if err := databaseTransaction.Commit(); err != nil {
+		return fmt.Errorf("commit database transaction: %w", err)
+	}
+
+	depth := latest - ancestor.number
+
+	chainID := strconv.FormatUint(i.chainID, 10)
+	reorgCounter.WithLabelValues(chainID).Inc()
+	reorgDepth.WithLabelValues(chainID).Observe(float64(depth))
+
+	trace.SpanFromContext(ctx).AddEvent("reorg", trace.WithAttributes(
+		attribute.Int64("chain.id", int64(i.chainID)),
+		attribute.Int64("block.number.ancestor", int64(ancestor.number)),
+		attribute.Int64("block.number.latest", int64(latest)),
+	))
+
+	zap.L().Warn(
+		"chain reorganized",
+		zap.Uint64("chain.id", i.chainID),
+		zap.Uint64("block.number.ancestor", ancestor.number),
+		zap.Stringer("block.hash.ancestor", ancestor.hash),
+		zap.Uint64("block.number.latest", latest),
+		zap.Uint64("depth", depth),
+	)
+
+	i.blockWindow.rewind(ancestor.number)
+	i.checkpoint.BlockNumber = ancestor.number
+	i.checkpoint.BlockHash = ancestor.hash
+
+	return nil
+}
+
+// findCommonAncestor returns the latest block of the window which is still canonical.
+// If the reorg is deeper than the window, the block of the finalized checkpoint is returned, as it cannot be reorganized.
+func (i *indexer) findCommonAncestor(ctx context.Context) (recentBlock, error) {
+	for index := len(i.blockWindow.blocks) - 1; index >= 0; index-- {
+		block := i.blockWindow.blocks[index]
+
+		canonical, err := i.isCanonical(ctx, block)
+		if err != nil {
+			return recentBlock{}, err
+		}
+
+		if canonical {
+			return block, nil
+		}
+	}
+
+	checkpoint, err := i.databaseClient.FindCheckpoint(ctx, i.chainID)
+	if err != nil {
+		return recentBlock{}, fmt.Errorf("find finalized checkpoint: %w", err)
+	}
+
+	zap.L().Warn(
+		"chain reorganized deeper than the block window",
+		zap.Uint64("chain.id", i.chainID),
+		zap.Uint64("block.number.oldest", i.blockWindow.blocks[0].number),
+		zap.Uint64("block.number.finalized", checkpoint.BlockNumber),
+	)
+
+	return recentBlock{number: checkpoint.BlockNumber, hash: checkpoint.BlockHash}, nil
+}
+
+// isCanonical returns true if the block is still in the canonical chain.
+func (i *indexer) isCanonical(ctx context.Context, block recentBlock) (bool, error) {
+	header, err := i.ethereumClient.HeaderByNumber(ctx, new(big.Int).SetUint64(block.number))
+	if err != nil {
+		return false, fmt.Errorf("get header by number %d: %w", block.number, err)
+	}
+
+	return header.Hash() == block.hash, nil
+}
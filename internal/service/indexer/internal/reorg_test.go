@@ -0,0 +1,157 @@
+package internal
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rss3-network/global-indexer/internal/database"
+	"github.com/rss3-network/global-indexer/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockWindow(t *testing.T) {
+	t.Parallel()
+
+	hash := func(number uint64) common.Hash {
+		return common.BigToHash(new(big.Int).SetUint64(number))
+	}
+
+	window := newBlockWindow(3)
+
+	_, exists := window.last()
+	require.False(t, exists)
+	assert.False(t, window.isOrphaned(1, hash(0)))
+
+	for number := uint64(1); number <= 4; number++ {
+		window.push(number, hash(number))
+	}
+
+	// The oldest block is evicted once the window is full.
+	require.Len(t, window.blocks, 3)
+	assert.Equal(t, uint64(2), window.blocks[0].number)
+
+	testCases := []struct {
+		name       string
+		number     uint64
+		parentHash common.Hash
+		orphaned   bool
+	}{
+		{
+			name:       "Canonical",
+			number:     5,
+			parentHash: hash(4),
+		},
+		{
+			name:       "Orphaned",
+			number:     5,
+			parentHash: hash(40),
+			orphaned:   true,
+		},
+		{
+			name:       "ParentNotInWindow",
+			number:     7,
+			parentHash: hash(60),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.orphaned, window.isOrphaned(tc.number, tc.parentHash))
+		})
+	}
+
+	// Pushing a block at an indexed height replaces the blocks at the same height or above.
+	replaced := newBlockWindow(3)
+
+	for number := uint64(1); number <= 3; number++ {
+		replaced.push(number, hash(number))
+	}
+
+	replaced.push(2, hash(20))
+
+	last, exists := replaced.last()
+	require.True(t, exists)
+	assert.Equal(t, recentBlock{number: 2, hash: hash(20)}, last)
+	assert.Len(t, replaced.blocks, 2)
+
+	replaced.rewind(1)
+
+	last, _ = replaced.last()
+	assert.Equal(t, uint64(1), last.number)
+}
+
+// unfinalizedDatabaseClient is a database client holding the blocks saved by the unfinalized indexer.
+type unfinalizedDatabaseClient struct {
+	database.Client
+
+	checkpoints []*schema.Checkpoint
+}
+
+func (c *unfinalizedDatabaseClient) FindUnfinalizedCheckpoints(_ context.Context, _ uint64, limit int) ([]*schema.Checkpoint, error) {
+	return c.checkpoints[max(len(c.checkpoints)-limit, 0):], nil
+}
+
+func TestLoadBlockWindow(t *testing.T) {
+	t.Parallel()
+
+	hash := func(number uint64) common.Hash {
+		return common.BigToHash(new(big.Int).SetUint64(number))
+	}
+
+	checkpoints := make([]*schema.Checkpoint, 0, blockWindowSize+10)
+
+	for number := uint64(1001); number <= 1000+blockWindowSize+10; number++ {
+		checkpoints = append(checkpoints, &schema.Checkpoint{ChainID: 1, BlockNumber: number, BlockHash: hash(number)})
+	}
+
+	testCases := []struct {
+		name        string
+		checkpoints []*schema.Checkpoint
+		finalized   uint64
+		expected    schema.Checkpoint
+	}{
+		{
+			name:        "AheadOfFinalized",
+			checkpoints: checkpoints,
+			finalized:   1100,
+			expected:    schema.Checkpoint{ChainID: 1, BlockNumber: 1000 + blockWindowSize + 10, BlockHash: hash(1000 + blockWindowSize + 10)},
+		},
+		{
+			name:        "BehindFinalized",
+			checkpoints: checkpoints,
+			finalized:   2000,
+			expected:    schema.Checkpoint{ChainID: 1, BlockNumber: 2000},
+		},
+		{
+			name:      "Empty",
+			finalized: 1100,
+			expected:  schema.Checkpoint{ChainID: 1, BlockNumber: 1100},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			instance := indexer{
+				databaseClient: &unfinalizedDatabaseClient{checkpoints: tc.checkpoints},
+				chainID:        1,
+				blockWindow:    newBlockWindow(blockWindowSize),
+				checkpoint:     &schema.Checkpoint{ChainID: 1, BlockNumber: tc.finalized},
+			}
+
+			require.NoError(t, instance.loadBlockWindow(context.Background()))
+			assert.Equal(t, tc.expected, *instance.checkpoint)
+			assert.Len(t, instance.blockWindow.blocks, min(len(tc.checkpoints), blockWindowSize))
+		})
+	}
+}
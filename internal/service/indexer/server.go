@@ -8,6 +8,7 @@ import (
 	"github.com/redis/go-redis/v9"
 	"github.com/rss3-network/global-indexer/internal/cache"
 	"github.com/rss3-network/global-indexer/internal/client/ethereum"
+	"github.com/rss3-network/global-indexer/internal/config"
 	"github.com/rss3-network/global-indexer/internal/config/flag"
 	"github.com/rss3-network/global-indexer/internal/database"
 	"github.com/rss3-network/global-indexer/internal/service"
@@ -24,6 +25,7 @@ type Server struct {
 	databaseClient           database.Client
 	cacheClient              cache.Client
 	ethereumMultiChainClient *ethereum.MultiChainClient
+	rss3ChainConfig          *config.RSS3Chain
 }
 
 func (s *Server) Name() string {
@@ -101,7 +103,7 @@ func (s *Server) newL1Indexer(finalized bool) (internal.Indexer, error) {
 		return nil, fmt.Errorf("new l1 handler: %w", err)
 	}
 
-	indexer, err := internal.NewIndexer(chainID, ethereumClient, s.databaseClient, handler, finalized)
+	indexer, err := internal.NewIndexer(chainID, ethereumClient, s.databaseClient, handler, finalized, s.rss3ChainConfig.BlockThreadsL1, s.logBlockRange(s.rss3ChainConfig.LogBlockRangeL1))
 	if err != nil {
 		return nil, fmt.Errorf("new l1 indexer: %w", err)
 	}
@@ -122,7 +124,7 @@ func (s *Server) newL2Indexer(finalized bool) (internal.Indexer, error) {
 		return nil, fmt.Errorf("new l2 handler: %w", err)
 	}
 
-	indexer, err := internal.NewIndexer(chainID, ethereumClient, s.databaseClient, handler, finalized)
+	indexer, err := internal.NewIndexer(chainID, ethereumClient, s.databaseClient, handler, finalized, s.rss3ChainConfig.BlockThreadsL2, s.logBlockRange(s.rss3ChainConfig.LogBlockRangeL2))
 	if err != nil {
 		return nil, fmt.Errorf("new l2 indexer: %w", err)
 	}
@@ -130,11 +132,21 @@ func (s *Server) newL2Indexer(finalized bool) (internal.Indexer, error) {
 	return indexer, nil
 }
 
-func NewServer(databaseClient database.Client, redisClient *redis.Client, ethereumMultiChainClient *ethereum.MultiChainClient) (service.Server, error) {
+// logBlockRange returns the block range of the log mode, which is zero if the indexers fetch every block and receipt.
+func (s *Server) logBlockRange(blockRange uint64) uint64 {
+	if s.rss3ChainConfig.IndexMode != config.IndexModeLog {
+		return 0
+	}
+
+	return blockRange
+}
+
+func NewServer(databaseClient database.Client, redisClient *redis.Client, ethereumMultiChainClient *ethereum.MultiChainClient, config *config.File) (service.Server, error) {
 	instance := Server{
 		databaseClient:           databaseClient,
 		cacheClient:              cache.New(redisClient),
 		ethereumMultiChainClient: ethereumMultiChainClient,
+		rss3ChainConfig:          config.RSS3Chain,
 	}
 
 	return &instance, nil
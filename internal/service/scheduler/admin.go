@@ -0,0 +1,154 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/creasty/defaults"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/rss3-network/global-indexer/internal/cronjob"
+	"github.com/rss3-network/global-indexer/internal/database"
+	adminmodel "github.com/rss3-network/global-indexer/internal/service/hub/model/admin"
+	"github.com/rss3-network/global-indexer/internal/service/hub/model/errorx"
+	"github.com/rss3-network/global-indexer/schema"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+)
+
+var _ echo.Validator = (*Validator)(nil)
+
+var defaultValidator = &Validator{
+	validate: validator.New(),
+}
+
+type Validator struct {
+	validate *validator.Validate
+}
+
+func (v *Validator) Validate(i interface{}) error {
+	return v.validate.Struct(i)
+}
+
+// Job is a job of the scheduler, along with its latest run.
+type Job struct {
+	Name    string `json:"name"`
+	Spec    string `json:"spec"`
+	Timeout string `json:"timeout"`
+	// NextRunAt is the next time the job is run on its spec.
+	NextRunAt *time.Time         `json:"next_run_at,omitempty"`
+	LastRun   *schema.CronJobRun `json:"last_run,omitempty"`
+}
+
+type GetJobRunsRequest struct {
+	Name   string  `param:"name" validate:"required"`
+	Cursor *uint64 `query:"cursor"`
+	Limit  int     `query:"limit" validate:"min=1,max=100" default:"20"`
+}
+
+type TriggerJobRequest struct {
+	Name string `param:"name" validate:"required"`
+}
+
+type admin struct {
+	runner         *cronjob.Runner
+	databaseClient database.Client
+}
+
+// GetJobs returns the jobs run by the scheduler.
+func (a *admin) GetJobs(c echo.Context) error {
+	entries := a.runner.Entries()
+
+	jobs := make([]*Job, 0, len(entries))
+
+	for _, entry := range entries {
+		runs, err := a.databaseClient.FindCronJobRuns(c.Request().Context(), schema.CronJobRunsQuery{
+			Job:   lo.ToPtr(entry.Job.Name()),
+			Limit: lo.ToPtr(1),
+		})
+		if err != nil {
+			zap.L().Error("find cron job runs failed", zap.String("job", entry.Job.Name()), zap.Error(err))
+
+			return errorx.InternalError(c)
+		}
+
+		jobs = append(jobs, &Job{
+			Name:      entry.Job.Name(),
+			Spec:      entry.Job.Spec(),
+			Timeout:   entry.Job.Timeout().String(),
+			NextRunAt: lo.Ternary(entry.Next.IsZero(), nil, lo.ToPtr(entry.Next)),
+			LastRun:   lo.FirstOrEmpty(runs),
+		})
+	}
+
+	return c.JSON(http.StatusOK, adminmodel.Response{
+		Data: jobs,
+	})
+}
+
+// GetJobRuns returns the recent runs of a job, including the runs of the other schedulers.
+func (a *admin) GetJobRuns(c echo.Context) error {
+	var request GetJobRunsRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := defaults.Set(&request); err != nil {
+		return errorx.BadRequestError(c, fmt.Errorf("set default failed: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	runs, err := a.databaseClient.FindCronJobRuns(c.Request().Context(), schema.CronJobRunsQuery{
+		Cursor: request.Cursor,
+		Job:    lo.ToPtr(request.Name),
+		Limit:  lo.ToPtr(request.Limit),
+	})
+	if err != nil {
+		zap.L().Error("find cron job runs failed", zap.String("job", request.Name), zap.Error(err))
+
+		return errorx.InternalError(c)
+	}
+
+	return c.JSON(http.StatusOK, adminmodel.Response{
+		Data: runs,
+	})
+}
+
+// TriggerJob runs a job immediately under its lock, the run is returned once it is started.
+func (a *admin) TriggerJob(c echo.Context) error {
+	var request TriggerJobRequest
+
+	if err := c.Bind(&request); err != nil {
+		return errorx.BadParamsError(c, fmt.Errorf("bind request: %w", err))
+	}
+
+	if err := c.Validate(&request); err != nil {
+		return errorx.ValidationFailedError(c, fmt.Errorf("validation failed: %w", err))
+	}
+
+	run, err := a.runner.Trigger(c.Request().Context(), request.Name)
+	if err != nil {
+		switch {
+		case errors.Is(err, cronjob.ErrJobNotFound):
+			return errorx.BadParamsError(c, err)
+		case errors.Is(err, cronjob.ErrJobRunning):
+			return errorx.ServiceUnavailableError(c, err)
+		default:
+			zap.L().Error("trigger cron job failed", zap.String("job", request.Name), zap.Error(err))
+
+			return errorx.InternalError(c)
+		}
+	}
+
+	zap.L().Info("triggered cron job", zap.String("job", request.Name), zap.Uint64("run", run.ID))
+
+	return c.JSON(http.StatusAccepted, adminmodel.Response{
+		Data: run,
+	})
+}
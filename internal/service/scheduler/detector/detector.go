@@ -3,24 +3,18 @@ package detector
 import (
 	"context"
 	"fmt"
-	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
-	"github.com/redis/go-redis/v9"
 	"github.com/rss3-network/global-indexer/internal/cronjob"
 	"github.com/rss3-network/global-indexer/internal/database"
-	"github.com/rss3-network/global-indexer/internal/service"
 	"go.uber.org/zap"
 )
 
-var _ service.Server = (*server)(nil)
+var _ cronjob.Job = (*server)(nil)
 
 var Name = "detector"
 
 type server struct {
-	cronJob        *cronjob.CronJob
 	databaseClient database.Client
 }
 
@@ -32,25 +26,15 @@ func (s *server) Spec() string {
 	return "*/5 * * * * *"
 }
 
+func (s *server) Timeout() time.Duration {
+	return 10 * time.Second
+}
+
 func (s *server) Run(ctx context.Context) error {
-	err := s.cronJob.AddFunc(ctx, s.Spec(), func() {
-		if err := s.updateNodeActivity(ctx); err != nil {
-			zap.L().Error("detect node activity error", zap.Error(err))
-			return
-		}
-	})
-	if err != nil {
-		return fmt.Errorf("add detector cron job: %w", err)
+	if err := s.updateNodeActivity(ctx); err != nil {
+		return fmt.Errorf("detect node activity: %w", err)
 	}
 
-	s.cronJob.Start()
-	defer s.cronJob.Stop()
-
-	stopchan := make(chan os.Signal, 1)
-
-	signal.Notify(stopchan, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM)
-	<-stopchan
-
 	return nil
 }
 
@@ -66,11 +50,8 @@ func (s *server) updateNodeActivity(ctx context.Context) error {
 	return nil
 }
 
-func New(databaseClient database.Client, redis *redis.Client) (service.Server, error) {
-	instance := server{
+func New(databaseClient database.Client) cronjob.Job {
+	return &server{
 		databaseClient: databaseClient,
-		cronJob:        cronjob.New(redis, Name, 10*time.Second),
 	}
-
-	return &instance, nil
 }
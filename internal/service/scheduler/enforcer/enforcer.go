@@ -11,43 +11,20 @@ import (
 	"github.com/rss3-network/global-indexer/contract/l2"
 	"github.com/rss3-network/global-indexer/internal/cache"
 	"github.com/rss3-network/global-indexer/internal/config"
+	"github.com/rss3-network/global-indexer/internal/cronjob"
 	"github.com/rss3-network/global-indexer/internal/database"
-	"github.com/rss3-network/global-indexer/internal/service"
 	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/enforcer"
 	epochfresher "github.com/rss3-network/global-indexer/internal/service/scheduler/enforcer/epoch_fresher"
 	federatedhandles "github.com/rss3-network/global-indexer/internal/service/scheduler/enforcer/federated_handles"
+	nodemaintenance "github.com/rss3-network/global-indexer/internal/service/scheduler/enforcer/node_maintenance"
 	nodestatus "github.com/rss3-network/global-indexer/internal/service/scheduler/enforcer/node_status"
 	reliabilityscore "github.com/rss3-network/global-indexer/internal/service/scheduler/enforcer/reliability_score"
-	"github.com/sourcegraph/conc/pool"
 )
 
+// Name is the name of the group of the jobs enforcing the Network rules.
 var Name = "enforcer"
 
-var _ service.Server = (*server)(nil)
-
-type server struct {
-	enforcers []service.Server
-}
-
-func (s *server) Name() string {
-	return Name
-}
-
-func (s *server) Run(ctx context.Context) error {
-	errorPool := pool.New().WithContext(ctx).WithCancelOnError().WithFirstError()
-
-	for _, e := range s.enforcers {
-		e := e
-
-		errorPool.Go(func(ctx context.Context) error {
-			return e.Run(ctx)
-		})
-	}
-
-	return errorPool.Wait()
-}
-
-func New(databaseClient database.Client, redis *redis.Client, ethereumClient *ethclient.Client, httpClient httputil.Client, config *config.File, txManager *txmgr.SimpleTxManager) (service.Server, error) {
+func New(databaseClient database.Client, redis *redis.Client, ethereumClient *ethclient.Client, httpClient httputil.Client, config *config.File, txManager *txmgr.SimpleTxManager) ([]cronjob.Job, error) {
 	chainID, err := ethereumClient.ChainID(context.Background())
 	if err != nil {
 		return nil, fmt.Errorf("get chain id: %w", err)
@@ -73,7 +50,7 @@ func New(databaseClient database.Client, redis *redis.Client, ethereumClient *et
 		return nil, fmt.Errorf("new staking contract: %w", err)
 	}
 
-	simpleEnforcer, err := enforcer.NewSimpleEnforcer(context.Background(), databaseClient, cache.New(redis), stakingContract, networkParamsContract, httpClient, txManager, config.Settler, chainID, false)
+	simpleEnforcer, err := enforcer.NewSimpleEnforcer(context.Background(), databaseClient, cache.New(redis), stakingContract, networkParamsContract, httpClient, txManager, config.Settler, config.Distributor, chainID, false)
 
 	if err != nil {
 		return nil, fmt.Errorf("new simple enforcer: %w", err)
@@ -89,12 +66,11 @@ func New(databaseClient database.Client, redis *redis.Client, ethereumClient *et
 		return nil, fmt.Errorf("get checkpoint: %w", err)
 	}
 
-	return &server{
-		enforcers: []service.Server{
-			nodestatus.New(redis, simpleEnforcer),
-			reliabilityscore.New(redis, simpleEnforcer),
-			epochfresher.New(redis, ethereumClient, checkpoint.BlockNumber, simpleEnforcer, contractStakingEvents, settlementContract, contractAddresses.AddressStakingProxy),
-			federatedhandles.New(redis, databaseClient, httpClient),
-		},
+	return []cronjob.Job{
+		nodestatus.New(simpleEnforcer),
+		nodemaintenance.New(simpleEnforcer),
+		reliabilityscore.New(simpleEnforcer),
+		epochfresher.New(ethereumClient, checkpoint.BlockNumber, simpleEnforcer, contractStakingEvents, settlementContract, contractAddresses.AddressStakingProxy),
+		federatedhandles.New(redis, databaseClient, httpClient),
 	}, nil
 }
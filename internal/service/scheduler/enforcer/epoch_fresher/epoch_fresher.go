@@ -15,20 +15,17 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
-	"github.com/redis/go-redis/v9"
 	"github.com/rss3-network/global-indexer/contract/l2"
 	"github.com/rss3-network/global-indexer/internal/cronjob"
-	"github.com/rss3-network/global-indexer/internal/service"
 	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/enforcer"
 	"go.uber.org/zap"
 )
 
-var _ service.Server = (*server)(nil)
+var _ cronjob.StartupJob = (*server)(nil)
 
 var Name = "epoch_fresher"
 
 type server struct {
-	cronJob                   *cronjob.CronJob
 	blockNumber               uint64
 	simpleEnforcer            *enforcer.SimpleEnforcer
 	contractStakingEvents     *l2.Events
@@ -41,6 +38,20 @@ func (s *server) Name() string {
 	return Name
 }
 
+// Spec restarts the epoch fresher if it stopped after failing to process the blocks.
+func (s *server) Spec() string {
+	return "@every 1m"
+}
+
+func (s *server) Timeout() time.Duration {
+	return time.Minute
+}
+
+// RunOnStart starts the epoch fresher when the scheduler is started, it keeps running until it fails or is canceled.
+func (s *server) RunOnStart() bool {
+	return true
+}
+
 func (s *server) Run(ctx context.Context) error {
 	retryableFunc := func() error {
 		for {
@@ -81,10 +92,7 @@ func (s *server) process(ctx context.Context) error {
 			zap.Duration("block.confirmationTime", blockConfirmationTime),
 		)
 
-		timer := time.NewTimer(blockConfirmationTime)
-		<-timer.C
-
-		return nil
+		return wait(ctx, blockConfirmationTime)
 	}
 
 	// Fetch logs from the previous block number to the latest block number.
@@ -112,8 +120,9 @@ func (s *server) process(ctx context.Context) error {
 			zap.Duration("newEpochWaitTime", newEpochWaitTime),
 		)
 
-		timer := time.NewTimer(newEpochWaitTime)
-		<-timer.C
+		if err := wait(ctx, newEpochWaitTime); err != nil {
+			return err
+		}
 	}
 
 	s.blockNumber = blockEnd
@@ -121,6 +130,19 @@ func (s *server) process(ctx context.Context) error {
 	return nil
 }
 
+// wait waits for the duration, or returns the error of the context if it is done first.
+func wait(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
 func (s *server) fetchLogs(ctx context.Context, blockStart, blockEnd uint64) ([]types.Log, error) {
 	query := ethereum.FilterQuery{
 		Addresses: []common.Address{
@@ -172,9 +194,8 @@ func (s *server) processLogs(ctx context.Context, logs []types.Log) error {
 	return nil
 }
 
-func New(redis *redis.Client, ethereumClient *ethclient.Client, blockNumber uint64, simpleEnforcer *enforcer.SimpleEnforcer, contractStakingEvents *l2.Events, settlementContract *l2.Settlement, settlementContractAddress common.Address) service.Server {
+func New(ethereumClient *ethclient.Client, blockNumber uint64, simpleEnforcer *enforcer.SimpleEnforcer, contractStakingEvents *l2.Events, settlementContract *l2.Settlement, settlementContractAddress common.Address) cronjob.Job {
 	return &server{
-		cronJob:                   cronjob.New(redis, Name, 1*time.Minute),
 		blockNumber:               blockNumber,
 		settlementContract:        settlementContract,
 		contractStakingEvents:     contractStakingEvents,
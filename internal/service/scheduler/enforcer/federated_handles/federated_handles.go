@@ -3,9 +3,6 @@ package federatedhandles
 import (
 	"context"
 	"fmt"
-	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -13,16 +10,13 @@ import (
 	"github.com/rss3-network/global-indexer/internal/cache"
 	"github.com/rss3-network/global-indexer/internal/cronjob"
 	"github.com/rss3-network/global-indexer/internal/database"
-	"github.com/rss3-network/global-indexer/internal/service"
-	"go.uber.org/zap"
 )
 
-var _ service.Server = (*server)(nil)
+var _ cronjob.StartupJob = (*server)(nil)
 
 var Name = "federated_handles"
 
 type server struct {
-	cronJob        *cronjob.CronJob
 	databaseClient database.Client
 	cacheClient    cache.Client
 	httpClient     httputil.Client
@@ -36,37 +30,25 @@ func (s *server) Spec() string {
 	return "@every 15m"
 }
 
-func (s *server) Run(ctx context.Context) error {
-	// initial execution of maintaining federated handles
-	if err := s.maintainFederatedHandles(ctx); err != nil {
-		zap.L().Error("initial execution of maintaining federated handles failed", zap.Error(err))
-	}
+func (s *server) Timeout() time.Duration {
+	return 10 * time.Second
+}
 
-	err := s.cronJob.AddFunc(ctx, s.Spec(), func() {
-		if err := s.maintainFederatedHandles(ctx); err != nil {
-			zap.L().Error("maintain federated handles error", zap.Error(err))
-			return
-		}
-	})
+// RunOnStart maintains the federated handles when the scheduler is started, rather than 15 minutes after it.
+func (s *server) RunOnStart() bool {
+	return true
+}
 
-	if err != nil {
-		return fmt.Errorf("add maintain federated handles cron job: %w", err)
+func (s *server) Run(ctx context.Context) error {
+	if err := s.maintainFederatedHandles(ctx); err != nil {
+		return fmt.Errorf("maintain federated handles: %w", err)
 	}
 
-	s.cronJob.Start()
-	defer s.cronJob.Stop()
-
-	stopChan := make(chan os.Signal, 1)
-
-	signal.Notify(stopChan, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM)
-	<-stopChan
-
 	return nil
 }
 
-func New(redisClient *redis.Client, databaseClient database.Client, httpClient httputil.Client) service.Server {
+func New(redisClient *redis.Client, databaseClient database.Client, httpClient httputil.Client) cronjob.Job {
 	return &server{
-		cronJob:        cronjob.New(redisClient, Name, 10*time.Second),
 		databaseClient: databaseClient,
 		cacheClient:    cache.New(redisClient),
 		httpClient:     httpClient,
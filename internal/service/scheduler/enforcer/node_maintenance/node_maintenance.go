@@ -0,0 +1,44 @@
+package nodemaintenance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rss3-network/global-indexer/internal/cronjob"
+	"github.com/rss3-network/global-indexer/internal/service/hub/handler/dsl/enforcer"
+)
+
+var _ cronjob.Job = (*server)(nil)
+
+var Name = "node_maintenance"
+
+type server struct {
+	simpleEnforcer *enforcer.SimpleEnforcer
+}
+
+func (s *server) Name() string {
+	return Name
+}
+
+func (s *server) Spec() string {
+	return "0 * * * * *"
+}
+
+func (s *server) Timeout() time.Duration {
+	return 30 * time.Second
+}
+
+func (s *server) Run(ctx context.Context) error {
+	if err := s.simpleEnforcer.MaintainNodeMaintenance(ctx); err != nil {
+		return fmt.Errorf("maintain node_maintenance: %w", err)
+	}
+
+	return nil
+}
+
+func New(simpleEnforcer *enforcer.SimpleEnforcer) cronjob.Job {
+	return &server{
+		simpleEnforcer: simpleEnforcer,
+	}
+}
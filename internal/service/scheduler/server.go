@@ -1,40 +1,233 @@
 package scheduler
 
 import (
+	"context"
+	"crypto/subtle"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
 
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
 	"github.com/redis/go-redis/v9"
 	"github.com/rss3-network/global-indexer/common/httputil"
 	"github.com/rss3-network/global-indexer/common/txmgr"
 	"github.com/rss3-network/global-indexer/internal/client/ethereum"
 	"github.com/rss3-network/global-indexer/internal/config"
 	"github.com/rss3-network/global-indexer/internal/config/flag"
+	"github.com/rss3-network/global-indexer/internal/cronjob"
 	"github.com/rss3-network/global-indexer/internal/database"
 	"github.com/rss3-network/global-indexer/internal/service"
 	"github.com/rss3-network/global-indexer/internal/service/scheduler/detector"
 	"github.com/rss3-network/global-indexer/internal/service/scheduler/enforcer"
+	epochfresher "github.com/rss3-network/global-indexer/internal/service/scheduler/enforcer/epoch_fresher"
+	federatedhandles "github.com/rss3-network/global-indexer/internal/service/scheduler/enforcer/federated_handles"
+	nodemaintenance "github.com/rss3-network/global-indexer/internal/service/scheduler/enforcer/node_maintenance"
+	nodestatus "github.com/rss3-network/global-indexer/internal/service/scheduler/enforcer/node_status"
+	reliabilityscore "github.com/rss3-network/global-indexer/internal/service/scheduler/enforcer/reliability_score"
 	"github.com/rss3-network/global-indexer/internal/service/scheduler/snapshot"
+	"github.com/rss3-network/global-indexer/internal/service/scheduler/snapshot/apy"
+	nodecount "github.com/rss3-network/global-indexer/internal/service/scheduler/snapshot/node_count"
+	operatorprofit "github.com/rss3-network/global-indexer/internal/service/scheduler/snapshot/operator_profit"
+	stakercount "github.com/rss3-network/global-indexer/internal/service/scheduler/snapshot/staker_count"
+	stakerprofit "github.com/rss3-network/global-indexer/internal/service/scheduler/snapshot/staker_profit"
 	"github.com/rss3-network/global-indexer/internal/service/scheduler/taxer"
 	"github.com/spf13/viper"
+	"go.uber.org/zap"
 )
 
-// NewServer creates a new scheduler server that executes cron jobs.
+const Name = "scheduler"
+
+const (
+	DefaultHost = "0.0.0.0"
+	DefaultPort = "8080"
+)
+
+// AllJobs selects the jobs of all the groups.
+const AllJobs = "all"
+
+// groups are the names of the jobs created together, a group is selected by its name.
+var groups = []struct {
+	name string
+	jobs []string
+}{
+	{name: detector.Name, jobs: []string{detector.Name}},
+	{name: enforcer.Name, jobs: []string{nodestatus.Name, nodemaintenance.Name, reliabilityscore.Name, epochfresher.Name, federatedhandles.Name}},
+	{name: snapshot.Name, jobs: []string{nodecount.Name, stakercount.Name, stakerprofit.Name, operatorprofit.Name, apy.Name}},
+	{name: taxer.Name, jobs: []string{taxer.Name}},
+}
+
+var _ service.Server = (*Server)(nil)
+
+// Server runs the selected cron jobs, and serves the admin API of the jobs if the admin config is set.
+type Server struct {
+	runner     *cronjob.Runner
+	httpServer *echo.Echo
+}
+
+func (s *Server) Name() string {
+	return Name
+}
+
+func (s *Server) Run(ctx context.Context) error {
+	s.runner.Start(ctx)
+	defer s.runner.Stop()
+
+	if s.httpServer != nil {
+		go func() {
+			if err := s.httpServer.Start(net.JoinHostPort(DefaultHost, DefaultPort)); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				zap.L().Error("serve admin api", zap.Error(err))
+			}
+		}()
+
+		defer func() {
+			if err := s.httpServer.Shutdown(context.Background()); err != nil {
+				zap.L().Error("shutdown admin api", zap.Error(err))
+			}
+		}()
+	}
+
+	stopchan := make(chan os.Signal, 1)
+
+	signal.Notify(stopchan, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM)
+	<-stopchan
+
+	return nil
+}
+
+// NewServer creates a new scheduler server that executes the cron jobs selected by the jobs flag,
+// or the jobs of the group selected by the server flag if the jobs flag is not set.
 func NewServer(databaseClient database.Client, redis *redis.Client, ethereumMultiChainClient *ethereum.MultiChainClient, httpClient httputil.Client, config *config.File, txManager *txmgr.SimpleTxManager) (service.Server, error) {
 	ethereumClient, err := ethereumMultiChainClient.Get(viper.GetUint64(flag.KeyChainIDL2))
 	if err != nil {
 		return nil, fmt.Errorf("get ethereum client: %w", err)
 	}
 
-	switch server := viper.GetString(flag.KeyServer); server {
+	selection := viper.GetStringSlice(flag.KeyJobs)
+	if len(selection) == 0 {
+		selection = []string{viper.GetString(flag.KeyServer)}
+	}
+
+	selectedJobs, err := selectJobs(selection)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []cronjob.Job
+
+	for _, group := range groups {
+		if !slices.ContainsFunc(group.jobs, func(job string) bool { return slices.Contains(selectedJobs, job) }) {
+			continue
+		}
+
+		groupJobs, err := newJobs(group.name, databaseClient, redis, ethereumClient, httpClient, config, txManager)
+		if err != nil {
+			return nil, fmt.Errorf("create %s jobs: %w", group.name, err)
+		}
+
+		for _, job := range groupJobs {
+			if slices.Contains(selectedJobs, job.Name()) {
+				jobs = append(jobs, job)
+			}
+		}
+	}
+
+	runner, err := cronjob.NewRunner(redis, databaseClient, config.CronJob.RunRetention, jobs...)
+	if err != nil {
+		return nil, fmt.Errorf("new job runner: %w", err)
+	}
+
+	zap.L().Info("scheduled jobs", zap.Strings("jobs", selectedJobs))
+
+	instance := Server{
+		runner: runner,
+	}
+
+	if config.Admin != nil {
+		instance.httpServer = newAdminServer(runner, databaseClient, config.Admin)
+	}
+
+	return &instance, nil
+}
+
+// selectJobs resolves the names of the jobs and the groups of jobs to the names of the jobs.
+func selectJobs(selection []string) ([]string, error) {
+	var jobs []string
+
+	for _, name := range selection {
+		found := false
+
+		for _, group := range groups {
+			if name == AllJobs || name == group.name {
+				jobs = append(jobs, group.jobs...)
+				found = true
+
+				continue
+			}
+
+			if slices.Contains(group.jobs, name) {
+				jobs = append(jobs, name)
+				found = true
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("unknown scheduler job: %s", name)
+		}
+	}
+
+	slices.Sort(jobs)
+
+	return slices.Compact(jobs), nil
+}
+
+func newJobs(group string, databaseClient database.Client, redis *redis.Client, ethereumClient *ethclient.Client, httpClient httputil.Client, config *config.File, txManager *txmgr.SimpleTxManager) ([]cronjob.Job, error) {
+	switch group {
 	case detector.Name:
-		return detector.New(databaseClient, redis)
+		return []cronjob.Job{detector.New(databaseClient)}, nil
 	case enforcer.Name:
 		return enforcer.New(databaseClient, redis, ethereumClient, httpClient, config, txManager)
 	case snapshot.Name:
 		return snapshot.New(databaseClient, redis, ethereumClient)
 	case taxer.Name:
-		return taxer.New(databaseClient, redis, ethereumClient, config, txManager)
+		server, err := taxer.New(databaseClient, ethereumClient, config, txManager)
+		if err != nil {
+			return nil, err
+		}
+
+		return []cronjob.Job{server}, nil
 	default:
-		return nil, fmt.Errorf("unknown scheduler server: %s", server)
+		return nil, fmt.Errorf("unknown scheduler group: %s", group)
+	}
+}
+
+// newAdminServer serves the admin API of the jobs, which is only accessible with the access token of the admin config.
+func newAdminServer(runner *cronjob.Runner, databaseClient database.Client, adminConfig *config.Admin) *echo.Echo {
+	httpServer := echo.New()
+
+	httpServer.HideBanner = true
+	httpServer.HidePort = true
+	httpServer.Validator = defaultValidator
+
+	admin := admin{
+		runner:         runner,
+		databaseClient: databaseClient,
 	}
+
+	group := httpServer.Group("/admin", middleware.KeyAuth(func(key string, _ echo.Context) (bool, error) {
+		return subtle.ConstantTimeCompare([]byte(key), []byte(adminConfig.AccessToken)) == 1, nil
+	}))
+	{
+		group.GET("/jobs", admin.GetJobs)
+		group.GET("/jobs/:name/runs", admin.GetJobRuns)
+		group.POST("/jobs/:name/runs", admin.TriggerJob)
+	}
+
+	return httpServer
 }
@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectJobs(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		selection []string
+		want      []string
+		wantError bool
+	}{
+		{
+			name:      "Group",
+			selection: []string{"detector"},
+			want:      []string{"detector"},
+		},
+		{
+			name:      "Jobs",
+			selection: []string{"node_count", "apy"},
+			want:      []string{"apy", "node_count"},
+		},
+		{
+			name:      "GroupAndJobOfGroup",
+			selection: []string{"snapshot", "apy"},
+			want:      []string{"apy", "node_count", "operator_profit", "staker_count", "staker_profit"},
+		},
+		{
+			name:      "All",
+			selection: []string{AllJobs},
+			want: []string{
+				"apy", "detector", "epoch_fresher", "federated_handles", "node_count", "node_maintenance",
+				"node_status", "operator_profit", "reliability_score", "staker_count", "staker_profit", "taxer",
+			},
+		},
+		{
+			name:      "UnknownJob",
+			selection: []string{"detector", "unknown"},
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			jobs, err := selectJobs(tc.selection)
+			if tc.wantError {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, jobs)
+		})
+	}
+}
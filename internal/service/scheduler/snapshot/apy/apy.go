@@ -5,9 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
-	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -16,7 +13,6 @@ import (
 	"github.com/rss3-network/global-indexer/internal/cache"
 	"github.com/rss3-network/global-indexer/internal/cronjob"
 	"github.com/rss3-network/global-indexer/internal/database"
-	"github.com/rss3-network/global-indexer/internal/service"
 	"github.com/rss3-network/global-indexer/schema"
 	"github.com/samber/lo"
 	"github.com/shopspring/decimal"
@@ -29,10 +25,9 @@ var (
 	CacheKeyEpochAverageAPY = "epoch_average_apy"
 )
 
-var _ service.Server = (*server)(nil)
+var _ cronjob.Job = (*server)(nil)
 
 type server struct {
-	cronJob         *cronjob.CronJob
 	databaseClient  database.Client
 	cacheClient     cache.Client
 	stakingContract *stakingv2.Staking
@@ -46,51 +41,36 @@ func (s *server) Spec() string {
 	return "0 */1 * * * *" // every minute
 }
 
-func (s *server) Run(ctx context.Context) error {
-	err := s.cronJob.AddFunc(ctx, s.Spec(), func() {
-		// Query the latest of the epoch apy snapshots.
-		snapshots, err := s.databaseClient.FindEpochAPYSnapshots(ctx, schema.EpochAPYSnapshotQuery{Limit: lo.ToPtr(1)})
-		if err != nil && !errors.Is(err, database.ErrorRowNotFound) {
-			zap.L().Error("find epoch APY snapshots", zap.Error(err))
-
-			return
-		}
-
-		// Query the latest epoch of the epoch events.
-		epochEvents, err := s.databaseClient.FindEpochs(ctx, &schema.FindEpochsQuery{Limit: lo.ToPtr(1)})
-		if err != nil && !errors.Is(err, database.ErrorRowNotFound) {
-			zap.L().Error("find epochs", zap.Error(err))
+func (s *server) Timeout() time.Duration {
+	return Timeout
+}
 
-			return
-		}
+func (s *server) Run(ctx context.Context) error {
+	// Query the latest of the epoch apy snapshots.
+	snapshots, err := s.databaseClient.FindEpochAPYSnapshots(ctx, schema.EpochAPYSnapshotQuery{Limit: lo.ToPtr(1)})
+	if err != nil && !errors.Is(err, database.ErrorRowNotFound) {
+		return fmt.Errorf("find epoch APY snapshots: %w", err)
+	}
 
-		var latestSnapshotEpochID uint64
+	// Query the latest epoch of the epoch events.
+	epochEvents, err := s.databaseClient.FindEpochs(ctx, &schema.FindEpochsQuery{Limit: lo.ToPtr(1)})
+	if err != nil && !errors.Is(err, database.ErrorRowNotFound) {
+		return fmt.Errorf("find epochs: %w", err)
+	}
 
-		if len(snapshots) > 0 {
-			latestSnapshotEpochID = snapshots[0].EpochID
-		}
+	var latestSnapshotEpochID uint64
 
-		// Save the minTokensToStake snapshots.
-		if latestSnapshotEpochID < epochEvents[0].ID {
-			if err := s.saveAPYToSnapshots(ctx, latestSnapshotEpochID, epochEvents[0]); err != nil {
-				zap.L().Error("save APY to snapshots", zap.Error(err))
+	if len(snapshots) > 0 {
+		latestSnapshotEpochID = snapshots[0].EpochID
+	}
 
-				return
-			}
+	// Save the minTokensToStake snapshots.
+	if latestSnapshotEpochID < epochEvents[0].ID {
+		if err := s.saveAPYToSnapshots(ctx, latestSnapshotEpochID, epochEvents[0]); err != nil {
+			return fmt.Errorf("save APY to snapshots: %w", err)
 		}
-	})
-	if err != nil {
-		return fmt.Errorf("add apy cron job: %w", err)
 	}
 
-	s.cronJob.Start()
-	defer s.cronJob.Stop()
-
-	stopchan := make(chan os.Signal, 1)
-
-	signal.Notify(stopchan, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM)
-	<-stopchan
-
 	return nil
 }
 
@@ -180,9 +160,8 @@ func (s *server) saveAPYToSnapshots(ctx context.Context, latestEpochSnapshot uin
 	return nil
 }
 
-func New(databaseClient database.Client, redisClient *redis.Client, stakingContract *stakingv2.Staking) service.Server {
+func New(databaseClient database.Client, redisClient *redis.Client, stakingContract *stakingv2.Staking) cronjob.Job {
 	return &server{
-		cronJob:         cronjob.New(redisClient, Name, Timeout),
 		cacheClient:     cache.New(redisClient),
 		databaseClient:  databaseClient,
 		stakingContract: stakingContract,
@@ -4,10 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
-	"os/signal"
 	"sync"
-	"syscall"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -16,7 +13,6 @@ import (
 	stakingv2 "github.com/rss3-network/global-indexer/contract/l2/staking/v2"
 	"github.com/rss3-network/global-indexer/internal/cronjob"
 	"github.com/rss3-network/global-indexer/internal/database"
-	"github.com/rss3-network/global-indexer/internal/service"
 	"github.com/rss3-network/global-indexer/schema"
 	"github.com/samber/lo"
 	"github.com/shopspring/decimal"
@@ -29,10 +25,9 @@ var (
 	Timeout = 3 * time.Minute
 )
 
-var _ service.Server = (*server)(nil)
+var _ cronjob.Job = (*server)(nil)
 
 type server struct {
-	cronJob         *cronjob.CronJob
 	databaseClient  database.Client
 	redisClient     *redis.Client
 	stakingContract *stakingv2.Staking
@@ -46,56 +41,40 @@ func (s *server) Spec() string {
 	return "0 */1 * * * *" // every minute
 }
 
-func (s *server) Run(ctx context.Context) error {
-	err := s.cronJob.AddFunc(ctx, s.Spec(), func() {
-		// Query the latest epoch of the staker profit snapshots.
-		snapshot, err := s.databaseClient.FindOperatorProfitSnapshots(ctx, schema.OperatorProfitSnapshotsQuery{Limit: lo.ToPtr(1)})
-		if err != nil && !errors.Is(err, database.ErrorRowNotFound) {
-			zap.L().Error("find staker profit snapshots", zap.Error(err))
-
-			return
-		}
-
-		// Query the latest epoch of the epoch events.
-		epochEvents, err := s.databaseClient.FindEpochs(ctx, &schema.FindEpochsQuery{Limit: lo.ToPtr(1)})
-		if err != nil && !errors.Is(err, database.ErrorRowNotFound) {
-			zap.L().Error("find epochs", zap.Error(err))
+func (s *server) Timeout() time.Duration {
+	return Timeout
+}
 
-			return
-		}
+func (s *server) Run(ctx context.Context) error {
+	// Query the latest epoch of the staker profit snapshots.
+	snapshot, err := s.databaseClient.FindOperatorProfitSnapshots(ctx, schema.OperatorProfitSnapshotsQuery{Limit: lo.ToPtr(1)})
+	if err != nil && !errors.Is(err, database.ErrorRowNotFound) {
+		return fmt.Errorf("find staker profit snapshots: %w", err)
+	}
 
-		var latestEpochSnapshot, latestEpochEvent uint64
+	// Query the latest epoch of the epoch events.
+	epochEvents, err := s.databaseClient.FindEpochs(ctx, &schema.FindEpochsQuery{Limit: lo.ToPtr(1)})
+	if err != nil && !errors.Is(err, database.ErrorRowNotFound) {
+		return fmt.Errorf("find epochs: %w", err)
+	}
 
-		if len(snapshot) > 0 {
-			latestEpochSnapshot = snapshot[0].EpochID
-		}
+	var latestEpochSnapshot, latestEpochEvent uint64
 
-		if len(epochEvents) > 0 {
-			latestEpochEvent = epochEvents[0].ID
-		}
+	if len(snapshot) > 0 {
+		latestEpochSnapshot = snapshot[0].EpochID
+	}
 
-		// Save the staker profit snapshots.
-		if latestEpochSnapshot < latestEpochEvent {
-			if err := s.saveOperatorProfitSnapshots(ctx, latestEpochSnapshot, latestEpochEvent); err != nil {
-				zap.L().Error("save staker profit snapshots", zap.Error(err))
+	if len(epochEvents) > 0 {
+		latestEpochEvent = epochEvents[0].ID
+	}
 
-				return
-			}
+	// Save the staker profit snapshots.
+	if latestEpochSnapshot < latestEpochEvent {
+		if err := s.saveOperatorProfitSnapshots(ctx, latestEpochSnapshot, latestEpochEvent); err != nil {
+			return fmt.Errorf("save staker profit snapshots: %w", err)
 		}
-	})
-
-	if err != nil {
-		return fmt.Errorf("add staker profit cron job: %w", err)
 	}
 
-	s.cronJob.Start()
-	defer s.cronJob.Stop()
-
-	stopchan := make(chan os.Signal, 1)
-
-	signal.Notify(stopchan, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM)
-	<-stopchan
-
 	return nil
 }
 
@@ -173,9 +152,8 @@ func (s *server) saveOperatorProfitSnapshots(ctx context.Context, latestEpochSna
 	return nil
 }
 
-func New(databaseClient database.Client, redisClient *redis.Client, stakingContract *stakingv2.Staking) service.Server {
+func New(databaseClient database.Client, redisClient *redis.Client, stakingContract *stakingv2.Staking) cronjob.Job {
 	return &server{
-		cronJob:         cronjob.New(redisClient, Name, Timeout),
 		databaseClient:  databaseClient,
 		redisClient:     redisClient,
 		stakingContract: stakingContract,
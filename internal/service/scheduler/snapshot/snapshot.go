@@ -8,43 +8,19 @@ import (
 	"github.com/redis/go-redis/v9"
 	"github.com/rss3-network/global-indexer/contract/l2"
 	stakingv2 "github.com/rss3-network/global-indexer/contract/l2/staking/v2"
+	"github.com/rss3-network/global-indexer/internal/cronjob"
 	"github.com/rss3-network/global-indexer/internal/database"
-	"github.com/rss3-network/global-indexer/internal/service"
 	"github.com/rss3-network/global-indexer/internal/service/scheduler/snapshot/apy"
 	nodecount "github.com/rss3-network/global-indexer/internal/service/scheduler/snapshot/node_count"
 	operatorprofit "github.com/rss3-network/global-indexer/internal/service/scheduler/snapshot/operator_profit"
 	stakercount "github.com/rss3-network/global-indexer/internal/service/scheduler/snapshot/staker_count"
 	stakerprofit "github.com/rss3-network/global-indexer/internal/service/scheduler/snapshot/staker_profit"
-	"github.com/sourcegraph/conc/pool"
 )
 
+// Name is the name of the group of the jobs taking the snapshots of the Network.
 var Name = "snapshot"
 
-var _ service.Server = (*server)(nil)
-
-type server struct {
-	snapshots []service.Server
-}
-
-func (s *server) Name() string {
-	return Name
-}
-
-func (s *server) Run(ctx context.Context) error {
-	errorPool := pool.New().WithContext(ctx).WithCancelOnError().WithFirstError()
-
-	for _, snapshot := range s.snapshots {
-		snapshot := snapshot
-
-		errorPool.Go(func(ctx context.Context) error {
-			return snapshot.Run(ctx)
-		})
-	}
-
-	return errorPool.Wait()
-}
-
-func New(databaseClient database.Client, redis *redis.Client, ethereumClient *ethclient.Client) (service.Server, error) {
+func New(databaseClient database.Client, redis *redis.Client, ethereumClient *ethclient.Client) ([]cronjob.Job, error) {
 	chainID, err := ethereumClient.ChainID(context.Background())
 	if err != nil {
 		return nil, fmt.Errorf("get chain id: %w", err)
@@ -60,13 +36,11 @@ func New(databaseClient database.Client, redis *redis.Client, ethereumClient *et
 		return nil, fmt.Errorf("new staking contract: %w", err)
 	}
 
-	return &server{
-		snapshots: []service.Server{
-			nodecount.New(databaseClient, redis),
-			stakercount.New(databaseClient, redis),
-			stakerprofit.New(databaseClient, redis, stakingContract),
-			operatorprofit.New(databaseClient, redis, stakingContract),
-			apy.New(databaseClient, redis, stakingContract),
-		},
+	return []cronjob.Job{
+		nodecount.New(databaseClient, redis),
+		stakercount.New(databaseClient, redis),
+		stakerprofit.New(databaseClient, redis, stakingContract),
+		operatorprofit.New(databaseClient, redis, stakingContract),
+		apy.New(databaseClient, redis, stakingContract),
 	}, nil
 }
@@ -5,10 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
-	"os"
-	"os/signal"
 	"sync"
-	"syscall"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -18,7 +15,6 @@ import (
 	stakingv2 "github.com/rss3-network/global-indexer/contract/l2/staking/v2"
 	"github.com/rss3-network/global-indexer/internal/cronjob"
 	"github.com/rss3-network/global-indexer/internal/database"
-	"github.com/rss3-network/global-indexer/internal/service"
 	"github.com/rss3-network/global-indexer/schema"
 	"github.com/samber/lo"
 	"github.com/shopspring/decimal"
@@ -31,10 +27,9 @@ var (
 	Timeout = 3 * time.Minute
 )
 
-var _ service.Server = (*server)(nil)
+var _ cronjob.Job = (*server)(nil)
 
 type server struct {
-	cronJob         *cronjob.CronJob
 	databaseClient  database.Client
 	redisClient     *redis.Client
 	stakingContract *stakingv2.Staking
@@ -48,82 +43,62 @@ func (s *server) Spec() string {
 	return "0 */1 * * * *" // every minute
 }
 
-func (s *server) Run(ctx context.Context) error {
-	err := s.cronJob.AddFunc(ctx, s.Spec(), func() {
-		// Query the latest epoch of the epoch events.
-		epochEvents, err := s.databaseClient.FindEpochs(ctx, &schema.FindEpochsQuery{Limit: lo.ToPtr(1)})
-		if err != nil && !errors.Is(err, database.ErrorRowNotFound) {
-			zap.L().Error("find epochs", zap.Error(err))
-
-			return
-		}
-
-		if len(epochEvents) == 0 {
-			return
-		}
-
-		var latestEpochSnapshot uint64
-
-		// Check the latest epoch of the staker profit snapshots.
-		for epochID := epochEvents[0].ID; epochID > 0; epochID-- {
-			// Query the epoch of the staker profit snapshots.
-			snapshots, err := s.databaseClient.FindStakerProfitSnapshots(ctx, schema.StakerProfitSnapshotsQuery{EpochID: lo.ToPtr(epochID)})
-			if err != nil && !errors.Is(err, database.ErrorRowNotFound) {
-				zap.L().Error("find staker profit snapshots", zap.Error(err))
-
-				return
-			}
-
-			if len(snapshots) == 0 {
-				continue
-			}
-
-			epochItems, err := s.databaseClient.FindEpochTransactions(ctx, epochID, 1, nil)
-			if err != nil {
-				zap.L().Error("find epoch transactions", zap.Error(err))
-
-				return
-			}
+func (s *server) Timeout() time.Duration {
+	return Timeout
+}
 
-			stakerCount, err := s.databaseClient.FindStakerCount(ctx, schema.StakeChipsQuery{
-				BlockNumber: epochItems[0].BlockNumber,
-			})
-			if err != nil {
-				zap.L().Error("find staker count", zap.Error(err))
+func (s *server) Run(ctx context.Context) error {
+	// Query the latest epoch of the epoch events.
+	epochEvents, err := s.databaseClient.FindEpochs(ctx, &schema.FindEpochsQuery{Limit: lo.ToPtr(1)})
+	if err != nil && !errors.Is(err, database.ErrorRowNotFound) {
+		return fmt.Errorf("find epochs: %w", err)
+	}
 
-				return
-			}
+	if len(epochEvents) == 0 {
+		return nil
+	}
 
-			if int64(len(snapshots)) < stakerCount {
-				continue
-			}
+	var latestEpochSnapshot uint64
 
-			latestEpochSnapshot = epochID
+	// Check the latest epoch of the staker profit snapshots.
+	for epochID := epochEvents[0].ID; epochID > 0; epochID-- {
+		// Query the epoch of the staker profit snapshots.
+		snapshots, err := s.databaseClient.FindStakerProfitSnapshots(ctx, schema.StakerProfitSnapshotsQuery{EpochID: lo.ToPtr(epochID)})
+		if err != nil && !errors.Is(err, database.ErrorRowNotFound) {
+			return fmt.Errorf("find staker profit snapshots: %w", err)
+		}
 
-			break
+		if len(snapshots) == 0 {
+			continue
 		}
 
-		// Save the staker profit snapshots.
-		if latestEpochSnapshot < epochEvents[0].ID {
-			if err := s.saveStakerProfitSnapshots(ctx, latestEpochSnapshot, epochEvents[0].ID); err != nil {
-				zap.L().Error("save staker profit snapshots", zap.Error(err))
+		epochItems, err := s.databaseClient.FindEpochTransactions(ctx, epochID, 1, nil)
+		if err != nil {
+			return fmt.Errorf("find epoch transactions: %w", err)
+		}
 
-				return
-			}
+		stakerCount, err := s.databaseClient.FindStakerCount(ctx, schema.StakeChipsQuery{
+			BlockNumber: epochItems[0].BlockNumber,
+		})
+		if err != nil {
+			return fmt.Errorf("find staker count: %w", err)
 		}
-	})
 
-	if err != nil {
-		return fmt.Errorf("add staker profit cron job: %w", err)
-	}
+		if int64(len(snapshots)) < stakerCount {
+			continue
+		}
 
-	s.cronJob.Start()
-	defer s.cronJob.Stop()
+		latestEpochSnapshot = epochID
 
-	stopchan := make(chan os.Signal, 1)
+		break
+	}
 
-	signal.Notify(stopchan, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM)
-	<-stopchan
+	// Save the staker profit snapshots.
+	if latestEpochSnapshot < epochEvents[0].ID {
+		if err := s.saveStakerProfitSnapshots(ctx, latestEpochSnapshot, epochEvents[0].ID); err != nil {
+			return fmt.Errorf("save staker profit snapshots: %w", err)
+		}
+	}
 
 	return nil
 }
@@ -277,9 +252,8 @@ func (s *server) buildStakerProfitSnapshots(ctx context.Context, currentEpoch *s
 	return profit, nil
 }
 
-func New(databaseClient database.Client, redisClient *redis.Client, stakingContract *stakingv2.Staking) service.Server {
+func New(databaseClient database.Client, redisClient *redis.Client, stakingContract *stakingv2.Staking) cronjob.Job {
 	return &server{
-		cronJob:         cronjob.New(redisClient, Name, Timeout),
 		databaseClient:  databaseClient,
 		redisClient:     redisClient,
 		stakingContract: stakingContract,
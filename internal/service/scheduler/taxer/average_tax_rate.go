@@ -10,6 +10,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/rss3-network/global-indexer/common/txmgr"
 	"github.com/rss3-network/global-indexer/contract/l2"
+	"github.com/rss3-network/global-indexer/internal/client/ethereum"
 	"github.com/rss3-network/global-indexer/schema"
 	"github.com/samber/lo"
 	"github.com/shopspring/decimal"
@@ -118,7 +119,7 @@ func (s *Server) calculateAverageTaxRate(ctx context.Context) (*decimal.Decimal,
 		}
 
 		// Query the VSL to complement the Node info.
-		nodeInfo, err := s.stakingContract.GetNodes(&bind.CallOpts{Context: ctx}, nodeAddresses)
+		nodeInfo, err := s.stakingContract.GetNodes(&bind.CallOpts{Context: ethereum.WithQuorum(ctx)}, nodeAddresses)
 		if err != nil {
 			zap.L().Error("get Nodes on the VSL by staking contract", zap.Error(err))
 
@@ -177,6 +178,7 @@ func (s *Server) sendTransaction(ctx context.Context, input []byte) (*common.Has
 		To:       lo.ToPtr(l2.ContractMap[s.chainID.Uint64()].AddressSettlementProxy),
 		GasLimit: s.settlerConfig.GasLimit,
 		Value:    big.NewInt(0),
+		Priority: txmgr.PriorityTax,
 	}
 
 	receipt, err := s.txManager.Send(ctx, txCandidate)
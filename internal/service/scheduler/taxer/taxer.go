@@ -4,24 +4,18 @@ import (
 	"context"
 	"fmt"
 	"math/big"
-	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/redis/go-redis/v9"
 	"github.com/rss3-network/global-indexer/common/txmgr"
 	"github.com/rss3-network/global-indexer/contract/l2"
 	stakingv2 "github.com/rss3-network/global-indexer/contract/l2/staking/v2"
 	"github.com/rss3-network/global-indexer/internal/config"
 	"github.com/rss3-network/global-indexer/internal/cronjob"
 	"github.com/rss3-network/global-indexer/internal/database"
-	"github.com/rss3-network/global-indexer/internal/service"
-	"go.uber.org/zap"
 )
 
-var _ service.Server = (*Server)(nil)
+var _ cronjob.Job = (*Server)(nil)
 
 var (
 	Name    = "taxer"
@@ -29,7 +23,6 @@ var (
 )
 
 type Server struct {
-	cronJob         *cronjob.CronJob
 	databaseClient  database.Client
 	chainID         *big.Int
 	stakingContract *stakingv2.Staking
@@ -45,31 +38,19 @@ func (s *Server) Spec() string {
 	return "*/10 * * * * *" // every 10 seconds
 }
 
-func (s *Server) Run(ctx context.Context) error {
-	err := s.cronJob.AddFunc(ctx, s.Spec(), func() {
-		if err := s.checkAndSubmitAverageTaxRate(ctx); err != nil {
-			zap.L().Error("submit average tax rate error", zap.Error(err))
-
-			return
-		}
-	})
+func (s *Server) Timeout() time.Duration {
+	return Timeout
+}
 
-	if err != nil {
-		return fmt.Errorf("add cron job error: %w", err)
+func (s *Server) Run(ctx context.Context) error {
+	if err := s.checkAndSubmitAverageTaxRate(ctx); err != nil {
+		return fmt.Errorf("submit average tax rate: %w", err)
 	}
 
-	s.cronJob.Start()
-	defer s.cronJob.Stop()
-
-	stopchan := make(chan os.Signal, 1)
-
-	signal.Notify(stopchan, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM)
-	<-stopchan
-
 	return nil
 }
 
-func New(databaseClient database.Client, redisClient *redis.Client, ethereumClient *ethclient.Client, config *config.File, txManager *txmgr.SimpleTxManager) (*Server, error) {
+func New(databaseClient database.Client, ethereumClient *ethclient.Client, config *config.File, txManager *txmgr.SimpleTxManager) (*Server, error) {
 	chainID, err := ethereumClient.ChainID(context.Background())
 	if err != nil {
 		return nil, fmt.Errorf("get chain ID: %w", err)
@@ -86,7 +67,6 @@ func New(databaseClient database.Client, redisClient *redis.Client, ethereumClie
 	}
 
 	server := &Server{
-		cronJob:         cronjob.New(redisClient, Name, Timeout),
 		databaseClient:  databaseClient,
 		chainID:         chainID,
 		stakingContract: stakingContract,
@@ -0,0 +1,264 @@
+package settler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rss3-network/global-indexer/internal/database"
+	"github.com/rss3-network/global-indexer/internal/service/settler/failure"
+	"github.com/rss3-network/global-indexer/schema"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+)
+
+// resumeEpochProof completes the Settlement of an Epoch interrupted by a previous run,
+// starting from its first unconfirmed batch.
+func (s *Server) resumeEpochProof(ctx context.Context) error {
+	batch, err := s.databaseClient.FindLatestSettlementBatch(ctx)
+	if err != nil {
+		if errors.Is(err, database.ErrorRowNotFound) {
+			return nil
+		}
+
+		return fmt.Errorf("find latest settlement batch: %w", err)
+	}
+
+	if batch.Status != schema.SettlementBatchStatusPending && batch.Data.IsFinal {
+		return nil
+	}
+
+	zap.L().Info("resume interrupted Settlement", zap.Uint64("epoch", batch.EpochID), zap.Uint64("batch", batch.Index))
+
+	return s.submitEpochProof(ctx, batch.EpochID)
+}
+
+// settleBatch sends a batch to the Settlement contract exactly once.
+// The batch is checkpointed before its transaction is sent, with the hash of each signed transaction before it is published, and after it is confirmed,
+// so that a batch left pending by a previous run is reconciled against the chain instead of being sent again.
+func (s *Server) settleBatch(ctx context.Context, batch *schema.SettlementBatch) error {
+	if batch.Status == schema.SettlementBatchStatusPending {
+		reconciled, err := s.reconcileSettlementBatch(ctx, batch)
+		if err != nil {
+			return fmt.Errorf("reconcile settlement batch %d of epoch %d: %w", batch.Index, batch.EpochID, err)
+		}
+
+		if reconciled {
+			return nil
+		}
+	}
+
+	input, err := s.prepareInputData(batch.Data)
+	if err != nil {
+		return err
+	}
+
+	blockNumber, err := s.ethereumClient.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("get latest block number: %w", err)
+	}
+
+	batch.CalldataHash = crypto.Keccak256Hash(input)
+	batch.BlockNumber = blockNumber
+	batch.Status = schema.SettlementBatchStatusPending
+
+	if err := s.databaseClient.SaveSettlementBatch(ctx, batch); err != nil {
+		return fmt.Errorf("save settlement batch: %w", err)
+	}
+
+	zap.L().Info("construct Settlement data", zap.Uint64("epoch", batch.EpochID), zap.Uint64("batch", batch.Index), zap.Any("transactionData", batch.Data))
+
+	// Invoke the Settlement contract
+	receipt, err := s.invokeSettlementContractWithRetry(ctx, batch.Data, func(ctx context.Context, tx *types.Transaction) error {
+		return s.checkpointSettlementTransaction(ctx, batch, tx)
+	})
+
+	switch {
+	case errors.Is(err, failure.ErrorSkipped):
+		zap.L().Warn("Settlement batch skipped by operator", zap.Uint64("epoch", batch.EpochID), zap.Uint64("batch", batch.Index))
+
+		batch.Status = schema.SettlementBatchStatusSkipped
+
+		if err := s.databaseClient.SaveSettlementBatch(ctx, batch); err != nil {
+			return fmt.Errorf("save settlement batch: %w", err)
+		}
+
+		return nil
+	case err != nil:
+		zap.L().Error("retry submitEpochProof invokeSettlementContract", zap.Error(err))
+
+		return err
+	}
+
+	if err := s.confirmSettlementBatch(ctx, batch, receipt); err != nil {
+		return err
+	}
+
+	zap.L().Info("Settlement contracted invoked successfully", zap.String("tx", receipt.TxHash.String()), zap.Any("data", batch.Data))
+
+	return nil
+}
+
+// checkpointSettlementTransaction saves the hash of a signed transaction of the batch before it is published,
+// so the batch can be reconciled against the transaction even if the journal of the transaction manager is lost.
+func (s *Server) checkpointSettlementTransaction(ctx context.Context, batch *schema.SettlementBatch, tx *types.Transaction) error {
+	if lo.FromPtr(batch.TransactionHash) == tx.Hash() {
+		return nil
+	}
+
+	batch.TransactionHash = lo.ToPtr(tx.Hash())
+
+	if err := s.databaseClient.SaveSettlementBatch(context.WithoutCancel(ctx), batch); err != nil {
+		return fmt.Errorf("save transaction hash of settlement batch: %w", err)
+	}
+
+	return nil
+}
+
+// reconcileSettlementBatch resolves a batch left pending by a previous run, it returns true if the batch needs no resending,
+// either because its transaction has landed on chain, or because an operator has skipped it.
+func (s *Server) reconcileSettlementBatch(ctx context.Context, batch *schema.SettlementBatch) (bool, error) {
+	skipped, err := s.isSettlementBatchSkipped(ctx, batch)
+	if err != nil {
+		return false, err
+	}
+
+	if skipped {
+		batch.Status = schema.SettlementBatchStatusSkipped
+
+		return true, s.databaseClient.SaveSettlementBatch(ctx, batch)
+	}
+
+	if err := s.waitForPendingTransactions(ctx); err != nil {
+		return false, err
+	}
+
+	receipt, err := s.findSettlementBatchReceipt(ctx, batch)
+	if err != nil {
+		return false, err
+	}
+
+	if receipt == nil {
+		zap.L().Info("pending Settlement batch not found on chain, resend it", zap.Uint64("epoch", batch.EpochID), zap.Uint64("batch", batch.Index))
+
+		return false, nil
+	}
+
+	zap.L().Info("pending Settlement batch found on chain", zap.Uint64("epoch", batch.EpochID), zap.Uint64("batch", batch.Index), zap.String("tx", receipt.TxHash.String()))
+
+	return true, s.confirmSettlementBatch(ctx, batch, receipt)
+}
+
+// isSettlementBatchSkipped checks if an operator has skipped a failed transaction of the batch.
+func (s *Server) isSettlementBatchSkipped(ctx context.Context, batch *schema.SettlementBatch) (bool, error) {
+	failures, err := s.databaseClient.FindSettlementFailures(ctx, schema.SettlementFailuresQuery{
+		Service:  lo.ToPtr(failure.ServiceSettler),
+		EpochID:  lo.ToPtr(batch.EpochID),
+		Statuses: []schema.SettlementFailureStatus{schema.SettlementFailureStatusSkipped},
+	})
+	if err != nil {
+		return false, fmt.Errorf("find skipped settlement failures: %w", err)
+	}
+
+	return lo.ContainsBy(failures, func(settlementFailure *schema.SettlementFailure) bool {
+		return crypto.Keccak256Hash(settlementFailure.Input) == batch.CalldataHash
+	}), nil
+}
+
+// waitForPendingTransactions waits until the transactions sent by the settler are all mined,
+// so that a transaction still in the mempool is not mistaken for a lost one.
+func (s *Server) waitForPendingTransactions(ctx context.Context) error {
+	from := s.txManager.From()
+
+	for {
+		minedNonce, err := s.ethereumClient.NonceAt(ctx, from, nil)
+		if err != nil {
+			return fmt.Errorf("get nonce: %w", err)
+		}
+
+		pendingNonce, err := s.ethereumClient.PendingNonceAt(ctx, from)
+		if err != nil {
+			return fmt.Errorf("get pending nonce: %w", err)
+		}
+
+		if pendingNonce <= minedNonce {
+			return nil
+		}
+
+		zap.L().Info("wait for pending transactions to be mined", zap.Uint64("nonce", minedNonce), zap.Uint64("pending_nonce", pendingNonce))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// findSettlementBatchReceipt returns the successful receipt of the transaction of the batch, or nil if it has not landed on chain.
+// The receipt is first fetched by the transaction hash saved on the batch before the transaction was published.
+// As the hash follows the latest submission, the transaction is then looked up in the journal of the transaction manager
+// by the input data of the batch, and its receipt is fetched by the hashes of all its submissions.
+func (s *Server) findSettlementBatchReceipt(ctx context.Context, batch *schema.SettlementBatch) (*types.Receipt, error) {
+	if batch.TransactionHash != nil {
+		receipt, err := s.ethereumClient.TransactionReceipt(ctx, *batch.TransactionHash)
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return nil, fmt.Errorf("get receipt of transaction %s: %w", batch.TransactionHash, err)
+		}
+
+		if receipt != nil && receipt.Status == types.ReceiptStatusSuccessful {
+			return receipt, nil
+		}
+	}
+
+	input, err := s.prepareInputData(batch.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	if crypto.Keccak256Hash(input) != batch.CalldataHash {
+		return nil, fmt.Errorf("input data of settlement batch %d of epoch %d does not match its calldata hash", batch.Index, batch.EpochID)
+	}
+
+	receipt, err := s.txManager.FindReceipt(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("find receipt of settlement batch: %w", err)
+	}
+
+	return receipt, nil
+}
+
+// confirmSettlementBatch checkpoints the batch as confirmed, and saves the Settlement as the reference point for the next Epoch.
+func (s *Server) confirmSettlementBatch(ctx context.Context, batch *schema.SettlementBatch, receipt *types.Receipt) error {
+	batch.TransactionHash = lo.ToPtr(receipt.TxHash)
+	batch.Status = schema.SettlementBatchStatusConfirmed
+
+	return s.databaseClient.WithTransaction(ctx, func(ctx context.Context, client database.Client) error {
+		if err := client.SaveSettlementBatch(ctx, batch); err != nil {
+			return fmt.Errorf("save settlement batch: %w", err)
+		}
+
+		if err := client.SaveEpochTrigger(ctx, &schema.EpochTrigger{
+			TransactionHash: receipt.TxHash,
+			EpochID:         batch.EpochID,
+			Data:            batch.Data,
+		}); err != nil {
+			return fmt.Errorf("save settler submitEpochProof: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// nextSettlementCursor returns the cursor of the batch following the one with the Settlement data.
+func nextSettlementCursor(cursor *string, data schema.SettlementData) *string {
+	if len(data.NodeAddress) == 0 {
+		return cursor
+	}
+
+	return lo.ToPtr(data.NodeAddress[len(data.NodeAddress)-1].String())
+}
@@ -0,0 +1,89 @@
+package settler
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rss3-network/global-indexer/internal/database"
+	"github.com/rss3-network/global-indexer/schema"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextSettlementCursor(t *testing.T) {
+	t.Parallel()
+
+	cursor := lo.ToPtr(common.Address{1}.String())
+
+	testCases := []struct {
+		name     string
+		cursor   *string
+		data     schema.SettlementData
+		expected *string
+	}{
+		{
+			name:     "FirstBatch",
+			data:     schema.SettlementData{NodeAddress: []common.Address{{2}, {3}}},
+			expected: lo.ToPtr(common.Address{3}.String()),
+		},
+		{
+			name:     "NextBatch",
+			cursor:   cursor,
+			data:     schema.SettlementData{NodeAddress: []common.Address{{2}, {3}}},
+			expected: lo.ToPtr(common.Address{3}.String()),
+		},
+		{
+			name:     "EmptyBatch",
+			cursor:   cursor,
+			data:     schema.SettlementData{},
+			expected: cursor,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, nextSettlementCursor(tc.cursor, tc.data))
+		})
+	}
+}
+
+// batchDatabaseClient is a database client recording the saved Settlement batches.
+type batchDatabaseClient struct {
+	database.Client
+
+	saved []common.Hash
+}
+
+func (c *batchDatabaseClient) SaveSettlementBatch(_ context.Context, batch *schema.SettlementBatch) error {
+	c.saved = append(c.saved, lo.FromPtr(batch.TransactionHash))
+
+	return nil
+}
+
+func TestCheckpointSettlementTransaction(t *testing.T) {
+	t.Parallel()
+
+	databaseClient := &batchDatabaseClient{}
+	server := &Server{databaseClient: databaseClient}
+	batch := &schema.SettlementBatch{Status: schema.SettlementBatchStatusPending}
+
+	tx := types.NewTx(&types.DynamicFeeTx{Nonce: 1, GasTipCap: big.NewInt(10)})
+	bumpedTx := types.NewTx(&types.DynamicFeeTx{Nonce: 1, GasTipCap: big.NewInt(11)})
+
+	// The hash of each submission is saved once, before it is published.
+	require.NoError(t, server.checkpointSettlementTransaction(context.Background(), batch, tx))
+	require.NoError(t, server.checkpointSettlementTransaction(context.Background(), batch, tx))
+	require.NoError(t, server.checkpointSettlementTransaction(context.Background(), batch, bumpedTx))
+
+	assert.Equal(t, []common.Hash{tx.Hash(), bumpedTx.Hash()}, databaseClient.saved)
+	assert.Equal(t, bumpedTx.Hash(), lo.FromPtr(batch.TransactionHash))
+	assert.Equal(t, schema.SettlementBatchStatusPending, batch.Status)
+}
@@ -0,0 +1,279 @@
+package failure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rss3-network/global-indexer/common/httputil"
+	"github.com/rss3-network/global-indexer/internal/config"
+	"github.com/rss3-network/global-indexer/internal/database"
+	"github.com/rss3-network/global-indexer/schema"
+	"github.com/samber/lo"
+	"go.uber.org/zap"
+)
+
+const (
+	ServiceSettler  = "settler"
+	ServiceEnforcer = "enforcer"
+)
+
+var (
+	ErrorSkipped = errors.New("failed transaction skipped by operator")
+	ErrorAborted = errors.New("failed transaction aborted by operator")
+)
+
+// TransactionFailedError is returned when a transaction to the Settlement contract is included with a failed receipt.
+// Resending the same transaction is unlikely to succeed, so it should not be retried automatically.
+type TransactionFailedError struct {
+	Receipt *types.Receipt
+}
+
+func (e *TransactionFailedError) Error() string {
+	return fmt.Sprintf("transaction %s failed", e.Receipt.TxHash)
+}
+
+// IsTransactionFailed reports whether the error is caused by a failed receipt.
+func IsTransactionFailed(err error) bool {
+	var failedErr *TransactionFailedError
+
+	return errors.As(err, &failedErr)
+}
+
+// SendFunc resends the input of the failure, and returns the receipt if the transaction succeeds.
+type SendFunc func(ctx context.Context, failure *schema.SettlementFailure) (*types.Receipt, error)
+
+// Handler persists the failed transactions of a service and blocks the service until an operator resolves them,
+// instead of blocking it forever.
+type Handler struct {
+	service        string
+	databaseClient database.Client
+	httpClient     httputil.Client
+	config         *config.SettlementFailure
+}
+
+func NewHandler(service string, databaseClient database.Client, httpClient httputil.Client, conf *config.SettlementFailure) *Handler {
+	return &Handler{
+		service:        service,
+		databaseClient: databaseClient,
+		httpClient:     httpClient,
+		config:         conf,
+	}
+}
+
+// Handle records the failed transaction, alerts the operators and blocks until they resolve it.
+// It returns the receipt of a successful retry, ErrorSkipped if the transaction is skipped, or ErrorAborted if it is aborted.
+// A nil Handler returns the failure as is.
+func (h *Handler) Handle(ctx context.Context, epochID uint64, input []byte, data *schema.SettlementData, failedErr *TransactionFailedError, send SendFunc) (*types.Receipt, error) {
+	if h == nil {
+		return nil, failedErr
+	}
+
+	failure := &schema.SettlementFailure{
+		Service:         h.service,
+		EpochID:         epochID,
+		TransactionHash: failedErr.Receipt.TxHash,
+		Input:           input,
+		Data:            data,
+		Attempts:        1,
+		Status:          schema.SettlementFailureStatusFailed,
+	}
+
+	if err := h.databaseClient.SaveSettlementFailure(ctx, failure); err != nil {
+		return nil, fmt.Errorf("save settlement failure: %w", err)
+	}
+
+	return h.resolve(ctx, failure, send)
+}
+
+// Resume blocks until the failures left unresolved by a previous run of the service are resolved,
+// the receipts of successful retries are handled by send.
+func (h *Handler) Resume(ctx context.Context, send SendFunc) error {
+	if h == nil {
+		return nil
+	}
+
+	failures, err := h.databaseClient.FindSettlementFailures(ctx, schema.SettlementFailuresQuery{
+		Service:  lo.ToPtr(h.service),
+		Statuses: []schema.SettlementFailureStatus{schema.SettlementFailureStatusFailed, schema.SettlementFailureStatusRetrying},
+	})
+	if err != nil {
+		return fmt.Errorf("find unresolved settlement failures: %w", err)
+	}
+
+	for _, failure := range failures {
+		zap.L().Info("resume unresolved settlement failure", zap.Uint64("id", failure.ID), zap.Uint64("epoch_id", failure.EpochID))
+
+		if _, err := h.resolve(ctx, failure, send); err != nil && !errors.Is(err, ErrorSkipped) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolve waits for an operator to act on the failure, and resends the transaction until it succeeds or is given up.
+func (h *Handler) resolve(ctx context.Context, failure *schema.SettlementFailure, send SendFunc) (*types.Receipt, error) {
+	for {
+		if failure.Status == schema.SettlementFailureStatusFailed {
+			zap.L().Error("settlement transaction failed, waiting for an operator to resolve it",
+				zap.Uint64("id", failure.ID), zap.String("service", h.service), zap.Uint64("epoch_id", failure.EpochID),
+				zap.String("tx", failure.TransactionHash.String()), zap.Uint64("attempts", failure.Attempts))
+
+			h.alert(ctx, failure)
+
+			status, err := h.wait(ctx, failure.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			failure.Status = status
+		}
+
+		switch failure.Status {
+		case schema.SettlementFailureStatusSkipped:
+			return nil, ErrorSkipped
+		case schema.SettlementFailureStatusAborted:
+			return nil, ErrorAborted
+		case schema.SettlementFailureStatusResolved:
+			return nil, nil
+		}
+
+		receipt, err := send(ctx, failure)
+		if err != nil {
+			var failedErr *TransactionFailedError
+			if !errors.As(err, &failedErr) {
+				// The failure stays retrying, so it is retried again when the service is resumed.
+				return nil, fmt.Errorf("retry settlement failure %d: %w", failure.ID, err)
+			}
+
+			failure.TransactionHash = failedErr.Receipt.TxHash
+			failure.Attempts++
+			failure.Status = schema.SettlementFailureStatusFailed
+		} else {
+			failure.TransactionHash = receipt.TxHash
+			failure.Status = schema.SettlementFailureStatusResolved
+		}
+
+		if err := h.databaseClient.SaveSettlementFailure(ctx, failure); err != nil {
+			return nil, fmt.Errorf("save settlement failure: %w", err)
+		}
+
+		if receipt != nil {
+			zap.L().Info("settlement failure resolved", zap.Uint64("id", failure.ID), zap.String("tx", receipt.TxHash.String()))
+
+			return receipt, nil
+		}
+	}
+}
+
+// wait polls the failure until an operator changes its status.
+func (h *Handler) wait(ctx context.Context, id uint64) (schema.SettlementFailureStatus, error) {
+	ticker := time.NewTicker(h.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-ticker.C:
+		}
+
+		failure, err := h.databaseClient.FindSettlementFailure(ctx, id)
+		if err != nil {
+			zap.L().Error("find settlement failure", zap.Uint64("id", id), zap.Error(err))
+
+			continue
+		}
+
+		if failure.Status != schema.SettlementFailureStatusFailed {
+			return failure.Status, nil
+		}
+	}
+}
+
+// alert notifies the alert webhook of the failure, errors are logged as the alert is best effort.
+func (h *Handler) alert(ctx context.Context, failure *schema.SettlementFailure) {
+	if h.config.AlertWebhook == "" {
+		return
+	}
+
+	body, err := json.Marshal(failure)
+	if err != nil {
+		zap.L().Error("marshal settlement failure alert", zap.Error(err))
+
+		return
+	}
+
+	response, err := h.httpClient.FetchWithMethod(ctx, http.MethodPost, h.config.AlertWebhook, "", bytes.NewReader(body))
+	if err != nil {
+		zap.L().Error("send settlement failure alert", zap.Uint64("id", failure.ID), zap.Error(err))
+
+		return
+	}
+
+	lo.Try(response.Close)
+}
+
+// Resolve applies the action of an operator to a failure waiting to be resolved, and records it in the audit trail.
+func Resolve(ctx context.Context, databaseClient database.Client, id uint64, action schema.SettlementFailureAction, operator, reason string) (*schema.SettlementFailure, error) {
+	var failure *schema.SettlementFailure
+
+	err := databaseClient.WithTransaction(ctx, func(ctx context.Context, client database.Client) (err error) {
+		if failure, err = client.FindSettlementFailure(ctx, id); err != nil {
+			return fmt.Errorf("find settlement failure %d: %w", id, err)
+		}
+
+		if failure.Status, err = transition(failure.Status, action); err != nil {
+			return err
+		}
+
+		if err := client.SaveSettlementFailure(ctx, failure); err != nil {
+			return fmt.Errorf("save settlement failure: %w", err)
+		}
+
+		audit := &schema.SettlementFailureAudit{
+			FailureID: failure.ID,
+			Action:    action,
+			Operator:  operator,
+			Reason:    reason,
+		}
+
+		if err := client.SaveSettlementFailureAudit(ctx, audit); err != nil {
+			return fmt.Errorf("save settlement failure audit: %w", err)
+		}
+
+		failure.Audits, err = client.FindSettlementFailureAudits(ctx, failure.ID)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return failure, nil
+}
+
+// transition returns the status of a failure after the action of an operator,
+// only the failures waiting to be resolved can be acted on.
+func transition(status schema.SettlementFailureStatus, action schema.SettlementFailureAction) (schema.SettlementFailureStatus, error) {
+	if status != schema.SettlementFailureStatusFailed {
+		return "", fmt.Errorf("cannot %s a %s settlement failure", action, status)
+	}
+
+	switch action {
+	case schema.SettlementFailureActionRetry:
+		return schema.SettlementFailureStatusRetrying, nil
+	case schema.SettlementFailureActionSkip:
+		return schema.SettlementFailureStatusSkipped, nil
+	case schema.SettlementFailureActionAbort:
+		return schema.SettlementFailureStatusAborted, nil
+	default:
+		return "", fmt.Errorf("unknown settlement failure action: %s", action)
+	}
+}
@@ -0,0 +1,76 @@
+package failure
+
+import (
+	"testing"
+
+	"github.com/rss3-network/global-indexer/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransition(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		status   schema.SettlementFailureStatus
+		action   schema.SettlementFailureAction
+		expected schema.SettlementFailureStatus
+		wantErr  bool
+	}{
+		{
+			name:     "Retry",
+			status:   schema.SettlementFailureStatusFailed,
+			action:   schema.SettlementFailureActionRetry,
+			expected: schema.SettlementFailureStatusRetrying,
+		},
+		{
+			name:     "Skip",
+			status:   schema.SettlementFailureStatusFailed,
+			action:   schema.SettlementFailureActionSkip,
+			expected: schema.SettlementFailureStatusSkipped,
+		},
+		{
+			name:     "Abort",
+			status:   schema.SettlementFailureStatusFailed,
+			action:   schema.SettlementFailureActionAbort,
+			expected: schema.SettlementFailureStatusAborted,
+		},
+		{
+			name:    "UnknownAction",
+			status:  schema.SettlementFailureStatusFailed,
+			action:  "ignore",
+			wantErr: true,
+		},
+		{
+			name:    "AlreadyRetrying",
+			status:  schema.SettlementFailureStatusRetrying,
+			action:  schema.SettlementFailureActionSkip,
+			wantErr: true,
+		},
+		{
+			name:    "AlreadyResolved",
+			status:  schema.SettlementFailureStatusResolved,
+			action:  schema.SettlementFailureActionRetry,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			status, err := transition(tc.status, tc.action)
+			if tc.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, status)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package failure
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rss3-network/global-indexer/internal/database"
+	"github.com/rss3-network/global-indexer/schema"
+	"go.uber.org/zap"
+)
+
+var _ prometheus.Collector = (*Collector)(nil)
+
+// Collector exports the number of unresolved settlement failures by service and status from the database,
+// so that the failures of the settler and the scheduler can be scraped from the hub.
+type Collector struct {
+	databaseClient database.Client
+	unresolved     *prometheus.Desc
+}
+
+func NewCollector(databaseClient database.Client) *Collector {
+	return &Collector{
+		databaseClient: databaseClient,
+		unresolved: prometheus.NewDesc(
+			"settlement_unresolved_failures",
+			"Number of failed settlement transactions waiting for an operator to resolve them",
+			[]string{"service", "status"},
+			nil,
+		),
+	}
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.unresolved
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	statuses := []schema.SettlementFailureStatus{schema.SettlementFailureStatusFailed, schema.SettlementFailureStatusRetrying}
+
+	failures, err := c.databaseClient.FindSettlementFailures(ctx, schema.SettlementFailuresQuery{Statuses: statuses})
+	if err != nil {
+		zap.L().Error("collect settlement failures", zap.Error(err))
+
+		return
+	}
+
+	counts := make(map[string]map[schema.SettlementFailureStatus]int)
+
+	for _, service := range []string{ServiceSettler, ServiceEnforcer} {
+		counts[service] = make(map[schema.SettlementFailureStatus]int)
+	}
+
+	for _, failure := range failures {
+		if counts[failure.Service] == nil {
+			counts[failure.Service] = make(map[schema.SettlementFailureStatus]int)
+		}
+
+		counts[failure.Service][failure.Status]++
+	}
+
+	for service, serviceCounts := range counts {
+		for _, status := range statuses {
+			ch <- prometheus.MustNewConstMetric(c.unresolved, prometheus.GaugeValue, float64(serviceCounts[status]), service, string(status))
+		}
+	}
+}
@@ -22,19 +22,20 @@ import (
 	"github.com/sourcegraph/conc/pool"
 )
 
-func (s *Server) calculateOperationRewards(ctx context.Context, operationStats []*schema.Stat, rewards *config.Rewards) ([]*big.Int, error) {
+// calculateOperationRewards calculates the Operation Rewards and the score breakdowns of the Nodes.
+func (s *Server) calculateOperationRewards(ctx context.Context, operationStats []*schema.Stat, rewards *config.Rewards) ([]*big.Int, []*schema.OperationScore, error) {
 	// If there are no nodes, return nil
 	if len(operationStats) == 0 {
-		return nil, nil
+		return nil, nil, nil
 	}
 
-	operationRewards, err := s.calculateFinalRewards(ctx, operationStats, rewards)
+	operationRewards, operationScores, err := s.calculateFinalRewards(ctx, operationStats, rewards)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to calculate operation rewards: %w", err)
+		return nil, nil, fmt.Errorf("failed to calculate operation rewards: %w", err)
 	}
 
-	return operationRewards, nil
+	return operationRewards, operationScores, nil
 }
 
 type StatValue struct {
@@ -43,7 +44,7 @@ type StatValue struct {
 }
 
 // calculateFinalRewards calculates the final rewards for each node based on the operation stats.
-func (s *Server) calculateFinalRewards(ctx context.Context, operationStats []*schema.Stat, rewards *config.Rewards) ([]*big.Int, error) {
+func (s *Server) calculateFinalRewards(ctx context.Context, operationStats []*schema.Stat, rewards *config.Rewards) ([]*big.Int, []*schema.OperationScore, error) {
 	operationRewards := make([]*big.Int, len(operationStats))
 	maxStatValue := StatValue{
 		validCount:    big.NewFloat(0),
@@ -74,10 +75,10 @@ func (s *Server) calculateFinalRewards(ctx context.Context, operationStats []*sc
 	}
 
 	if err := checkRewardsCeiling(operationRewards, rewards.OperationRewards); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return operationRewards, nil
+	return operationRewards, newOperationScores(operationStats, statValues, maxStatValue, rewards), nil
 }
 
 // processStat processes the stat for the operation rewards calculation.
@@ -144,26 +145,7 @@ func calculateScores(ctx context.Context, operationStats []*schema.Stat, statsDa
 				return nil
 			}
 
-			distributionScore := new(big.Float).
-				Sub(
-					calculateScore(statsData[i].validCount, maxValues.validCount, rewards.OperationScore.Distribution.Weight, 1),
-					calculateScore(statsData[i].invalidCount, maxValues.invalidCount, rewards.OperationScore.Distribution.Weight, rewards.OperationScore.Distribution.WeightInvalid),
-				)
-
-			dataScore := new(big.Float).Add(calculateScore(statsData[i].networkCount, maxValues.networkCount, rewards.OperationScore.Data.Weight, rewards.OperationScore.Data.WeightNetwork),
-				new(big.Float).Add(
-					calculateScore(statsData[i].indexerCount, maxValues.indexerCount, rewards.OperationScore.Data.Weight, rewards.OperationScore.Data.WeightIndexer),
-					calculateScore(statsData[i].activityCount, maxValues.activityCount, rewards.OperationScore.Data.Weight, rewards.OperationScore.Data.WeightActivity),
-				),
-			)
-
-			stabilityScore := new(big.Float).
-				Add(
-					calculateScore(statsData[i].upTime, maxValues.upTime, rewards.OperationScore.Stability.Weight, rewards.OperationScore.Stability.WeightUptime),
-					calculateScore(big.NewFloat(float64(lo.Ternary(statsData[i].isLatestVersion, 1, 0))), big.NewFloat(1), rewards.OperationScore.Stability.Weight, rewards.OperationScore.Stability.WeightVersion),
-				)
-
-			scores[i] = new(big.Float).Add(distributionScore, new(big.Float).Add(dataScore, stabilityScore))
+			scores[i] = calculateScoreBreakdown(statsData[i], maxValues, rewards).total()
 
 			mu.Lock()
 			// If the score is less than 0, set it to 0
@@ -183,6 +165,78 @@ func calculateScores(ctx context.Context, operationStats []*schema.Stat, statsDa
 	return scores, totalScore
 }
 
+// scoreBreakdown is the operation score of a Node by category.
+type scoreBreakdown struct {
+	distribution, data, stability *big.Float
+}
+
+// total returns the sum of the scores of all categories.
+func (b *scoreBreakdown) total() *big.Float {
+	return new(big.Float).Add(b.distribution, new(big.Float).Add(b.data, b.stability))
+}
+
+// calculateScoreBreakdown calculates the distribution, data and stability scores of a Node.
+func calculateScoreBreakdown(statValue, maxValues StatValue, rewards *config.Rewards) *scoreBreakdown {
+	distributionScore := new(big.Float).
+		Sub(
+			calculateScore(statValue.validCount, maxValues.validCount, rewards.OperationScore.Distribution.Weight, 1),
+			calculateScore(statValue.invalidCount, maxValues.invalidCount, rewards.OperationScore.Distribution.Weight, rewards.OperationScore.Distribution.WeightInvalid),
+		)
+
+	dataScore := new(big.Float).Add(calculateScore(statValue.networkCount, maxValues.networkCount, rewards.OperationScore.Data.Weight, rewards.OperationScore.Data.WeightNetwork),
+		new(big.Float).Add(
+			calculateScore(statValue.indexerCount, maxValues.indexerCount, rewards.OperationScore.Data.Weight, rewards.OperationScore.Data.WeightIndexer),
+			calculateScore(statValue.activityCount, maxValues.activityCount, rewards.OperationScore.Data.Weight, rewards.OperationScore.Data.WeightActivity),
+		),
+	)
+
+	stabilityScore := new(big.Float).
+		Add(
+			calculateScore(statValue.upTime, maxValues.upTime, rewards.OperationScore.Stability.Weight, rewards.OperationScore.Stability.WeightUptime),
+			calculateScore(big.NewFloat(float64(lo.Ternary(statValue.isLatestVersion, 1, 0))), big.NewFloat(1), rewards.OperationScore.Stability.Weight, rewards.OperationScore.Stability.WeightVersion),
+		)
+
+	return &scoreBreakdown{
+		distribution: distributionScore,
+		data:         dataScore,
+		stability:    stabilityScore,
+	}
+}
+
+// newOperationScores converts the score breakdowns of the Nodes for the Settlement preview,
+// the Nodes without a stat or demoted have no score.
+func newOperationScores(operationStats []*schema.Stat, statsData []StatValue, maxValues StatValue, rewards *config.Rewards) []*schema.OperationScore {
+	operationScores := make([]*schema.OperationScore, len(operationStats))
+
+	for i := range operationStats {
+		if operationStats[i] == nil || operationStats[i].EpochInvalidRequest >= int64(model.DemotionCountBeforeSlashing) {
+			continue
+		}
+
+		breakdown := calculateScoreBreakdown(statsData[i], maxValues, rewards)
+
+		// The negative total score is set to 0, as calculateScores does.
+		totalScore := breakdown.total()
+		if totalScore.Sign() < 0 {
+			totalScore.SetFloat64(0)
+		}
+
+		distribution, _ := breakdown.distribution.Float64()
+		data, _ := breakdown.data.Float64()
+		stability, _ := breakdown.stability.Float64()
+		total, _ := totalScore.Float64()
+
+		operationScores[i] = &schema.OperationScore{
+			Distribution: distribution,
+			Data:         data,
+			Stability:    stability,
+			Total:        total,
+		}
+	}
+
+	return operationScores
+}
+
 // maxFloat returns the maximum of two big.Float values.
 func maxFloat(a, b *big.Float) *big.Float {
 	if a.Cmp(b) > 0 {
@@ -9,6 +9,8 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/rss3-network/global-indexer/internal/config"
 	"github.com/rss3-network/global-indexer/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCalculateScores(t *testing.T) {
@@ -162,3 +164,64 @@ func TestCalculateScores(t *testing.T) {
 		})
 	}
 }
+
+func TestNewOperationScores(t *testing.T) {
+	t.Parallel()
+
+	operationStats := []*schema.Stat{
+		{Address: common.HexToAddress("0x0")},
+		nil,
+	}
+
+	statsData := []StatValue{
+		{
+			validCount:      big.NewFloat(1300),
+			invalidCount:    big.NewFloat(2),
+			networkCount:    big.NewFloat(16),
+			indexerCount:    big.NewFloat(75),
+			activityCount:   big.NewFloat(1913144890),
+			upTime:          big.NewFloat(3),
+			isLatestVersion: true,
+		},
+		{},
+	}
+
+	maxValue := StatValue{
+		validCount:    big.NewFloat(1300),
+		invalidCount:  big.NewFloat(3),
+		networkCount:  big.NewFloat(16),
+		indexerCount:  big.NewFloat(75),
+		activityCount: big.NewFloat(1913144890),
+		upTime:        big.NewFloat(21),
+	}
+
+	rewards := &config.Rewards{
+		OperationScore: &config.OperationScore{
+			Distribution: &config.Distribution{
+				Weight:        0.6,
+				WeightInvalid: 0.5,
+			},
+			Data: &config.Data{
+				Weight:         0.3,
+				WeightNetwork:  0.3,
+				WeightIndexer:  0.6,
+				WeightActivity: 0.1,
+			},
+			Stability: &config.Stability{
+				Weight:        0.1,
+				WeightUptime:  0.7,
+				WeightVersion: 0.3,
+			},
+		},
+	}
+
+	operationScores := newOperationScores(operationStats, statsData, maxValue, rewards)
+
+	require.Len(t, operationScores, 2)
+	require.NotNil(t, operationScores[0])
+	assert.InDelta(t, 0.4, operationScores[0].Distribution, 1e-9)
+	assert.InDelta(t, 0.3, operationScores[0].Data, 1e-9)
+	assert.InDelta(t, 0.04, operationScores[0].Stability, 1e-9)
+	assert.InDelta(t, 0.74, operationScores[0].Total, 1e-9)
+	assert.Nil(t, operationScores[1])
+}
@@ -0,0 +1,94 @@
+package settler
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/rss3-network/global-indexer/common/httputil"
+	"github.com/rss3-network/global-indexer/internal/client/ethereum"
+	"github.com/rss3-network/global-indexer/internal/config"
+	"github.com/rss3-network/global-indexer/internal/database"
+	"github.com/rss3-network/global-indexer/schema"
+	"github.com/samber/lo"
+)
+
+// Previewer computes the Settlement data of an Epoch the same way the settler does,
+// without sending any transaction or saving anything to the database.
+// It allows the operators to check the reward math before it goes on chain.
+type Previewer struct {
+	server *Server
+}
+
+// Preview computes the Settlement data of the epoch in the batches that would be submitted to the Settlement contract.
+func (p *Previewer) Preview(ctx context.Context, epoch uint64) (*schema.SettlementPreview, error) {
+	return p.server.previewSettlement(ctx, epoch)
+}
+
+// previewSettlement mirrors the batching of submitEpochProof, with the construction of each batch kept apart from its submission.
+func (s *Server) previewSettlement(ctx context.Context, epoch uint64) (*schema.SettlementPreview, error) {
+	preview := &schema.SettlementPreview{
+		Epoch:   new(big.Int).SetUint64(epoch),
+		Batches: make([]*schema.SettlementData, 0),
+		Nodes:   make([]*schema.SettlementPreviewNode, 0),
+	}
+
+	var cursor *string
+
+	for batch := 0; ; batch++ {
+		settlementData, operationScores, err := s.constructSettlementBatch(ctx, epoch, cursor)
+		if err != nil {
+			return nil, fmt.Errorf("construct Settlement data of batch %d: %w", batch, err)
+		}
+
+		// No qualified Nodes found in the database.
+		if settlementData == nil {
+			break
+		}
+
+		// An empty batch is submitted only if it is the first one, as submitEpochProof does.
+		if len(settlementData.NodeAddress) == 0 && batch > 0 {
+			break
+		}
+
+		preview.Batches = append(preview.Batches, settlementData)
+
+		for i, address := range settlementData.NodeAddress {
+			node := &schema.SettlementPreviewNode{
+				Address:          address,
+				Batch:            batch,
+				OperationRewards: big.NewInt(0),
+				RequestCount:     settlementData.RequestCount[i],
+			}
+
+			if i < len(settlementData.OperationRewards) {
+				node.OperationRewards = settlementData.OperationRewards[i]
+			}
+
+			if i < len(operationScores) {
+				node.Score = operationScores[i]
+			}
+
+			preview.Nodes = append(preview.Nodes, node)
+		}
+
+		if settlementData.IsFinal || len(settlementData.NodeAddress) == 0 {
+			break
+		}
+
+		cursor = lo.ToPtr(settlementData.NodeAddress[len(settlementData.NodeAddress)-1].String())
+	}
+
+	return preview, nil
+}
+
+func NewPreviewer(databaseClient database.Client, ethereumMultiChainClient *ethereum.MultiChainClient, config *config.File, httpClient httputil.Client) (*Previewer, error) {
+	server, err := newServer(databaseClient, ethereumMultiChainClient, config, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Previewer{
+		server: server,
+	}, nil
+}
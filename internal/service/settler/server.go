@@ -22,6 +22,7 @@ import (
 	"github.com/rss3-network/global-indexer/internal/config/flag"
 	"github.com/rss3-network/global-indexer/internal/database"
 	"github.com/rss3-network/global-indexer/internal/service"
+	"github.com/rss3-network/global-indexer/internal/service/settler/failure"
 	"github.com/rss3-network/global-indexer/schema"
 	"github.com/samber/lo"
 	"github.com/sourcegraph/conc/pool"
@@ -44,6 +45,7 @@ type Server struct {
 	networkParamsContract *l2.NetworkParams
 	config                *config.File
 	httpClient            httputil.Client
+	failureHandler        *failure.Handler
 }
 
 func (s *Server) Name() string {
@@ -55,6 +57,19 @@ func (s *Server) Run(ctx context.Context) error {
 
 	// Listen epoch event
 	errorPool.Go(func(ctx context.Context) error {
+		// Wait for the failed transactions left by a previous run to be resolved before submitting new ones
+		if err := s.failureHandler.Resume(ctx, s.resendSettlementFailure); err != nil {
+			zap.L().Error("resume settlement failures", zap.Error(err))
+
+			return err
+		}
+
+		if err := s.resumeEpochProof(ctx); err != nil {
+			zap.L().Error("resume epoch proof", zap.Error(err))
+
+			return err
+		}
+
 		if err := s.listenEpochEvent(ctx); err != nil {
 			zap.L().Error("listen epoch event", zap.Error(err))
 
@@ -249,6 +264,21 @@ func NewServer(databaseClient database.Client, redisClient *redis.Client, ethere
 	redisPool := goredis.NewPool(redisClient)
 	rs := redsync.New(redisPool)
 
+	server, err := newServer(databaseClient, ethereumMultiChainClient, config, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	server.mutex = rs.NewMutex(Name, redsync.WithExpiry(5*time.Minute))
+	server.txManager = txManager
+	server.failureHandler = failure.NewHandler(failure.ServiceSettler, databaseClient, httpClient, config.Settler.Failure)
+
+	return server, nil
+}
+
+// newServer creates a Server with the clients and contracts required to construct the Settlement data,
+// but without the ones required to submit it.
+func newServer(databaseClient database.Client, ethereumMultiChainClient *ethereum.MultiChainClient, config *config.File, httpClient httputil.Client) (*Server, error) {
 	chainID := new(big.Int).SetUint64(viper.GetUint64(flag.KeyChainIDL2))
 
 	ethereumClient, err := ethereumMultiChainClient.Get(chainID.Uint64())
@@ -278,10 +308,8 @@ func NewServer(databaseClient database.Client, redisClient *redis.Client, ethere
 
 	server := &Server{
 		chainID:               chainID,
-		mutex:                 rs.NewMutex(Name, redsync.WithExpiry(5*time.Minute)),
 		ethereumClient:        ethereumClient,
 		databaseClient:        databaseClient,
-		txManager:             txManager,
 		stakingContract:       stakingContract,
 		settlementContract:    settlementContract,
 		config:                config,
@@ -8,13 +8,16 @@ import (
 	"time"
 
 	"github.com/avast/retry-go/v4"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/rss3-network/global-indexer/common/txmgr"
 	"github.com/rss3-network/global-indexer/contract/l2"
 	stakingv2 "github.com/rss3-network/global-indexer/contract/l2/staking/v2"
+	ethereumclient "github.com/rss3-network/global-indexer/internal/client/ethereum"
 	"github.com/rss3-network/global-indexer/internal/database"
+	"github.com/rss3-network/global-indexer/internal/service/settler/failure"
 	"github.com/rss3-network/global-indexer/schema"
 	"github.com/samber/lo"
 	"go.uber.org/zap"
@@ -37,11 +40,28 @@ func (s *Server) submitEpochProof(ctx context.Context, epoch uint64) error {
 		}
 	}()
 
+	// Resume from the batches checkpointed by a previous run
+	batches, err := s.databaseClient.FindSettlementBatches(ctx, epoch)
+	if err != nil {
+		return fmt.Errorf("find settlement batches: %w", err)
+	}
+
 	var (
-		cursor      *string
-		firstInvoke = true
+		cursor *string
+		index  uint64
 	)
 
+	for _, batch := range batches {
+		if batch.Status == schema.SettlementBatchStatusPending {
+			if err := s.settleBatch(ctx, batch); err != nil {
+				return err
+			}
+		}
+
+		cursor = nextSettlementCursor(cursor, batch.Data)
+		index = batch.Index + 1
+	}
+
 	for {
 		msg := "construct Settlement data"
 		// Construct transactionData as required by the Settlement contract
@@ -53,40 +73,25 @@ func (s *Server) submitEpochProof(ctx context.Context, epoch uint64) error {
 		}
 
 		// Finish processing when conditions are met
-		if len(transactionData.NodeAddress) == 0 && !firstInvoke {
+		if len(transactionData.NodeAddress) == 0 && index > 0 {
 			zap.L().Info("finished processing transactionData.")
 
 			break
 		}
 
-		zap.L().Info(msg, zap.Any("transactionData", transactionData))
-
-		// Invoke the Settlement contract
-		receipt, err := retry.DoWithData(
-			func() (*types.Receipt, error) {
-				return s.invokeSettlementContract(ctx, *transactionData)
-			},
-			retry.Delay(time.Second),
-			retry.Attempts(5),
-		)
-		if err != nil {
-			zap.L().Error("retry submitEpochProof invokeSettlementContract", zap.Error(err))
-
-			return err
+		batch := &schema.SettlementBatch{
+			EpochID: epoch,
+			Index:   index,
+			Cursor:  cursor,
+			Data:    *transactionData,
 		}
 
-		// Save the Settlement to the database, as the reference point for the next Epoch
-		if err := s.saveSettlement(ctx, receipt, *transactionData); err != nil {
+		if err := s.settleBatch(ctx, batch); err != nil {
 			return err
 		}
 
-		zap.L().Info("Settlement contracted invoked successfully", zap.String("tx", receipt.TxHash.String()), zap.Any("data", *transactionData))
-
-		firstInvoke = false
-
-		if len(transactionData.NodeAddress) > 0 {
-			cursor = lo.ToPtr(transactionData.NodeAddress[len(transactionData.NodeAddress)-1].String())
-		}
+		cursor = nextSettlementCursor(cursor, batch.Data)
+		index++
 	}
 
 	zap.L().Info("Epoch Proof submitted successfully", zap.Uint64("settler", epoch))
@@ -118,10 +123,25 @@ func (s *Server) retryEpochProof(ctx context.Context, epochID uint64) error {
 	}
 
 	for _, trigger := range epochTriggers {
+		// Skip the transactions which survived the block reorganization, so that they are not submitted twice
+		receipt, err := s.ethereumClient.TransactionReceipt(ctx, trigger.TransactionHash)
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return fmt.Errorf("get receipt of transaction %s: %w", trigger.TransactionHash, err)
+		}
+
+		if receipt != nil && receipt.Status == types.ReceiptStatusSuccessful {
+			zap.L().Info("Settlement transaction still on chain", zap.Uint64("epoch_id", epochID), zap.String("tx", trigger.TransactionHash.String()))
+
+			continue
+		}
+
 		// Invoke the Settlement contract
-		receipt, err := retry.DoWithData(func() (*types.Receipt, error) {
-			return s.invokeSettlementContract(ctx, trigger.Data)
-		}, retry.Delay(time.Second), retry.Attempts(5))
+		receipt, err = s.invokeSettlementContractWithRetry(ctx, trigger.Data, nil)
+		if errors.Is(err, failure.ErrorSkipped) {
+			zap.L().Warn("Settlement batch skipped by operator", zap.Uint64("epoch_id", epochID), zap.Any("data", trigger.Data))
+
+			continue
+		}
 
 		if err != nil {
 			zap.L().Error("retry submitEpochProof invokeSettlementContract", zap.Error(err))
@@ -138,6 +158,14 @@ func (s *Server) retryEpochProof(ctx context.Context, epochID uint64) error {
 
 // constructSettlementData constructs Settlement data as required by the Settlement contract
 func (s *Server) constructSettlementData(ctx context.Context, epoch uint64, cursor *string) (*schema.SettlementData, error) {
+	settlementData, _, err := s.constructSettlementBatch(ctx, epoch, cursor)
+
+	return settlementData, err
+}
+
+// constructSettlementBatch constructs the Settlement data of a batch of Nodes starting after the cursor,
+// along with the score breakdowns of the Nodes in the batch.
+func (s *Server) constructSettlementBatch(ctx context.Context, epoch uint64, cursor *string) (*schema.SettlementData, []*schema.OperationScore, error) {
 	// batchSize is the number of Nodes to process in each batch.
 	// This is to prevent the contract call from running out of gas.
 	// TODO: This method needs to be refactored when the number of nodes exceeds the batch size value.
@@ -160,12 +188,12 @@ func (s *Server) constructSettlementData(ctx context.Context, epoch uint64, curs
 	if err != nil {
 		// No qualified Nodes found in the database
 		if errors.Is(err, database.ErrorRowNotFound) {
-			return nil, nil
+			return nil, nil, nil
 		}
 
 		zap.L().Error("No qualified Nodes found", zap.Error(err), zap.Any("cursor", cursor))
 
-		return nil, err
+		return nil, nil, err
 	}
 
 	// isFinal is true if it's the last batch of Nodes
@@ -174,21 +202,21 @@ func (s *Server) constructSettlementData(ctx context.Context, epoch uint64, curs
 		nodes = nodes[:batchSize]
 	}
 
-	filterNodeAddresses, filterNodes, err := s.filter(nodes)
+	filterNodeAddresses, filterNodes, err := s.filter(ctx, nodes)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Calculate the number of requests for the Nodes
 	requestCount, operationStats, err := s.prepareRequestCounts(ctx, filterNodeAddresses, filterNodes)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Calculate the Operation rewards for the Nodes
-	operationRewards, err := s.calculateOperationRewards(ctx, operationStats, s.config.Rewards)
+	operationRewards, operationScores, err := s.calculateOperationRewards(ctx, operationStats, s.config.Rewards)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	return &schema.SettlementData{
@@ -197,16 +225,16 @@ func (s *Server) constructSettlementData(ctx context.Context, epoch uint64, curs
 		OperationRewards: operationRewards,
 		RequestCount:     requestCount,
 		IsFinal:          isFinal,
-	}, nil
+	}, operationScores, nil
 }
 
 // filter retrieves Node information from a staking contract.
-func (s *Server) filter(nodes []*schema.Node) ([]common.Address, []*schema.Node, error) {
+func (s *Server) filter(ctx context.Context, nodes []*schema.Node) ([]common.Address, []*schema.Node, error) {
 	nodeAddresses := lo.Map(nodes, func(node *schema.Node, _ int) common.Address {
 		return node.Address
 	})
 
-	nodeInfoList, err := s.stakingContract.GetNodes(&bind.CallOpts{}, nodeAddresses)
+	nodeInfoList, err := s.stakingContract.GetNodes(&bind.CallOpts{Context: ethereumclient.WithQuorum(ctx)}, nodeAddresses)
 	if err != nil {
 		return nil, nil, fmt.Errorf("get Nodes from chain: %w", err)
 	}
@@ -236,15 +264,51 @@ func isValidStatus(status uint8) bool {
 		status == uint8(schema.NodeStatusSlashing)
 }
 
+// invokeSettlementContractWithRetry invokes the Settlement contract, retrying the transactions that could not be sent.
+// A transaction included with a failed receipt is not retried automatically,
+// instead it blocks until an operator retries, skips or aborts it.
+// The beforePublish hook, if any, is called with each signed transaction before it is published.
+func (s *Server) invokeSettlementContractWithRetry(ctx context.Context, data schema.SettlementData, beforePublish func(ctx context.Context, tx *types.Transaction) error) (*types.Receipt, error) {
+	receipt, err := retry.DoWithData(
+		func() (*types.Receipt, error) {
+			return s.invokeSettlementContract(ctx, data, beforePublish)
+		},
+		retry.Delay(time.Second),
+		retry.Attempts(5),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(func(err error) bool {
+			return !failure.IsTransactionFailed(err)
+		}),
+	)
+
+	var failedErr *failure.TransactionFailedError
+	if !errors.As(err, &failedErr) {
+		return receipt, err
+	}
+
+	input, err := s.prepareInputData(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.failureHandler.Handle(ctx, data.Epoch.Uint64(), input, &data, failedErr, s.resendSettlementFailure)
+}
+
+// resendSettlementFailure resends a failed transaction.
+// The Settlement batch of a successful resend is confirmed when the batch is reconciled.
+func (s *Server) resendSettlementFailure(ctx context.Context, settlementFailure *schema.SettlementFailure) (*types.Receipt, error) {
+	return s.sendTransaction(ctx, settlementFailure.Input, nil)
+}
+
 // invokeSettlementContract invokes the Settlement contract with prepared data
 // and saves the Settlement to the database
-func (s *Server) invokeSettlementContract(ctx context.Context, data schema.SettlementData) (*types.Receipt, error) {
+func (s *Server) invokeSettlementContract(ctx context.Context, data schema.SettlementData, beforePublish func(ctx context.Context, tx *types.Transaction) error) (*types.Receipt, error) {
 	input, err := s.prepareInputData(data)
 	if err != nil {
 		return nil, err
 	}
 
-	receipt, err := s.sendTransaction(ctx, input)
+	receipt, err := s.sendTransaction(ctx, input, beforePublish)
 	if err != nil {
 		return nil, err
 	}
@@ -252,13 +316,17 @@ func (s *Server) invokeSettlementContract(ctx context.Context, data schema.Settl
 	return receipt, nil
 }
 
-// sendTransaction sends the transaction and returns the receipt if successful
-func (s *Server) sendTransaction(ctx context.Context, input []byte) (*types.Receipt, error) {
+// sendTransaction sends the transaction and returns the receipt if successful.
+// The transaction is only published once journaled, so a transaction which has landed on chain can always be found by its input.
+func (s *Server) sendTransaction(ctx context.Context, input []byte, beforePublish func(ctx context.Context, tx *types.Transaction) error) (*types.Receipt, error) {
 	txCandidate := txmgr.TxCandidate{
-		TxData:   input,
-		To:       lo.ToPtr(l2.ContractMap[s.chainID.Uint64()].AddressSettlementProxy),
-		GasLimit: s.config.Settler.GasLimit,
-		Value:    big.NewInt(0),
+		TxData:         input,
+		To:             lo.ToPtr(l2.ContractMap[s.chainID.Uint64()].AddressSettlementProxy),
+		GasLimit:       s.config.Settler.GasLimit,
+		Value:          big.NewInt(0),
+		Priority:       txmgr.PrioritySettlement,
+		RequireJournal: true,
+		BeforePublish:  beforePublish,
 	}
 
 	receipt, err := s.txManager.Send(ctx, txCandidate)
@@ -269,25 +337,9 @@ func (s *Server) sendTransaction(ctx context.Context, input []byte) (*types.Rece
 	if receipt.Status != types.ReceiptStatusSuccessful {
 		zap.L().Error("received an invalid transaction receipt", zap.String("tx", receipt.TxHash.String()))
 
-		// select {} purposely block the process as it is a critical error and meaningless to continue
-		// if panic() is called, the process will be restarted by the supervisor
-		// we do not want that as it will be stuck in the same state
-		select {}
+		return nil, &failure.TransactionFailedError{Receipt: receipt}
 	}
 
 	// return the receipt if the transaction is successful
 	return receipt, nil
 }
-
-// saveSettlement saves the Settlement data to the database
-func (s *Server) saveSettlement(ctx context.Context, receipt *types.Receipt, data schema.SettlementData) error {
-	if err := s.databaseClient.SaveEpochTrigger(ctx, &schema.EpochTrigger{
-		TransactionHash: receipt.TxHash,
-		EpochID:         data.Epoch.Uint64(),
-		Data:            data,
-	}); err != nil {
-		return fmt.Errorf("save settler submitEpochProof: %w", err)
-	}
-
-	return nil
-}
@@ -0,0 +1,42 @@
+package schema
+
+import "time"
+
+type CronJobRunTrigger string
+
+const (
+	// CronJobRunTriggerSchedule means the job was run on its schedule.
+	CronJobRunTriggerSchedule CronJobRunTrigger = "schedule"
+	// CronJobRunTriggerManual means the job was triggered by an operator through the admin API.
+	CronJobRunTriggerManual CronJobRunTrigger = "manual"
+)
+
+type CronJobRunStatus string
+
+const (
+	CronJobRunStatusRunning   CronJobRunStatus = "running"
+	CronJobRunStatusSucceeded CronJobRunStatus = "succeeded"
+	CronJobRunStatusFailed    CronJobRunStatus = "failed"
+	// CronJobRunStatusSkipped means the job was not run on its schedule, as it was running in another scheduler.
+	CronJobRunStatusSkipped CronJobRunStatus = "skipped"
+)
+
+// CronJobRun is a run of a cron job of the scheduler.
+type CronJobRun struct {
+	ID      uint64            `json:"id"`
+	Job     string            `json:"job"`
+	Trigger CronJobRunTrigger `json:"trigger"`
+	Status  CronJobRunStatus  `json:"status"`
+	// Error is the error returned by a failed run.
+	Error     string     `json:"error,omitempty"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	// Duration is the milliseconds taken by the run, it is 0 while the job is running.
+	Duration int64 `json:"duration"`
+}
+
+type CronJobRunsQuery struct {
+	Cursor *uint64
+	Job    *string
+	Limit  *int
+}
@@ -22,3 +22,30 @@ type SettlementData struct {
 	RequestCount     []*big.Int       `json:"request_count"`
 	IsFinal          bool             `json:"is_final"`
 }
+
+// SettlementPreview is the Settlement data of an Epoch computed without being submitted on chain.
+type SettlementPreview struct {
+	Epoch *big.Int `json:"epoch"`
+	// Batches are the Settlement data in the batches that would be submitted to the Settlement contract.
+	Batches []*SettlementData        `json:"batches"`
+	Nodes   []*SettlementPreviewNode `json:"nodes"`
+}
+
+type SettlementPreviewNode struct {
+	Address common.Address `json:"address"`
+	// Batch is the index of the batch the Node is settled in.
+	Batch            int      `json:"batch"`
+	OperationRewards *big.Int `json:"operation_rewards"`
+	RequestCount     *big.Int `json:"request_count"`
+	// Score is nil if the Node has no stat or has been demoted, in which case it receives no Operation Rewards.
+	Score *OperationScore `json:"score"`
+}
+
+// OperationScore is the breakdown of the operation score of a Node,
+// the Operation Rewards of a batch are distributed in proportion to the total scores.
+type OperationScore struct {
+	Distribution float64 `json:"distribution"`
+	Data         float64 `json:"data"`
+	Stability    float64 `json:"stability"`
+	Total        float64 `json:"total"`
+}
@@ -3,6 +3,7 @@ package schema
 import (
 	"encoding/json"
 	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/rss3-network/global-indexer/contract/l2"
@@ -35,6 +36,62 @@ type Node struct {
 	Type                   string                 `json:"type"`
 	AccessToken            string                 `json:"-"`
 	CreatedAt              int64                  `json:"created_at"`
+	CircuitBreaker         *NodeCircuitBreaker    `json:"circuit_breaker,omitempty"`
+	Preferences            *NodePreferences       `json:"-"`
+}
+
+// NodePreferences are set by the operator of a Node through the signed preferences API.
+type NodePreferences struct {
+	// AlertWebhooks are notified with a POST request when the Node goes offline outside of its maintenance windows.
+	AlertWebhooks []string `json:"alert_webhooks,omitempty"`
+	// OptOutRSS and OptOutAI exclude the Node from the routing of the RSS and the AI requests.
+	OptOutRSS          bool                     `json:"opt_out_rss"`
+	OptOutAI           bool                     `json:"opt_out_ai"`
+	MaintenanceWindows []*NodeMaintenanceWindow `json:"maintenance_windows,omitempty"`
+	// MaintenanceHistory is kept by the hub to bound the maintenance time of the Node, it holds the windows ending within the maintenance period,
+	// the ended and the canceled ones included once they have started.
+	MaintenanceHistory []*NodeMaintenanceWindow `json:"maintenance_history,omitempty"`
+}
+
+// NodeMaintenanceWindow is a period declared by the operator of a Node, in which the Node is under maintenance.
+type NodeMaintenanceWindow struct {
+	// StartAt is the unix timestamp of the start of the window, Duration is the seconds the window lasts.
+	StartAt  int64 `json:"start_at"`
+	Duration int64 `json:"duration"`
+}
+
+// InMaintenance returns whether the time is within one of the maintenance windows.
+func (p *NodePreferences) InMaintenance(t time.Time) bool {
+	if p == nil {
+		return false
+	}
+
+	for _, window := range p.MaintenanceWindows {
+		if window.Contains(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Contains returns whether the time is within the window.
+func (w *NodeMaintenanceWindow) Contains(t time.Time) bool {
+	return t.Unix() >= w.StartAt && t.Unix() < w.StartAt+w.Duration
+}
+
+// Ended returns whether the window has ended at the time.
+func (w *NodeMaintenanceWindow) Ended(t time.Time) bool {
+	return w.StartAt+w.Duration <= t.Unix()
+}
+
+// NodeCircuitBreaker is the state of the circuit breaker of a Node in the distributor.
+type NodeCircuitBreaker struct {
+	State               string `json:"state"`
+	ConsecutiveFailures int64  `json:"consecutive_failures"`
+	WindowRequests      int64  `json:"window_requests"`
+	WindowFailures      int64  `json:"window_failures"`
+	OpenedAt            int64  `json:"opened_at,omitempty"`
 }
 
 type NodeLocation struct {
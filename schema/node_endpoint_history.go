@@ -0,0 +1,31 @@
+package schema
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NodeEndpointHistory is a change of the endpoint or the settings of a Node made by its registration.
+type NodeEndpointHistory struct {
+	ID       uint64         `json:"id"`
+	Address  common.Address `json:"address"`
+	Endpoint string         `json:"endpoint"`
+	// PreviousEndpoint is the endpoint before the change, it is empty for the first registration of the Node.
+	PreviousEndpoint string `json:"previous_endpoint"`
+	// Stream and Config are recorded but not served, like those of the Node.
+	Stream json.RawMessage `json:"-"`
+	Config json.RawMessage `json:"-"`
+	// AccessTokenChanged is whether the access token was changed, the access tokens themselves are not recorded.
+	AccessTokenChanged bool `json:"access_token_changed"`
+	// Verified is whether the Node proved the ownership of the new endpoint by serving a token signed by the Node.
+	Verified  bool      `json:"verified"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type NodeEndpointHistoryQuery struct {
+	Address *common.Address
+	Cursor  *uint64
+	Limit   *int
+}
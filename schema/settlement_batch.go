@@ -0,0 +1,39 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type SettlementBatchStatus string
+
+const (
+	// SettlementBatchStatusPending means the batch is persisted before being sent, it may or may not have landed on chain.
+	SettlementBatchStatusPending SettlementBatchStatus = "pending"
+	// SettlementBatchStatusConfirmed means the transaction of the batch succeeded.
+	SettlementBatchStatusConfirmed SettlementBatchStatus = "confirmed"
+	// SettlementBatchStatusSkipped means the transaction of the batch failed and was skipped by an operator.
+	SettlementBatchStatusSkipped SettlementBatchStatus = "skipped"
+)
+
+// SettlementBatch is the checkpoint of a batch of Nodes in the Settlement of an Epoch.
+type SettlementBatch struct {
+	EpochID uint64 `json:"epoch_id"`
+	// Index is the position of the batch in the Epoch, starting from 0.
+	Index uint64 `json:"index"`
+	// Cursor is the address of the last Node of the previous batch.
+	Cursor *string `json:"cursor,omitempty"`
+	// CalldataHash is the Keccak-256 hash of the input data of the transaction.
+	CalldataHash common.Hash `json:"calldata_hash"`
+	// BlockNumber is the latest block number before the transaction was sent,
+	// the transaction can only be included in the blocks after it.
+	BlockNumber uint64 `json:"block_number"`
+	// TransactionHash is the hash of the latest signed transaction, saved before it is published,
+	// or of the included transaction once the batch is confirmed.
+	TransactionHash *common.Hash          `json:"transaction_hash,omitempty"`
+	Data            SettlementData        `json:"data"`
+	Status          SettlementBatchStatus `json:"status"`
+	CreatedAt       time.Time             `json:"created_at"`
+	UpdatedAt       time.Time             `json:"updated_at"`
+}
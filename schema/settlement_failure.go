@@ -0,0 +1,73 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+type SettlementFailureStatus string
+
+const (
+	// SettlementFailureStatusFailed means the transaction failed and is waiting for an operator to resolve it.
+	SettlementFailureStatusFailed SettlementFailureStatus = "failed"
+	// SettlementFailureStatusRetrying means an operator asked to resend the transaction.
+	SettlementFailureStatusRetrying SettlementFailureStatus = "retrying"
+	// SettlementFailureStatusResolved means a resent transaction succeeded.
+	SettlementFailureStatusResolved SettlementFailureStatus = "resolved"
+	// SettlementFailureStatusSkipped means an operator gave up the transaction and let the service move on.
+	SettlementFailureStatusSkipped SettlementFailureStatus = "skipped"
+	// SettlementFailureStatusAborted means an operator gave up the transaction and stopped the service.
+	SettlementFailureStatusAborted SettlementFailureStatus = "aborted"
+)
+
+// Unresolved reports whether the failure still blocks the service which sent the transaction.
+func (s SettlementFailureStatus) Unresolved() bool {
+	return s == SettlementFailureStatusFailed || s == SettlementFailureStatusRetrying
+}
+
+type SettlementFailureAction string
+
+const (
+	SettlementFailureActionRetry SettlementFailureAction = "retry"
+	SettlementFailureActionSkip  SettlementFailureAction = "skip"
+	SettlementFailureActionAbort SettlementFailureAction = "abort"
+)
+
+// SettlementFailure is a transaction to the Settlement contract included with a failed receipt.
+type SettlementFailure struct {
+	ID uint64 `json:"id"`
+	// Service is the name of the service which sent the transaction.
+	Service string `json:"service"`
+	EpochID uint64 `json:"epoch_id"`
+	// TransactionHash is the hash of the latest failed attempt.
+	TransactionHash common.Hash `json:"transaction_hash"`
+	// Input is the input data of the transaction, which is resent on retry.
+	Input hexutil.Bytes `json:"input"`
+	// Data is the Settlement data of the transaction, it is only set for the Epoch proofs sent by the settler.
+	Data     *SettlementData         `json:"data,omitempty"`
+	Attempts uint64                  `json:"attempts"`
+	Status   SettlementFailureStatus `json:"status"`
+	// Audits are the actions taken by the operators on the failure.
+	Audits    []*SettlementFailureAudit `json:"audits,omitempty"`
+	CreatedAt time.Time                 `json:"created_at"`
+	UpdatedAt time.Time                 `json:"updated_at"`
+}
+
+// SettlementFailureAudit is an action taken by an operator on a SettlementFailure.
+type SettlementFailureAudit struct {
+	ID        uint64                  `json:"id"`
+	FailureID uint64                  `json:"failure_id"`
+	Action    SettlementFailureAction `json:"action"`
+	Operator  string                  `json:"operator"`
+	Reason    string                  `json:"reason"`
+	CreatedAt time.Time               `json:"created_at"`
+}
+
+type SettlementFailuresQuery struct {
+	Service  *string
+	EpochID  *uint64
+	Statuses []SettlementFailureStatus
+	Limit    *int
+}
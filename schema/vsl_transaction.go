@@ -0,0 +1,83 @@
+package schema
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+type VSLTransactionStatus string
+
+const (
+	// VSLTransactionStatusPending means the transaction is being sent, or was left unresolved by a stopped service.
+	VSLTransactionStatusPending VSLTransactionStatus = "pending"
+	// VSLTransactionStatusConfirmed means the transaction was included with a successful receipt.
+	VSLTransactionStatusConfirmed VSLTransactionStatus = "confirmed"
+	// VSLTransactionStatusFailed means the transaction was included with a failed receipt.
+	VSLTransactionStatusFailed VSLTransactionStatus = "failed"
+	// VSLTransactionStatusAborted means the sending was given up before the transaction was included.
+	VSLTransactionStatusAborted VSLTransactionStatus = "aborted"
+	// VSLTransactionStatusDropped means the nonce of the transaction was used by another transaction.
+	VSLTransactionStatusDropped VSLTransactionStatus = "dropped"
+)
+
+// Unresolved reports whether the transaction may still be included.
+func (s VSLTransactionStatus) Unresolved() bool {
+	return s == VSLTransactionStatusPending || s == VSLTransactionStatusAborted
+}
+
+// VSLTransaction is a transaction sent to the VSL by the transaction manager.
+type VSLTransaction struct {
+	ID        uint64          `json:"id"`
+	ChainID   uint64          `json:"chain_id"`
+	From      common.Address  `json:"from"`
+	To        *common.Address `json:"to,omitempty"`
+	Nonce     uint64          `json:"nonce"`
+	Input     hexutil.Bytes   `json:"input"`
+	Value     *big.Int        `json:"value"`
+	GasLimit  uint64          `json:"gas_limit"`
+	GasTipCap *big.Int        `json:"gas_tip_cap"`
+	GasFeeCap *big.Int        `json:"gas_fee_cap"`
+	// TransactionHash is the hash of the included submission, or of the latest one if none is included.
+	TransactionHash *common.Hash         `json:"transaction_hash,omitempty"`
+	Status          VSLTransactionStatus `json:"status"`
+	BlockNumber     *uint64              `json:"block_number,omitempty"`
+	GasUsed         *uint64              `json:"gas_used,omitempty"`
+	// Fee is the fee paid for the included transaction, including the L1 data fee.
+	Fee *big.Int `json:"fee,omitempty"`
+	// Error is the reason the sending was aborted.
+	Error string `json:"error,omitempty"`
+	// Submissions are the signed transactions published for the transaction, one for each resubmission.
+	Submissions []*VSLTransactionSubmission `json:"submissions,omitempty"`
+	CreatedAt   time.Time                   `json:"created_at"`
+	UpdatedAt   time.Time                   `json:"updated_at"`
+}
+
+// VSLTransactionSubmission is a signed transaction published for a VSLTransaction.
+type VSLTransactionSubmission struct {
+	ID              uint64      `json:"id"`
+	TransactionID   uint64      `json:"transaction_id"`
+	TransactionHash common.Hash `json:"transaction_hash"`
+	Nonce           uint64      `json:"nonce"`
+	GasTipCap       *big.Int    `json:"gas_tip_cap"`
+	GasFeeCap       *big.Int    `json:"gas_fee_cap"`
+	CreatedAt       time.Time   `json:"created_at"`
+}
+
+type VSLTransactionFeesQuery struct {
+	From common.Address
+	// Since is the earliest time the transactions were created at.
+	Since time.Time
+}
+
+type VSLTransactionsQuery struct {
+	// Cursor is the ID of the last transaction of the previous page.
+	Cursor   *uint64
+	From     *common.Address
+	Statuses []VSLTransactionStatus
+	// Input filters the transactions by their input data.
+	Input []byte
+	Limit *int
+}